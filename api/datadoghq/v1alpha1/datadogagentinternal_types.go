@@ -34,6 +34,10 @@ type DatadogAgentInternalStatus struct {
 	// RemoteConfigConfiguration stores the configuration received from RemoteConfig.
 	// +optional
 	RemoteConfigConfiguration *v2alpha1.RemoteConfigConfiguration `json:"remoteConfigConfiguration,omitempty"`
+	// Patches reports the result of spec.patches on each object they target.
+	// +optional
+	// +listType=atomic
+	Patches []v2alpha1.ObjectPatchStatus `json:"patches,omitempty"`
 }
 
 // DatadogAgentInternal is the Schema for the datadogagentinternals API
@@ -21,12 +21,15 @@ var datadogAgentProfileFeatureAllowlist = map[string]struct{}{
 }
 
 var datadogAgentProfileComponentOverrideAllowlist = map[string]struct{}{
-	"containers":        {},
-	"priorityClassName": {},
-	"runtimeClassName":  {},
-	"updateStrategy":    {},
-	"labels":            {},
-	"volumes":           {},
+	"containers":                  {},
+	"priorityClassName":           {},
+	"runtimeClassName":            {},
+	"updateStrategy":              {},
+	"labels":                      {},
+	"volumes":                     {},
+	"extraContainers":             {},
+	"extraInitContainers":         {},
+	"extraInitContainersPosition": {},
 }
 
 var datadogAgentProfileContainerOverrideAllowlist = map[string]struct{}{
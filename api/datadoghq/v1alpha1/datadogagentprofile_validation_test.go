@@ -298,12 +298,15 @@ func TestValidateDatadogAgentProfileFeaturesAllowlist(t *testing.T) {
 
 func TestValidateDatadogAgentProfileComponentOverrideAllowlist(t *testing.T) {
 	allowedComponentOverrideFields := map[string]struct{}{
-		"Containers":        {},
-		"PriorityClassName": {},
-		"RuntimeClassName":  {},
-		"UpdateStrategy":    {},
-		"Labels":            {},
-		"Volumes":           {},
+		"Containers":                  {},
+		"PriorityClassName":           {},
+		"RuntimeClassName":            {},
+		"UpdateStrategy":              {},
+		"Labels":                      {},
+		"Volumes":                     {},
+		"ExtraContainers":             {},
+		"ExtraInitContainers":         {},
+		"ExtraInitContainersPosition": {},
 	}
 
 	overrideType := reflect.TypeOf(v2alpha1.DatadogAgentComponentOverride{})
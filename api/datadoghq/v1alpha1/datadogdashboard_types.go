@@ -12,6 +12,7 @@ import (
 
 // DatadogDashboardSpec defines the desired state of DatadogDashboard
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(self.widgets) || size(self.widgets) == 0 || !has(self.widgetsYAML) || size(self.widgetsYAML) == 0",message="widgets and widgetsYAML are mutually exclusive"
 type DatadogDashboardSpec struct {
 	// Description is the description of the dashboard.
 	// +optional
@@ -47,9 +48,15 @@ type DatadogDashboardSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Title string `json:"title,omitempty"`
-	// Widgets is a JSON string representation of a list of Datadog API Widgets
+	// Widgets is a JSON string representation of a list of Datadog API Widgets.
+	// It can't be set with WidgetsYAML.
 	// +optional
 	Widgets string `json:"widgets,omitempty"`
+	// WidgetsYAML is the list of widgets of the dashboard, with typed definitions for the common
+	// widget types and a raw definition for the others. It can't be set with Widgets.
+	// +listType=atomic
+	// +optional
+	WidgetsYAML []DashboardWidget `json:"widgetsYAML,omitempty"`
 }
 
 // DatadogDashboardStatus defines the observed state of DatadogDashboard
@@ -12,7 +12,7 @@ import (
 )
 
 // IsValidDatadogDashboard use to check if a DatadogDashboardSpec is valid by checking
-// that the required fields are defined
+// that the required fields are defined and that the widgets are valid Datadog API widgets
 func IsValidDatadogDashboard(spec *DatadogDashboardSpec) error {
 	var errs []error
 	if spec.Title == "" {
@@ -23,5 +23,9 @@ func IsValidDatadogDashboard(spec *DatadogDashboardSpec) error {
 		errs = append(errs, fmt.Errorf("spec.LayoutType must be defined"))
 	}
 
+	if _, err := spec.DatadogWidgets(); err != nil {
+		errs = append(errs, err)
+	}
+
 	return utilserrors.NewAggregate(errs)
 }
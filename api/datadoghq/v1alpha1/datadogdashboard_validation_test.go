@@ -24,6 +24,37 @@ func TestIsValidDatadogDashboard(t *testing.T) {
 	missingLayoutType := &DatadogDashboardSpec{
 		Title: "test",
 	}
+	invalidWidgetsJSON := &DatadogDashboardSpec{
+		LayoutType: datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:      "test",
+		Widgets:    `[{"definition": {"type": "note", "content": "test"}`,
+	}
+	invalidWidget := &DatadogDashboardSpec{
+		LayoutType: datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:      "test",
+		Widgets:    `[{"definition": {"type": "note", "content": "test"}}, {"definition": {"content": "test"}}]`,
+	}
+	unparsedWidget := &DatadogDashboardSpec{
+		LayoutType: datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:      "test",
+		Widgets:    `[{"definition": {"type": "note", "content": "test"}}, {"definition": {"type": "note", "text_align": "middle"}}]`,
+	}
+	unknownWidgetType := &DatadogDashboardSpec{
+		LayoutType: datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:      "test",
+		Widgets:    `[{"definition": {"type": "note", "content": "test"}}, {"definition": {"type": "future_widget", "text_align": "middle"}}]`,
+	}
+	widgetsAndWidgetsYAML := &DatadogDashboardSpec{
+		LayoutType:  datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:       "test",
+		Widgets:     `[{"definition": {"type": "note", "content": "test"}}]`,
+		WidgetsYAML: []DashboardWidget{{DashboardGroupedWidget: DashboardGroupedWidget{Note: &DashboardNoteWidgetDefinition{Content: "test"}}}},
+	}
+	validWidgetsYAML := &DatadogDashboardSpec{
+		LayoutType:  datadogV1.DASHBOARDLAYOUTTYPE_ORDERED,
+		Title:       "test",
+		WidgetsYAML: []DashboardWidget{{DashboardGroupedWidget: DashboardGroupedWidget{Note: &DashboardNoteWidgetDefinition{Content: "test"}}}},
+	}
 
 	testCases := []struct {
 		name    string
@@ -44,6 +75,33 @@ func TestIsValidDatadogDashboard(t *testing.T) {
 			spec:    missingLayoutType,
 			wantErr: "spec.LayoutType must be defined",
 		},
+		{
+			name:    "dashboard with invalid widgets JSON",
+			spec:    invalidWidgetsJSON,
+			wantErr: "spec.Widgets is not a valid JSON list of widgets: unexpected end of JSON input",
+		},
+		{
+			name:    "dashboard with an invalid widget",
+			spec:    invalidWidget,
+			wantErr: "spec.Widgets[1] definition doesn't match any widget type of the Datadog API",
+		},
+		{
+			name: "dashboard with a widget of a known type the client can't parse",
+			spec: unparsedWidget,
+		},
+		{
+			name: "dashboard with a widget of an unknown type",
+			spec: unknownWidgetType,
+		},
+		{
+			name:    "dashboard with widgets and widgetsYAML",
+			spec:    widgetsAndWidgetsYAML,
+			wantErr: "spec.Widgets and spec.WidgetsYAML are mutually exclusive",
+		},
+		{
+			name: "dashboard with widgetsYAML",
+			spec: validWidgetsYAML,
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,395 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DashboardWidget is a widget of a dashboard. Exactly one of its definitions must be set.
+// +k8s:openapi-gen=true
+type DashboardWidget struct {
+	DashboardGroupedWidget `json:",inline"`
+	// Group is the definition of a group widget, holding other widgets.
+	// +optional
+	Group *DashboardGroupWidgetDefinition `json:"group,omitempty"`
+}
+
+// DashboardGroupedWidget is a widget that can be part of a group widget. Exactly one of its
+// definitions must be set.
+// +k8s:openapi-gen=true
+type DashboardGroupedWidget struct {
+	// ID is the ID of the widget.
+	// +optional
+	ID *int64 `json:"id,omitempty"`
+	// Layout is the position and size of the widget. It's required for the widgets of a dashboard
+	// with the 'free' layout type or the 'fixed' reflow type.
+	// +optional
+	Layout *DashboardWidgetLayout `json:"layout,omitempty"`
+	// Timeseries is the definition of a timeseries widget.
+	// +optional
+	Timeseries *DashboardTimeseriesWidgetDefinition `json:"timeseries,omitempty"`
+	// QueryValue is the definition of a query value widget.
+	// +optional
+	QueryValue *DashboardQueryValueWidgetDefinition `json:"queryValue,omitempty"`
+	// Toplist is the definition of a top list widget.
+	// +optional
+	Toplist *DashboardToplistWidgetDefinition `json:"toplist,omitempty"`
+	// Note is the definition of a note widget.
+	// +optional
+	Note *DashboardNoteWidgetDefinition `json:"note,omitempty"`
+	// Raw is the definition of a widget of any other type, as the `definition` of a widget
+	// in the Datadog API, such as `{"type": "free_text", "text": "Overview"}`.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Raw *runtime.RawExtension `json:"raw,omitempty"`
+}
+
+// DashboardWidgetLayout is the position and size of a widget.
+// +k8s:openapi-gen=true
+type DashboardWidgetLayout struct {
+	// X is the position of the widget on the x (horizontal) axis.
+	// +kubebuilder:validation:Minimum=0
+	X int64 `json:"x"`
+	// Y is the position of the widget on the y (vertical) axis.
+	// +kubebuilder:validation:Minimum=0
+	Y int64 `json:"y"`
+	// Width is the width of the widget.
+	// +kubebuilder:validation:Minimum=0
+	Width int64 `json:"width"`
+	// Height is the height of the widget.
+	// +kubebuilder:validation:Minimum=0
+	Height int64 `json:"height"`
+	// IsColumnBreak starts a new column at the widget, for a dashboard with the 'ordered' layout type
+	// and the 'fixed' reflow type.
+	// +optional
+	IsColumnBreak *bool `json:"isColumnBreak,omitempty"`
+}
+
+// DashboardTimeseriesWidgetDefinition is the definition of a timeseries widget.
+// +k8s:openapi-gen=true
+type DashboardTimeseriesWidgetDefinition struct {
+	// Title is the title of the widget.
+	// +optional
+	Title *string `json:"title,omitempty"`
+	// TitleAlign is the alignment of the title.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TitleAlign *datadogV1.WidgetTextAlign `json:"titleAlign,omitempty"`
+	// TitleSize is the size of the title.
+	// +optional
+	TitleSize *string `json:"titleSize,omitempty"`
+	// ShowLegend shows the legend of the widget.
+	// +optional
+	ShowLegend *bool `json:"showLegend,omitempty"`
+	// LegendLayout is the layout of the legend.
+	// +kubebuilder:validation:Enum=auto;horizontal;vertical
+	// +optional
+	LegendLayout *datadogV1.TimeseriesWidgetLegendLayout `json:"legendLayout,omitempty"`
+	// LegendColumns are the columns displayed in the legend.
+	// +kubebuilder:validation:items:Enum=value;avg;sum;min;max
+	// +listType=set
+	// +optional
+	LegendColumns []datadogV1.TimeseriesWidgetLegendColumn `json:"legendColumns,omitempty"`
+	// Requests are the requests of the widget.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Requests []DashboardTimeseriesWidgetRequest `json:"requests"`
+	// Yaxis is the left y-axis of the widget.
+	// +optional
+	Yaxis *DashboardWidgetAxis `json:"yaxis,omitempty"`
+	// RightYaxis is the right y-axis of the widget.
+	// +optional
+	RightYaxis *DashboardWidgetAxis `json:"rightYaxis,omitempty"`
+	// Markers are the markers drawn on the widget.
+	// +listType=atomic
+	// +optional
+	Markers []DashboardWidgetMarker `json:"markers,omitempty"`
+}
+
+// DashboardTimeseriesWidgetRequest is a request of a timeseries widget.
+// +k8s:openapi-gen=true
+type DashboardTimeseriesWidgetRequest struct {
+	DashboardWidgetRequest `json:",inline"`
+	// DisplayType is how the request is displayed.
+	// +kubebuilder:validation:Enum=area;bars;line;overlay
+	// +optional
+	DisplayType *datadogV1.WidgetDisplayType `json:"displayType,omitempty"`
+	// Style is the style of the request.
+	// +optional
+	Style *DashboardWidgetRequestStyle `json:"style,omitempty"`
+	// OnRightYaxis displays the request on the right y-axis.
+	// +optional
+	OnRightYaxis *bool `json:"onRightYaxis,omitempty"`
+}
+
+// DashboardQueryValueWidgetDefinition is the definition of a query value widget.
+// +k8s:openapi-gen=true
+type DashboardQueryValueWidgetDefinition struct {
+	// Title is the title of the widget.
+	// +optional
+	Title *string `json:"title,omitempty"`
+	// TitleAlign is the alignment of the title.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TitleAlign *datadogV1.WidgetTextAlign `json:"titleAlign,omitempty"`
+	// TitleSize is the size of the title.
+	// +optional
+	TitleSize *string `json:"titleSize,omitempty"`
+	// Autoscale automatically scales the value.
+	// +optional
+	Autoscale *bool `json:"autoscale,omitempty"`
+	// CustomUnit is the unit displayed with the value.
+	// +optional
+	CustomUnit *string `json:"customUnit,omitempty"`
+	// Precision is the number of decimals of the value.
+	// +optional
+	Precision *int64 `json:"precision,omitempty"`
+	// TextAlign is the alignment of the value.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TextAlign *datadogV1.WidgetTextAlign `json:"textAlign,omitempty"`
+	// Requests are the requests of the widget.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Requests []DashboardQueryValueWidgetRequest `json:"requests"`
+}
+
+// DashboardQueryValueWidgetRequest is a request of a query value widget.
+// +k8s:openapi-gen=true
+type DashboardQueryValueWidgetRequest struct {
+	DashboardWidgetRequest `json:",inline"`
+	// Aggregator is the aggregation of the query Q.
+	// +kubebuilder:validation:Enum=avg;last;max;min;sum;percentile
+	// +optional
+	Aggregator *datadogV1.WidgetAggregator `json:"aggregator,omitempty"`
+	// ConditionalFormats are the formats applied to the value depending on its value.
+	// +listType=atomic
+	// +optional
+	ConditionalFormats []DashboardWidgetConditionalFormat `json:"conditionalFormats,omitempty"`
+}
+
+// DashboardToplistWidgetDefinition is the definition of a top list widget.
+// +k8s:openapi-gen=true
+type DashboardToplistWidgetDefinition struct {
+	// Title is the title of the widget.
+	// +optional
+	Title *string `json:"title,omitempty"`
+	// TitleAlign is the alignment of the title.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TitleAlign *datadogV1.WidgetTextAlign `json:"titleAlign,omitempty"`
+	// TitleSize is the size of the title.
+	// +optional
+	TitleSize *string `json:"titleSize,omitempty"`
+	// Requests are the requests of the widget.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Requests []DashboardToplistWidgetRequest `json:"requests"`
+}
+
+// DashboardToplistWidgetRequest is a request of a top list widget.
+// +k8s:openapi-gen=true
+type DashboardToplistWidgetRequest struct {
+	DashboardWidgetRequest `json:",inline"`
+	// ConditionalFormats are the formats applied to the values depending on their value.
+	// +listType=atomic
+	// +optional
+	ConditionalFormats []DashboardWidgetConditionalFormat `json:"conditionalFormats,omitempty"`
+}
+
+// DashboardNoteWidgetDefinition is the definition of a note widget.
+// +k8s:openapi-gen=true
+type DashboardNoteWidgetDefinition struct {
+	// Content is the content of the note, in Markdown.
+	// +kubebuilder:validation:MinLength=1
+	Content string `json:"content"`
+	// BackgroundColor is the background color of the note.
+	// +optional
+	BackgroundColor *string `json:"backgroundColor,omitempty"`
+	// FontSize is the size of the text.
+	// +optional
+	FontSize *string `json:"fontSize,omitempty"`
+	// TextAlign is the horizontal alignment of the text.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TextAlign *datadogV1.WidgetTextAlign `json:"textAlign,omitempty"`
+	// VerticalAlign is the vertical alignment of the text.
+	// +kubebuilder:validation:Enum=center;top;bottom
+	// +optional
+	VerticalAlign *datadogV1.WidgetVerticalAlign `json:"verticalAlign,omitempty"`
+	// HasPadding adds a padding around the text.
+	// +optional
+	HasPadding *bool `json:"hasPadding,omitempty"`
+	// ShowTick shows a tick on the note.
+	// +optional
+	ShowTick *bool `json:"showTick,omitempty"`
+	// TickEdge is the edge of the note with the tick.
+	// +kubebuilder:validation:Enum=bottom;left;right;top
+	// +optional
+	TickEdge *datadogV1.WidgetTickEdge `json:"tickEdge,omitempty"`
+	// TickPos is the position of the tick on its edge, such as '50%'.
+	// +optional
+	TickPos *string `json:"tickPos,omitempty"`
+}
+
+// DashboardGroupWidgetDefinition is the definition of a group widget.
+// +k8s:openapi-gen=true
+type DashboardGroupWidgetDefinition struct {
+	// Title is the title of the group.
+	// +optional
+	Title *string `json:"title,omitempty"`
+	// TitleAlign is the alignment of the title.
+	// +kubebuilder:validation:Enum=center;left;right
+	// +optional
+	TitleAlign *datadogV1.WidgetTextAlign `json:"titleAlign,omitempty"`
+	// ShowTitle shows the title of the group.
+	// +optional
+	ShowTitle *bool `json:"showTitle,omitempty"`
+	// BackgroundColor is the background color of the group title.
+	// +optional
+	BackgroundColor *string `json:"backgroundColor,omitempty"`
+	// BannerImg is the URL of the image displayed as a banner of the group.
+	// +optional
+	BannerImg *string `json:"bannerImg,omitempty"`
+	// LayoutType is the layout type of the group.
+	// +kubebuilder:validation:Enum=ordered
+	// +kubebuilder:default=ordered
+	// +optional
+	LayoutType datadogV1.WidgetLayoutType `json:"layoutType,omitempty"`
+	// Widgets are the widgets of the group.
+	// +listType=atomic
+	Widgets []DashboardGroupedWidget `json:"widgets"`
+}
+
+// DashboardWidgetRequest holds the queries of a widget request. Either Q or Queries must be set.
+// +k8s:openapi-gen=true
+type DashboardWidgetRequest struct {
+	// Q is a metric query, such as 'avg:system.cpu.user{*} by {host}'.
+	// +optional
+	Q *string `json:"q,omitempty"`
+	// Queries are the metric queries combined by the Formulas. Use a raw widget for
+	// queries of other data sources.
+	// +listType=atomic
+	// +optional
+	Queries []DashboardWidgetMetricQuery `json:"queries,omitempty"`
+	// Formulas are the formulas computed from the Queries.
+	// +listType=atomic
+	// +optional
+	Formulas []DashboardWidgetFormula `json:"formulas,omitempty"`
+	// ResponseFormat is the format of the response of the Queries.
+	// +kubebuilder:validation:Enum=timeseries;scalar;event_list
+	// +optional
+	ResponseFormat *datadogV1.FormulaAndFunctionResponseFormat `json:"responseFormat,omitempty"`
+}
+
+// DashboardWidgetMetricQuery is a metric query of a widget request.
+// +k8s:openapi-gen=true
+type DashboardWidgetMetricQuery struct {
+	// Name is the name of the query, used in the formulas.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Query is the metric query, such as 'avg:system.cpu.user{*} by {host}'.
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+	// Aggregator is the aggregation of the query, for the scalar response format.
+	// +kubebuilder:validation:Enum=avg;min;max;sum;last;area;l2norm;percentile
+	// +optional
+	Aggregator *datadogV1.FormulaAndFunctionMetricAggregation `json:"aggregator,omitempty"`
+}
+
+// DashboardWidgetFormula is a formula of a widget request.
+// +k8s:openapi-gen=true
+type DashboardWidgetFormula struct {
+	// Formula is the formula, such as 'query1 / query2'.
+	// +kubebuilder:validation:MinLength=1
+	Formula string `json:"formula"`
+	// Alias is the name displayed for the formula.
+	// +optional
+	Alias *string `json:"alias,omitempty"`
+	// Limit limits the number of series of the formula.
+	// +optional
+	Limit *DashboardWidgetFormulaLimit `json:"limit,omitempty"`
+}
+
+// DashboardWidgetFormulaLimit limits the number of series of a formula.
+// +k8s:openapi-gen=true
+type DashboardWidgetFormulaLimit struct {
+	// Count is the number of series.
+	// +optional
+	Count *int64 `json:"count,omitempty"`
+	// Order is the order of the series.
+	// +kubebuilder:validation:Enum=asc;desc
+	// +optional
+	Order *datadogV1.QuerySortOrder `json:"order,omitempty"`
+}
+
+// DashboardWidgetConditionalFormat is a format applied to a value depending on its value.
+// +k8s:openapi-gen=true
+type DashboardWidgetConditionalFormat struct {
+	// Comparator is the comparison of the value to Value.
+	// +kubebuilder:validation:Enum="=";">";">=";"<";"<="
+	Comparator datadogV1.WidgetComparator `json:"comparator"`
+	// Value is the number compared to, such as '0.5'.
+	Value string `json:"value"`
+	// Palette is the color palette applied when the comparison is true, such as 'white_on_red'.
+	Palette datadogV1.WidgetPalette `json:"palette"`
+}
+
+// DashboardWidgetRequestStyle is the style of a widget request.
+// +k8s:openapi-gen=true
+type DashboardWidgetRequestStyle struct {
+	// Palette is the color palette of the request, such as 'dog_classic'.
+	// +optional
+	Palette *string `json:"palette,omitempty"`
+	// LineType is the type of the lines.
+	// +kubebuilder:validation:Enum=dashed;dotted;solid
+	// +optional
+	LineType *datadogV1.WidgetLineType `json:"lineType,omitempty"`
+	// LineWidth is the width of the lines.
+	// +kubebuilder:validation:Enum=normal;thick;thin
+	// +optional
+	LineWidth *datadogV1.WidgetLineWidth `json:"lineWidth,omitempty"`
+	// OrderBy is how the series are ordered.
+	// +kubebuilder:validation:Enum=tags;values
+	// +optional
+	OrderBy *datadogV1.WidgetStyleOrderBy `json:"orderBy,omitempty"`
+}
+
+// DashboardWidgetAxis is an axis of a widget.
+// +k8s:openapi-gen=true
+type DashboardWidgetAxis struct {
+	// Label is the label of the axis.
+	// +optional
+	Label *string `json:"label,omitempty"`
+	// Min is the minimum value of the axis, or 'auto'.
+	// +optional
+	Min *string `json:"min,omitempty"`
+	// Max is the maximum value of the axis, or 'auto'.
+	// +optional
+	Max *string `json:"max,omitempty"`
+	// Scale is the scale of the axis, such as 'linear' or 'log'.
+	// +optional
+	Scale *string `json:"scale,omitempty"`
+	// IncludeZero always includes zero in the axis.
+	// +optional
+	IncludeZero *bool `json:"includeZero,omitempty"`
+}
+
+// DashboardWidgetMarker is a marker drawn on a widget.
+// +k8s:openapi-gen=true
+type DashboardWidgetMarker struct {
+	// Value is the value of the marker, such as 'y = 15' or '10 < y < 20'.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+	// DisplayType is how the marker is displayed, such as 'error dashed'.
+	// +optional
+	DisplayType *string `json:"displayType,omitempty"`
+	// Label is the label of the marker.
+	// +optional
+	Label *string `json:"label,omitempty"`
+}
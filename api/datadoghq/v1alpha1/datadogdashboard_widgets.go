@@ -0,0 +1,405 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// DatadogWidgets returns the widgets of the dashboard, from either Widgets or WidgetsYAML, as
+// Datadog API widgets. It returns an error if the widgets don't match the models of the Datadog
+// API client.
+func (spec *DatadogDashboardSpec) DatadogWidgets() ([]datadogV1.Widget, error) {
+	if spec.Widgets != "" && len(spec.WidgetsYAML) > 0 {
+		return nil, fmt.Errorf("spec.Widgets and spec.WidgetsYAML are mutually exclusive")
+	}
+
+	widgets := []datadogV1.Widget{}
+	var errs []error
+	if spec.Widgets != "" {
+		if err := json.Unmarshal([]byte(spec.Widgets), &widgets); err != nil {
+			return nil, fmt.Errorf("spec.Widgets is not a valid JSON list of widgets: %w", err)
+		}
+		for i := range widgets {
+			// The widgets written for the Datadog API are sent as they are when the client can't parse them.
+			errs = append(errs, validateWidget(fmt.Sprintf("spec.Widgets[%d]", i), widgets[i], true)...)
+		}
+		return widgets, utilserrors.NewAggregate(errs)
+	}
+
+	for i := range spec.WidgetsYAML {
+		widget, widgetErrs := spec.WidgetsYAML[i].datadogWidget(fmt.Sprintf("spec.WidgetsYAML[%d]", i))
+		errs = append(errs, widgetErrs...)
+		widgets = append(widgets, widget)
+	}
+	return widgets, utilserrors.NewAggregate(errs)
+}
+
+// widgetTypes is the set of the widget types known by the Datadog API client, read from the
+// allowed values of the type of each widget definition.
+var widgetTypes = func() map[string]bool {
+	types := map[string]bool{}
+	definitions := reflect.TypeOf(datadogV1.WidgetDefinition{})
+	for i := 0; i < definitions.NumField(); i++ {
+		definition := definitions.Field(i).Type
+		if definition.Kind() != reflect.Pointer || definition.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		typeField, found := definition.Elem().FieldByName("Type")
+		if !found {
+			continue
+		}
+		allowedValues := reflect.New(typeField.Type).MethodByName("GetAllowedValues")
+		if !allowedValues.IsValid() {
+			continue
+		}
+		values := allowedValues.Call(nil)[0]
+		for j := 0; j < values.Len(); j++ {
+			types[values.Index(j).String()] = true
+		}
+	}
+	return types
+}()
+
+// unparsedWidgetType returns the type of a widget definition that the Datadog API client
+// couldn't parse, because it doesn't know the type or some of the values, such as the values
+// added to an enum by a newer version of the API. It is empty for the parsed definitions.
+func unparsedWidgetType(definition datadogV1.WidgetDefinition) string {
+	unparsed, ok := definition.UnparsedObject.(map[string]interface{})
+	if !ok || definition.GetActualInstance() != nil {
+		return ""
+	}
+	widgetType, _ := unparsed["type"].(string)
+	return widgetType
+}
+
+// validateWidget checks that a widget, and the widgets of a group, match the widget definition
+// of their type in the Datadog API client. The widgets of the types unknown to the client are
+// not checked, nor the widgets of the known types the client couldn't parse when allowUnparsed
+// is set.
+func validateWidget(path string, widget datadogV1.Widget, allowUnparsed bool) []error {
+	if widget.UnparsedObject != nil || widget.Layout != nil && widget.Layout.UnparsedObject != nil {
+		return []error{fmt.Errorf("%s is not a valid widget", path)}
+	}
+	if widget.Definition.GetActualInstance() == nil {
+		if widgetType := unparsedWidgetType(widget.Definition); widgetType != "" && (allowUnparsed || !widgetTypes[widgetType]) {
+			return nil
+		}
+		return []error{fmt.Errorf("%s definition doesn't match any widget type of the Datadog API", path)}
+	}
+	var errs []error
+	if group := widget.Definition.GroupWidgetDefinition; group != nil {
+		for i := range group.Widgets {
+			errs = append(errs, validateWidget(fmt.Sprintf("%s.widgets[%d]", path, i), group.Widgets[i], allowUnparsed)...)
+		}
+	}
+	return errs
+}
+
+// UnparsedWidgetTypes returns the sorted types of the widgets of a dashboard, groups included,
+// that the Datadog API client couldn't parse. These widgets are sent to the Datadog API without
+// being validated.
+func UnparsedWidgetTypes(widgets []datadogV1.Widget) []string {
+	types := map[string]bool{}
+	var walk func(widgets []datadogV1.Widget)
+	walk = func(widgets []datadogV1.Widget) {
+		for i := range widgets {
+			if widgetType := unparsedWidgetType(widgets[i].Definition); widgetType != "" {
+				types[widgetType] = true
+			} else if group := widgets[i].Definition.GroupWidgetDefinition; group != nil {
+				walk(group.Widgets)
+			}
+		}
+	}
+	walk(widgets)
+	return slices.Sorted(maps.Keys(types))
+}
+
+// checkWidget validates a widget built from its typed definition, after a round trip through
+// the JSON representation of the Datadog API, to catch the values the models don't accept.
+func checkWidget(path string, widget datadogV1.Widget) []error {
+	data, err := json.Marshal(widget)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	parsed := datadogV1.Widget{}
+	if err = json.Unmarshal(data, &parsed); err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	return validateWidget(path, parsed, false)
+}
+
+func (w *DashboardWidget) datadogWidget(path string) (datadogV1.Widget, []error) {
+	if w.Group == nil {
+		return w.DashboardGroupedWidget.datadogWidget(path)
+	}
+	if w.DashboardGroupedWidget.definitionCount() > 0 {
+		return datadogV1.Widget{}, []error{fmt.Errorf("%s must have exactly one definition", path)}
+	}
+
+	var errs []error
+	widgets := make([]datadogV1.Widget, 0, len(w.Group.Widgets))
+	for i := range w.Group.Widgets {
+		widget, widgetErrs := w.Group.Widgets[i].datadogWidget(fmt.Sprintf("%s.group.widgets[%d]", path, i))
+		errs = append(errs, widgetErrs...)
+		widgets = append(widgets, widget)
+	}
+	layoutType := w.Group.LayoutType
+	if layoutType == "" {
+		layoutType = datadogV1.WIDGETLAYOUTTYPE_ORDERED
+	}
+	group := datadogV1.NewGroupWidgetDefinition(layoutType, datadogV1.GROUPWIDGETDEFINITIONTYPE_GROUP, widgets)
+	group.Title = w.Group.Title
+	group.TitleAlign = w.Group.TitleAlign
+	group.ShowTitle = w.Group.ShowTitle
+	group.BackgroundColor = w.Group.BackgroundColor
+	group.BannerImg = w.Group.BannerImg
+
+	widget := w.DashboardGroupedWidget.newWidget(datadogV1.GroupWidgetDefinitionAsWidgetDefinition(group))
+	if len(errs) > 0 {
+		return widget, errs
+	}
+	return widget, checkWidget(path, widget)
+}
+
+func (w *DashboardGroupedWidget) definitionCount() int {
+	count := 0
+	for _, set := range []bool{w.Timeseries != nil, w.QueryValue != nil, w.Toplist != nil, w.Note != nil, w.Raw != nil} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+func (w *DashboardGroupedWidget) newWidget(definition datadogV1.WidgetDefinition) datadogV1.Widget {
+	widget := datadogV1.NewWidget(definition)
+	widget.Id = w.ID
+	if w.Layout != nil {
+		widget.Layout = datadogV1.NewWidgetLayout(w.Layout.Height, w.Layout.Width, w.Layout.X, w.Layout.Y)
+		widget.Layout.IsColumnBreak = w.Layout.IsColumnBreak
+	}
+	return *widget
+}
+
+func (w *DashboardGroupedWidget) datadogWidget(path string) (datadogV1.Widget, []error) {
+	if w.definitionCount() != 1 {
+		return datadogV1.Widget{}, []error{fmt.Errorf("%s must have exactly one definition", path)}
+	}
+
+	var definition datadogV1.WidgetDefinition
+	var errs []error
+	switch {
+	case w.Timeseries != nil:
+		definition, errs = w.Timeseries.datadogDefinition(path + ".timeseries")
+	case w.QueryValue != nil:
+		definition, errs = w.QueryValue.datadogDefinition(path + ".queryValue")
+	case w.Toplist != nil:
+		definition, errs = w.Toplist.datadogDefinition(path + ".toplist")
+	case w.Note != nil:
+		definition = datadogV1.NoteWidgetDefinitionAsWidgetDefinition(w.Note.datadogDefinition())
+	case w.Raw != nil:
+		if err := json.Unmarshal(w.Raw.Raw, &definition); err != nil {
+			return datadogV1.Widget{}, []error{fmt.Errorf("%s.raw is not a valid widget definition: %w", path, err)}
+		}
+	}
+	widget := w.newWidget(definition)
+	if len(errs) > 0 {
+		return widget, errs
+	}
+	return widget, checkWidget(path, widget)
+}
+
+func (d *DashboardTimeseriesWidgetDefinition) datadogDefinition(path string) (datadogV1.WidgetDefinition, []error) {
+	var errs []error
+	requests := make([]datadogV1.TimeseriesWidgetRequest, 0, len(d.Requests))
+	for i, request := range d.Requests {
+		requestPath := fmt.Sprintf("%s.requests[%d]", path, i)
+		queries, formulas, requestErrs := request.DashboardWidgetRequest.datadogQueries(requestPath)
+		errs = append(errs, requestErrs...)
+		requests = append(requests, datadogV1.TimeseriesWidgetRequest{
+			Q:              request.Q,
+			Queries:        queries,
+			Formulas:       formulas,
+			ResponseFormat: request.ResponseFormat,
+			DisplayType:    request.DisplayType,
+			Style:          request.Style.datadogStyle(),
+			OnRightYaxis:   request.OnRightYaxis,
+		})
+	}
+
+	definition := datadogV1.NewTimeseriesWidgetDefinition(requests, datadogV1.TIMESERIESWIDGETDEFINITIONTYPE_TIMESERIES)
+	definition.Title = d.Title
+	definition.TitleAlign = d.TitleAlign
+	definition.TitleSize = d.TitleSize
+	definition.ShowLegend = d.ShowLegend
+	definition.LegendLayout = d.LegendLayout
+	definition.LegendColumns = d.LegendColumns
+	definition.Yaxis = d.Yaxis.datadogAxis()
+	definition.RightYaxis = d.RightYaxis.datadogAxis()
+	for _, marker := range d.Markers {
+		definition.Markers = append(definition.Markers, datadogV1.WidgetMarker{
+			Value:       marker.Value,
+			DisplayType: marker.DisplayType,
+			Label:       marker.Label,
+		})
+	}
+	return datadogV1.TimeseriesWidgetDefinitionAsWidgetDefinition(definition), errs
+}
+
+func (d *DashboardQueryValueWidgetDefinition) datadogDefinition(path string) (datadogV1.WidgetDefinition, []error) {
+	var errs []error
+	requests := make([]datadogV1.QueryValueWidgetRequest, 0, len(d.Requests))
+	for i, request := range d.Requests {
+		requestPath := fmt.Sprintf("%s.requests[%d]", path, i)
+		queries, formulas, requestErrs := request.DashboardWidgetRequest.datadogQueries(requestPath)
+		errs = append(errs, requestErrs...)
+		conditionalFormats, formatErrs := datadogConditionalFormats(requestPath, request.ConditionalFormats)
+		errs = append(errs, formatErrs...)
+		requests = append(requests, datadogV1.QueryValueWidgetRequest{
+			Q:                  request.Q,
+			Queries:            queries,
+			Formulas:           formulas,
+			ResponseFormat:     request.ResponseFormat,
+			Aggregator:         request.Aggregator,
+			ConditionalFormats: conditionalFormats,
+		})
+	}
+
+	definition := datadogV1.NewQueryValueWidgetDefinition(requests, datadogV1.QUERYVALUEWIDGETDEFINITIONTYPE_QUERY_VALUE)
+	definition.Title = d.Title
+	definition.TitleAlign = d.TitleAlign
+	definition.TitleSize = d.TitleSize
+	definition.Autoscale = d.Autoscale
+	definition.CustomUnit = d.CustomUnit
+	definition.Precision = d.Precision
+	definition.TextAlign = d.TextAlign
+	return datadogV1.QueryValueWidgetDefinitionAsWidgetDefinition(definition), errs
+}
+
+func (d *DashboardToplistWidgetDefinition) datadogDefinition(path string) (datadogV1.WidgetDefinition, []error) {
+	var errs []error
+	requests := make([]datadogV1.ToplistWidgetRequest, 0, len(d.Requests))
+	for i, request := range d.Requests {
+		requestPath := fmt.Sprintf("%s.requests[%d]", path, i)
+		queries, formulas, requestErrs := request.DashboardWidgetRequest.datadogQueries(requestPath)
+		errs = append(errs, requestErrs...)
+		conditionalFormats, formatErrs := datadogConditionalFormats(requestPath, request.ConditionalFormats)
+		errs = append(errs, formatErrs...)
+		requests = append(requests, datadogV1.ToplistWidgetRequest{
+			Q:                  request.Q,
+			Queries:            queries,
+			Formulas:           formulas,
+			ResponseFormat:     request.ResponseFormat,
+			ConditionalFormats: conditionalFormats,
+		})
+	}
+
+	definition := datadogV1.NewToplistWidgetDefinition(requests, datadogV1.TOPLISTWIDGETDEFINITIONTYPE_TOPLIST)
+	definition.Title = d.Title
+	definition.TitleAlign = d.TitleAlign
+	definition.TitleSize = d.TitleSize
+	return datadogV1.ToplistWidgetDefinitionAsWidgetDefinition(definition), errs
+}
+
+func (d *DashboardNoteWidgetDefinition) datadogDefinition() *datadogV1.NoteWidgetDefinition {
+	definition := datadogV1.NewNoteWidgetDefinition(d.Content, datadogV1.NOTEWIDGETDEFINITIONTYPE_NOTE)
+	definition.BackgroundColor = d.BackgroundColor
+	definition.FontSize = d.FontSize
+	definition.TextAlign = d.TextAlign
+	definition.VerticalAlign = d.VerticalAlign
+	definition.HasPadding = d.HasPadding
+	definition.ShowTick = d.ShowTick
+	definition.TickEdge = d.TickEdge
+	definition.TickPos = d.TickPos
+	return definition
+}
+
+// datadogQueries returns the queries and formulas of a request. The models of the queries and
+// formulas don't flag their invalid values when they are unmarshalled in a request, so they are
+// checked here.
+func (r *DashboardWidgetRequest) datadogQueries(path string) ([]datadogV1.FormulaAndFunctionQueryDefinition, []datadogV1.WidgetFormula, []error) {
+	var errs []error
+	if r.Q == nil && len(r.Queries) == 0 {
+		errs = append(errs, fmt.Errorf("%s must have either q or queries", path))
+	}
+
+	var queries []datadogV1.FormulaAndFunctionQueryDefinition
+	for i, query := range r.Queries {
+		if query.Aggregator != nil && !query.Aggregator.IsValid() {
+			errs = append(errs, fmt.Errorf("%s.queries[%d].aggregator %q is not valid", path, i, *query.Aggregator))
+		}
+		metricQuery := datadogV1.NewFormulaAndFunctionMetricQueryDefinition(datadogV1.FORMULAANDFUNCTIONMETRICDATASOURCE_METRICS, query.Name, query.Query)
+		metricQuery.Aggregator = query.Aggregator
+		queries = append(queries, datadogV1.FormulaAndFunctionMetricQueryDefinitionAsFormulaAndFunctionQueryDefinition(metricQuery))
+	}
+
+	var formulas []datadogV1.WidgetFormula
+	for i, formula := range r.Formulas {
+		widgetFormula := datadogV1.NewWidgetFormula(formula.Formula)
+		widgetFormula.Alias = formula.Alias
+		if formula.Limit != nil {
+			if formula.Limit.Order != nil && !formula.Limit.Order.IsValid() {
+				errs = append(errs, fmt.Errorf("%s.formulas[%d].limit.order %q is not valid", path, i, *formula.Limit.Order))
+			}
+			widgetFormula.Limit = &datadogV1.WidgetFormulaLimit{Count: formula.Limit.Count, Order: formula.Limit.Order}
+		}
+		formulas = append(formulas, *widgetFormula)
+	}
+	return queries, formulas, errs
+}
+
+func datadogConditionalFormats(path string, formats []DashboardWidgetConditionalFormat) ([]datadogV1.WidgetConditionalFormat, []error) {
+	var errs []error
+	var conditionalFormats []datadogV1.WidgetConditionalFormat
+	for i, format := range formats {
+		if !format.Comparator.IsValid() {
+			errs = append(errs, fmt.Errorf("%s.conditionalFormats[%d].comparator %q is not valid", path, i, format.Comparator))
+		}
+		if !format.Palette.IsValid() {
+			errs = append(errs, fmt.Errorf("%s.conditionalFormats[%d].palette %q is not valid", path, i, format.Palette))
+		}
+		value, err := strconv.ParseFloat(format.Value, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.conditionalFormats[%d].value %q is not a number", path, i, format.Value))
+		}
+		conditionalFormats = append(conditionalFormats, *datadogV1.NewWidgetConditionalFormat(format.Comparator, format.Palette, value))
+	}
+	return conditionalFormats, errs
+}
+
+func (s *DashboardWidgetRequestStyle) datadogStyle() *datadogV1.WidgetRequestStyle {
+	if s == nil {
+		return nil
+	}
+	return &datadogV1.WidgetRequestStyle{
+		Palette:   s.Palette,
+		LineType:  s.LineType,
+		LineWidth: s.LineWidth,
+		OrderBy:   s.OrderBy,
+	}
+}
+
+func (a *DashboardWidgetAxis) datadogAxis() *datadogV1.WidgetAxis {
+	if a == nil {
+		return nil
+	}
+	return &datadogV1.WidgetAxis{
+		Label:       a.Label,
+		Min:         a.Min,
+		Max:         a.Max,
+		Scale:       a.Scale,
+		IncludeZero: a.IncludeZero,
+	}
+}
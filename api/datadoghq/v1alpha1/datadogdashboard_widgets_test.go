@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestDatadogWidgets(t *testing.T) {
+	spec := DatadogDashboardSpec{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+widgetsYAML:
+  - id: 1
+    layout: {x: 0, "y": 1, width: 4, height: 2}
+    timeseries:
+      title: CPU
+      showLegend: true
+      legendColumns: [avg, max]
+      requests:
+        - queries:
+            - name: query1
+              query: avg:system.cpu.user{*} by {host}
+          formulas:
+            - formula: query1
+              limit: {count: 10, order: desc}
+          responseFormat: timeseries
+          displayType: line
+          style: {palette: dog_classic, lineWidth: thin}
+      markers:
+        - value: y = 90
+          displayType: error dashed
+  - group:
+      title: Overview
+      widgets:
+        - note:
+            content: Requests per service
+            textAlign: center
+        - queryValue:
+            precision: 2
+            requests:
+              - q: sum:trace.http.request.hits{*}
+                aggregator: sum
+                conditionalFormats:
+                  - comparator: ">"
+                    value: "0.5"
+                    palette: white_on_red
+        - toplist:
+            requests:
+              - q: top(avg:system.load.1{*} by {host}, 10, 'mean', 'desc')
+        - raw:
+            type: free_text
+            text: Overview
+`), &spec))
+
+	widgets, err := spec.DatadogWidgets()
+	require.NoError(t, err)
+	data, err := json.Marshal(widgets)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"id": 1,
+			"layout": {"x": 0, "y": 1, "width": 4, "height": 2},
+			"definition": {
+				"type": "timeseries",
+				"title": "CPU",
+				"show_legend": true,
+				"legend_columns": ["avg", "max"],
+				"requests": [{
+					"queries": [{"data_source": "metrics", "name": "query1", "query": "avg:system.cpu.user{*} by {host}"}],
+					"formulas": [{"formula": "query1", "limit": {"count": 10, "order": "desc"}}],
+					"response_format": "timeseries",
+					"display_type": "line",
+					"style": {"palette": "dog_classic", "line_width": "thin"}
+				}],
+				"markers": [{"value": "y = 90", "display_type": "error dashed"}]
+			}
+		},
+		{
+			"definition": {
+				"type": "group",
+				"layout_type": "ordered",
+				"title": "Overview",
+				"widgets": [
+					{"definition": {"type": "note", "content": "Requests per service", "text_align": "center"}},
+					{"definition": {
+						"type": "query_value",
+						"precision": 2,
+						"requests": [{
+							"q": "sum:trace.http.request.hits{*}",
+							"aggregator": "sum",
+							"conditional_formats": [{"comparator": ">", "value": 0.5, "palette": "white_on_red"}]
+						}]
+					}},
+					{"definition": {
+						"type": "toplist",
+						"requests": [{"q": "top(avg:system.load.1{*} by {host}, 10, 'mean', 'desc')"}]
+					}},
+					{"definition": {"type": "free_text", "text": "Overview"}}
+				]
+			}
+		}
+	]`, string(data))
+}
+
+func TestDatadogWidgetsUnknownTypes(t *testing.T) {
+	spec := DatadogDashboardSpec{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+widgetsYAML:
+  - raw:
+      type: future_widget
+      title: Future
+  - group:
+      widgets:
+        - raw: {type: other_future_widget}
+        - note: {content: test}
+`), &spec))
+
+	widgets, err := spec.DatadogWidgets()
+	require.NoError(t, err, "the widgets of unknown types are not validated")
+	assert.Equal(t, []string{"future_widget", "other_future_widget"}, UnparsedWidgetTypes(widgets))
+	data, err := json.Marshal(widgets[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"definition": {"type": "future_widget", "title": "Future"}}`, string(data), "the widgets of unknown types are sent as they are")
+
+	spec = DatadogDashboardSpec{Widgets: `[{"definition": {"type": "note", "content": "test"}}, {"definition": {"type": "future_widget"}}]`}
+	widgets, err = spec.DatadogWidgets()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"future_widget"}, UnparsedWidgetTypes(widgets))
+
+	// The known widget types with values unknown to the client are sent as they are from spec.Widgets.
+	spec = DatadogDashboardSpec{Widgets: `[{"definition": {"type": "note", "content": "test", "text_align": "justify"}}]`}
+	widgets, err = spec.DatadogWidgets()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"note"}, UnparsedWidgetTypes(widgets))
+	data, err = json.Marshal(widgets[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"definition": {"type": "note", "content": "test", "text_align": "justify"}}`, string(data))
+
+	spec = DatadogDashboardSpec{Widgets: `[{"definition": {"type": "note", "content": "test"}}]`}
+	widgets, err = spec.DatadogWidgets()
+	require.NoError(t, err)
+	assert.Empty(t, UnparsedWidgetTypes(widgets))
+}
+
+func TestDatadogWidgetsErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		widgets string
+		wantErr string
+	}{
+		{
+			name: "several definitions",
+			widgets: `
+- note: {content: test}
+  raw: {type: free_text, text: test}`,
+			wantErr: "spec.WidgetsYAML[0] must have exactly one definition",
+		},
+		{
+			name: "group with another definition",
+			widgets: `
+- note: {content: test}
+  group: {widgets: []}`,
+			wantErr: "spec.WidgetsYAML[0] must have exactly one definition",
+		},
+		{
+			name: "request without query",
+			widgets: `
+- timeseries:
+    requests:
+      - displayType: line`,
+			wantErr: "spec.WidgetsYAML[0].timeseries.requests[0] must have either q or queries",
+		},
+		{
+			name: "invalid palette",
+			widgets: `
+- group:
+    widgets:
+      - toplist:
+          requests:
+            - q: avg:system.load.1{*} by {host}
+              conditionalFormats:
+                - {comparator: ">", value: "1", palette: crimson}`,
+			wantErr: `spec.WidgetsYAML[0].group.widgets[0].toplist.requests[0].conditionalFormats[0].palette "crimson" is not valid`,
+		},
+		{
+			name: "invalid conditional format value",
+			widgets: `
+- queryValue:
+    requests:
+      - q: sum:trace.http.request.hits{*}
+        conditionalFormats:
+          - {comparator: ">", value: 500m, palette: white_on_red}`,
+			wantErr: `spec.WidgetsYAML[0].queryValue.requests[0].conditionalFormats[0].value "500m" is not a number`,
+		},
+		{
+			name: "invalid raw definition",
+			widgets: `
+- raw: {type: free_text}`,
+			wantErr: "spec.WidgetsYAML[0] definition doesn't match any widget type of the Datadog API",
+		},
+		{
+			name: "invalid enum value",
+			widgets: `
+- note: {content: test, tickEdge: middle}`,
+			wantErr: "spec.WidgetsYAML[0] definition doesn't match any widget type of the Datadog API",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			spec := DatadogDashboardSpec{}
+			require.NoError(t, yaml.Unmarshal([]byte(test.widgets), &spec.WidgetsYAML))
+			_, err := spec.DatadogWidgets()
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogMonitorTemplateSpec defines the desired state of DatadogMonitorTemplate
+// +k8s:openapi-gen=true
+type DatadogMonitorTemplateSpec struct {
+	// Target selects the namespaces or the workloads a DatadogMonitor is generated for.
+	Target DatadogTemplateTarget `json:"target"`
+
+	// Template is the spec of the generated DatadogMonitors. Its string fields are Go templates using
+	// the `[[` and `]]` delimiters, rendered with the `.Namespace` and `.Workload` of each target.
+	Template DatadogMonitorSpec `json:"template"`
+}
+
+// DatadogMonitorTemplate generates a DatadogMonitor for each namespace or workload it selects.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogmonitortemplates,scope=Cluster,shortName=ddmt
+// +kubebuilder:printcolumn:name="targets",type="integer",JSONPath=".status.targets"
+// +kubebuilder:printcolumn:name="instances",type="integer",JSONPath=".status.instances"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+type DatadogMonitorTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogMonitorTemplateSpec `json:"spec,omitempty"`
+	Status DatadogTemplateStatus      `json:"status,omitempty"`
+}
+
+// DatadogMonitorTemplateList contains a list of DatadogMonitorTemplate
+// +kubebuilder:object:root=true
+type DatadogMonitorTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogMonitorTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogMonitorTemplate{}, &DatadogMonitorTemplateList{})
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogSLOTemplateSpec defines the desired state of DatadogSLOTemplate
+// +k8s:openapi-gen=true
+type DatadogSLOTemplateSpec struct {
+	// Target selects the namespaces or the workloads a DatadogSLO is generated for.
+	Target DatadogTemplateTarget `json:"target"`
+
+	// Template is the spec of the generated DatadogSLOs. Its string fields are Go templates using
+	// the `[[` and `]]` delimiters, rendered with the `.Namespace` and `.Workload` of each target.
+	Template DatadogSLOSpec `json:"template"`
+}
+
+// DatadogSLOTemplate generates a DatadogSLO for each namespace or workload it selects.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogslotemplates,scope=Cluster,shortName=ddslot
+// +kubebuilder:printcolumn:name="targets",type="integer",JSONPath=".status.targets"
+// +kubebuilder:printcolumn:name="instances",type="integer",JSONPath=".status.instances"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+type DatadogSLOTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogSLOTemplateSpec `json:"spec,omitempty"`
+	Status DatadogTemplateStatus  `json:"status,omitempty"`
+}
+
+// DatadogSLOTemplateList contains a list of DatadogSLOTemplate
+// +kubebuilder:object:root=true
+type DatadogSLOTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogSLOTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogSLOTemplate{}, &DatadogSLOTemplateList{})
+}
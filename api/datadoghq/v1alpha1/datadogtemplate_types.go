@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogTemplateTarget selects the namespaces or the workloads a template is instantiated for.
+// +k8s:openapi-gen=true
+type DatadogTemplateTarget struct {
+	// NamespaceSelector selects the namespaces by label. An empty selector selects all the namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Workloads instantiates the template once per matching workload of the selected namespaces,
+	// instead of once per namespace.
+	// +optional
+	Workloads *DatadogTemplateWorkloadSelector `json:"workloads,omitempty"`
+}
+
+// DatadogTemplateWorkloadSelector selects workloads of a given kind.
+// +k8s:openapi-gen=true
+type DatadogTemplateWorkloadSelector struct {
+	// Kind is the kind of the selected workloads.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind DatadogTemplateWorkloadKind `json:"kind"`
+
+	// Selector selects the workloads by label. An empty selector selects all the workloads of this kind.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// DatadogTemplateWorkloadKind is a kind of workload a template can be instantiated for.
+type DatadogTemplateWorkloadKind string
+
+const (
+	// DatadogTemplateWorkloadKindDeployment selects Deployments.
+	DatadogTemplateWorkloadKindDeployment DatadogTemplateWorkloadKind = "Deployment"
+	// DatadogTemplateWorkloadKindStatefulSet selects StatefulSets.
+	DatadogTemplateWorkloadKindStatefulSet DatadogTemplateWorkloadKind = "StatefulSet"
+	// DatadogTemplateWorkloadKindDaemonSet selects DaemonSets.
+	DatadogTemplateWorkloadKindDaemonSet DatadogTemplateWorkloadKind = "DaemonSet"
+)
+
+// DatadogTemplateStatus defines the observed state of a DatadogMonitorTemplate or a DatadogSLOTemplate.
+// +k8s:openapi-gen=true
+type DatadogTemplateStatus struct {
+	// ObservedGeneration is the most recent generation observed for this resource.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represents the latest available observations of the state of the template.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Targets is the number of namespaces or workloads selected by the template.
+	// +optional
+	Targets int32 `json:"targets,omitempty"`
+
+	// Instances is the number of resources generated from the template.
+	// +optional
+	Instances int32 `json:"instances,omitempty"`
+}
+
+const (
+	// DatadogTemplateConditionTypeReady reports whether the template is instantiated for all its targets.
+	DatadogTemplateConditionTypeReady = "Ready"
+)
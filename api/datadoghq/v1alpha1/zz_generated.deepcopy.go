@@ -37,88 +37,746 @@ func (in *CreateStrategy) DeepCopy() *CreateStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardGroupWidgetDefinition) DeepCopyInto(out *DashboardGroupWidgetDefinition) {
+	*out = *in
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.TitleAlign != nil {
+		in, out := &in.TitleAlign, &out.TitleAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.ShowTitle != nil {
+		in, out := &in.ShowTitle, &out.ShowTitle
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BackgroundColor != nil {
+		in, out := &in.BackgroundColor, &out.BackgroundColor
+		*out = new(string)
+		**out = **in
+	}
+	if in.BannerImg != nil {
+		in, out := &in.BannerImg, &out.BannerImg
+		*out = new(string)
+		**out = **in
+	}
+	if in.Widgets != nil {
+		in, out := &in.Widgets, &out.Widgets
+		*out = make([]DashboardGroupedWidget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardGroupWidgetDefinition.
+func (in *DashboardGroupWidgetDefinition) DeepCopy() *DashboardGroupWidgetDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardGroupWidgetDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardGroupedWidget) DeepCopyInto(out *DashboardGroupedWidget) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Layout != nil {
+		in, out := &in.Layout, &out.Layout
+		*out = new(DashboardWidgetLayout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeseries != nil {
+		in, out := &in.Timeseries, &out.Timeseries
+		*out = new(DashboardTimeseriesWidgetDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryValue != nil {
+		in, out := &in.QueryValue, &out.QueryValue
+		*out = new(DashboardQueryValueWidgetDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Toplist != nil {
+		in, out := &in.Toplist, &out.Toplist
+		*out = new(DashboardToplistWidgetDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Note != nil {
+		in, out := &in.Note, &out.Note
+		*out = new(DashboardNoteWidgetDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Raw != nil {
+		in, out := &in.Raw, &out.Raw
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardGroupedWidget.
+func (in *DashboardGroupedWidget) DeepCopy() *DashboardGroupedWidget {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardGroupedWidget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardNoteWidgetDefinition) DeepCopyInto(out *DashboardNoteWidgetDefinition) {
+	*out = *in
+	if in.BackgroundColor != nil {
+		in, out := &in.BackgroundColor, &out.BackgroundColor
+		*out = new(string)
+		**out = **in
+	}
+	if in.FontSize != nil {
+		in, out := &in.FontSize, &out.FontSize
+		*out = new(string)
+		**out = **in
+	}
+	if in.TextAlign != nil {
+		in, out := &in.TextAlign, &out.TextAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.VerticalAlign != nil {
+		in, out := &in.VerticalAlign, &out.VerticalAlign
+		*out = new(datadogV1.WidgetVerticalAlign)
+		**out = **in
+	}
+	if in.HasPadding != nil {
+		in, out := &in.HasPadding, &out.HasPadding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ShowTick != nil {
+		in, out := &in.ShowTick, &out.ShowTick
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TickEdge != nil {
+		in, out := &in.TickEdge, &out.TickEdge
+		*out = new(datadogV1.WidgetTickEdge)
+		**out = **in
+	}
+	if in.TickPos != nil {
+		in, out := &in.TickPos, &out.TickPos
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardNoteWidgetDefinition.
+func (in *DashboardNoteWidgetDefinition) DeepCopy() *DashboardNoteWidgetDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardNoteWidgetDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardQueryValueWidgetDefinition) DeepCopyInto(out *DashboardQueryValueWidgetDefinition) {
+	*out = *in
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.TitleAlign != nil {
+		in, out := &in.TitleAlign, &out.TitleAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.TitleSize != nil {
+		in, out := &in.TitleSize, &out.TitleSize
+		*out = new(string)
+		**out = **in
+	}
+	if in.Autoscale != nil {
+		in, out := &in.Autoscale, &out.Autoscale
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomUnit != nil {
+		in, out := &in.CustomUnit, &out.CustomUnit
+		*out = new(string)
+		**out = **in
+	}
+	if in.Precision != nil {
+		in, out := &in.Precision, &out.Precision
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TextAlign != nil {
+		in, out := &in.TextAlign, &out.TextAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]DashboardQueryValueWidgetRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardQueryValueWidgetDefinition.
+func (in *DashboardQueryValueWidgetDefinition) DeepCopy() *DashboardQueryValueWidgetDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardQueryValueWidgetDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardQueryValueWidgetRequest) DeepCopyInto(out *DashboardQueryValueWidgetRequest) {
+	*out = *in
+	in.DashboardWidgetRequest.DeepCopyInto(&out.DashboardWidgetRequest)
+	if in.Aggregator != nil {
+		in, out := &in.Aggregator, &out.Aggregator
+		*out = new(datadogV1.WidgetAggregator)
+		**out = **in
+	}
+	if in.ConditionalFormats != nil {
+		in, out := &in.ConditionalFormats, &out.ConditionalFormats
+		*out = make([]DashboardWidgetConditionalFormat, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardQueryValueWidgetRequest.
+func (in *DashboardQueryValueWidgetRequest) DeepCopy() *DashboardQueryValueWidgetRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardQueryValueWidgetRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashboardTemplateVariable) DeepCopyInto(out *DashboardTemplateVariable) {
 	*out = *in
-	if in.AvailableValues != nil {
-		in, out := &in.AvailableValues, &out.AvailableValues
-		*out = new([]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]string, len(*in))
-			copy(*out, *in)
-		}
+	if in.AvailableValues != nil {
+		in, out := &in.AvailableValues, &out.AvailableValues
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariable.
+func (in *DashboardTemplateVariable) DeepCopy() *DashboardTemplateVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTemplateVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardTemplateVariablePreset) DeepCopyInto(out *DashboardTemplateVariablePreset) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateVariables != nil {
+		in, out := &in.TemplateVariables, &out.TemplateVariables
+		*out = make([]DashboardTemplateVariablePresetValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariablePreset.
+func (in *DashboardTemplateVariablePreset) DeepCopy() *DashboardTemplateVariablePreset {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTemplateVariablePreset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardTemplateVariablePresetValue) DeepCopyInto(out *DashboardTemplateVariablePresetValue) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariablePresetValue.
+func (in *DashboardTemplateVariablePresetValue) DeepCopy() *DashboardTemplateVariablePresetValue {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTemplateVariablePresetValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardTimeseriesWidgetDefinition) DeepCopyInto(out *DashboardTimeseriesWidgetDefinition) {
+	*out = *in
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.TitleAlign != nil {
+		in, out := &in.TitleAlign, &out.TitleAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.TitleSize != nil {
+		in, out := &in.TitleSize, &out.TitleSize
+		*out = new(string)
+		**out = **in
+	}
+	if in.ShowLegend != nil {
+		in, out := &in.ShowLegend, &out.ShowLegend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LegendLayout != nil {
+		in, out := &in.LegendLayout, &out.LegendLayout
+		*out = new(datadogV1.TimeseriesWidgetLegendLayout)
+		**out = **in
+	}
+	if in.LegendColumns != nil {
+		in, out := &in.LegendColumns, &out.LegendColumns
+		*out = make([]datadogV1.TimeseriesWidgetLegendColumn, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]DashboardTimeseriesWidgetRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Yaxis != nil {
+		in, out := &in.Yaxis, &out.Yaxis
+		*out = new(DashboardWidgetAxis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RightYaxis != nil {
+		in, out := &in.RightYaxis, &out.RightYaxis
+		*out = new(DashboardWidgetAxis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Markers != nil {
+		in, out := &in.Markers, &out.Markers
+		*out = make([]DashboardWidgetMarker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTimeseriesWidgetDefinition.
+func (in *DashboardTimeseriesWidgetDefinition) DeepCopy() *DashboardTimeseriesWidgetDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTimeseriesWidgetDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardTimeseriesWidgetRequest) DeepCopyInto(out *DashboardTimeseriesWidgetRequest) {
+	*out = *in
+	in.DashboardWidgetRequest.DeepCopyInto(&out.DashboardWidgetRequest)
+	if in.DisplayType != nil {
+		in, out := &in.DisplayType, &out.DisplayType
+		*out = new(datadogV1.WidgetDisplayType)
+		**out = **in
+	}
+	if in.Style != nil {
+		in, out := &in.Style, &out.Style
+		*out = new(DashboardWidgetRequestStyle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnRightYaxis != nil {
+		in, out := &in.OnRightYaxis, &out.OnRightYaxis
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTimeseriesWidgetRequest.
+func (in *DashboardTimeseriesWidgetRequest) DeepCopy() *DashboardTimeseriesWidgetRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTimeseriesWidgetRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardToplistWidgetDefinition) DeepCopyInto(out *DashboardToplistWidgetDefinition) {
+	*out = *in
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.TitleAlign != nil {
+		in, out := &in.TitleAlign, &out.TitleAlign
+		*out = new(datadogV1.WidgetTextAlign)
+		**out = **in
+	}
+	if in.TitleSize != nil {
+		in, out := &in.TitleSize, &out.TitleSize
+		*out = new(string)
+		**out = **in
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]DashboardToplistWidgetRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardToplistWidgetDefinition.
+func (in *DashboardToplistWidgetDefinition) DeepCopy() *DashboardToplistWidgetDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardToplistWidgetDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardToplistWidgetRequest) DeepCopyInto(out *DashboardToplistWidgetRequest) {
+	*out = *in
+	in.DashboardWidgetRequest.DeepCopyInto(&out.DashboardWidgetRequest)
+	if in.ConditionalFormats != nil {
+		in, out := &in.ConditionalFormats, &out.ConditionalFormats
+		*out = make([]DashboardWidgetConditionalFormat, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardToplistWidgetRequest.
+func (in *DashboardToplistWidgetRequest) DeepCopy() *DashboardToplistWidgetRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardToplistWidgetRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidget) DeepCopyInto(out *DashboardWidget) {
+	*out = *in
+	in.DashboardGroupedWidget.DeepCopyInto(&out.DashboardGroupedWidget)
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(DashboardGroupWidgetDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidget.
+func (in *DashboardWidget) DeepCopy() *DashboardWidget {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetAxis) DeepCopyInto(out *DashboardWidgetAxis) {
+	*out = *in
+	if in.Label != nil {
+		in, out := &in.Label, &out.Label
+		*out = new(string)
+		**out = **in
+	}
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(string)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scale != nil {
+		in, out := &in.Scale, &out.Scale
+		*out = new(string)
+		**out = **in
+	}
+	if in.IncludeZero != nil {
+		in, out := &in.IncludeZero, &out.IncludeZero
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetAxis.
+func (in *DashboardWidgetAxis) DeepCopy() *DashboardWidgetAxis {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetAxis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetConditionalFormat) DeepCopyInto(out *DashboardWidgetConditionalFormat) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetConditionalFormat.
+func (in *DashboardWidgetConditionalFormat) DeepCopy() *DashboardWidgetConditionalFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetConditionalFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetFormula) DeepCopyInto(out *DashboardWidgetFormula) {
+	*out = *in
+	if in.Alias != nil {
+		in, out := &in.Alias, &out.Alias
+		*out = new(string)
+		**out = **in
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(DashboardWidgetFormulaLimit)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetFormula.
+func (in *DashboardWidgetFormula) DeepCopy() *DashboardWidgetFormula {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetFormula)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetFormulaLimit) DeepCopyInto(out *DashboardWidgetFormulaLimit) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(datadogV1.QuerySortOrder)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetFormulaLimit.
+func (in *DashboardWidgetFormulaLimit) DeepCopy() *DashboardWidgetFormulaLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetFormulaLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetLayout) DeepCopyInto(out *DashboardWidgetLayout) {
+	*out = *in
+	if in.IsColumnBreak != nil {
+		in, out := &in.IsColumnBreak, &out.IsColumnBreak
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Defaults != nil {
-		in, out := &in.Defaults, &out.Defaults
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetLayout.
+func (in *DashboardWidgetLayout) DeepCopy() *DashboardWidgetLayout {
+	if in == nil {
+		return nil
 	}
-	if in.Prefix != nil {
-		in, out := &in.Prefix, &out.Prefix
+	out := new(DashboardWidgetLayout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetMarker) DeepCopyInto(out *DashboardWidgetMarker) {
+	*out = *in
+	if in.DisplayType != nil {
+		in, out := &in.DisplayType, &out.DisplayType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Label != nil {
+		in, out := &in.Label, &out.Label
 		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariable.
-func (in *DashboardTemplateVariable) DeepCopy() *DashboardTemplateVariable {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetMarker.
+func (in *DashboardWidgetMarker) DeepCopy() *DashboardWidgetMarker {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardTemplateVariable)
+	out := new(DashboardWidgetMarker)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardTemplateVariablePreset) DeepCopyInto(out *DashboardTemplateVariablePreset) {
+func (in *DashboardWidgetMetricQuery) DeepCopyInto(out *DashboardWidgetMetricQuery) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
+	if in.Aggregator != nil {
+		in, out := &in.Aggregator, &out.Aggregator
+		*out = new(datadogV1.FormulaAndFunctionMetricAggregation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetMetricQuery.
+func (in *DashboardWidgetMetricQuery) DeepCopy() *DashboardWidgetMetricQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetMetricQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetRequest) DeepCopyInto(out *DashboardWidgetRequest) {
+	*out = *in
+	if in.Q != nil {
+		in, out := &in.Q, &out.Q
 		*out = new(string)
 		**out = **in
 	}
-	if in.TemplateVariables != nil {
-		in, out := &in.TemplateVariables, &out.TemplateVariables
-		*out = make([]DashboardTemplateVariablePresetValue, len(*in))
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]DashboardWidgetMetricQuery, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Formulas != nil {
+		in, out := &in.Formulas, &out.Formulas
+		*out = make([]DashboardWidgetFormula, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResponseFormat != nil {
+		in, out := &in.ResponseFormat, &out.ResponseFormat
+		*out = new(datadogV1.FormulaAndFunctionResponseFormat)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariablePreset.
-func (in *DashboardTemplateVariablePreset) DeepCopy() *DashboardTemplateVariablePreset {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetRequest.
+func (in *DashboardWidgetRequest) DeepCopy() *DashboardWidgetRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardTemplateVariablePreset)
+	out := new(DashboardWidgetRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardTemplateVariablePresetValue) DeepCopyInto(out *DashboardTemplateVariablePresetValue) {
+func (in *DashboardWidgetRequestStyle) DeepCopyInto(out *DashboardWidgetRequestStyle) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
+	if in.Palette != nil {
+		in, out := &in.Palette, &out.Palette
 		*out = new(string)
 		**out = **in
 	}
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.LineType != nil {
+		in, out := &in.LineType, &out.LineType
+		*out = new(datadogV1.WidgetLineType)
+		**out = **in
+	}
+	if in.LineWidth != nil {
+		in, out := &in.LineWidth, &out.LineWidth
+		*out = new(datadogV1.WidgetLineWidth)
+		**out = **in
+	}
+	if in.OrderBy != nil {
+		in, out := &in.OrderBy, &out.OrderBy
+		*out = new(datadogV1.WidgetStyleOrderBy)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTemplateVariablePresetValue.
-func (in *DashboardTemplateVariablePresetValue) DeepCopy() *DashboardTemplateVariablePresetValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetRequestStyle.
+func (in *DashboardWidgetRequestStyle) DeepCopy() *DashboardWidgetRequestStyle {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardTemplateVariablePresetValue)
+	out := new(DashboardWidgetRequestStyle)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -217,6 +875,11 @@ func (in *DatadogAgentInternalStatus) DeepCopyInto(out *DatadogAgentInternalStat
 		*out = new(v2alpha1.RemoteConfigConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]v2alpha1.ObjectPatchStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentInternalStatus.
@@ -696,6 +1359,13 @@ func (in *DatadogDashboardSpec) DeepCopyInto(out *DatadogDashboardSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WidgetsYAML != nil {
+		in, out := &in.WidgetsYAML, &out.WidgetsYAML
+		*out = make([]DashboardWidget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDashboardSpec.
@@ -1803,6 +2473,82 @@ func (in *DatadogMonitorStatus) DeepCopy() *DatadogMonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorTemplate) DeepCopyInto(out *DatadogMonitorTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorTemplate.
+func (in *DatadogMonitorTemplate) DeepCopy() *DatadogMonitorTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogMonitorTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorTemplateList) DeepCopyInto(out *DatadogMonitorTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogMonitorTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorTemplateList.
+func (in *DatadogMonitorTemplateList) DeepCopy() *DatadogMonitorTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogMonitorTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorTemplateSpec) DeepCopyInto(out *DatadogMonitorTemplateSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorTemplateSpec.
+func (in *DatadogMonitorTemplateSpec) DeepCopy() *DatadogMonitorTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogMonitorTriggeredState) DeepCopyInto(out *DatadogMonitorTriggeredState) {
 	*out = *in
@@ -2126,6 +2872,82 @@ func (in *DatadogSLOStatus) DeepCopy() *DatadogSLOStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOTemplate) DeepCopyInto(out *DatadogSLOTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOTemplate.
+func (in *DatadogSLOTemplate) DeepCopy() *DatadogSLOTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLOTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOTemplateList) DeepCopyInto(out *DatadogSLOTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogSLOTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOTemplateList.
+func (in *DatadogSLOTemplateList) DeepCopy() *DatadogSLOTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLOTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOTemplateSpec) DeepCopyInto(out *DatadogSLOTemplateSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOTemplateSpec.
+func (in *DatadogSLOTemplateSpec) DeepCopy() *DatadogSLOTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogSLOTimeSlice) DeepCopyInto(out *DatadogSLOTimeSlice) {
 	*out = *in
@@ -2142,6 +2964,69 @@ func (in *DatadogSLOTimeSlice) DeepCopy() *DatadogSLOTimeSlice {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogTemplateStatus) DeepCopyInto(out *DatadogTemplateStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogTemplateStatus.
+func (in *DatadogTemplateStatus) DeepCopy() *DatadogTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogTemplateTarget) DeepCopyInto(out *DatadogTemplateTarget) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(DatadogTemplateWorkloadSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogTemplateTarget.
+func (in *DatadogTemplateTarget) DeepCopy() *DatadogTemplateTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogTemplateTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogTemplateWorkloadSelector) DeepCopyInto(out *DatadogTemplateWorkloadSelector) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogTemplateWorkloadSelector.
+func (in *DatadogTemplateWorkloadSelector) DeepCopy() *DatadogTemplateWorkloadSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogTemplateWorkloadSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileAffinity) DeepCopyInto(out *ProfileAffinity) {
 	*out = *in
@@ -18,9 +18,28 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.CreateStrategy":                                                 schema_datadog_operator_api_datadoghq_v1alpha1_CreateStrategy(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupWidgetDefinition":                                 schema_datadog_operator_api_datadoghq_v1alpha1_DashboardGroupWidgetDefinition(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupedWidget":                                         schema_datadog_operator_api_datadoghq_v1alpha1_DashboardGroupedWidget(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardNoteWidgetDefinition":                                  schema_datadog_operator_api_datadoghq_v1alpha1_DashboardNoteWidgetDefinition(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetDefinition":                            schema_datadog_operator_api_datadoghq_v1alpha1_DashboardQueryValueWidgetDefinition(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetRequest":                               schema_datadog_operator_api_datadoghq_v1alpha1_DashboardQueryValueWidgetRequest(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariable":                                      schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariable(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePreset":                                schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePreset(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePresetValue":                           schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePresetValue(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetDefinition":                            schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTimeseriesWidgetDefinition(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetRequest":                               schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTimeseriesWidgetRequest(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetDefinition":                               schema_datadog_operator_api_datadoghq_v1alpha1_DashboardToplistWidgetDefinition(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetRequest":                                  schema_datadog_operator_api_datadoghq_v1alpha1_DashboardToplistWidgetRequest(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidget":                                                schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidget(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetAxis":                                            schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetAxis(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetConditionalFormat":                               schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetConditionalFormat(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula":                                         schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetFormula(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormulaLimit":                                    schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetFormulaLimit(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetLayout":                                          schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetLayout(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMarker":                                          schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetMarker(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery":                                     schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetMetricQuery(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetRequest":                                         schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetRequest(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetRequestStyle":                                    schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetRequestStyle(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogAgentInternal":                                           schema_datadog_operator_api_datadoghq_v1alpha1_DatadogAgentInternal(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogAgentInternalStatus":                                     schema_datadog_operator_api_datadoghq_v1alpha1_DatadogAgentInternalStatus(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogAgentProfile":                                            schema_datadog_operator_api_datadoghq_v1alpha1_DatadogAgentProfile(ref),
@@ -52,13 +71,20 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorOptionsThresholds":                                schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorOptionsThresholds(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorSpec":                                             schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorSpec(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorStatus":                                           schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorStatus(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorTemplate":                                         schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTemplate(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorTemplateSpec":                                     schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTemplateSpec(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorTriggeredState":                                   schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTriggeredState(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLO":                                                     schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLO(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOControllerOptions":                                    schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOControllerOptions(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOQuery":                                                schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOQuery(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOSpec":                                                 schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOSpec(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOStatus":                                               schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOStatus(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOTemplate":                                             schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTemplate(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOTemplateSpec":                                         schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTemplateSpec(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOTimeSlice":                                            schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTimeSlice(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateStatus":                                          schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateStatus(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateTarget":                                          schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateTarget(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateWorkloadSelector":                                schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateWorkloadSelector(ref),
 	}
 }
 
@@ -114,151 +140,1339 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_CreateStrategy(ref common.Re
 	}
 }
 
-func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariable(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardGroupWidgetDefinition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DashboardTemplateVariable Template variable.",
+				Description: "DashboardGroupWidgetDefinition is the definition of a group widget.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"availableValues": {
+					"title": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The list of values that the template variable drop-down is limited to.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Title is the title of the group.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"defaults": {
+					"titleAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleAlign is the alignment of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"showTitle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ShowTitle shows the title of the group.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"backgroundColor": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackgroundColor is the background color of the group title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bannerImg": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BannerImg is the URL of the image displayed as a banner of the group.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"layoutType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LayoutType is the layout type of the group.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"widgets": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-type": "set",
+								"x-kubernetes-list-type": "atomic",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "One or many default values for template variables on load. If more than one default is specified, they will be unioned together with `OR`. Cannot be used in conjunction with `default`.",
+							Description: "Widgets are the widgets of the group.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupedWidget"),
 									},
 								},
 							},
 						},
 					},
-					"name": {
+				},
+				Required: []string{"widgets"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupedWidget"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardGroupedWidget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardGroupedWidget is a widget that can be part of a group widget. Exactly one of its definitions must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the variable.",
+							Description: "ID is the ID of the widget.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"layout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Layout is the position and size of the widget. It's required for the widgets of a dashboard with the 'free' layout type or the 'fixed' reflow type.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetLayout"),
+						},
+					},
+					"timeseries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeseries is the definition of a timeseries widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetDefinition"),
+						},
+					},
+					"queryValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "QueryValue is the definition of a query value widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetDefinition"),
+						},
+					},
+					"toplist": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Toplist is the definition of a top list widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetDefinition"),
+						},
+					},
+					"note": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Note is the definition of a note widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardNoteWidgetDefinition"),
+						},
+					},
+					"raw": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Raw is the definition of a widget of any other type, as the `definition` of a widget in the Datadog API, such as `{\"type\": \"free_text\", \"text\": \"Overview\"}`.",
+							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardNoteWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetLayout", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardNoteWidgetDefinition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardNoteWidgetDefinition is the definition of a note widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"content": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Content is the content of the note, in Markdown.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"prefix": {
+					"backgroundColor": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The tag prefix associated with the variable. Only tags with this prefix appear in the variable drop-down.",
+							Description: "BackgroundColor is the background color of the note.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fontSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FontSize is the size of the text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"textAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TextAlign is the horizontal alignment of the text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"verticalAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VerticalAlign is the vertical alignment of the text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hasPadding": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HasPadding adds a padding around the text.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"showTick": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ShowTick shows a tick on the note.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"tickEdge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TickEdge is the edge of the note with the tick.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tickPos": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TickPos is the position of the tick on its edge, such as '50%'.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"content"},
 			},
 		},
 	}
 }
 
-func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePreset(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardQueryValueWidgetDefinition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DashboardTemplateVariablePreset Template variables saved views.",
+				Description: "DashboardQueryValueWidgetDefinition is the definition of a query value widget.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"title": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the variable.",
+							Description: "Title is the title of the widget.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"templateVariables": {
+					"titleAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleAlign is the alignment of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"titleSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleSize is the size of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"autoscale": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Autoscale automatically scales the value.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"customUnit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CustomUnit is the unit displayed with the value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"precision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Precision is the number of decimals of the value.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"textAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TextAlign is the alignment of the value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requests": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-map-keys": []interface{}{
-									"name",
-								},
-								"x-kubernetes-list-type": "map",
+								"x-kubernetes-list-type": "atomic",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "List of variables.",
+							Description: "Requests are the requests of the widget.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePresetValue"),
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetRequest"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"requests"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePresetValue"},
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetRequest"},
 	}
 }
 
-func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePresetValue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardQueryValueWidgetRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DashboardTemplateVariablePresetValue Template variables saved views.",
+				Description: "DashboardQueryValueWidgetRequest is a request of a query value widget.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"q": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the variable.",
+							Description: "Q is a metric query, such as 'avg:system.cpu.user{*} by {host}'.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"values": {
+					"queries": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-type": "set",
+								"x-kubernetes-list-type": "atomic",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "One or many template variable values within the saved view, which will be unioned together using `OR` if more than one is specified. Cannot be used in conjunction with `value`.",
+							Description: "Queries are the metric queries combined by the Formulas. Use a raw widget for queries of other data sources.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"),
+									},
+								},
+							},
+						},
+					},
+					"formulas": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Formulas are the formulas computed from the Queries.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula"),
+									},
+								},
+							},
+						},
+					},
+					"responseFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResponseFormat is the format of the response of the Queries.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"aggregator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Aggregator is the aggregation of the query Q.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditionalFormats": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ConditionalFormats are the formats applied to the value depending on its value.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetConditionalFormat"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetConditionalFormat", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariable(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardTemplateVariable Template variable.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"availableValues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The list of values that the template variable drop-down is limited to.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"defaults": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "One or many default values for template variables on load. If more than one default is specified, they will be unioned together with `OR`. Cannot be used in conjunction with `default`.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the variable.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"prefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The tag prefix associated with the variable. Only tags with this prefix appear in the variable drop-down.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePreset(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardTemplateVariablePreset Template variables saved views.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the variable.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"templateVariables": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "List of variables.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePresetValue"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePresetValue"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTemplateVariablePresetValue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardTemplateVariablePresetValue Template variables saved views.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the variable.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"values": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "One or many template variable values within the saved view, which will be unioned together using `OR` if more than one is specified. Cannot be used in conjunction with `value`.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTimeseriesWidgetDefinition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardTimeseriesWidgetDefinition is the definition of a timeseries widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Title is the title of the widget.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"titleAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleAlign is the alignment of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"titleSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleSize is the size of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"showLegend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ShowLegend shows the legend of the widget.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"legendLayout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LegendLayout is the layout of the legend.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"legendColumns": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "LegendColumns are the columns displayed in the legend.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"requests": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Requests are the requests of the widget.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetRequest"),
+									},
+								},
+							},
+						},
+					},
+					"yaxis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Yaxis is the left y-axis of the widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetAxis"),
+						},
+					},
+					"rightYaxis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RightYaxis is the right y-axis of the widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetAxis"),
+						},
+					},
+					"markers": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Markers are the markers drawn on the widget.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMarker"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"requests"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetRequest", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetAxis", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMarker"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardTimeseriesWidgetRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardTimeseriesWidgetRequest is a request of a timeseries widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"q": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Q is a metric query, such as 'avg:system.cpu.user{*} by {host}'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"queries": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Queries are the metric queries combined by the Formulas. Use a raw widget for queries of other data sources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"),
+									},
+								},
+							},
+						},
+					},
+					"formulas": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Formulas are the formulas computed from the Queries.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula"),
+									},
+								},
+							},
+						},
+					},
+					"responseFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResponseFormat is the format of the response of the Queries.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"displayType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DisplayType is how the request is displayed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"style": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Style is the style of the request.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetRequestStyle"),
+						},
+					},
+					"onRightYaxis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OnRightYaxis displays the request on the right y-axis.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetRequestStyle"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardToplistWidgetDefinition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardToplistWidgetDefinition is the definition of a top list widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Title is the title of the widget.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"titleAlign": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleAlign is the alignment of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"titleSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TitleSize is the size of the title.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requests": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Requests are the requests of the widget.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetRequest"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"requests"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetRequest"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardToplistWidgetRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardToplistWidgetRequest is a request of a top list widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"q": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Q is a metric query, such as 'avg:system.cpu.user{*} by {host}'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"queries": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Queries are the metric queries combined by the Formulas. Use a raw widget for queries of other data sources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"),
+									},
+								},
+							},
+						},
+					},
+					"formulas": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Formulas are the formulas computed from the Queries.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula"),
+									},
+								},
+							},
+						},
+					},
+					"responseFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResponseFormat is the format of the response of the Queries.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditionalFormats": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ConditionalFormats are the formats applied to the values depending on their value.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetConditionalFormat"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetConditionalFormat", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidget is a widget of a dashboard. Exactly one of its definitions must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the ID of the widget.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"layout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Layout is the position and size of the widget. It's required for the widgets of a dashboard with the 'free' layout type or the 'fixed' reflow type.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetLayout"),
+						},
+					},
+					"timeseries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeseries is the definition of a timeseries widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetDefinition"),
+						},
+					},
+					"queryValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "QueryValue is the definition of a query value widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetDefinition"),
+						},
+					},
+					"toplist": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Toplist is the definition of a top list widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetDefinition"),
+						},
+					},
+					"note": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Note is the definition of a note widget.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardNoteWidgetDefinition"),
+						},
+					},
+					"raw": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Raw is the definition of a widget of any other type, as the `definition` of a widget in the Datadog API, such as `{\"type\": \"free_text\", \"text\": \"Overview\"}`.",
+							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Group is the definition of a group widget, holding other widgets.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupWidgetDefinition"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardGroupWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardNoteWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardQueryValueWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTimeseriesWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardToplistWidgetDefinition", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetLayout", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetAxis(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetAxis is an axis of a widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"label": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Label is the label of the axis.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"min": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Min is the minimum value of the axis, or 'auto'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"max": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Max is the maximum value of the axis, or 'auto'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"scale": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scale is the scale of the axis, such as 'linear' or 'log'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"includeZero": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IncludeZero always includes zero in the axis.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetConditionalFormat(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetConditionalFormat is a format applied to a value depending on its value.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"comparator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Comparator is the comparison of the value to Value.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value is the number compared to, such as '0.5'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"palette": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Palette is the color palette applied when the comparison is true, such as 'white_on_red'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"comparator", "value", "palette"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetFormula(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetFormula is a formula of a widget request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"formula": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Formula is the formula, such as 'query1 / query2'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"alias": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alias is the name displayed for the formula.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"limit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limit limits the number of series of the formula.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormulaLimit"),
+						},
+					},
+				},
+				Required: []string{"formula"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormulaLimit"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetFormulaLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetFormulaLimit limits the number of series of a formula.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"count": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Count is the number of series.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"order": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Order is the order of the series.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetLayout(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetLayout is the position and size of a widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"x": {
+						SchemaProps: spec.SchemaProps{
+							Description: "X is the position of the widget on the x (horizontal) axis.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"y": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Y is the position of the widget on the y (vertical) axis.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"width": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Width is the width of the widget.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"height": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Height is the height of the widget.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"isColumnBreak": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IsColumnBreak starts a new column at the widget, for a dashboard with the 'ordered' layout type and the 'fixed' reflow type.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"x", "y", "width", "height"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetMarker(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetMarker is a marker drawn on a widget.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value is the value of the marker, such as 'y = 15' or '10 < y < 20'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"displayType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DisplayType is how the marker is displayed, such as 'error dashed'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"label": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Label is the label of the marker.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"value"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetMetricQuery(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetMetricQuery is a metric query of a widget request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the query, used in the formulas.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"query": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Query is the metric query, such as 'avg:system.cpu.user{*} by {host}'.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"aggregator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Aggregator is the aggregation of the query, for the scalar response format.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "query"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetRequest holds the queries of a widget request. Either Q or Queries must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"q": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Q is a metric query, such as 'avg:system.cpu.user{*} by {host}'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"queries": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Queries are the metric queries combined by the Formulas. Use a raw widget for queries of other data sources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"),
+									},
+								},
+							},
+						},
+					},
+					"formulas": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Formulas are the formulas computed from the Queries.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula"),
+									},
+								},
+							},
+						},
+					},
+					"responseFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResponseFormat is the format of the response of the Queries.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetFormula", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidgetMetricQuery"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DashboardWidgetRequestStyle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DashboardWidgetRequestStyle is the style of a widget request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"palette": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Palette is the color palette of the request, such as 'dog_classic'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lineType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LineType is the type of the lines.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lineWidth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LineWidth is the width of the lines.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"orderBy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OrderBy is how the series are ordered.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
 			},
 		},
 	}
@@ -370,11 +1584,30 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogAgentInternalStatus(r
 							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration"),
 						},
 					},
+					"patches": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Patches reports the result of spec.patches on each object they target.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ObjectPatchStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DaemonSetStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DeploymentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DaemonSetStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DeploymentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ObjectPatchStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
 	}
 }
 
@@ -1054,16 +2287,35 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogDashboardSpec(ref com
 					},
 					"widgets": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Widgets is a JSON string representation of a list of Datadog API Widgets",
+							Description: "Widgets is a JSON string representation of a list of Datadog API Widgets. It can't be set with WidgetsYAML.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"widgetsYAML": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "WidgetsYAML is the list of widgets of the dashboard, with typed definitions for the common widget types and a raw definition for the others. It can't be set with Widgets.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidget"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariable", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePreset"},
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariable", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardTemplateVariablePreset", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DashboardWidget"},
 	}
 }
 
@@ -2247,6 +3499,83 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorStatus(ref com
 	}
 }
 
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogMonitorTemplate generates a DatadogMonitor for each namespace or workload it selects.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorTemplateSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorTemplateSpec", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogMonitorTemplateSpec defines the desired state of DatadogMonitorTemplate",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target selects the namespaces or the workloads a DatadogMonitor is generated for.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateTarget"),
+						},
+					},
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template is the spec of the generated DatadogMonitors. Its string fields are Go templates using the `[[` and `]]` delimiters, rendered with the `.Namespace` and `.Workload` of each target.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorSpec"),
+						},
+					},
+				},
+				Required: []string{"target", "template"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogMonitorSpec", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateTarget"},
+	}
+}
+
 func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogMonitorTriggeredState(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2591,6 +3920,83 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOStatus(ref common.
 	}
 }
 
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogSLOTemplate generates a DatadogSLO for each namespace or workload it selects.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOTemplateSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOTemplateSpec", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogSLOTemplateSpec defines the desired state of DatadogSLOTemplate",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target selects the namespaces or the workloads a DatadogSLO is generated for.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateTarget"),
+						},
+					},
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template is the spec of the generated DatadogSLOs. Its string fields are Go templates using the `[[` and `]]` delimiters, rendered with the `.Namespace` and `.Workload` of each target.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOSpec"),
+						},
+					},
+				},
+				Required: []string{"target", "template"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogSLOSpec", "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateTarget"},
+	}
+}
+
 func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTimeSlice(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2628,3 +4034,120 @@ func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogSLOTimeSlice(ref comm
 			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogTemplateStatus defines the observed state of a DatadogMonitorTemplate or a DatadogSLOTemplate.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the most recent generation observed for this resource.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions represents the latest available observations of the state of the template.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"targets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Targets is the number of namespaces or workloads selected by the template.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"instances": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Instances is the number of resources generated from the template.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogTemplateTarget selects the namespaces or the workloads a template is instantiated for.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceSelector selects the namespaces by label. An empty selector selects all the namespaces.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"workloads": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Workloads instantiates the template once per matching workload of the selected namespaces, instead of once per namespace.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateWorkloadSelector"),
+						},
+					},
+				},
+				Required: []string{"namespaceSelector"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1.DatadogTemplateWorkloadSelector", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v1alpha1_DatadogTemplateWorkloadSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogTemplateWorkloadSelector selects workloads of a given kind.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is the kind of the selected workloads.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector selects the workloads by label. An empty selector selects all the workloads of this kind.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+				Required: []string{"kind"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
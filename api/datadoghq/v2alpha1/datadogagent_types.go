@@ -40,6 +40,12 @@ type DatadogAgentSpec struct {
 	// Override the default configurations of the agents
 	// +optional
 	Override map[ComponentName]*DatadogAgentComponentOverride `json:"override,omitempty"`
+
+	// Patches are applied, in order, to the objects generated by the operator, after the overrides.
+	// They are meant for the fields the overrides do not cover.
+	// +optional
+	// +listType=atomic
+	Patches []ObjectPatch `json:"patches,omitempty"`
 }
 
 // DatadogFeatures are features running on the Agent and Cluster Agent.
@@ -446,6 +452,73 @@ type LogCollectionFeatureConfig struct {
 	// See also: https://docs.datadoghq.com/agent/logs/auto_multiline_detection/
 	// +optional
 	AutoMultiLineDetection *bool `json:"autoMultiLineDetection,omitempty"`
+
+	// ProcessingRules are global processing rules, applied by the Agent to all the logs it collects.
+	// See also: https://docs.datadoghq.com/agent/logs/advanced_log_collection/#global-processing-rules
+	// +optional
+	// +listType=atomic
+	ProcessingRules []LogProcessingRule `json:"processingRules,omitempty"`
+
+	// ContainerInclude selects the containers to collect logs from, even if they are selected by ContainerExclude.
+	// See also: https://docs.datadoghq.com/agent/guide/autodiscovery-management/
+	// +optional
+	ContainerInclude *LogContainerFilter `json:"containerInclude,omitempty"`
+
+	// ContainerExclude selects the containers not to collect logs from.
+	// See also: https://docs.datadoghq.com/agent/guide/autodiscovery-management/
+	// +optional
+	ContainerExclude *LogContainerFilter `json:"containerExclude,omitempty"`
+}
+
+// LogProcessingRuleType is the type of a log processing rule.
+// +kubebuilder:validation:Enum=exclude_at_match;include_at_match;mask_sequences;multi_line
+type LogProcessingRuleType string
+
+const (
+	// LogProcessingRuleExcludeAtMatch drops the logs matching the pattern.
+	LogProcessingRuleExcludeAtMatch LogProcessingRuleType = "exclude_at_match"
+	// LogProcessingRuleIncludeAtMatch only keeps the logs matching the pattern.
+	LogProcessingRuleIncludeAtMatch LogProcessingRuleType = "include_at_match"
+	// LogProcessingRuleMaskSequences replaces the sequences matching the pattern.
+	LogProcessingRuleMaskSequences LogProcessingRuleType = "mask_sequences"
+	// LogProcessingRuleMultiLine aggregates the lines following a line starting with the pattern.
+	LogProcessingRuleMultiLine LogProcessingRuleType = "multi_line"
+)
+
+// LogProcessingRule is a processing rule applied by the Agent to the logs it collects.
+type LogProcessingRule struct {
+	// Type is the type of the rule: exclude_at_match, include_at_match, mask_sequences or multi_line.
+	Type LogProcessingRuleType `json:"type"`
+
+	// Name identifies the rule.
+	Name string `json:"name"`
+
+	// Pattern is the regular expression (RE2 syntax) the rule applies to.
+	// A multi_line rule matches it at the beginning of the lines.
+	Pattern string `json:"pattern"`
+
+	// ReplacePlaceholder replaces the sequences matched by a mask_sequences rule.
+	// +optional
+	ReplacePlaceholder *string `json:"replacePlaceholder,omitempty"`
+}
+
+// LogContainerFilter selects containers by namespace, name or image.
+// Each entry is a regular expression (RE2 syntax), a container is selected when it matches any of them.
+type LogContainerFilter struct {
+	// Namespaces selects containers by Kubernetes namespace.
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Names selects containers by name.
+	// +optional
+	// +listType=set
+	Names []string `json:"names,omitempty"`
+
+	// Images selects containers by image.
+	// +optional
+	// +listType=set
+	Images []string `json:"images,omitempty"`
 }
 
 // LiveProcessCollectionFeatureConfig contains Process Collection configuration.
@@ -1964,6 +2037,135 @@ type GlobalConfig struct {
 	// Default: 'false'
 	// +optional
 	UseVSock *bool `json:"useVSock,omitempty"`
+
+	// Untaint configures the conditions the untaint controller checks on the node Agent before removing
+	// the agent.datadoghq.com/not-ready startup taint from its node.
+	// Only used when the operator runs the untaint controller.
+	// +optional
+	Untaint *UntaintConfig `json:"untaint,omitempty"`
+
+	// RolloutDowntime configures a Datadog downtime that the operator schedules to mute monitors
+	// while the Agents are rolled out or a Fleet Automation experiment is running.
+	// It requires the operator to be configured with Datadog API and application keys.
+	// +optional
+	RolloutDowntime *RolloutDowntimeConfig `json:"rolloutDowntime,omitempty"`
+
+	// ResourceRecommender configures the recommender that suggests requests and limits for the Agent containers
+	// from their observed usage. The recommendations are reported in `status.resourceRecommendations`.
+	// It requires the metrics.k8s.io API, usually served by the metrics-server.
+	// +optional
+	ResourceRecommender *ResourceRecommenderConfig `json:"resourceRecommender,omitempty"`
+}
+
+// ResourceRecommenderMode is what the resource recommender does with its recommendations.
+// +kubebuilder:validation:Enum=Recommend;Apply
+type ResourceRecommenderMode string
+
+const (
+	// ResourceRecommenderModeRecommend only reports the recommendations in the status.
+	ResourceRecommenderModeRecommend ResourceRecommenderMode = "Recommend"
+	// ResourceRecommenderModeApply also sets the recommendations as the resources of the containers
+	// whose resources are not set in the overrides, during the next rollout.
+	ResourceRecommenderModeApply ResourceRecommenderMode = "Apply"
+)
+
+// ResourceRecommenderConfig configures the resource recommender.
+// +k8s:openapi-gen=true
+type ResourceRecommenderConfig struct {
+	// Enabled enables the resource recommender.
+	// Default: false
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Mode is `Recommend` to only report the recommendations, or `Apply` to also apply them through the
+	// overrides when the Agents are next rolled out. Resources set in the overrides are never replaced.
+	// Default: 'Recommend'
+	// +optional
+	Mode *ResourceRecommenderMode `json:"mode,omitempty"`
+
+	// RequestPercentile is the percentile of the observed usage recommended as request.
+	// Default: 90
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	RequestPercentile *int32 `json:"requestPercentile,omitempty"`
+
+	// LimitPercentile is the percentile of the observed usage the recommended limit is computed from.
+	// Default: 99
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	LimitPercentile *int32 `json:"limitPercentile,omitempty"`
+
+	// LimitMarginPercent is the margin added to the limit percentile, in percent.
+	// Default: 20
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	LimitMarginPercent *int32 `json:"limitMarginPercent,omitempty"`
+}
+
+// RolloutDowntimeConfig configures the Datadog downtime scheduled during Agent rollouts.
+// +k8s:openapi-gen=true
+type RolloutDowntimeConfig struct {
+	// MonitorTags selects the monitors muted by the downtime: a monitor is muted when it has all these tags.
+	// +listType=set
+	MonitorTags []string `json:"monitorTags"`
+
+	// Scope restricts the downtime to the monitor groups matching this query, for example `kube_cluster_name:prod`.
+	// Default: '*'
+	// +optional
+	Scope *string `json:"scope,omitempty"`
+
+	// MaxDuration is the longest time the downtime stays active. The downtime is canceled when the rollout
+	// completes or once this duration has elapsed, whichever comes first.
+	// Default: '1h'
+	// +optional
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+}
+
+// UntaintConfig configures how the untaint controller decides that the node Agent is ready on a node.
+// +k8s:openapi-gen=true
+type UntaintConfig struct {
+	// ReadinessRules are evaluated on the node Agent pod once it is Ready.
+	// All of them must pass before the startup taint is removed from the node.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ReadinessRules []UntaintReadinessRule `json:"readinessRules,omitempty"`
+}
+
+// UntaintReadinessRule is a condition the node Agent pod must satisfy before its node is untainted.
+// All the conditions set on a rule must pass.
+// +k8s:openapi-gen=true
+type UntaintReadinessRule struct {
+	// Name identifies the rule in the untaint controller events and metrics.
+	Name string `json:"name"`
+
+	// Containers lists the node Agent containers that must be Ready, for example `trace-agent` or `system-probe`.
+	// Containers missing from the pod, because the features they serve are disabled, are ignored.
+	// +optional
+	// +listType=set
+	Containers []common.AgentContainerName `json:"containers,omitempty"`
+
+	// HostPathSocket requires a socket shared with the host, such as the APM or DogStatsD socket, to be present.
+	// +optional
+	HostPathSocket *UntaintHostPathSocket `json:"hostPathSocket,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds the node Agent pod must have been Ready.
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+}
+
+// UntaintHostPathSocket is a socket created by a node Agent container in a host path volume.
+// The operator adds a readiness probe checking the socket to the container, which must not define
+// its own readiness probe, and the rule passes once the container is Ready.
+// +k8s:openapi-gen=true
+type UntaintHostPathSocket struct {
+	// Container is the node Agent container creating the socket.
+	Container common.AgentContainerName `json:"container"`
+
+	// Path is the path of the socket in the container, for example `/var/run/datadog/apm.socket`.
+	Path string `json:"path"`
 }
 
 // DatadogCredentials is a generic structure that holds credentials to access Datadog.
@@ -2182,6 +2384,11 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	CreatePodDisruptionBudget *bool `json:"createPodDisruptionBudget,omitempty"`
 
+	// Autoscaling configures the autoscaling of the component.
+	// Not applicable for the Node Agent.
+	// +optional
+	Autoscaling *ComponentAutoscalingConfig `json:"autoscaling,omitempty"`
+
 	// Set CreateRbac to false to prevent automatic creation of Role/ClusterRole for this component
 	// +optional
 	CreateRbac *bool `json:"createRbac,omitempty"`
@@ -2245,6 +2452,26 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	Containers map[common.AgentContainerName]*DatadogAgentGenericContainer `json:"containers,omitempty"`
 
+	// ExtraContainers are added to the pods of the component, alongside the containers of the operator,
+	// for instance a log-shipping or a Vault agent sidecar. Their names and ports must not conflict with
+	// the ones of the operator, and they can only mount the volumes of the pod.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+
+	// ExtraInitContainers are added to the init containers of the pods of the component.
+	// Their names must not conflict with the ones of the operator, and they can only mount the volumes of the pod.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
+	// ExtraInitContainersPosition is the position of the extra init containers relative to the init
+	// containers of the operator: `After` (default) or `Before`.
+	// +optional
+	ExtraInitContainersPosition *ExtraInitContainersPosition `json:"extraInitContainersPosition,omitempty"`
+
 	// Specify additional volumes in the different components (Datadog Agent, Cluster Agent, Cluster Check Runner).
 	// +optional
 	// +listType=map
@@ -2422,6 +2649,86 @@ type DatadogAgentGenericContainer struct {
 	AppArmorProfileName *string `json:"appArmorProfileName,omitempty"`
 }
 
+// ComponentAutoscalingConfig configures the autoscaling of a component.
+// +k8s:openapi-gen=true
+type ComponentAutoscalingConfig struct {
+	// Vertical configures a VerticalPodAutoscaler for the Deployment of the component.
+	// +optional
+	Vertical *VerticalAutoscalingConfig `json:"vertical,omitempty"`
+}
+
+// VerticalAutoscalingUpdateMode is the update mode of a VerticalPodAutoscaler.
+// +kubebuilder:validation:Enum=Off;Initial;Recreate;InPlaceOrRecreate
+type VerticalAutoscalingUpdateMode string
+
+const (
+	// VerticalAutoscalingUpdateModeOff only computes recommendations, the pods are not updated.
+	VerticalAutoscalingUpdateModeOff VerticalAutoscalingUpdateMode = "Off"
+	// VerticalAutoscalingUpdateModeInitial sets the resources when the pods are created.
+	VerticalAutoscalingUpdateModeInitial VerticalAutoscalingUpdateMode = "Initial"
+	// VerticalAutoscalingUpdateModeRecreate also evicts the pods whose resources differ from the recommendations.
+	VerticalAutoscalingUpdateModeRecreate VerticalAutoscalingUpdateMode = "Recreate"
+	// VerticalAutoscalingUpdateModeInPlaceOrRecreate resizes the pods in place when possible, and evicts them otherwise.
+	VerticalAutoscalingUpdateModeInPlaceOrRecreate VerticalAutoscalingUpdateMode = "InPlaceOrRecreate"
+)
+
+// VerticalAutoscalingControlledValues are the resource values a VerticalPodAutoscaler updates.
+// +kubebuilder:validation:Enum=RequestsAndLimits;RequestsOnly
+type VerticalAutoscalingControlledValues string
+
+const (
+	// VerticalAutoscalingControlledRequestsAndLimits updates the requests, and scales the limits proportionally.
+	VerticalAutoscalingControlledRequestsAndLimits VerticalAutoscalingControlledValues = "RequestsAndLimits"
+	// VerticalAutoscalingControlledRequestsOnly only updates the requests.
+	VerticalAutoscalingControlledRequestsOnly VerticalAutoscalingControlledValues = "RequestsOnly"
+)
+
+// VerticalAutoscalingConfig configures the VerticalPodAutoscaler of a component.
+// It requires the VerticalPodAutoscaler to be installed in the cluster.
+// +k8s:openapi-gen=true
+type VerticalAutoscalingConfig struct {
+	// Enabled creates a VerticalPodAutoscaler for the Deployment of the component.
+	// The resources of the containers set by the operator are then only the initial resources of the pods.
+	// Default: false
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// UpdateMode is how the VerticalPodAutoscaler applies its recommendations to the pods.
+	// Default: 'Recreate'
+	// +optional
+	UpdateMode *VerticalAutoscalingUpdateMode `json:"updateMode,omitempty"`
+
+	// MinAllowed is the minimum resources recommended for each container.
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// MaxAllowed is the maximum resources recommended for each container.
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// ControlledResources are the resources the VerticalPodAutoscaler updates.
+	// Default: cpu and memory
+	// +optional
+	// +listType=set
+	ControlledResources []corev1.ResourceName `json:"controlledResources,omitempty"`
+
+	// ControlledValues are the values of the resources the VerticalPodAutoscaler updates.
+	// Default: 'RequestsAndLimits'
+	// +optional
+	ControlledValues *VerticalAutoscalingControlledValues `json:"controlledValues,omitempty"`
+}
+
+// ExtraInitContainersPosition is the position of the extra init containers relative to the init containers of the operator.
+// +kubebuilder:validation:Enum=Before;After
+type ExtraInitContainersPosition string
+
+const (
+	// ExtraInitContainersBefore runs the extra init containers before the init containers of the operator.
+	ExtraInitContainersBefore ExtraInitContainersPosition = "Before"
+	// ExtraInitContainersAfter runs the extra init containers after the init containers of the operator.
+	ExtraInitContainersAfter ExtraInitContainersPosition = "After"
+)
+
 type ContainerStrategyType string
 
 const (
@@ -2534,6 +2841,66 @@ type ExperimentStatus struct {
 	TerminationReason string `json:"terminationReason,omitempty"`
 }
 
+// ObjectPatchType is the type of an object patch.
+// +kubebuilder:validation:Enum=JSONPatch;StrategicMerge
+type ObjectPatchType string
+
+const (
+	// ObjectPatchJSONPatch is an RFC 6902 JSON patch: a list of operations.
+	ObjectPatchJSONPatch ObjectPatchType = "JSONPatch"
+	// ObjectPatchStrategicMerge is a strategic merge patch: a partial object.
+	ObjectPatchStrategicMerge ObjectPatchType = "StrategicMerge"
+)
+
+// ObjectPatch is a patch applied to the objects generated by the operator.
+type ObjectPatch struct {
+	// Target selects the objects the patch is applied to.
+	Target ObjectPatchTarget `json:"target"`
+
+	// Type is the type of the patch: JSONPatch or StrategicMerge.
+	Type ObjectPatchType `json:"type"`
+
+	// Patch is the patch, in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// ObjectPatchTarget selects objects generated by the operator.
+// An object is selected when it matches all the fields that are set.
+type ObjectPatchTarget struct {
+	// Kind of the objects, for instance DaemonSet, Deployment, Service or ClusterRole.
+	Kind string `json:"kind"`
+
+	// Name of the objects.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Component the objects belong to: nodeAgent, clusterAgent, clusterChecksRunner or otelAgentGateway.
+	// Only the objects labeled with their component, like the workloads, can be selected by component.
+	// +optional
+	Component ComponentName `json:"component,omitempty"`
+}
+
+// ObjectPatchStatus is the result of a patch on one of the objects it targets.
+// +k8s:openapi-gen=true
+type ObjectPatchStatus struct {
+	// Index of the patch in spec.patches.
+	Index int32 `json:"index"`
+	// Kind of the object.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Namespace of the object, empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Applied is true when the patch was applied to the object.
+	Applied bool `json:"applied"`
+	// Message explains why the patch was not applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // DatadogAgentStatus defines the observed state of DatadogAgent.
 // +k8s:openapi-gen=true
 type DatadogAgentStatus struct {
@@ -2569,6 +2936,59 @@ type DatadogAgentStatus struct {
 	// means no provider was detected or configured.
 	// +optional
 	ClusterProvider string `json:"clusterProvider,omitempty"`
+	// RolloutDowntime tracks the Datadog downtime scheduled for the ongoing rollout.
+	// +optional
+	RolloutDowntime *RolloutDowntimeStatus `json:"rolloutDowntime,omitempty"`
+	// Patches reports the result of spec.patches on each object they target.
+	// +optional
+	// +listType=atomic
+	Patches []ObjectPatchStatus `json:"patches,omitempty"`
+	// ResourceRecommendations are the requests and limits suggested by the resource recommender.
+	// +optional
+	// +listType=atomic
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+}
+
+// ResourceRecommendation is the resources recommended for a container of the Agent, computed from its observed usage.
+// +k8s:openapi-gen=true
+type ResourceRecommendation struct {
+	// Profile is the DatadogAgentProfile of the node Agent pods the recommendation is computed for.
+	// Empty for the pods of the default profile and for the cluster-level components.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+	// Component is the component running the container.
+	Component ComponentName `json:"component"`
+	// Container is the name of the container.
+	Container string `json:"container"`
+	// Requests are the recommended requests.
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// Limits are the recommended limits.
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+	// Samples is the number of usage samples the recommendation is computed from.
+	Samples int32 `json:"samples"`
+}
+
+// RolloutDowntimeStatus is the state of the Datadog downtime scheduled for a rollout.
+// +k8s:openapi-gen=true
+type RolloutDowntimeStatus struct {
+	// ID is the identifier of the Datadog downtime. It is cleared once the downtime is canceled,
+	// the status being kept until the rollout completes so that no new downtime is scheduled for it.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Trigger is what started the downtime: `Rollout` or `Experiment`.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+	// SpecHash is the hash of the DatadogAgentInternal specs whose rollout the downtime covers.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// Start is the time at which the downtime was scheduled.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+	// End is the time at which the downtime ends at the latest.
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
 }
 
 // DatadogAgent defines Agent configuration, see reference https://github.com/DataDog/datadog-operator/blob/main/docs/configuration.v2alpha1.md
@@ -7,9 +7,16 @@ package v2alpha1
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/common"
 )
 
 // reservedExtraLabelPrefixes holds label-key prefixes that are owned by the
@@ -34,6 +41,28 @@ func ValidateDatadogAgent(dda *DatadogAgent) error {
 		return err
 	}
 
+	if err := validateUntaintConfig(dda.Spec.Global.Untaint, dda.Spec.Override[NodeAgentComponentName]); err != nil {
+		return err
+	}
+
+	if err := validateRolloutDowntimeConfig(dda.Spec.Global.RolloutDowntime); err != nil {
+		return err
+	}
+
+	if dda.Spec.Features != nil {
+		if err := validateLogCollectionConfig(dda.Spec.Features.LogCollection); err != nil {
+			return err
+		}
+	}
+
+	if err := validatePatches(dda.Spec.Patches); err != nil {
+		return err
+	}
+
+	if err := validateComponentAutoscaling(dda.Spec.Override); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -68,3 +97,229 @@ func validateCommonLabels(commonLabels map[string]string) error {
 	}
 	return nil
 }
+
+// containersWithReadinessProbe are the node Agent containers defining a readiness probe by default.
+var containersWithReadinessProbe = map[common.AgentContainerName]struct{}{
+	common.CoreAgentContainerName:               {},
+	common.UnprivilegedSingleAgentContainerName: {},
+	common.AgentDataPlaneContainerName:          {},
+}
+
+// validateUntaintConfig returns an error if an untaint readiness rule has no
+// name, shares its name with another rule, has no condition, or defines an
+// incomplete host path socket, or a socket in a container which already has a
+// readiness probe, since the socket probe can't be added to it.
+func validateUntaintConfig(untaint *UntaintConfig, nodeAgentOverride *DatadogAgentComponentOverride) error {
+	if untaint == nil {
+		return nil
+	}
+	names := make(map[string]struct{}, len(untaint.ReadinessRules))
+	for i, rule := range untaint.ReadinessRules {
+		if rule.Name == "" {
+			return fmt.Errorf("spec.global.untaint.readinessRules[%d] must have a name", i)
+		}
+		if _, found := names[rule.Name]; found {
+			return fmt.Errorf("spec.global.untaint.readinessRules contains duplicate rule %q", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+
+		if len(rule.Containers) == 0 && rule.HostPathSocket == nil && rule.MinReadySeconds == nil {
+			return fmt.Errorf("spec.global.untaint.readinessRules rule %q must set at least one of containers, hostPathSocket or minReadySeconds", rule.Name)
+		}
+		if rule.MinReadySeconds != nil && *rule.MinReadySeconds < 0 {
+			return fmt.Errorf("spec.global.untaint.readinessRules rule %q has a negative minReadySeconds", rule.Name)
+		}
+		if socket := rule.HostPathSocket; socket != nil {
+			if socket.Container == "" {
+				return fmt.Errorf("spec.global.untaint.readinessRules rule %q must set hostPathSocket.container", rule.Name)
+			}
+			if !path.IsAbs(socket.Path) {
+				return fmt.Errorf("spec.global.untaint.readinessRules rule %q has a non absolute hostPathSocket.path %q", rule.Name, socket.Path)
+			}
+			if hasReadinessProbe(socket.Container, nodeAgentOverride) {
+				return fmt.Errorf("spec.global.untaint.readinessRules rule %q has a hostPathSocket in container %q, which already has a readiness probe; use containers instead", rule.Name, socket.Container)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRolloutDowntimeConfig(downtime *RolloutDowntimeConfig) error {
+	if downtime == nil {
+		return nil
+	}
+	if len(downtime.MonitorTags) == 0 {
+		return fmt.Errorf("spec.global.rolloutDowntime.monitorTags must contain at least one tag")
+	}
+	for i, tag := range downtime.MonitorTags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("spec.global.rolloutDowntime.monitorTags[%d] must not be empty", i)
+		}
+	}
+	if downtime.Scope != nil && strings.TrimSpace(*downtime.Scope) == "" {
+		return fmt.Errorf("spec.global.rolloutDowntime.scope must not be empty when set")
+	}
+	if downtime.MaxDuration != nil && downtime.MaxDuration.Duration <= 0 {
+		return fmt.Errorf("spec.global.rolloutDowntime.maxDuration must be positive")
+	}
+	return nil
+}
+
+// validateComponentAutoscaling returns an error if vertical autoscaling is
+// configured for the Node Agent, which runs as a DaemonSet, or if its
+// minAllowed resources exceed its maxAllowed resources.
+func validateComponentAutoscaling(overrides map[ComponentName]*DatadogAgentComponentOverride) error {
+	for name, override := range overrides {
+		if override == nil || override.Autoscaling == nil || override.Autoscaling.Vertical == nil {
+			continue
+		}
+		if name == NodeAgentComponentName {
+			return fmt.Errorf("spec.override.%s.autoscaling is not supported, the Node Agent runs as a DaemonSet", name)
+		}
+		vertical := override.Autoscaling.Vertical
+		for resourceName, minAllowed := range vertical.MinAllowed {
+			if maxAllowed, found := vertical.MaxAllowed[resourceName]; found && minAllowed.Cmp(maxAllowed) > 0 {
+				return fmt.Errorf("spec.override.%s.autoscaling.vertical.minAllowed.%s is greater than maxAllowed.%s", name, resourceName, resourceName)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLogCollectionConfig returns an error if a log processing rule is
+// incomplete, or if a regular expression of the processing rules or container
+// filters would be rejected by the Agent.
+func validateLogCollectionConfig(logCollection *LogCollectionFeatureConfig) error {
+	if logCollection == nil {
+		return nil
+	}
+	for i, rule := range logCollection.ProcessingRules {
+		if rule.Name == "" {
+			return fmt.Errorf("spec.features.logCollection.processingRules[%d] must have a name", i)
+		}
+		if rule.Pattern == "" {
+			return fmt.Errorf("spec.features.logCollection.processingRules rule %q must have a pattern", rule.Name)
+		}
+		// The Agent anchors multi-line patterns at the beginning of the lines.
+		pattern := rule.Pattern
+		switch rule.Type {
+		case LogProcessingRuleExcludeAtMatch, LogProcessingRuleIncludeAtMatch, LogProcessingRuleMaskSequences:
+		case LogProcessingRuleMultiLine:
+			pattern = "^" + pattern
+		default:
+			return fmt.Errorf("spec.features.logCollection.processingRules rule %q has an unsupported type %q", rule.Name, rule.Type)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("spec.features.logCollection.processingRules rule %q has an invalid pattern: %w", rule.Name, err)
+		}
+	}
+
+	filters := []struct {
+		field  string
+		filter *LogContainerFilter
+	}{
+		{"containerInclude", logCollection.ContainerInclude},
+		{"containerExclude", logCollection.ContainerExclude},
+	}
+	for _, f := range filters {
+		field, filter := f.field, f.filter
+		if filter == nil {
+			continue
+		}
+		for _, pattern := range slices.Concat(filter.Namespaces, filter.Names, filter.Images) {
+			// The Agent splits the container filters on whitespaces.
+			if pattern == "" || strings.ContainsFunc(pattern, unicode.IsSpace) {
+				return fmt.Errorf("spec.features.logCollection.%s contains %q, container filters must be non-empty and contain no whitespace", field, pattern)
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("spec.features.logCollection.%s contains an invalid pattern: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePatches returns an error if a patch has no target kind, targets an
+// unknown component, or is not a well-formed patch of its type. Whether a patch
+// applies to the objects it targets is only known at reconcile time, and is
+// reported in the status.
+func validatePatches(patches []ObjectPatch) error {
+	for i, patch := range patches {
+		if patch.Target.Kind == "" {
+			return fmt.Errorf("spec.patches[%d].target.kind must be set", i)
+		}
+		switch patch.Target.Component {
+		case "", NodeAgentComponentName, ClusterAgentComponentName, ClusterChecksRunnerComponentName, OtelAgentGatewayComponentName:
+		default:
+			return fmt.Errorf("spec.patches[%d].target.component has an unknown component %q", i, patch.Target.Component)
+		}
+
+		var err error
+		switch patch.Type {
+		case ObjectPatchJSONPatch:
+			err = validateJSONPatch(patch.Patch)
+		case ObjectPatchStrategicMerge:
+			var obj map[string]any
+			if err = yaml.Unmarshal([]byte(patch.Patch), &obj); err == nil && len(obj) == 0 {
+				err = fmt.Errorf("the patch is empty")
+			}
+		default:
+			err = fmt.Errorf("unsupported type %q", patch.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("spec.patches[%d] is invalid: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateJSONPatch returns an error if patch is not a list of RFC 6902
+// operations with the fields their operation requires.
+func validateJSONPatch(patch string) error {
+	var operations []map[string]any
+	if err := yaml.Unmarshal([]byte(patch), &operations); err != nil {
+		return err
+	}
+	if len(operations) == 0 {
+		return fmt.Errorf("the patch has no operation")
+	}
+	for i, operation := range operations {
+		op, _ := operation["op"].(string)
+		fields := []string{"path"}
+		switch op {
+		case "add", "replace", "test":
+			fields = append(fields, "value")
+		case "move", "copy":
+			fields = append(fields, "from")
+		case "remove":
+		default:
+			return fmt.Errorf("operation %d has an unsupported op %q", i, op)
+		}
+		for _, field := range fields {
+			if _, found := operation[field]; !found {
+				return fmt.Errorf("operation %d (%s) must set %s", i, op, field)
+			}
+		}
+		for _, field := range []string{"path", "from"} {
+			if pointer, found := operation[field]; found {
+				if s, ok := pointer.(string); !ok || !strings.HasPrefix(s, "/") {
+					return fmt.Errorf("operation %d (%s) has an invalid %s %v, expected a JSON pointer", i, op, field, pointer)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasReadinessProbe returns true if the node Agent container defines a readiness
+// probe, by default or in the node Agent override.
+func hasReadinessProbe(container common.AgentContainerName, nodeAgentOverride *DatadogAgentComponentOverride) bool {
+	if _, found := containersWithReadinessProbe[container]; found {
+		return true
+	}
+	if nodeAgentOverride == nil {
+		return false
+	}
+	override, found := nodeAgentOverride.Containers[container]
+	return found && override != nil && override.ReadinessProbe != nil
+}
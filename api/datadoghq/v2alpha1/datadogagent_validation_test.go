@@ -7,9 +7,15 @@ package v2alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/common"
 )
 
 func TestValidateDatadogAgent_CommonLabels_ReservedKeys(t *testing.T) {
@@ -117,3 +123,423 @@ func TestValidateDatadogAgent_CommonLabels_ReservedKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDatadogAgent_UntaintReadinessRules(t *testing.T) {
+	tests := []struct {
+		name           string
+		rules          []UntaintReadinessRule
+		override       map[ComponentName]*DatadogAgentComponentOverride
+		wantErr        bool
+		errMsgContains string
+	}{
+		{
+			name:    "no rules",
+			rules:   nil,
+			wantErr: false,
+		},
+		{
+			name: "valid rules",
+			rules: []UntaintReadinessRule{
+				{Name: "apm", Containers: []common.AgentContainerName{common.TraceAgentContainerName}},
+				{Name: "apm-socket", HostPathSocket: &UntaintHostPathSocket{Container: common.TraceAgentContainerName, Path: "/var/run/datadog/apm.socket"}},
+				{Name: "warmup", MinReadySeconds: ptr.To[int32](30)},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "missing name",
+			rules:          []UntaintReadinessRule{{MinReadySeconds: ptr.To[int32](30)}},
+			wantErr:        true,
+			errMsgContains: "must have a name",
+		},
+		{
+			name: "duplicate name",
+			rules: []UntaintReadinessRule{
+				{Name: "warmup", MinReadySeconds: ptr.To[int32](30)},
+				{Name: "warmup", MinReadySeconds: ptr.To[int32](60)},
+			},
+			wantErr:        true,
+			errMsgContains: "duplicate rule",
+		},
+		{
+			name:           "no condition",
+			rules:          []UntaintReadinessRule{{Name: "empty"}},
+			wantErr:        true,
+			errMsgContains: "at least one of",
+		},
+		{
+			name:           "negative min ready seconds",
+			rules:          []UntaintReadinessRule{{Name: "warmup", MinReadySeconds: ptr.To[int32](-1)}},
+			wantErr:        true,
+			errMsgContains: "negative minReadySeconds",
+		},
+		{
+			name:           "socket without container",
+			rules:          []UntaintReadinessRule{{Name: "apm-socket", HostPathSocket: &UntaintHostPathSocket{Path: "/var/run/datadog/apm.socket"}}},
+			wantErr:        true,
+			errMsgContains: "hostPathSocket.container",
+		},
+		{
+			name:           "relative socket path",
+			rules:          []UntaintReadinessRule{{Name: "apm-socket", HostPathSocket: &UntaintHostPathSocket{Container: common.TraceAgentContainerName, Path: "apm.socket"}}},
+			wantErr:        true,
+			errMsgContains: "non absolute",
+		},
+		{
+			name:           "socket in container with a default readiness probe",
+			rules:          []UntaintReadinessRule{{Name: "dogstatsd-socket", HostPathSocket: &UntaintHostPathSocket{Container: common.CoreAgentContainerName, Path: "/var/run/datadog/dsd.socket"}}},
+			wantErr:        true,
+			errMsgContains: "already has a readiness probe",
+		},
+		{
+			name:  "socket in container with an overridden readiness probe",
+			rules: []UntaintReadinessRule{{Name: "apm-socket", HostPathSocket: &UntaintHostPathSocket{Container: common.TraceAgentContainerName, Path: "/var/run/datadog/apm.socket"}}},
+			override: map[ComponentName]*DatadogAgentComponentOverride{
+				NodeAgentComponentName: {
+					Containers: map[common.AgentContainerName]*DatadogAgentGenericContainer{
+						common.TraceAgentContainerName: {ReadinessProbe: &corev1.Probe{}},
+					},
+				},
+			},
+			wantErr:        true,
+			errMsgContains: "already has a readiness probe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{
+				Spec: DatadogAgentSpec{
+					Global: &GlobalConfig{
+						Credentials: &DatadogCredentials{
+							APIKey: ptr.To("key"),
+						},
+						Untaint: &UntaintConfig{ReadinessRules: tt.rules},
+					},
+					Override: tt.override,
+				},
+			}
+			err := ValidateDatadogAgent(dda)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsgContains != "" {
+					assert.Contains(t, err.Error(), tt.errMsgContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDatadogAgent_RolloutDowntime(t *testing.T) {
+	tests := []struct {
+		name           string
+		downtime       *RolloutDowntimeConfig
+		errMsgContains string
+	}{
+		{
+			name:     "valid",
+			downtime: &RolloutDowntimeConfig{MonitorTags: []string{"team:agent"}, Scope: ptr.To("kube_cluster_name:prod"), MaxDuration: &metav1.Duration{Duration: time.Hour}},
+		},
+		{
+			name:           "no monitor tags",
+			downtime:       &RolloutDowntimeConfig{},
+			errMsgContains: "at least one tag",
+		},
+		{
+			name:           "empty monitor tag",
+			downtime:       &RolloutDowntimeConfig{MonitorTags: []string{"team:agent", " "}},
+			errMsgContains: "monitorTags[1] must not be empty",
+		},
+		{
+			name:           "empty scope",
+			downtime:       &RolloutDowntimeConfig{MonitorTags: []string{"team:agent"}, Scope: ptr.To("")},
+			errMsgContains: "scope must not be empty",
+		},
+		{
+			name:           "zero max duration",
+			downtime:       &RolloutDowntimeConfig{MonitorTags: []string{"team:agent"}, MaxDuration: &metav1.Duration{}},
+			errMsgContains: "maxDuration must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{
+				Spec: DatadogAgentSpec{
+					Global: &GlobalConfig{
+						Credentials:     &DatadogCredentials{APIKey: ptr.To("key")},
+						RolloutDowntime: tt.downtime,
+					},
+				},
+			}
+			err := ValidateDatadogAgent(dda)
+			if tt.errMsgContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsgContains)
+			}
+		})
+	}
+}
+
+func TestValidateDatadogAgent_LogCollection(t *testing.T) {
+	tests := []struct {
+		name           string
+		logCollection  *LogCollectionFeatureConfig
+		wantErr        bool
+		errMsgContains string
+	}{
+		{
+			name:          "no log collection",
+			logCollection: nil,
+			wantErr:       false,
+		},
+		{
+			name: "valid config",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{
+					{Type: LogProcessingRuleExcludeAtMatch, Name: "exclude_healthchecks", Pattern: "GET /healthz"},
+					{Type: LogProcessingRuleMaskSequences, Name: "mask_tokens", Pattern: `token=\w+`, ReplacePlaceholder: ptr.To("token=[REDACTED]")},
+					{Type: LogProcessingRuleMultiLine, Name: "new_log_start_with_date", Pattern: `\d{4}-\d{2}-\d{2}`},
+				},
+				ContainerInclude: &LogContainerFilter{Namespaces: []string{"^payments$"}},
+				ContainerExclude: &LogContainerFilter{Namespaces: []string{".*"}, Images: []string{"^busybox"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rule without name",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: LogProcessingRuleExcludeAtMatch, Pattern: "debug"}},
+			},
+			wantErr:        true,
+			errMsgContains: "processingRules[0] must have a name",
+		},
+		{
+			name: "rule without pattern",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: LogProcessingRuleIncludeAtMatch, Name: "errors"}},
+			},
+			wantErr:        true,
+			errMsgContains: "must have a pattern",
+		},
+		{
+			name: "unsupported rule type",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: "drop", Name: "errors", Pattern: "error"}},
+			},
+			wantErr:        true,
+			errMsgContains: "unsupported type",
+		},
+		{
+			name: "invalid rule pattern",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: LogProcessingRuleExcludeAtMatch, Name: "lookahead", Pattern: "(?=debug)"}},
+			},
+			wantErr:        true,
+			errMsgContains: `rule "lookahead" has an invalid pattern`,
+		},
+		{
+			// The Agent compiles multi-line patterns as "^" + pattern, which makes "*" valid.
+			name: "multi-line pattern compiled with an anchor",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: LogProcessingRuleMultiLine, Name: "any", Pattern: "*"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid pattern without anchor",
+			logCollection: &LogCollectionFeatureConfig{
+				ProcessingRules: []LogProcessingRule{{Type: LogProcessingRuleIncludeAtMatch, Name: "any", Pattern: "*"}},
+			},
+			wantErr:        true,
+			errMsgContains: "missing argument to repetition operator: `*`",
+		},
+		{
+			name: "invalid container filter pattern",
+			logCollection: &LogCollectionFeatureConfig{
+				ContainerExclude: &LogContainerFilter{Names: []string{"app["}},
+			},
+			wantErr:        true,
+			errMsgContains: "containerExclude contains an invalid pattern",
+		},
+		{
+			name: "container filter with whitespace",
+			logCollection: &LogCollectionFeatureConfig{
+				ContainerInclude: &LogContainerFilter{Namespaces: []string{"team a"}},
+			},
+			wantErr:        true,
+			errMsgContains: "containerInclude contains \"team a\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{
+				Spec: DatadogAgentSpec{
+					Global: &GlobalConfig{
+						Credentials: &DatadogCredentials{
+							APIKey: ptr.To("key"),
+						},
+					},
+					Features: &DatadogFeatures{LogCollection: tt.logCollection},
+				},
+			}
+			err := ValidateDatadogAgent(dda)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsgContains != "" {
+					assert.Contains(t, err.Error(), tt.errMsgContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateDatadogAgent_Patches(t *testing.T) {
+	tests := []struct {
+		name           string
+		patches        []ObjectPatch
+		errMsgContains string
+	}{
+		{
+			name: "valid patches",
+			patches: []ObjectPatch{
+				{
+					Target: ObjectPatchTarget{Kind: "DaemonSet", Component: NodeAgentComponentName},
+					Type:   ObjectPatchJSONPatch,
+					Patch:  "- op: replace\n  path: /spec/template/spec/terminationGracePeriodSeconds\n  value: 60\n- op: remove\n  path: /spec/template/spec/hostAliases",
+				},
+				{
+					Target: ObjectPatchTarget{Kind: "Service", Name: "datadog-agent"},
+					Type:   ObjectPatchStrategicMerge,
+					Patch:  `{"spec": {"externalTrafficPolicy": "Local"}}`,
+				},
+			},
+		},
+		{
+			name:           "no target kind",
+			patches:        []ObjectPatch{{Type: ObjectPatchStrategicMerge, Patch: "spec: {}"}},
+			errMsgContains: "spec.patches[0].target.kind must be set",
+		},
+		{
+			name:           "unknown component",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet", Component: "agent"}, Type: ObjectPatchStrategicMerge, Patch: "spec: {}"}},
+			errMsgContains: "unknown component \"agent\"",
+		},
+		{
+			name:           "unsupported type",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: "MergePatch", Patch: "spec: {}"}},
+			errMsgContains: "unsupported type \"MergePatch\"",
+		},
+		{
+			name:           "empty strategic merge patch",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchStrategicMerge}},
+			errMsgContains: "the patch is empty",
+		},
+		{
+			name:           "strategic merge patch that is not an object",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchStrategicMerge, Patch: "- spec"}},
+			errMsgContains: "spec.patches[0] is invalid",
+		},
+		{
+			name:           "JSON patch that is not a list",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchJSONPatch, Patch: "op: remove"}},
+			errMsgContains: "spec.patches[0] is invalid",
+		},
+		{
+			name:           "unsupported JSON patch op",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchJSONPatch, Patch: `[{"op": "merge", "path": "/spec"}]`}},
+			errMsgContains: "unsupported op \"merge\"",
+		},
+		{
+			name:           "JSON patch operation without value",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchJSONPatch, Patch: `[{"op": "add", "path": "/spec/minReadySeconds"}]`}},
+			errMsgContains: "operation 0 (add) must set value",
+		},
+		{
+			name:           "JSON patch operation with an invalid path",
+			patches:        []ObjectPatch{{Target: ObjectPatchTarget{Kind: "DaemonSet"}, Type: ObjectPatchJSONPatch, Patch: `[{"op": "copy", "from": "/spec", "path": "spec"}]`}},
+			errMsgContains: "invalid path spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{
+				Spec: DatadogAgentSpec{
+					Global: &GlobalConfig{
+						Credentials: &DatadogCredentials{APIKey: ptr.To("key")},
+					},
+					Patches: tt.patches,
+				},
+			}
+			err := ValidateDatadogAgent(dda)
+			if tt.errMsgContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsgContains)
+			}
+		})
+	}
+}
+
+func TestValidateDatadogAgent_ComponentAutoscaling(t *testing.T) {
+	tests := []struct {
+		name           string
+		override       map[ComponentName]*DatadogAgentComponentOverride
+		errMsgContains string
+	}{
+		{
+			name: "valid vertical autoscaling",
+			override: map[ComponentName]*DatadogAgentComponentOverride{
+				ClusterAgentComponentName: {Autoscaling: &ComponentAutoscalingConfig{Vertical: &VerticalAutoscalingConfig{
+					Enabled:    ptr.To(true),
+					MinAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+					MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi"), corev1.ResourceCPU: resource.MustParse("1")},
+				}}},
+				NodeAgentComponentName: {},
+			},
+		},
+		{
+			name: "vertical autoscaling of the node agent",
+			override: map[ComponentName]*DatadogAgentComponentOverride{
+				NodeAgentComponentName: {Autoscaling: &ComponentAutoscalingConfig{Vertical: &VerticalAutoscalingConfig{Enabled: ptr.To(true)}}},
+			},
+			errMsgContains: "spec.override.nodeAgent.autoscaling is not supported",
+		},
+		{
+			name: "minAllowed greater than maxAllowed",
+			override: map[ComponentName]*DatadogAgentComponentOverride{
+				ClusterChecksRunnerComponentName: {Autoscaling: &ComponentAutoscalingConfig{Vertical: &VerticalAutoscalingConfig{
+					MinAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+					MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				}}},
+			},
+			errMsgContains: "spec.override.clusterChecksRunner.autoscaling.vertical.minAllowed.cpu is greater than maxAllowed.cpu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &DatadogAgent{
+				Spec: DatadogAgentSpec{
+					Global: &GlobalConfig{
+						Credentials: &DatadogCredentials{APIKey: ptr.To("key")},
+					},
+					Override: tt.override,
+				},
+			}
+			err := ValidateDatadogAgent(dda)
+			if tt.errMsgContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsgContains)
+			}
+		})
+	}
+}
@@ -847,6 +847,26 @@ func (in *ClusterChecksFeatureConfig) DeepCopy() *ClusterChecksFeatureConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentAutoscalingConfig) DeepCopyInto(out *ComponentAutoscalingConfig) {
+	*out = *in
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoscalingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentAutoscalingConfig.
+func (in *ComponentAutoscalingConfig) DeepCopy() *ComponentAutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentAutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapConfig) DeepCopyInto(out *ConfigMapConfig) {
 	*out = *in
@@ -1053,6 +1073,11 @@ func (in *DatadogAgentComponentOverride) DeepCopyInto(out *DatadogAgentComponent
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ComponentAutoscalingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CreateRbac != nil {
 		in, out := &in.CreateRbac, &out.CreateRbac
 		*out = new(bool)
@@ -1129,6 +1154,25 @@ func (in *DatadogAgentComponentOverride) DeepCopyInto(out *DatadogAgentComponent
 			(*out)[key] = outVal
 		}
 	}
+	if in.ExtraContainers != nil {
+		in, out := &in.ExtraContainers, &out.ExtraContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraInitContainersPosition != nil {
+		in, out := &in.ExtraInitContainersPosition, &out.ExtraInitContainersPosition
+		*out = new(ExtraInitContainersPosition)
+		**out = **in
+	}
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make([]corev1.Volume, len(*in))
@@ -1388,6 +1432,11 @@ func (in *DatadogAgentSpec) DeepCopyInto(out *DatadogAgentSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ObjectPatch, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentSpec.
@@ -1451,6 +1500,23 @@ func (in *DatadogAgentStatus) DeepCopyInto(out *DatadogAgentStatus) {
 		*out = new(ExperimentStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RolloutDowntime != nil {
+		in, out := &in.RolloutDowntime, &out.RolloutDowntime
+		*out = new(RolloutDowntimeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ObjectPatchStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentStatus.
@@ -2258,6 +2324,21 @@ func (in *GlobalConfig) DeepCopyInto(out *GlobalConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Untaint != nil {
+		in, out := &in.Untaint, &out.Untaint
+		*out = new(UntaintConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutDowntime != nil {
+		in, out := &in.RolloutDowntime, &out.RolloutDowntime
+		*out = new(RolloutDowntimeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommender != nil {
+		in, out := &in.ResourceRecommender, &out.ResourceRecommender
+		*out = new(ResourceRecommenderConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalConfig.
@@ -2655,6 +2736,23 @@ func (in *LogCollectionFeatureConfig) DeepCopyInto(out *LogCollectionFeatureConf
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ProcessingRules != nil {
+		in, out := &in.ProcessingRules, &out.ProcessingRules
+		*out = make([]LogProcessingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerInclude != nil {
+		in, out := &in.ContainerInclude, &out.ContainerInclude
+		*out = new(LogContainerFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerExclude != nil {
+		in, out := &in.ContainerExclude, &out.ContainerExclude
+		*out = new(LogContainerFilter)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectionFeatureConfig.
@@ -2667,6 +2765,56 @@ func (in *LogCollectionFeatureConfig) DeepCopy() *LogCollectionFeatureConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogContainerFilter) DeepCopyInto(out *LogContainerFilter) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogContainerFilter.
+func (in *LogContainerFilter) DeepCopy() *LogContainerFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(LogContainerFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogProcessingRule) DeepCopyInto(out *LogProcessingRule) {
+	*out = *in
+	if in.ReplacePlaceholder != nil {
+		in, out := &in.ReplacePlaceholder, &out.ReplacePlaceholder
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogProcessingRule.
+func (in *LogProcessingRule) DeepCopy() *LogProcessingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(LogProcessingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metric) DeepCopyInto(out *Metric) {
 	*out = *in
@@ -3051,6 +3199,52 @@ func (in *OTLPReceiverConfig) DeepCopy() *OTLPReceiverConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPatch) DeepCopyInto(out *ObjectPatch) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPatch.
+func (in *ObjectPatch) DeepCopy() *ObjectPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPatchStatus) DeepCopyInto(out *ObjectPatchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPatchStatus.
+func (in *ObjectPatchStatus) DeepCopy() *ObjectPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPatchTarget) DeepCopyInto(out *ObjectPatchTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPatchTarget.
+func (in *ObjectPatchTarget) DeepCopy() *ObjectPatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrchestratorExplorerFeatureConfig) DeepCopyInto(out *OrchestratorExplorerFeatureConfig) {
 	*out = *in
@@ -3354,6 +3548,128 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommenderConfig) DeepCopyInto(out *ResourceRecommenderConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ResourceRecommenderMode)
+		**out = **in
+	}
+	if in.RequestPercentile != nil {
+		in, out := &in.RequestPercentile, &out.RequestPercentile
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LimitPercentile != nil {
+		in, out := &in.LimitPercentile, &out.LimitPercentile
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LimitMarginPercent != nil {
+		in, out := &in.LimitMarginPercent, &out.LimitMarginPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommenderConfig.
+func (in *ResourceRecommenderConfig) DeepCopy() *ResourceRecommenderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommenderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutDowntimeConfig) DeepCopyInto(out *RolloutDowntimeConfig) {
+	*out = *in
+	if in.MonitorTags != nil {
+		in, out := &in.MonitorTags, &out.MonitorTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxDuration != nil {
+		in, out := &in.MaxDuration, &out.MaxDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutDowntimeConfig.
+func (in *RolloutDowntimeConfig) DeepCopy() *RolloutDowntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutDowntimeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutDowntimeStatus) DeepCopyInto(out *RolloutDowntimeStatus) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutDowntimeStatus.
+func (in *RolloutDowntimeStatus) DeepCopy() *RolloutDowntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutDowntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SBOMContainerImageConfig) DeepCopyInto(out *SBOMContainerImageConfig) {
 	*out = *in
@@ -3830,6 +4146,122 @@ func (in *UnixDomainSocketConfig) DeepCopy() *UnixDomainSocketConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UntaintConfig) DeepCopyInto(out *UntaintConfig) {
+	*out = *in
+	if in.ReadinessRules != nil {
+		in, out := &in.ReadinessRules, &out.ReadinessRules
+		*out = make([]UntaintReadinessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UntaintConfig.
+func (in *UntaintConfig) DeepCopy() *UntaintConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UntaintConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UntaintHostPathSocket) DeepCopyInto(out *UntaintHostPathSocket) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UntaintHostPathSocket.
+func (in *UntaintHostPathSocket) DeepCopy() *UntaintHostPathSocket {
+	if in == nil {
+		return nil
+	}
+	out := new(UntaintHostPathSocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UntaintReadinessRule) DeepCopyInto(out *UntaintReadinessRule) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]common.AgentContainerName, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostPathSocket != nil {
+		in, out := &in.HostPathSocket, &out.HostPathSocket
+		*out = new(UntaintHostPathSocket)
+		**out = **in
+	}
+	if in.MinReadySeconds != nil {
+		in, out := &in.MinReadySeconds, &out.MinReadySeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UntaintReadinessRule.
+func (in *UntaintReadinessRule) DeepCopy() *UntaintReadinessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(UntaintReadinessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalAutoscalingConfig) DeepCopyInto(out *VerticalAutoscalingConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UpdateMode != nil {
+		in, out := &in.UpdateMode, &out.UpdateMode
+		*out = new(VerticalAutoscalingUpdateMode)
+		**out = **in
+	}
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ControlledResources != nil {
+		in, out := &in.ControlledResources, &out.ControlledResources
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlledValues != nil {
+		in, out := &in.ControlledValues, &out.ControlledValues
+		*out = new(VerticalAutoscalingControlledValues)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoscalingConfig.
+func (in *VerticalAutoscalingConfig) DeepCopy() *VerticalAutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalAutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadAutoscalingFeatureConfig) DeepCopyInto(out *WorkloadAutoscalingFeatureConfig) {
 	*out = *in
@@ -20,6 +20,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CSIAPMConfig":                        schema_datadog_operator_api_datadoghq_v2alpha1_CSIAPMConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CSPMHostBenchmarksConfig":            schema_datadog_operator_api_datadoghq_v2alpha1_CSPMHostBenchmarksConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CelWorkloadExcludeConfig":            schema_datadog_operator_api_datadoghq_v2alpha1_CelWorkloadExcludeConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ComponentAutoscalingConfig":          schema_datadog_operator_api_datadoghq_v2alpha1_ComponentAutoscalingConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ControlPlaneMonitoringFeatureConfig": schema_datadog_operator_api_datadoghq_v2alpha1_ControlPlaneMonitoringFeatureConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CoreConfig":                          schema_datadog_operator_api_datadoghq_v2alpha1_CoreConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CustomConfig":                        schema_datadog_operator_api_datadoghq_v2alpha1_CustomConfig(ref),
@@ -49,15 +50,24 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OTLPHTTPConfig":                      schema_datadog_operator_api_datadoghq_v2alpha1_OTLPHTTPConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OTLPProtocolsConfig":                 schema_datadog_operator_api_datadoghq_v2alpha1_OTLPProtocolsConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OTLPReceiverConfig":                  schema_datadog_operator_api_datadoghq_v2alpha1_OTLPReceiverConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ObjectPatchStatus":                   schema_datadog_operator_api_datadoghq_v2alpha1_ObjectPatchStatus(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OrchestratorExplorerFeatureConfig":   schema_datadog_operator_api_datadoghq_v2alpha1_OrchestratorExplorerFeatureConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OtelAgentGatewayFeatureConfig":       schema_datadog_operator_api_datadoghq_v2alpha1_OtelAgentGatewayFeatureConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OtelCollectorFeatureConfig":          schema_datadog_operator_api_datadoghq_v2alpha1_OtelCollectorFeatureConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.PrometheusScrapeFeatureConfig":       schema_datadog_operator_api_datadoghq_v2alpha1_PrometheusScrapeFeatureConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration":           schema_datadog_operator_api_datadoghq_v2alpha1_RemoteConfigConfiguration(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommendation":              schema_datadog_operator_api_datadoghq_v2alpha1_ResourceRecommendation(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommenderConfig":           schema_datadog_operator_api_datadoghq_v2alpha1_ResourceRecommenderConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeConfig":               schema_datadog_operator_api_datadoghq_v2alpha1_RolloutDowntimeConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeStatus":               schema_datadog_operator_api_datadoghq_v2alpha1_RolloutDowntimeStatus(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SeccompConfig":                       schema_datadog_operator_api_datadoghq_v2alpha1_SeccompConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretBackendConfig":                 schema_datadog_operator_api_datadoghq_v2alpha1_SecretBackendConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretBackendRolesConfig":            schema_datadog_operator_api_datadoghq_v2alpha1_SecretBackendRolesConfig(ref),
 		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UnixDomainSocketConfig":              schema_datadog_operator_api_datadoghq_v2alpha1_UnixDomainSocketConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintConfig":                       schema_datadog_operator_api_datadoghq_v2alpha1_UntaintConfig(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintHostPathSocket":               schema_datadog_operator_api_datadoghq_v2alpha1_UntaintHostPathSocket(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintReadinessRule":                schema_datadog_operator_api_datadoghq_v2alpha1_UntaintReadinessRule(ref),
+		"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.VerticalAutoscalingConfig":           schema_datadog_operator_api_datadoghq_v2alpha1_VerticalAutoscalingConfig(ref),
 	}
 }
 
@@ -156,6 +166,27 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_CelWorkloadExcludeConfig(ref
 	}
 }
 
+func schema_datadog_operator_api_datadoghq_v2alpha1_ComponentAutoscalingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ComponentAutoscalingConfig configures the autoscaling of a component.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"vertical": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Vertical configures a VerticalPodAutoscaler for the Deployment of the component.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.VerticalAutoscalingConfig"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.VerticalAutoscalingConfig"},
+	}
+}
+
 func schema_datadog_operator_api_datadoghq_v2alpha1_ControlPlaneMonitoringFeatureConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -696,11 +727,55 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_DatadogAgentStatus(ref commo
 							Format:      "",
 						},
 					},
+					"rolloutDowntime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RolloutDowntime tracks the Datadog downtime scheduled for the ongoing rollout.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeStatus"),
+						},
+					},
+					"patches": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Patches reports the result of spec.patches on each object they target.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ObjectPatchStatus"),
+									},
+								},
+							},
+						},
+					},
+					"resourceRecommendations": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceRecommendations are the requests and limits suggested by the resource recommender.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommendation"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DaemonSetStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DeploymentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ExperimentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DaemonSetStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DeploymentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ExperimentStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ObjectPatchStatus", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RemoteConfigConfiguration", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommendation", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
 	}
 }
 
@@ -1636,11 +1711,29 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_GlobalConfig(ref common.Refe
 							Format:      "",
 						},
 					},
+					"untaint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Untaint configures the conditions the untaint controller checks on the node Agent before removing the agent.datadoghq.com/not-ready startup taint from its node. Only used when the operator runs the untaint controller.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintConfig"),
+						},
+					},
+					"rolloutDowntime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RolloutDowntime configures a Datadog downtime that the operator schedules to mute monitors while the Agents are rolled out or a Fleet Automation experiment is running. It requires the operator to be configured with Datadog API and application keys.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeConfig"),
+						},
+					},
+					"resourceRecommender": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceRecommender configures the recommender that suggests requests and limits for the Agent containers from their observed usage. The recommendations are reported in `status.resourceRecommendations`. It requires the metrics.k8s.io API, usually served by the metrics-server.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommenderConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CSIConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DatadogCredentials", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.Endpoint", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.FIPSConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.KubeletConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.LocalService", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.NetworkPolicyConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OriginDetectionUnified", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretBackendConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretConfig", "k8s.io/api/core/v1.EnvVar"},
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.CSIConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.DatadogCredentials", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.Endpoint", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.FIPSConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.KubeletConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.LocalService", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.NetworkPolicyConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.OriginDetectionUnified", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.ResourceRecommenderConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.RolloutDowntimeConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretBackendConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.SecretConfig", "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintConfig", "k8s.io/api/core/v1.EnvVar"},
 	}
 }
 
@@ -2007,6 +2100,64 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_OTLPReceiverConfig(ref commo
 	}
 }
 
+func schema_datadog_operator_api_datadoghq_v2alpha1_ObjectPatchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ObjectPatchStatus is the result of a patch on one of the objects it targets.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"index": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Index of the patch in spec.patches.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind of the object.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the object, empty for cluster-scoped objects.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the object.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"applied": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Applied is true when the patch was applied to the object.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message explains why the patch was not applied.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"index", "applied"},
+			},
+		},
+	}
+}
+
 func schema_datadog_operator_api_datadoghq_v2alpha1_OrchestratorExplorerFeatureConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2255,6 +2406,226 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_RemoteConfigConfiguration(re
 	}
 }
 
+func schema_datadog_operator_api_datadoghq_v2alpha1_ResourceRecommendation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceRecommendation is the resources recommended for a container of the Agent, computed from its observed usage.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile is the DatadogAgentProfile of the node Agent pods the recommendation is computed for. Empty for the pods of the default profile and for the cluster-level components.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"component": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Component is the component running the container.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"container": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Container is the name of the container.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Requests are the recommended requests.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"limits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits are the recommended limits.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"samples": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Samples is the number of usage samples the recommendation is computed from.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"component", "container", "samples"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_ResourceRecommenderConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceRecommenderConfig configures the resource recommender.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled enables the resource recommender. Default: false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode is `Recommend` to only report the recommendations, or `Apply` to also apply them through the overrides when the Agents are next rolled out. Resources set in the overrides are never replaced. Default: 'Recommend'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requestPercentile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestPercentile is the percentile of the observed usage recommended as request. Default: 90",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"limitPercentile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LimitPercentile is the percentile of the observed usage the recommended limit is computed from. Default: 99",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"limitMarginPercent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LimitMarginPercent is the margin added to the limit percentile, in percent. Default: 20",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_RolloutDowntimeConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RolloutDowntimeConfig configures the Datadog downtime scheduled during Agent rollouts.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"monitorTags": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "MonitorTags selects the monitors muted by the downtime: a monitor is muted when it has all these tags.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"scope": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scope restricts the downtime to the monitor groups matching this query, for example `kube_cluster_name:prod`. Default: '*'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxDuration is the longest time the downtime stays active. The downtime is canceled when the rollout completes or once this duration has elapsed, whichever comes first. Default: '1h'",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"monitorTags"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_RolloutDowntimeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RolloutDowntimeStatus is the state of the Datadog downtime scheduled for a rollout.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the identifier of the Datadog downtime. It is cleared once the downtime is canceled, the status being kept until the rollout completes so that no new downtime is scheduled for it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"trigger": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Trigger is what started the downtime: `Rollout` or `Experiment`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"specHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SpecHash is the hash of the DatadogAgentInternal specs whose rollout the downtime covers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Start is the time at which the downtime was scheduled.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"end": {
+						SchemaProps: spec.SchemaProps{
+							Description: "End is the time at which the downtime ends at the latest.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_datadog_operator_api_datadoghq_v2alpha1_SeccompConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2439,3 +2810,211 @@ func schema_datadog_operator_api_datadoghq_v2alpha1_UnixDomainSocketConfig(ref c
 		},
 	}
 }
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_UntaintConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UntaintConfig configures how the untaint controller decides that the node Agent is ready on a node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"readinessRules": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessRules are evaluated on the node Agent pod once it is Ready. All of them must pass before the startup taint is removed from the node.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintReadinessRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintReadinessRule"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_UntaintHostPathSocket(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UntaintHostPathSocket is a socket created by a node Agent container in a host path volume. The operator adds a readiness probe checking the socket to the container, which must not define its own readiness probe, and the rule passes once the container is Ready.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"container": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Container is the node Agent container creating the socket.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path of the socket in the container, for example `/var/run/datadog/apm.socket`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"container", "path"},
+			},
+		},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_UntaintReadinessRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UntaintReadinessRule is a condition the node Agent pod must satisfy before its node is untainted. All the conditions set on a rule must pass.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the rule in the untaint controller events and metrics.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"containers": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Containers lists the node Agent containers that must be Ready, for example `trace-agent` or `system-probe`. Containers missing from the pod, because the features they serve are disabled, are ignored.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"hostPathSocket": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HostPathSocket requires a socket shared with the host, such as the APM or DogStatsD socket, to be present.",
+							Ref:         ref("github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintHostPathSocket"),
+						},
+					},
+					"minReadySeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinReadySeconds is the minimum number of seconds the node Agent pod must have been Ready.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1.UntaintHostPathSocket"},
+	}
+}
+
+func schema_datadog_operator_api_datadoghq_v2alpha1_VerticalAutoscalingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VerticalAutoscalingConfig configures the VerticalPodAutoscaler of a component. It requires the VerticalPodAutoscaler to be installed in the cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled creates a VerticalPodAutoscaler for the Deployment of the component. The resources of the containers set by the operator are then only the initial resources of the pods. Default: false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"updateMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpdateMode is how the VerticalPodAutoscaler applies its recommendations to the pods. Default: 'Recreate'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"minAllowed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinAllowed is the minimum resources recommended for each container.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"maxAllowed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxAllowed is the maximum resources recommended for each container.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"controlledResources": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ControlledResources are the resources the VerticalPodAutoscaler updates. Default: cpu and memory",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"controlledValues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ControlledValues are the values of the resources the VerticalPodAutoscaler updates. Default: 'RequestsAndLimits'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/agent/check"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/agent/diagnose"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/agent/find"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/agent/upgrade"
 )
@@ -37,6 +38,7 @@ func New(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(upgrade.New(streams))
 	cmd.AddCommand(check.New(streams))
 	cmd.AddCommand(find.New(streams))
+	cmd.AddCommand(diagnose.New(streams))
 
 	o := newOptions(streams)
 	o.configFlags.AddFlags(cmd.Flags())
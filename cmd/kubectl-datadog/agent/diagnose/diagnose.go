@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diagnose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var diagnoseExample = `
+  # explain why no healthy agent runs on node foo
+  %[1]s diagnose --node foo
+
+  # same diagnosis as JSON
+  %[1]s diagnose --node foo -o json
+`
+
+// options provides information required by Datadog diagnose command
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args     []string
+	nodeName string
+	output   string
+}
+
+// newOptions provides an instance of options with default values
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "diagnose" sub command
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "diagnose --node [node name] [flags]",
+		Short:        "Explain why no healthy agent runs on a given node",
+		Example:      fmt.Sprintf(diagnoseExample, "kubectl datadog agent"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run(c)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.nodeName, "node", "", "", "Name of the node to diagnose")
+	cmd.Flags().StringVarP(&o.output, "output", "o", outputText, "Output format. One of: text, json")
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided
+func (o *options) validate() error {
+	if len(o.args) > 0 {
+		return errors.New("no arguments are allowed, use --node")
+	}
+	if o.nodeName == "" {
+		return errors.New("--node is required")
+	}
+	if o.output != outputText && o.output != outputJSON {
+		return fmt.Errorf("unsupported output format %q, must be one of: text, json", o.output)
+	}
+	return nil
+}
+
+// run runs the diagnose command
+func (o *options) run(cmd *cobra.Command) error {
+	diag, err := diagnoseNode(cmd.Context(), o.Client, o.UserNamespace, o.nodeName, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if o.output == outputJSON {
+		out, err := json.MarshalIndent(diag, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode diagnosis: %w", err)
+		}
+		_, err = fmt.Fprintln(o.Out, string(out))
+		return err
+	}
+
+	diag.render(o.Out)
+	return nil
+}
@@ -0,0 +1,609 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+	"github.com/DataDog/datadog-operator/pkg/untaint"
+)
+
+const (
+	defaultProfileName = "default"
+
+	// podNodeNameField and eventInvolvedObjectNameField are the field selectors used to
+	// restrict the pods and events listed to the diagnosed node.
+	podNodeNameField             = "spec.nodeName"
+	eventInvolvedObjectNameField = "involvedObject.name"
+
+	// Reasons of the Node events emitted by the untaint controller
+	taintRemovedReason   = "TaintRemoved"
+	untaintTimeoutReason = "UntaintTimeout"
+
+	timeFormat = "2006-01-02T15:04:05Z07:00"
+)
+
+// diagnosis explains the state of the node Agent on a node.
+type diagnosis struct {
+	Node          string               `json:"node"`
+	Profile       profileDiagnosis     `json:"profile"`
+	DaemonSets    []daemonSetDiagnosis `json:"daemonSets"`
+	AgentPods     []podDiagnosis       `json:"agentPods"`
+	TaintTimeline []timelineEntry      `json:"taintTimeline"`
+	Events        []eventEntry         `json:"events"`
+	Findings      []string             `json:"findings"`
+}
+
+// profileDiagnosis describes which DatadogAgentProfile applies to the node.
+type profileDiagnosis struct {
+	Selected        string   `json:"selected"`
+	Matching        []string `json:"matching,omitempty"`
+	Conflicting     []string `json:"conflicting,omitempty"`
+	Invalid         []string `json:"invalid,omitempty"`
+	NodeLabel       string   `json:"nodeLabel,omitempty"`
+	AffinityMatches bool     `json:"affinityMatches"`
+}
+
+// daemonSetDiagnosis describes whether an agent DaemonSet can run a pod on the node.
+type daemonSetDiagnosis struct {
+	Name                  string   `json:"name"`
+	Profile               string   `json:"profile"`
+	Expected              bool     `json:"expected"`
+	AffinityMatches       bool     `json:"affinityMatches"`
+	UntoleratedTaints     []string `json:"untoleratedTaints,omitempty"`
+	InsufficientResources []string `json:"insufficientResources,omitempty"`
+}
+
+// podDiagnosis describes an agent pod running on, or pending for, the node.
+type podDiagnosis struct {
+	Name    string   `json:"name"`
+	Phase   string   `json:"phase"`
+	Ready   bool     `json:"ready"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+type timelineEntry struct {
+	Time    metav1.Time `json:"time"`
+	Message string      `json:"message"`
+}
+
+type eventEntry struct {
+	Time    metav1.Time `json:"time"`
+	Object  string      `json:"object"`
+	Type    string      `json:"type"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+}
+
+// diagnoseNode gathers the node, the DatadogAgentProfiles, the agent DaemonSets, pods and events
+// and explains why no healthy agent runs on the node.
+func diagnoseNode(ctx context.Context, c client.Client, namespace, nodeName string, now time.Time) (*diagnosis, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("node %s not found", nodeName)
+		}
+		return nil, fmt.Errorf("unable to get node %s: %w", nodeName, err)
+	}
+
+	diag := &diagnosis{Node: node.Name}
+
+	profiles := &v1alpha1.DatadogAgentProfileList{}
+	if err := c.List(ctx, profiles, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list DatadogAgentProfiles: %w", err)
+	}
+	diag.Profile = diagnoseProfiles(profiles.Items, node)
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, daemonSets, client.InNamespace(namespace), client.MatchingLabels{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix}); err != nil {
+		return nil, fmt.Errorf("unable to list agent DaemonSets: %w", err)
+	}
+
+	nodePods := &corev1.PodList{}
+	if err := c.List(ctx, nodePods, client.MatchingFields{podNodeNameField: node.Name}); err != nil {
+		return nil, fmt.Errorf("unable to list pods on node %s: %w", node.Name, err)
+	}
+	requested := requestedResources(nodePods.Items)
+
+	for i := range daemonSets.Items {
+		diag.DaemonSets = append(diag.DaemonSets, diagnoseDaemonSet(&daemonSets.Items[i], node, diag.Profile.Selected, requested))
+	}
+
+	agentPods := &corev1.PodList{}
+	if err := c.List(ctx, agentPods, client.InNamespace(namespace), client.MatchingLabels{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix}); err != nil {
+		return nil, fmt.Errorf("unable to list agent pods: %w", err)
+	}
+	var pods []corev1.Pod
+	for _, pod := range agentPods.Items {
+		if podTargetsNode(&pod, node.Name) {
+			pods = append(pods, pod)
+			diag.AgentPods = append(diag.AgentPods, diagnosePod(&pod))
+		}
+	}
+
+	events, err := listEvents(ctx, c, namespace, node.Name, pods)
+	if err != nil {
+		return nil, err
+	}
+	diag.Events = events
+	diag.TaintTimeline = taintTimeline(node, pods, events, now)
+	diag.Findings = diag.findings(node)
+
+	return diag, nil
+}
+
+// diagnoseProfiles evaluates the profiles in the order the operator applies them: the oldest
+// valid profile matching the node is selected and the next matching ones conflict with it.
+func diagnoseProfiles(profiles []v1alpha1.DatadogAgentProfile, node *corev1.Node) profileDiagnosis {
+	diag := profileDiagnosis{NodeLabel: node.Labels[constants.ProfileLabelKey]}
+
+	for _, profile := range agentprofile.SortProfiles(profiles) {
+		requirements, err := agentprofile.ValidateProfileAndReturnRequirements(&profile)
+		if err != nil {
+			diag.Invalid = append(diag.Invalid, fmt.Sprintf("%s: %v", profile.Name, err))
+			continue
+		}
+		if !requirementsMatch(requirements, node.Labels) {
+			continue
+		}
+		diag.Matching = append(diag.Matching, profile.Name)
+		if diag.Selected != "" || profile.Status.Applied == metav1.ConditionFalse {
+			diag.Conflicting = append(diag.Conflicting, profile.Name)
+			continue
+		}
+		diag.Selected = profile.Name
+		diag.AffinityMatches = nodeAffinityMatches(agentprofile.AffinityOverride(&profile), node)
+	}
+
+	if diag.Selected == "" {
+		defaultProfile := agentprofile.DefaultProfile()
+		diag.Selected = defaultProfileName
+		diag.AffinityMatches = nodeAffinityMatches(agentprofile.AffinityOverride(&defaultProfile), node)
+	}
+
+	return diag
+}
+
+// diagnoseDaemonSet checks the DaemonSet pod template scheduling constraints against the node.
+func diagnoseDaemonSet(ds *appsv1.DaemonSet, node *corev1.Node, selectedProfile string, requested corev1.ResourceList) daemonSetDiagnosis {
+	profile := ds.Spec.Template.Labels[constants.ProfileLabelKey]
+	if profile == "" {
+		profile = defaultProfileName
+	}
+	diag := daemonSetDiagnosis{
+		Name:            ds.Name,
+		Profile:         profile,
+		Expected:        profile == selectedProfile,
+		AffinityMatches: nodeSelectorMatches(ds.Spec.Template.Spec.NodeSelector, node) && nodeAffinityMatches(ds.Spec.Template.Spec.Affinity, node),
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !toleratesTaint(ds.Spec.Template.Spec.Tolerations, &taint) {
+			diag.UntoleratedTaints = append(diag.UntoleratedTaints, taint.ToString())
+		}
+	}
+
+	podRequests := podRequests(&ds.Spec.Template.Spec)
+	for _, name := range sortedResourceNames(podRequests) {
+		request := podRequests[name]
+		allocatable, found := node.Status.Allocatable[name]
+		if !found {
+			diag.InsufficientResources = append(diag.InsufficientResources, fmt.Sprintf("%s: requests %s, not allocatable on the node", name, request.String()))
+			continue
+		}
+		available := allocatable.DeepCopy()
+		if used, found := requested[name]; found {
+			available.Sub(used)
+		}
+		if request.Cmp(available) > 0 {
+			diag.InsufficientResources = append(diag.InsufficientResources, fmt.Sprintf("%s: requests %s, %s available out of %s allocatable", name, request.String(), available.String(), allocatable.String()))
+		}
+	}
+
+	return diag
+}
+
+// diagnosePod summarizes why an agent pod is not ready.
+func diagnosePod(pod *corev1.Pod) podDiagnosis {
+	diag := podDiagnosis{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+		Ready: common.IsPodReady(pod),
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			diag.Reasons = append(diag.Reasons, fmt.Sprintf("not scheduled: %s %s", cond.Reason, cond.Message))
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			diag.Reasons = append(diag.Reasons, strings.TrimSpace(fmt.Sprintf("container %s waiting: %s %s", status.Name, status.State.Waiting.Reason, status.State.Waiting.Message)))
+		}
+		if status.LastTerminationState.Terminated != nil {
+			terminated := status.LastTerminationState.Terminated
+			diag.Reasons = append(diag.Reasons, fmt.Sprintf("container %s last terminated: %s (exit code %d, %d restarts)", status.Name, terminated.Reason, terminated.ExitCode, status.RestartCount))
+		}
+	}
+	return diag
+}
+
+// listEvents returns the events of the agent pods and of the node, oldest first.
+func listEvents(ctx context.Context, c client.Client, namespace, nodeName string, pods []corev1.Pod) ([]eventEntry, error) {
+	podNames := map[string]struct{}{}
+	for _, pod := range pods {
+		podNames[pod.Name] = struct{}{}
+	}
+
+	var entries []eventEntry
+	podEvents := &corev1.EventList{}
+	if err := c.List(ctx, podEvents, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list events: %w", err)
+	}
+	for _, event := range podEvents.Items {
+		if _, found := podNames[event.InvolvedObject.Name]; found && event.InvolvedObject.Kind == "Pod" {
+			entries = append(entries, newEventEntry(&event))
+		}
+	}
+
+	nodeEvents := &corev1.EventList{}
+	if err := c.List(ctx, nodeEvents, client.MatchingFields{eventInvolvedObjectNameField: nodeName}); err != nil {
+		return nil, fmt.Errorf("unable to list node events: %w", err)
+	}
+	for _, event := range nodeEvents.Items {
+		if event.InvolvedObject.Kind == "Node" {
+			entries = append(entries, newEventEntry(&event))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(&entries[j].Time)
+	})
+	return entries, nil
+}
+
+func newEventEntry(event *corev1.Event) eventEntry {
+	eventTime := event.LastTimestamp
+	if eventTime.IsZero() {
+		eventTime = metav1.NewTime(event.EventTime.Time)
+	}
+	if eventTime.IsZero() {
+		eventTime = event.CreationTimestamp
+	}
+	return eventEntry{
+		Time:    eventTime,
+		Object:  fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		Type:    event.Type,
+		Reason:  event.Reason,
+		Message: event.Message,
+	}
+}
+
+// taintTimeline rebuilds the agent-not-ready taint history from the node, the agent pods and the untaint controller events.
+func taintTimeline(node *corev1.Node, pods []corev1.Pod, events []eventEntry, now time.Time) []timelineEntry {
+	var timeline []timelineEntry
+	if !node.CreationTimestamp.IsZero() {
+		timeline = append(timeline, timelineEntry{Time: node.CreationTimestamp, Message: "node created"})
+	}
+	for _, pod := range pods {
+		if !pod.CreationTimestamp.IsZero() {
+			timeline = append(timeline, timelineEntry{Time: pod.CreationTimestamp, Message: fmt.Sprintf("agent pod %s created", pod.Name)})
+		}
+		if pod.Status.StartTime != nil {
+			timeline = append(timeline, timelineEntry{Time: *pod.Status.StartTime, Message: fmt.Sprintf("agent pod %s started", pod.Name)})
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				timeline = append(timeline, timelineEntry{Time: cond.LastTransitionTime, Message: fmt.Sprintf("agent pod %s ready", pod.Name)})
+			}
+		}
+	}
+	for _, event := range events {
+		if event.Reason == taintRemovedReason || event.Reason == untaintTimeoutReason {
+			timeline = append(timeline, timelineEntry{Time: event.Time, Message: fmt.Sprintf("%s: %s", event.Reason, event.Message)})
+		}
+	}
+
+	state := "taint absent"
+	if hasAgentNotReadyTaint(node) {
+		state = "taint still present"
+	}
+	timeline = append(timeline, timelineEntry{Time: metav1.NewTime(now), Message: state})
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(&timeline[j].Time)
+	})
+	return timeline
+}
+
+// findings explains, from the gathered data, why no healthy agent runs on the node.
+func (d *diagnosis) findings(node *corev1.Node) []string {
+	var findings []string
+
+	for _, invalid := range d.Profile.Invalid {
+		findings = append(findings, fmt.Sprintf("invalid DatadogAgentProfile is ignored: %s", invalid))
+	}
+	if len(d.Profile.Conflicting) > 0 {
+		findings = append(findings, fmt.Sprintf("DatadogAgentProfiles %s match the node but are not applied because they conflict with another profile", strings.Join(d.Profile.Conflicting, ", ")))
+	}
+	if d.Profile.Selected != defaultProfileName && d.Profile.NodeLabel != d.Profile.Selected {
+		findings = append(findings, fmt.Sprintf("node matches DatadogAgentProfile %s but is not labeled %s=%s yet (the create strategy may delay it)", d.Profile.Selected, constants.ProfileLabelKey, d.Profile.Selected))
+	} else if d.Profile.Selected == defaultProfileName && d.Profile.NodeLabel != "" {
+		findings = append(findings, fmt.Sprintf("node is labeled %s=%s but no valid DatadogAgentProfile matches it", constants.ProfileLabelKey, d.Profile.NodeLabel))
+	}
+
+	expected := slices.IndexFunc(d.DaemonSets, func(ds daemonSetDiagnosis) bool { return ds.Expected })
+	if expected < 0 {
+		findings = append(findings, fmt.Sprintf("no agent DaemonSet found for profile %s", d.Profile.Selected))
+	} else {
+		ds := d.DaemonSets[expected]
+		if !ds.AffinityMatches {
+			findings = append(findings, fmt.Sprintf("DaemonSet %s node affinity does not match the node", ds.Name))
+		}
+		for _, taint := range ds.UntoleratedTaints {
+			findings = append(findings, fmt.Sprintf("DaemonSet %s does not tolerate the node taint %s", ds.Name, taint))
+		}
+		if len(d.AgentPods) == 0 {
+			for _, res := range ds.InsufficientResources {
+				findings = append(findings, fmt.Sprintf("DaemonSet %s does not fit on the node: %s", ds.Name, res))
+			}
+		}
+	}
+
+	healthy := false
+	for _, pod := range d.AgentPods {
+		if pod.Ready {
+			healthy = true
+			continue
+		}
+		if len(pod.Reasons) == 0 {
+			findings = append(findings, fmt.Sprintf("agent pod %s is %s and not ready", pod.Name, pod.Phase))
+		}
+		for _, reason := range pod.Reasons {
+			findings = append(findings, fmt.Sprintf("agent pod %s: %s", pod.Name, reason))
+		}
+	}
+	if len(d.AgentPods) == 0 {
+		findings = append(findings, "no agent pod is scheduled on the node")
+	}
+	for _, event := range d.Events {
+		if event.Type == corev1.EventTypeWarning {
+			findings = append(findings, fmt.Sprintf("%s %s: %s", event.Object, event.Reason, event.Message))
+		}
+	}
+
+	if hasAgentNotReadyTaint(node) {
+		findings = append(findings, fmt.Sprintf("node still has the %s taint", untaint.AgentNotReadyTaintKey))
+	}
+
+	if healthy && len(findings) == 0 {
+		findings = append(findings, "a healthy agent pod runs on the node")
+	}
+	return findings
+}
+
+// render writes the diagnosis as text.
+func (d *diagnosis) render(out io.Writer) {
+	fmt.Fprintf(out, "Node %s\n", d.Node)
+
+	fmt.Fprintln(out, "\nDatadogAgentProfile:")
+	fmt.Fprintf(out, "  selected: %s\n", d.Profile.Selected)
+	fmt.Fprintf(out, "  affinity matches: %t\n", d.Profile.AffinityMatches)
+	if len(d.Profile.Matching) > 0 {
+		fmt.Fprintf(out, "  matching: %s\n", strings.Join(d.Profile.Matching, ", "))
+	}
+	if len(d.Profile.Conflicting) > 0 {
+		fmt.Fprintf(out, "  conflicting: %s\n", strings.Join(d.Profile.Conflicting, ", "))
+	}
+
+	fmt.Fprintln(out, "\nDaemonSets:")
+	table := common.NewTable(out, "Name", "Profile", "Expected", "Affinity", "Untolerated-Taints", "Insufficient-Resources")
+	for _, ds := range d.DaemonSets {
+		_ = table.Append([]string{ds.Name, ds.Profile, fmt.Sprint(ds.Expected), fmt.Sprint(ds.AffinityMatches), strings.Join(ds.UntoleratedTaints, ", "), strings.Join(ds.InsufficientResources, "; ")})
+	}
+	_ = table.Render()
+
+	fmt.Fprintln(out, "\nAgent pods:")
+	table = common.NewTable(out, "Name", "Phase", "Ready", "Reasons")
+	for _, pod := range d.AgentPods {
+		_ = table.Append([]string{pod.Name, pod.Phase, fmt.Sprint(pod.Ready), strings.Join(pod.Reasons, "; ")})
+	}
+	_ = table.Render()
+
+	fmt.Fprintf(out, "\n%s taint timeline:\n", untaint.AgentNotReadyTaintKey)
+	for _, entry := range d.TaintTimeline {
+		fmt.Fprintf(out, "  %s  %s\n", entry.Time.Format(timeFormat), entry.Message)
+	}
+
+	fmt.Fprintln(out, "\nEvents:")
+	table = common.NewTable(out, "Time", "Object", "Type", "Reason", "Message")
+	for _, event := range d.Events {
+		_ = table.Append([]string{event.Time.Format(timeFormat), event.Object, event.Type, event.Reason, event.Message})
+	}
+	_ = table.Render()
+
+	fmt.Fprintln(out, "\nDiagnosis:")
+	for _, finding := range d.Findings {
+		fmt.Fprintf(out, "  - %s\n", finding)
+	}
+}
+
+// podTargetsNode returns true if the pod runs on the node, or is a pending DaemonSet pod created for it.
+func podTargetsNode(pod *corev1.Pod, nodeName string) bool {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName == nodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	// The DaemonSet controller pins its pods to a node with a metadata.name match field
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == metav1.ObjectNameField && field.Operator == corev1.NodeSelectorOpIn && slices.Contains(field.Values, nodeName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeAffinityMatches returns true if the node satisfies the required node affinity.
+func nodeAffinityMatches(affinity *corev1.Affinity, node *corev1.Node) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches returns true if the node satisfies all the term requirements.
+// As for the scheduler, an empty term matches no node.
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		requirement, err := labels.NewRequirement(expr.Key, nodeSelectorOperatorToSelectionOperator(expr.Operator), expr.Values)
+		if err != nil || !requirement.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		if field.Key != metav1.ObjectNameField {
+			return false
+		}
+		requirement, err := labels.NewRequirement(field.Key, nodeSelectorOperatorToSelectionOperator(field.Operator), field.Values)
+		if err != nil || !requirement.Matches(labels.Set{metav1.ObjectNameField: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorMatches(nodeSelector map[string]string, node *corev1.Node) bool {
+	return labels.SelectorFromSet(nodeSelector).Matches(labels.Set(node.Labels))
+}
+
+func nodeSelectorOperatorToSelectionOperator(op corev1.NodeSelectorOperator) selection.Operator {
+	switch op {
+	case corev1.NodeSelectorOpIn:
+		return selection.In
+	case corev1.NodeSelectorOpNotIn:
+		return selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		return selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		return selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		return selection.LessThan
+	default:
+		return ""
+	}
+}
+
+func requirementsMatch(requirements []*labels.Requirement, nodeLabels map[string]string) bool {
+	for _, requirement := range requirements {
+		if !requirement.Matches(labels.Set(nodeLabels)) {
+			return false
+		}
+	}
+	return true
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(klog.Background(), taint, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedResources sums the requests of the pods running on the node, agent pods excluded
+// since the diagnosed DaemonSet pod would replace them.
+func requestedResources(pods []corev1.Pod) corev1.ResourceList {
+	requested := corev1.ResourceList{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Labels[apicommon.AgentDeploymentComponentLabelKey] == constants.DefaultAgentResourceSuffix {
+			continue
+		}
+		addResources(requested, podRequests(&pod.Spec))
+	}
+	return requested
+}
+
+// podRequests returns the effective requests of a pod: the sum of its containers requests,
+// or the largest init container request when it is higher.
+func podRequests(spec *corev1.PodSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, found := requests[name]; !found || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+func addResources(total, list corev1.ResourceList) {
+	for name, quantity := range list {
+		if current, found := total[name]; found {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func hasAgentNotReadyTaint(node *corev1.Node) bool {
+	return slices.ContainsFunc(node.Spec.Taints, untaint.IsAgentNotReadyTaint)
+}
@@ -0,0 +1,238 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diagnose
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/untaint"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, v1alpha1.AddToScheme(s))
+
+	return fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithIndex(&corev1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithIndex(&corev1.Event{}, eventInvolvedObjectNameField, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Event).InvolvedObject.Name}
+		}).
+		Build()
+}
+
+func newProfile(name string, created time.Time, nodeLabels map[string]string) *v1alpha1.DatadogAgentProfile {
+	profile := &v1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec: v1alpha1.DatadogAgentProfileSpec{
+			ProfileAffinity: &v1alpha1.ProfileAffinity{},
+			Config:          &v2alpha1.DatadogAgentSpec{},
+		},
+	}
+	for key, value := range nodeLabels {
+		profile.Spec.ProfileAffinity.ProfileNodeAffinity = append(profile.Spec.ProfileAffinity.ProfileNodeAffinity, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		})
+	}
+	return profile
+}
+
+func newAgentDaemonSet(name string, profile *v1alpha1.DatadogAgentProfile, cpu string, tolerations ...corev1.Toleration) *appsv1.DaemonSet {
+	podLabels := map[string]string{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix}
+	if !agentprofile.IsDefaultProfile(profile.Namespace, profile.Name) {
+		podLabels[constants.ProfileLabelKey] = profile.Name
+	}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "datadog",
+			Name:      name,
+			Labels:    map[string]string{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Affinity:    agentprofile.AffinityOverride(profile),
+					Tolerations: tolerations,
+					Containers: []corev1.Container{{
+						Name: "agent",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse(cpu),
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func Test_diagnoseNode(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	defaultProfile := agentprofile.DefaultProfile()
+	gpu := newProfile("gpu", now.Add(-2*time.Hour), map[string]string{"accelerator": "gpu"})
+	gpuLate := newProfile("gpu-late", now.Add(-time.Hour), map[string]string{"accelerator": "gpu"})
+	invalid := newProfile("invalid", now.Add(-time.Hour), nil)
+	invalid.Spec.ProfileAffinity = nil
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-a",
+			CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute)),
+			Labels:            map[string]string{"accelerator": "gpu"},
+		},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			untaint.AgentNotReadyTaint(),
+			{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+		}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+	}
+	appPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "app"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+			Containers: []corev1.Container{{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	nodeEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "node-a.untaint"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         untaintTimeoutReason,
+		Message:        "agent not scheduled",
+		LastTimestamp:  metav1.NewTime(now.Add(-5 * time.Minute)),
+	}
+
+	c := newFakeClient(t,
+		node, appPod, nodeEvent, gpu, gpuLate, invalid,
+		newAgentDaemonSet("datadog-agent", &defaultProfile, "100m"),
+		newAgentDaemonSet("gpu-agent", gpu, "1", untaint.AgentNotReadyEqualToleration()),
+	)
+
+	diag, err := diagnoseNode(context.TODO(), c, "datadog", "node-a", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "gpu", diag.Profile.Selected)
+	assert.Equal(t, []string{"gpu", "gpu-late"}, diag.Profile.Matching)
+	assert.Equal(t, []string{"gpu-late"}, diag.Profile.Conflicting)
+	assert.Len(t, diag.Profile.Invalid, 1)
+	// The node is not labeled with the profile yet
+	assert.False(t, diag.Profile.AffinityMatches)
+
+	require.Len(t, diag.DaemonSets, 2)
+	assert.Equal(t, daemonSetDiagnosis{
+		Name:              "datadog-agent",
+		Profile:           defaultProfileName,
+		AffinityMatches:   true,
+		UntoleratedTaints: []string{"agent.datadoghq.com/not-ready=presence:NoSchedule", "nvidia.com/gpu=present:NoSchedule"},
+	}, diag.DaemonSets[0])
+	assert.Equal(t, daemonSetDiagnosis{
+		Name:                  "gpu-agent",
+		Profile:               "gpu",
+		Expected:              true,
+		UntoleratedTaints:     []string{"nvidia.com/gpu=present:NoSchedule"},
+		InsufficientResources: []string{"cpu: requests 1, 500m available out of 2 allocatable"},
+	}, diag.DaemonSets[1])
+
+	assert.Empty(t, diag.AgentPods)
+	require.Len(t, diag.Events, 1)
+	require.Len(t, diag.TaintTimeline, 3)
+	for i, expected := range []timelineEntry{
+		{Time: metav1.NewTime(now.Add(-10 * time.Minute)), Message: "node created"},
+		{Time: metav1.NewTime(now.Add(-5 * time.Minute)), Message: "UntaintTimeout: agent not scheduled"},
+		{Time: metav1.NewTime(now), Message: "taint still present"},
+	} {
+		assert.True(t, expected.Time.Equal(&diag.TaintTimeline[i].Time))
+		assert.Equal(t, expected.Message, diag.TaintTimeline[i].Message)
+	}
+
+	assert.Contains(t, diag.Findings, "DaemonSet gpu-agent node affinity does not match the node")
+	assert.Contains(t, diag.Findings, "DaemonSet gpu-agent does not tolerate the node taint nvidia.com/gpu=present:NoSchedule")
+	assert.Contains(t, diag.Findings, "DaemonSet gpu-agent does not fit on the node: cpu: requests 1, 500m available out of 2 allocatable")
+	assert.Contains(t, diag.Findings, "no agent pod is scheduled on the node")
+	assert.Contains(t, diag.Findings, "node still has the agent.datadoghq.com/not-ready taint")
+
+	var out bytes.Buffer
+	diag.render(&out)
+	assert.Contains(t, out.String(), "Node node-a")
+	assert.Contains(t, out.String(), "selected: gpu")
+
+	_, err = diagnoseNode(context.TODO(), c, "datadog", "missing", now)
+	assert.EqualError(t, err, "node missing not found")
+}
+
+func Test_diagnoseNode_healthy(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	defaultProfile := agentprofile.DefaultProfile()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status:     corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+	}
+	agentPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "datadog",
+			Name:      "datadog-agent-abcde",
+			Labels:    map[string]string{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-a"},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))}},
+		},
+	}
+
+	c := newFakeClient(t, node, agentPod, newAgentDaemonSet("datadog-agent", &defaultProfile, "100m"))
+
+	diag, err := diagnoseNode(context.TODO(), c, "datadog", "node-a", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultProfileName, diag.Profile.Selected)
+	assert.True(t, diag.Profile.AffinityMatches)
+	assert.Equal(t, []podDiagnosis{{Name: "datadog-agent-abcde", Phase: "Running", Ready: true}}, diag.AgentPods)
+	assert.Equal(t, []string{"a healthy agent pod runs on the node"}, diag.Findings)
+}
+
+func Test_podTargetsNode(t *testing.T) {
+	pending := &corev1.Pod{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchFields: []corev1.NodeSelectorRequirement{{Key: metav1.ObjectNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}}},
+		}}},
+	}}}}
+
+	assert.True(t, podTargetsNode(pending, "node-a"))
+	assert.False(t, podTargetsNode(pending, "node-b"))
+	assert.True(t, podTargetsNode(&corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-a"}}, "node-a"))
+	assert.False(t, podTargetsNode(&corev1.Pod{}, "node-a"))
+}
@@ -0,0 +1,104 @@
+package apply
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/display"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/karpenter"
+)
+
+// displayClusterAutoscalerMigration shows the scaling policy of
+// cluster-autoscaler side by side with the NodePools it is carried over to,
+// followed by the settings that have no Karpenter equivalent.
+func displayClusterAutoscalerMigration(w io.Writer, cfg *clusterautoscaler.Config, nodePools []karpenter.NodePool) {
+	fmt.Fprintf(w, "Carrying the scaling policy of cluster-autoscaler %s/%s over to the NodePools:\n", cfg.Namespace, cfg.Name)
+
+	slices.SortFunc(nodePools, func(a, b karpenter.NodePool) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	// The disruption settings are the same for all the NodePools.
+	rows := [][]string{{"(all)", clusterAutoscalerGlobalPolicy(cfg), "(all)", karpenterGlobalPolicy(karpenter.NewNodePool(nodePools[0]))}}
+	for _, np := range nodePools {
+		asgs := np.GetAutoscalingGroups()
+		rows = append(rows, []string{
+			lo.CoalesceOrEmpty(strings.Join(lo.Map(asgs, func(asg karpenter.AutoscalingGroup, _ int) string { return asg.Name }), ", "), "-"),
+			clusterAutoscalerNodeGroupPolicy(cfg, asgs),
+			np.GetName(),
+			karpenterNodePoolPolicy(np.GetScalingPolicy()),
+		})
+	}
+	display.PrintTable(w, []string{"Auto Scaling groups", "cluster-autoscaler", "NodePool", "Karpenter"}, rows...)
+
+	if cfg.BalanceSimilarNodeGroups {
+		fmt.Fprintln(w, "--balance-similar-node-groups has no NodePool equivalent: use topology spread constraints to balance workloads across zones.")
+	}
+	fmt.Fprintln(w, "Minimum sizes are not carried over: Karpenter only provisions nodes for pending pods.")
+}
+
+func clusterAutoscalerGlobalPolicy(cfg *clusterautoscaler.Config) string {
+	parts := []string{"expander " + strings.Join(cfg.Expanders, ",")}
+	if cfg.ScaleDownEnabled != nil {
+		parts = append(parts, "scale-down-enabled "+strconv.FormatBool(*cfg.ScaleDownEnabled))
+	}
+	if cfg.ScaleDownUnneededTime != nil {
+		parts = append(parts, "scale-down-unneeded-time "+cfg.ScaleDownUnneededTime.String())
+	}
+	if cfg.MaxScaleDownParallelism != nil {
+		parts = append(parts, "max-scale-down-parallelism "+strconv.Itoa(*cfg.MaxScaleDownParallelism))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// karpenterGlobalPolicy returns the disruption settings of a generated
+// NodePool.
+func karpenterGlobalPolicy(np *karpv1.NodePool) string {
+	consolidateAfter := karpv1.Never
+	if d := np.Spec.Disruption.ConsolidateAfter.Duration; d != nil {
+		consolidateAfter = d.String()
+	}
+	budgets := lo.Map(np.Spec.Disruption.Budgets, func(b karpv1.Budget, _ int) string { return b.Nodes })
+	return "consolidateAfter " + consolidateAfter + ", budget " + strings.Join(budgets, ",") + " nodes"
+}
+
+func clusterAutoscalerNodeGroupPolicy(cfg *clusterautoscaler.Config, asgs []karpenter.AutoscalingGroup) string {
+	var parts []string
+	for _, asg := range asgs {
+		var sizes []string
+		if ng, ok := cfg.NodeGroup(asg.Name); ok {
+			sizes = []string{"min " + strconv.Itoa(int(ng.MinSize)), "max " + strconv.Itoa(int(ng.MaxSize))}
+		} else {
+			if asg.MinSize != nil {
+				sizes = append(sizes, "min "+strconv.Itoa(int(*asg.MinSize)))
+			}
+			if asg.MaxSize != nil {
+				sizes = append(sizes, "max "+strconv.Itoa(int(*asg.MaxSize)))
+			}
+		}
+		if p, ok := cfg.Priority(asg.Name); ok {
+			sizes = append(sizes, "priority "+strconv.Itoa(p))
+		}
+		if len(sizes) > 0 {
+			parts = append(parts, strings.Join(sizes, " "))
+		}
+	}
+	return lo.CoalesceOrEmpty(strings.Join(parts, "; "), "-")
+}
+
+func karpenterNodePoolPolicy(policy karpenter.ScalingPolicy) string {
+	var parts []string
+	if policy.Weight != nil {
+		parts = append(parts, "weight "+strconv.Itoa(int(*policy.Weight)))
+	}
+	if policy.MaxNodes != nil {
+		parts = append(parts, "limits.nodes "+strconv.FormatInt(*policy.MaxNodes, 10))
+	}
+	return lo.CoalesceOrEmpty(strings.Join(parts, ", "), "-")
+}
@@ -0,0 +1,60 @@
+package apply
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/karpenter"
+)
+
+func TestDisplayClusterAutoscalerMigration(t *testing.T) {
+	nps := karpenter.NewNodePoolsSet()
+	nps.Add(karpenter.NodePoolsSetAddParams{
+		AMIFamily:         "AL2023",
+		SubnetIDs:         []string{"subnet-a"},
+		AutoscalingGroups: []karpenter.AutoscalingGroup{{Name: "eks-spot", MinSize: new(int32(1)), MaxSize: new(int32(10))}},
+	})
+	cfg := &clusterautoscaler.Config{
+		Namespace:                "kube-system",
+		Name:                     "cluster-autoscaler",
+		Expanders:                []string{clusterautoscaler.PriorityExpander},
+		BalanceSimilarNodeGroups: true,
+		ScaleDownUnneededTime:    new(15 * time.Minute),
+		Priorities:               map[int][]*regexp.Regexp{50: {regexp.MustCompile(".*spot.*")}},
+	}
+	nps.ApplyClusterAutoscalerConfig(cfg)
+	nodePools := nps.GetNodePools()
+	require.Len(t, nodePools, 1)
+
+	var out bytes.Buffer
+	displayClusterAutoscalerMigration(&out, cfg, nodePools)
+
+	assert.Contains(t, out.String(), "cluster-autoscaler kube-system/cluster-autoscaler")
+	assert.Contains(t, out.String(), "expander priority, scale-down-unneeded-time 15m0s")
+	assert.Contains(t, out.String(), "consolidateAfter 15m0s, budget 10% nodes")
+	assert.Contains(t, out.String(), "│ eks-spot ")
+	assert.Contains(t, out.String(), "min 1 max 10 priority 50")
+	assert.Contains(t, out.String(), "weight 50, limits.nodes 10")
+	assert.Contains(t, out.String(), "│ "+nodePools[0].GetName()+" ")
+	assert.Contains(t, out.String(), "--balance-similar-node-groups has no NodePool equivalent")
+}
+
+func TestKarpenterGlobalPolicy(t *testing.T) {
+	nps := karpenter.NewNodePoolsSet()
+	nps.Add(karpenter.NodePoolsSetAddParams{
+		AMIFamily: "AL2023",
+		SubnetIDs: []string{"subnet-a"},
+	})
+	np := nps.GetNodePools()[0]
+	assert.Equal(t, "consolidateAfter 5m0s, budget 10% nodes", karpenterGlobalPolicy(karpenter.NewNodePool(np)))
+
+	nps.ApplyClusterAutoscalerConfig(&clusterautoscaler.Config{ScaleDownEnabled: new(false)})
+	np = nps.GetNodePools()[0]
+	assert.Equal(t, "consolidateAfter 5m0s, budget 0 nodes", karpenterGlobalPolicy(karpenter.NewNodePool(np)))
+}
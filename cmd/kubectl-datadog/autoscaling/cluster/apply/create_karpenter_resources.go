@@ -39,3 +39,13 @@ func (c *CreateKarpenterResources) Set(s string) error {
 func (_ *CreateKarpenterResources) Type() string {
 	return "CreateKarpenterResources"
 }
+
+// createsEC2NodeClasses returns whether EC2NodeClass resources are created.
+func (c CreateKarpenterResources) createsEC2NodeClasses() bool {
+	return c == CreateKarpenterResourcesEC2NodeClass || c == CreateKarpenterResourcesAll
+}
+
+// createsNodePools returns whether NodePool resources are created.
+func (c CreateKarpenterResources) createsNodePools() bool {
+	return c == CreateKarpenterResourcesAll
+}
@@ -0,0 +1,494 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	karpawsv1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/samber/lo"
+	"helm.sh/helm/v3/pkg/registry"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/aws"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/awsauth"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clients"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterinfo"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/display"
+	commoneks "github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/eks"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/helm"
+	commonk8s "github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/k8s"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/karpenter"
+)
+
+// Files of a plan, relative to the output directory.
+const (
+	planFile           = "plan.yaml"
+	cloudFormationDir  = "cloudformation"
+	helmValuesFile     = "helm/karpenter-values.yaml"
+	karpenterResources = "manifests/karpenter-resources.yaml"
+)
+
+// Plan describes every change Run makes to converge the cluster. It is built
+// by BuildPlan with read-only calls only, so that the changes can be reviewed
+// before they are made.
+type Plan struct {
+	ClusterName string      `json:"clusterName"`
+	InstallMode InstallMode `json:"installMode"`
+	// Stacks are the CloudFormation stacks, in creation order.
+	Stacks []StackPlan `json:"stacks"`
+	// OIDCProviderArn is the IAM OIDC provider used by the Karpenter IRSA role
+	// in fargate mode. CreateOIDCProvider is set when it does not exist yet.
+	OIDCProviderArn    string `json:"oidcProviderArn,omitempty"`
+	CreateOIDCProvider bool   `json:"createOIDCProvider,omitempty"`
+	// AWSAuthRole is the role mapping added to the `aws-auth` ConfigMap, if
+	// the cluster has one.
+	AWSAuthRole *AWSAuthRolePlan `json:"awsAuthRole,omitempty"`
+	HelmRelease HelmReleasePlan  `json:"helmRelease"`
+	// KarpenterResources is the file holding the EC2NodeClasses and
+	// NodePools, if any is created.
+	KarpenterResources string                    `json:"karpenterResources,omitempty"`
+	EC2NodeClasses     []*karpawsv1.EC2NodeClass `json:"-"`
+	NodePools          []*karpv1.NodePool        `json:"-"`
+	// ClusterInfoConfigMap is the ConfigMap the node-management snapshot is
+	// written to. The snapshot is taken once Karpenter is installed, so its
+	// content is not part of the plan.
+	ClusterInfoConfigMap string `json:"clusterInfoConfigMap"`
+
+	// oidcIssuerURL is the issuer of the IAM OIDC provider to create.
+	oidcIssuerURL string
+	// clusterAutoscaler is the configuration of the cluster-autoscaler the
+	// NodePools are migrated from, if any.
+	clusterAutoscaler *clusterautoscaler.Config
+	inferredNodePools []karpenter.NodePool
+}
+
+// StackPlan describes a CloudFormation stack to create or update.
+type StackPlan struct {
+	Name string `json:"name"`
+	// Exists is set when the stack is updated rather than created.
+	Exists         bool              `json:"exists"`
+	TemplateFile   string            `json:"templateFile"`
+	ParametersFile string            `json:"parametersFile"`
+	Parameters     map[string]string `json:"parameters"`
+	Tags           map[string]string `json:"tags"`
+	TemplateBody   string            `json:"-"`
+
+	existing *aws.Stack
+}
+
+// AWSAuthRolePlan is the `aws-auth` role mapping added by Run.
+type AWSAuthRolePlan struct {
+	RoleArn  string   `json:"rolearn"`
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// HelmReleasePlan describes the Karpenter Helm release to install or upgrade.
+type HelmReleasePlan struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Chart     string `json:"chart"`
+	// Version is the version of the chart matching
+	// RunOptions.KarpenterVersion, the latest one when it is empty.
+	Version    string         `json:"version"`
+	ValuesFile string         `json:"valuesFile"`
+	Values     map[string]any `json:"-"`
+}
+
+// PlanFile is a file of a rendered Plan.
+type PlanFile struct {
+	Path    string
+	Content []byte
+}
+
+// planOnly returns whether Run only reports the changes it would make.
+func (o RunOptions) planOnly() bool {
+	return o.DryRun || o.OutputDir != ""
+}
+
+// runPlan builds the plan and prints it, or writes it to opts.OutputDir.
+func runPlan(streams genericclioptions.IOStreams, plan *Plan, opts RunOptions) error {
+	if plan.clusterAutoscaler != nil && len(plan.NodePools) > 0 {
+		displayClusterAutoscalerMigration(streams.ErrOut, plan.clusterAutoscaler, plan.inferredNodePools)
+	}
+
+	if opts.OutputDir == "" {
+		return plan.Print(streams.Out)
+	}
+
+	if err := plan.Write(opts.OutputDir); err != nil {
+		return err
+	}
+	display.PrintBox(streams.Out,
+		"Plan written to "+opts.OutputDir+".",
+		"",
+		"Nothing was changed on cluster "+opts.ClusterName+".",
+	)
+	return nil
+}
+
+// BuildPlan computes the changes Run makes for opts: it runs the checks and
+// the inference, and resolves the Karpenter chart version through rc. It only
+// issues read-only calls, the changes are made by Apply.
+//
+// Values that only exist once a resource is created are derived from their
+// well-known naming scheme: the ARN of an IAM OIDC provider that does not
+// exist yet, and the ARN of the Karpenter IRSA role before its stack is
+// created. Apply uses the actual values.
+func BuildPlan(ctx context.Context, cli *clients.Clients, rc *registry.Client, opts RunOptions) (*Plan, error) {
+	accountID, err := clients.GetAWSAccountID(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		ClusterName:          opts.ClusterName,
+		InstallMode:          opts.InstallMode,
+		ClusterInfoConfigMap: opts.KarpenterNamespace + "/" + clusterinfo.ConfigMapName,
+	}
+
+	karpenterStackName := KarpenterStackName(opts.ClusterName)
+	karpenterStack, err := aws.GetStack(ctx, cli.CloudFormation, karpenterStackName)
+	if err != nil {
+		return nil, err
+	}
+	plan.Stacks = append(plan.Stacks, newStackPlan(karpenterStackName, KarpenterCfn, karpenterStackParameters(opts.ClusterName), nil, karpenterStack))
+
+	describeOut, err := cli.EKS.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: awssdk.String(opts.ClusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster %s: %w", opts.ClusterName, err)
+	}
+	cluster := describeOut.Cluster
+	supportsAPIAuth := commoneks.SupportsAPIAuthenticationMode(cluster)
+
+	ddStackName := DDKarpenterStackName(opts.ClusterName)
+	ddStack, err := aws.GetStack(ctx, cli.CloudFormation, ddStackName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkInstallModeTag(ddStack, opts.InstallMode); err != nil {
+		return nil, err
+	}
+	modeTags := map[string]string{InstallModeTagKey: string(opts.InstallMode)}
+
+	var irsaRoleArn string
+	switch opts.InstallMode {
+	case InstallModeExistingNodes:
+		isUnmanagedEKSPIAInstalled, err := commoneks.IsThereUnmanagedEKSPodIdentityAgentInstalled(ctx, cli.EKS, opts.ClusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if EKS pod identity agent is installed: %w", err)
+		}
+		plan.Stacks = append(plan.Stacks, newStackPlan(ddStackName, DdKarpenterCfn, existingNodesStackParameters(opts, !isUnmanagedEKSPIAInstalled, supportsAPIAuth), modeTags, ddStack))
+
+	case InstallModeFargate:
+		issuerURL, err := commoneks.GetClusterOIDCIssuerURL(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cluster OIDC issuer URL: %w", err)
+		}
+		plan.OIDCProviderArn, err = commoneks.FindOIDCProvider(ctx, cli.IAM, issuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up OIDC provider: %w", err)
+		}
+		if plan.OIDCProviderArn == "" {
+			plan.CreateOIDCProvider = true
+			plan.oidcIssuerURL = issuerURL
+			plan.OIDCProviderArn = "arn:aws:iam::" + accountID + ":oidc-provider/" + strings.TrimPrefix(issuerURL, "https://")
+		}
+
+		subnets, err := fargateSubnets(ctx, cli, opts, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkFargateStackImmutability(ddStack, opts.KarpenterNamespace, subnets); err != nil {
+			return nil, err
+		}
+		plan.Stacks = append(plan.Stacks, newStackPlan(ddStackName, DdKarpenterFargateCfn, fargateStackParameters(opts, plan.OIDCProviderArn, issuerURL, subnets, supportsAPIAuth), modeTags, ddStack))
+
+		// The role is named after the cluster by the stack template.
+		irsaRoleArn = ddStack.OutputMap()["KarpenterRoleArn"]
+		if irsaRoleArn == "" {
+			irsaRoleArn = "arn:aws:iam::" + accountID + ":role/" + opts.ClusterName + "-karpenter"
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported install mode %q", opts.InstallMode)
+	}
+
+	awsAuthConfigMapPresent, err := awsauth.IsConfigMapPresent(ctx, cli.K8sClientset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if aws-auth ConfigMap is present: %w", err)
+	}
+	if awsAuthConfigMapPresent {
+		role := karpenterNodeRoleMapping(accountID, opts.ClusterName)
+		plan.AWSAuthRole = &AWSAuthRolePlan{
+			RoleArn:  role.RoleArn,
+			Username: role.Username,
+			Groups:   role.Groups,
+		}
+	}
+
+	chartVersion, err := helm.ResolveVersion(rc, karpenterOCIRegistry, opts.KarpenterVersion)
+	if err != nil {
+		return nil, err
+	}
+	plan.HelmRelease = HelmReleasePlan{
+		Name:       karpenterReleaseName,
+		Namespace:  opts.KarpenterNamespace,
+		Chart:      karpenterOCIRegistry,
+		Version:    chartVersion,
+		ValuesFile: helmValuesFile,
+		Values:     karpenterHelmValues(opts.ClusterName, opts.InstallMode, irsaRoleArn),
+	}
+
+	if opts.CreateKarpenterResources == CreateKarpenterResourcesNone {
+		return plan, nil
+	}
+
+	nodePoolsSet, caConfig, err := inferNodePoolsSet(ctx, cli, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CreateKarpenterResources.createsEC2NodeClasses() {
+		plan.EC2NodeClasses = lo.Map(nodePoolsSet.GetEC2NodeClasses(), func(nc karpenter.EC2NodeClass, _ int) *karpawsv1.EC2NodeClass {
+			return karpenter.NewEC2NodeClass(opts.ClusterName, nc)
+		})
+	}
+	if opts.CreateKarpenterResources.createsNodePools() {
+		plan.clusterAutoscaler = caConfig
+		plan.inferredNodePools = nodePoolsSet.GetNodePools()
+		plan.NodePools = lo.Map(plan.inferredNodePools, func(np karpenter.NodePool, _ int) *karpv1.NodePool {
+			return karpenter.NewNodePool(np)
+		})
+	}
+	if len(plan.EC2NodeClasses) > 0 || len(plan.NodePools) > 0 {
+		plan.KarpenterResources = karpenterResources
+	}
+
+	return plan, nil
+}
+
+func newStackPlan(name, templateBody string, params, extraTags map[string]string, existing *aws.Stack) StackPlan {
+	return StackPlan{
+		Name:           name,
+		Exists:         existing != nil,
+		TemplateFile:   filepath.Join(cloudFormationDir, name+".yaml"),
+		ParametersFile: filepath.Join(cloudFormationDir, name+".parameters.json"),
+		Parameters:     params,
+		Tags:           aws.StackTags(extraTags),
+		TemplateBody:   templateBody,
+		existing:       existing,
+	}
+}
+
+// Apply makes the changes of the plan, in order: the IAM OIDC provider, the
+// CloudFormation stacks, the `aws-auth` role mapping, the Helm release, the
+// Karpenter resources and the cluster-info ConfigMap. rc is the registry
+// client the Helm chart is pulled with.
+func (p *Plan) Apply(ctx context.Context, configFlags *genericclioptions.ConfigFlags, cli *clients.Clients, rc *registry.Client) error {
+	if p.CreateOIDCProvider {
+		oidcArn, err := commoneks.EnsureOIDCProvider(ctx, cli.IAM, p.oidcIssuerURL)
+		if err != nil {
+			return fmt.Errorf("failed to ensure OIDC provider: %w", err)
+		}
+		p.setOIDCProviderArn(oidcArn)
+	}
+
+	for _, stack := range p.Stacks {
+		if err := aws.CreateOrUpdateStackWithExisting(ctx, cli.CloudFormation, stack.Name, stack.TemplateBody, stack.Parameters, stack.Tags, stack.existing); err != nil {
+			return fmt.Errorf("failed to create or update Cloud Formation stack: %w", err)
+		}
+	}
+
+	if p.InstallMode == InstallModeFargate {
+		irsaRoleArn, err := karpenterRoleArn(ctx, cli, DDKarpenterStackName(p.ClusterName))
+		if err != nil {
+			return err
+		}
+		p.HelmRelease.Values = karpenterHelmValues(p.ClusterName, p.InstallMode, irsaRoleArn)
+	}
+
+	if p.AWSAuthRole == nil {
+		log.Println("aws-auth ConfigMap not present, skipping role addition.")
+	} else if err := awsauth.EnsureRole(ctx, cli.K8sClientset, awsauth.RoleMapping{
+		RoleArn:  p.AWSAuthRole.RoleArn,
+		Username: p.AWSAuthRole.Username,
+		Groups:   p.AWSAuthRole.Groups,
+	}); err != nil {
+		return fmt.Errorf("failed to update aws-auth ConfigMap: %w", err)
+	}
+
+	actionConfig, err := helm.NewActionConfig(configFlags, p.HelmRelease.Namespace)
+	if err != nil {
+		return err
+	}
+	actionConfig.RegistryClient = rc
+	if err = helm.CreateOrUpgrade(ctx, actionConfig, p.HelmRelease.Name, p.HelmRelease.Namespace, p.HelmRelease.Chart, p.HelmRelease.Version, p.HelmRelease.Values); err != nil {
+		return fmt.Errorf("failed to create or update Helm release: %w", err)
+	}
+
+	for _, nc := range p.EC2NodeClasses {
+		if err = commonk8s.CreateOrUpdate(ctx, cli.K8sClient, nc.DeepCopy()); err != nil {
+			return fmt.Errorf("failed to create or update EC2NodeClass %s: %w", nc.Name, err)
+		}
+	}
+	for _, np := range p.NodePools {
+		if err = commonk8s.CreateOrUpdate(ctx, cli.K8sClient, np.DeepCopy()); err != nil {
+			return fmt.Errorf("failed to create or update NodePool %s: %w", np.Name, err)
+		}
+	}
+
+	if err = recordClusterInfo(ctx, cli, p.ClusterName, p.HelmRelease.Namespace); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	return nil
+}
+
+// setOIDCProviderArn replaces the OIDC provider ARN derived by BuildPlan with
+// the one of the created provider.
+func (p *Plan) setOIDCProviderArn(arn string) {
+	p.OIDCProviderArn = arn
+	for _, stack := range p.Stacks {
+		if _, ok := stack.Parameters["OIDCProviderArn"]; ok {
+			stack.Parameters["OIDCProviderArn"] = arn
+		}
+	}
+}
+
+// karpenterRoleArn reads the ARN of the Karpenter IRSA role from the outputs
+// of the fargate stack, which only exist once it is created.
+func karpenterRoleArn(ctx context.Context, cli *clients.Clients, stackName string) (string, error) {
+	stack, err := aws.GetStack(ctx, cli.CloudFormation, stackName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stack outputs: %w", err)
+	}
+	if stack == nil {
+		return "", fmt.Errorf("stack %s disappeared right after successful create/update", stackName)
+	}
+	irsaRoleArn := stack.OutputMap()["KarpenterRoleArn"]
+	if irsaRoleArn == "" {
+		return "", fmt.Errorf("stack %s did not produce a KarpenterRoleArn output", stackName)
+	}
+	return irsaRoleArn, nil
+}
+
+// Files renders the plan: a plan.yaml summary, the CloudFormation templates
+// and their parameters in the `aws cloudformation --parameters` format, the
+// Helm values and the Karpenter resource manifests.
+func (p *Plan) Files() ([]PlanFile, error) {
+	summary, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	files := []PlanFile{{Path: planFile, Content: summary}}
+
+	for _, stack := range p.Stacks {
+		type parameter struct {
+			ParameterKey   string
+			ParameterValue string
+		}
+		params := lo.Map(slices.Sorted(maps.Keys(stack.Parameters)), func(k string, _ int) parameter {
+			return parameter{ParameterKey: k, ParameterValue: stack.Parameters[k]}
+		})
+		paramsJSON, err := json.MarshalIndent(params, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal parameters of stack %s: %w", stack.Name, err)
+		}
+		files = append(files,
+			PlanFile{Path: stack.TemplateFile, Content: []byte(stack.TemplateBody)},
+			PlanFile{Path: stack.ParametersFile, Content: append(paramsJSON, '\n')},
+		)
+	}
+
+	values, err := yaml.Marshal(p.HelmRelease.Values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Helm values: %w", err)
+	}
+	files = append(files, PlanFile{Path: p.HelmRelease.ValuesFile, Content: values})
+
+	if p.KarpenterResources != "" {
+		var manifests []string
+		for _, nc := range p.EC2NodeClasses {
+			out, err := yaml.Marshal(nc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal EC2NodeClass %s: %w", nc.Name, err)
+			}
+			manifests = append(manifests, string(out))
+		}
+		for _, np := range p.NodePools {
+			out, err := yaml.Marshal(np)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal NodePool %s: %w", np.Name, err)
+			}
+			manifests = append(manifests, string(out))
+		}
+		files = append(files, PlanFile{Path: p.KarpenterResources, Content: []byte(strings.Join(manifests, "---\n"))})
+	}
+
+	return files, nil
+}
+
+// Write writes the files of the plan to dir. dir must not exist or be empty,
+// so that no file of a previous plan is left over.
+func (p *Plan) Write(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read output directory %s: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("output directory %s is not empty", dir)
+	}
+
+	files, err := p.Files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		log.Printf("Wrote %s.", path)
+	}
+	return nil
+}
+
+// Print writes the files of the plan to w, each one preceded by a
+// `# Source: <path>` header.
+func (p *Plan) Print(w io.Writer) error {
+	files, err := p.Files()
+	if err != nil {
+		return err
+	}
+	for i, f := range files {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# Source: %s\n", f.Path)
+		if _, err := w.Write(f.Content); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(string(f.Content), "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
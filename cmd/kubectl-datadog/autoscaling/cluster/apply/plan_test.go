@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	karpawsv1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/karpenter"
+)
+
+func testPlan() *Plan {
+	nps := karpenter.NewNodePoolsSet()
+	nps.Add(karpenter.NodePoolsSetAddParams{
+		AMIFamily:    "AL2023",
+		SubnetIDs:    []string{"subnet-a"},
+		Architecture: "amd64",
+	})
+	nc := nps.GetEC2NodeClasses()[0]
+	np := nps.GetNodePools()[0]
+
+	return &Plan{
+		ClusterName: "my-cluster",
+		InstallMode: InstallModeExistingNodes,
+		Stacks: []StackPlan{
+			newStackPlan(KarpenterStackName("my-cluster"), KarpenterCfn, karpenterStackParameters("my-cluster"), nil, nil),
+		},
+		HelmRelease: HelmReleasePlan{
+			Name:       karpenterReleaseName,
+			Namespace:  "dd-karpenter",
+			Chart:      karpenterOCIRegistry,
+			Version:    "1.9.0",
+			ValuesFile: helmValuesFile,
+			Values:     karpenterHelmValues("my-cluster", InstallModeExistingNodes, ""),
+		},
+		KarpenterResources:   karpenterResources,
+		EC2NodeClasses:       []*karpawsv1.EC2NodeClass{karpenter.NewEC2NodeClass("my-cluster", nc)},
+		NodePools:            []*karpv1.NodePool{karpenter.NewNodePool(np)},
+		ClusterInfoConfigMap: "dd-karpenter/dd-cluster-info",
+	}
+}
+
+func TestPlanFiles(t *testing.T) {
+	files, err := testPlan().Files()
+	require.NoError(t, err)
+
+	byPath := map[string][]byte{}
+	for _, f := range files {
+		byPath[f.Path] = f.Content
+	}
+	assert.Len(t, byPath, len(files), "file paths must be unique")
+
+	var summary map[string]any
+	require.NoError(t, yaml.Unmarshal(byPath["plan.yaml"], &summary))
+	assert.Equal(t, "my-cluster", summary["clusterName"])
+	assert.Equal(t, "manifests/karpenter-resources.yaml", summary["karpenterResources"])
+	assert.Equal(t, "dd-karpenter/dd-cluster-info", summary["clusterInfoConfigMap"])
+	assert.Equal(t, "1.9.0", summary["helmRelease"].(map[string]any)["version"])
+	stacks, ok := summary["stacks"].([]any)
+	require.True(t, ok)
+	require.Len(t, stacks, 1)
+	stack := stacks[0].(map[string]any)
+	assert.Equal(t, false, stack["exists"])
+	assert.Equal(t, "cloudformation/dd-karpenter-my-cluster-karpenter.yaml", stack["templateFile"])
+	assert.Equal(t, "kubectl-datadog", stack["tags"].(map[string]any)["managed-by"])
+
+	assert.Equal(t, KarpenterCfn, string(byPath["cloudformation/dd-karpenter-my-cluster-karpenter.yaml"]))
+
+	var params []map[string]string
+	require.NoError(t, json.Unmarshal(byPath["cloudformation/dd-karpenter-my-cluster-karpenter.parameters.json"], &params))
+	assert.Equal(t, []map[string]string{{"ParameterKey": "ClusterName", "ParameterValue": "my-cluster"}}, params)
+
+	var values map[string]any
+	require.NoError(t, yaml.Unmarshal(byPath["helm/karpenter-values.yaml"], &values))
+	assert.Equal(t, "my-cluster", values["settings"].(map[string]any)["clusterName"])
+
+	manifests := string(byPath["manifests/karpenter-resources.yaml"])
+	assert.Contains(t, manifests, "kind: EC2NodeClass")
+	assert.Contains(t, manifests, "kind: NodePool")
+	assert.Contains(t, manifests, "\n---\n")
+}
+
+func TestPlanFilesWithoutKarpenterResources(t *testing.T) {
+	plan := testPlan()
+	plan.KarpenterResources = ""
+	plan.EC2NodeClasses = nil
+	plan.NodePools = nil
+
+	files, err := plan.Files()
+	require.NoError(t, err)
+	for _, f := range files {
+		assert.NotEqual(t, karpenterResources, f.Path)
+	}
+}
+
+func TestPlanWrite(t *testing.T) {
+	t.Run("writes every file", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "plan")
+		require.NoError(t, testPlan().Write(dir))
+
+		for _, path := range []string{
+			"plan.yaml",
+			"cloudformation/dd-karpenter-my-cluster-karpenter.yaml",
+			"cloudformation/dd-karpenter-my-cluster-karpenter.parameters.json",
+			"helm/karpenter-values.yaml",
+			"manifests/karpenter-resources.yaml",
+		} {
+			assert.FileExists(t, filepath.Join(dir, path))
+		}
+	})
+
+	t.Run("refuses a non-empty directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.yaml"), nil, 0o644))
+
+		err := testPlan().Write(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not empty")
+	})
+}
+
+func TestPlanPrint(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, testPlan().Print(&out))
+
+	assert.Contains(t, out.String(), "# Source: plan.yaml\n")
+	assert.Contains(t, out.String(), "# Source: helm/karpenter-values.yaml\n")
+	assert.Contains(t, out.String(), "# Source: manifests/karpenter-resources.yaml\n")
+}
+
+func TestPlanSetOIDCProviderArn(t *testing.T) {
+	const derived = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/ABC"
+	const created = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/abc"
+	opts := RunOptions{ClusterName: "my-cluster", KarpenterNamespace: "dd-karpenter"}
+	plan := &Plan{
+		OIDCProviderArn:    derived,
+		CreateOIDCProvider: true,
+		Stacks: []StackPlan{
+			newStackPlan(KarpenterStackName("my-cluster"), KarpenterCfn, karpenterStackParameters("my-cluster"), nil, nil),
+			newStackPlan(DDKarpenterStackName("my-cluster"), DdKarpenterFargateCfn, fargateStackParameters(opts, derived, "https://oidc.eks.us-east-1.amazonaws.com/id/ABC", []string{"subnet-a"}, true), nil, nil),
+		},
+	}
+
+	plan.setOIDCProviderArn(created)
+
+	assert.Equal(t, created, plan.OIDCProviderArn)
+	assert.NotContains(t, plan.Stacks[0].Parameters, "OIDCProviderArn")
+	assert.Equal(t, created, plan.Stacks[1].Parameters["OIDCProviderArn"])
+}
+
+func TestRunOptionsPlanOnly(t *testing.T) {
+	assert.False(t, RunOptions{}.planOnly())
+	assert.True(t, RunOptions{DryRun: true}.planOnly())
+	assert.True(t, RunOptions{OutputDir: "out"}.planOnly())
+}
@@ -15,13 +15,12 @@ import (
 	"strings"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fatih/color"
 	"github.com/pkg/browser"
 	"helm.sh/helm/v3/pkg/registry"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -29,11 +28,11 @@ import (
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/aws"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/awsauth"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clients"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterinfo"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/display"
 	commoneks "github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/eks"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/eksautomode"
-	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/helm"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/karpenter"
 	"github.com/DataDog/datadog-operator/pkg/plugin/common"
 	"github.com/DataDog/datadog-operator/pkg/version"
@@ -43,6 +42,7 @@ import (
 
 const (
 	karpenterOCIRegistry = "oci://public.ecr.aws/karpenter/karpenter"
+	karpenterReleaseName = "karpenter"
 )
 
 var (
@@ -97,6 +97,11 @@ type RunOptions struct {
 	CreateKarpenterResources CreateKarpenterResources
 	InferenceMethod          InferenceMethod
 	Debug                    bool
+	// DryRun prints the changes Run would make instead of making them.
+	DryRun bool
+	// OutputDir, when set, is the directory where Run writes the changes it
+	// would make instead of making them.
+	OutputDir string
 	// ActionLabel prefixes the opening "<label> Karpenter on cluster <c>."
 	// box. install passes "Installing", update passes "Updating".
 	ActionLabel string
@@ -135,7 +140,9 @@ func DetectedInstallMode(stack *aws.Stack) InstallMode {
 
 // Run converges the cluster towards the Karpenter deployment described by
 // opts. It is idempotent: calling it twice with the same opts is a no-op on
-// the second run.
+// the second run. With opts.DryRun or opts.OutputDir, it only reports the
+// changes it would make. The changes are computed by BuildPlan and made by
+// Plan.Apply.
 func Run(ctx context.Context, streams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, clientset *kubernetes.Clientset, opts RunOptions) error {
 	log.SetOutput(streams.ErrOut)
 	ctrl.SetLogger(zap.New(zap.UseDevMode(false), zap.WriteTo(streams.ErrOut)))
@@ -154,7 +161,12 @@ func Run(ctx context.Context, streams genericclioptions.IOStreams, configFlags *
 		return displayForeignKarpenterMessage(streams, opts.ClusterName, k)
 	}
 
-	display.PrintBox(streams.Out, opts.ActionLabel+" Karpenter on cluster "+opts.ClusterName+".")
+	if opts.planOnly() {
+		// Keep stdout for the plan itself.
+		display.PrintBox(streams.ErrOut, opts.ActionLabel+" Karpenter on cluster "+opts.ClusterName+" (dry run).")
+	} else {
+		display.PrintBox(streams.Out, opts.ActionLabel+" Karpenter on cluster "+opts.ClusterName+".")
+	}
 
 	cli, err := clients.Build(ctx, configFlags, clientset)
 	if err != nil {
@@ -165,132 +177,87 @@ func Run(ctx context.Context, streams genericclioptions.IOStreams, configFlags *
 		return err
 	}
 
-	irsaRoleArn, err := createCloudFormationStacks(ctx, cli, opts)
+	rc, err := registry.NewClient(
+		registry.ClientOptDebug(opts.Debug),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(log.Writer()),
+	)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create registry client: %w", err)
 	}
 
-	if err = updateAwsAuthConfigMap(ctx, cli, opts.ClusterName); err != nil {
+	plan, err := BuildPlan(ctx, cli, rc, opts)
+	if err != nil {
 		return err
 	}
 
-	if err = installHelmChart(ctx, configFlags, opts, irsaRoleArn); err != nil {
-		return err
+	if opts.planOnly() {
+		return runPlan(streams, plan, opts)
 	}
 
-	if err = createNodePoolResources(ctx, streams, cli, opts); err != nil {
-		return err
+	if plan.clusterAutoscaler != nil && len(plan.NodePools) > 0 {
+		displayClusterAutoscalerMigration(streams.Out, plan.clusterAutoscaler, plan.inferredNodePools)
 	}
 
-	if err = recordClusterInfo(ctx, cli, clientset.Discovery(), opts.ClusterName, opts.KarpenterNamespace); err != nil {
-		log.Printf("Warning: %v", err)
+	if opts.Debug && plan.KarpenterResources != "" {
+		fmt.Fprintf(streams.Out, "Creating the following resources:\n %s\n", spew.Sdump(plan.EC2NodeClasses, plan.NodePools))
+	}
+
+	if err = plan.Apply(ctx, configFlags, cli, rc); err != nil {
+		return err
 	}
 
 	return displaySuccessMessage(streams, opts.ClusterName, opts.CreateKarpenterResources)
 }
 
-// createCloudFormationStacks creates (or updates) the CFN stacks required for
-// the selected install mode. Returns the IRSA role ARN when the mode is
-// fargate; empty string otherwise.
-func createCloudFormationStacks(ctx context.Context, cli *clients.Clients, opts RunOptions) (string, error) {
-	// The first stack (karpenter.yaml) is mode-independent — its resources
-	// (KarpenterNodeRole, the Karpenter controller managed policies, SQS,
-	// EventBridge) are identical in both modes, so no guardrail or install-mode
-	// tag is needed.
-	karpenterStackName := KarpenterStackName(opts.ClusterName)
-	if err := aws.CreateOrUpdateStack(ctx, cli.CloudFormation, karpenterStackName, KarpenterCfn, map[string]string{
-		"ClusterName": opts.ClusterName,
-	}, nil); err != nil {
-		return "", fmt.Errorf("failed to create or update Cloud Formation stack: %w", err)
-	}
-
-	describeOut, err := cli.EKS.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: awssdk.String(opts.ClusterName)})
-	if err != nil {
-		return "", fmt.Errorf("failed to describe cluster %s: %w", opts.ClusterName, err)
+// karpenterStackParameters returns the parameters of the KarpenterStackName
+// stack.
+func karpenterStackParameters(clusterName string) map[string]string {
+	return map[string]string{
+		"ClusterName": clusterName,
 	}
-	cluster := describeOut.Cluster
-	supportsAPIAuth := commoneks.SupportsAPIAuthenticationMode(cluster)
+}
 
-	ddStackName := DDKarpenterStackName(opts.ClusterName)
-	ddStack, err := aws.GetStack(ctx, cli.CloudFormation, ddStackName)
-	if err != nil {
-		return "", err
+// existingNodesStackParameters returns the parameters of the DDKarpenterStackName
+// stack in existing-nodes mode.
+func existingNodesStackParameters(opts RunOptions, deployPodIdentityAddon, deployNodeAccessEntry bool) map[string]string {
+	return map[string]string{
+		"ClusterName":            opts.ClusterName,
+		"KarpenterNamespace":     opts.KarpenterNamespace,
+		"DeployPodIdentityAddon": strconv.FormatBool(deployPodIdentityAddon),
+		"DeployNodeAccessEntry":  strconv.FormatBool(deployNodeAccessEntry),
 	}
-	if err := checkInstallModeTag(ddStack, opts.InstallMode); err != nil {
-		return "", err
-	}
-	modeTags := map[string]string{InstallModeTagKey: string(opts.InstallMode)}
-
-	switch opts.InstallMode {
-	case InstallModeExistingNodes:
-		isUnmanagedEKSPIAInstalled, err := commoneks.IsThereUnmanagedEKSPodIdentityAgentInstalled(ctx, cli.EKS, opts.ClusterName)
-		if err != nil {
-			return "", fmt.Errorf("failed to check if EKS pod identity agent is installed: %w", err)
-		}
-		if err := aws.CreateOrUpdateStackWithExisting(ctx, cli.CloudFormation, ddStackName, DdKarpenterCfn, map[string]string{
-			"ClusterName":            opts.ClusterName,
-			"KarpenterNamespace":     opts.KarpenterNamespace,
-			"DeployPodIdentityAddon": strconv.FormatBool(!isUnmanagedEKSPIAInstalled),
-			"DeployNodeAccessEntry":  strconv.FormatBool(supportsAPIAuth),
-		}, modeTags, ddStack); err != nil {
-			return "", fmt.Errorf("failed to create or update Cloud Formation stack: %w", err)
-		}
-		return "", nil
-
-	case InstallModeFargate:
-		issuerURL, err := commoneks.GetClusterOIDCIssuerURL(cluster)
-		if err != nil {
-			return "", fmt.Errorf("failed to get cluster OIDC issuer URL: %w", err)
-		}
-		oidcArn, err := commoneks.EnsureOIDCProvider(ctx, cli.IAM, issuerURL)
-		if err != nil {
-			return "", fmt.Errorf("failed to ensure OIDC provider: %w", err)
-		}
-
-		subnets := opts.FargateSubnets
-		if len(subnets) == 0 {
-			subnets, err = commoneks.GetClusterPrivateSubnets(ctx, cli.EC2, cluster)
-			if err != nil {
-				return "", fmt.Errorf("failed to discover private subnets: %w", err)
-			}
-		}
-		// Sort so that different orderings of the same subnet set produce the
-		// same CloudFormation parameter value, making reruns idempotent and
-		// the immutability check order-independent.
-		slices.Sort(subnets)
-
-		if err = checkFargateStackImmutability(ddStack, opts.KarpenterNamespace, subnets); err != nil {
-			return "", err
-		}
+}
 
-		if err = aws.CreateOrUpdateStackWithExisting(ctx, cli.CloudFormation, ddStackName, DdKarpenterFargateCfn, map[string]string{
-			"ClusterName":           opts.ClusterName,
-			"KarpenterNamespace":    opts.KarpenterNamespace,
-			"OIDCProviderArn":       oidcArn,
-			"OIDCProviderURL":       strings.TrimPrefix(issuerURL, "https://"),
-			"FargateSubnets":        strings.Join(subnets, ","),
-			"DeployNodeAccessEntry": strconv.FormatBool(supportsAPIAuth),
-		}, modeTags, ddStack); err != nil {
-			return "", fmt.Errorf("failed to create or update Cloud Formation stack: %w", err)
-		}
+// fargateStackParameters returns the parameters of the DDKarpenterStackName
+// stack in fargate mode.
+func fargateStackParameters(opts RunOptions, oidcProviderArn, issuerURL string, subnets []string, deployNodeAccessEntry bool) map[string]string {
+	return map[string]string{
+		"ClusterName":           opts.ClusterName,
+		"KarpenterNamespace":    opts.KarpenterNamespace,
+		"OIDCProviderArn":       oidcProviderArn,
+		"OIDCProviderURL":       strings.TrimPrefix(issuerURL, "https://"),
+		"FargateSubnets":        strings.Join(subnets, ","),
+		"DeployNodeAccessEntry": strconv.FormatBool(deployNodeAccessEntry),
+	}
+}
 
-		// Re-read the stack to pick up the outputs (not present pre-create).
-		updated, err := aws.GetStack(ctx, cli.CloudFormation, ddStackName)
+// fargateSubnets returns the subnets of the Fargate profile: the
+// --fargate-subnets ones if any, the private subnets of the cluster otherwise.
+func fargateSubnets(ctx context.Context, cli *clients.Clients, opts RunOptions, cluster *ekstypes.Cluster) ([]string, error) {
+	subnets := slices.Clone(opts.FargateSubnets)
+	if len(subnets) == 0 {
+		var err error
+		subnets, err = commoneks.GetClusterPrivateSubnets(ctx, cli.EC2, cluster)
 		if err != nil {
-			return "", fmt.Errorf("failed to read stack outputs: %w", err)
-		}
-		if updated == nil {
-			return "", fmt.Errorf("stack %s disappeared right after successful create/update", ddStackName)
-		}
-		irsaRoleArn := updated.OutputMap()["KarpenterRoleArn"]
-		if irsaRoleArn == "" {
-			return "", fmt.Errorf("stack %s did not produce a KarpenterRoleArn output", ddStackName)
+			return nil, fmt.Errorf("failed to discover private subnets: %w", err)
 		}
-		return irsaRoleArn, nil
-
-	default:
-		return "", fmt.Errorf("unsupported install mode %q", opts.InstallMode)
 	}
+	// Sort so that different orderings of the same subnet set produce the
+	// same CloudFormation parameter value, making reruns idempotent and
+	// the immutability check order-independent.
+	slices.Sort(subnets)
+	return subnets, nil
 }
 
 // checkInstallModeTag verifies that an existing CFN stack's install-mode tag
@@ -328,53 +295,14 @@ func checkFargateStackImmutability(stack *aws.Stack, namespace string, subnets [
 	return nil
 }
 
-func updateAwsAuthConfigMap(ctx context.Context, cli *clients.Clients, clusterName string) error {
-	awsAuthConfigMapPresent, err := awsauth.IsConfigMapPresent(ctx, cli.K8sClientset)
-	if err != nil {
-		return fmt.Errorf("failed to check if aws-auth ConfigMap is present: %w", err)
-	}
-
-	if !awsAuthConfigMapPresent {
-		log.Println("aws-auth ConfigMap not present, skipping role addition.")
-		return nil
-	}
-
-	accountID, err := clients.GetAWSAccountID(ctx, cli)
-	if err != nil {
-		return err
-	}
-
-	// Add role mapping in the `aws-auth` ConfigMap
-	if err = awsauth.EnsureRole(ctx, cli.K8sClientset, awsauth.RoleMapping{
+// karpenterNodeRoleMapping returns the `aws-auth` role mapping letting the
+// nodes started by Karpenter join the cluster.
+func karpenterNodeRoleMapping(accountID, clusterName string) awsauth.RoleMapping {
+	return awsauth.RoleMapping{
 		RoleArn:  "arn:aws:iam::" + accountID + ":role/KarpenterNodeRole-" + clusterName,
 		Username: "system:node:{{EC2PrivateDNSName}}",
 		Groups:   []string{"system:bootstrappers", "system:nodes"},
-	}); err != nil {
-		return fmt.Errorf("failed to update aws-auth ConfigMap: %w", err)
 	}
-
-	return nil
-}
-
-func installHelmChart(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts RunOptions, irsaRoleArn string) error {
-	actionConfig, err := helm.NewActionConfig(configFlags, opts.KarpenterNamespace)
-	if err != nil {
-		return err
-	}
-
-	if actionConfig.RegistryClient, err = registry.NewClient(
-		registry.ClientOptDebug(opts.Debug),
-		registry.ClientOptEnableCache(true),
-		registry.ClientOptWriter(log.Writer()),
-	); err != nil {
-		return fmt.Errorf("failed to create registry client: %w", err)
-	}
-
-	if err = helm.CreateOrUpgrade(ctx, actionConfig, "karpenter", opts.KarpenterNamespace, karpenterOCIRegistry, opts.KarpenterVersion, karpenterHelmValues(opts.ClusterName, opts.InstallMode, irsaRoleArn)); err != nil {
-		return fmt.Errorf("failed to create or update Helm release: %w", err)
-	}
-
-	return nil
 }
 
 // karpenterHelmValues returns the Helm values for the Karpenter chart.
@@ -449,11 +377,11 @@ func karpenterHelmValues(clusterName string, mode InstallMode, irsaRoleArn strin
 	return values
 }
 
-func createNodePoolResources(ctx context.Context, streams genericclioptions.IOStreams, cli *clients.Clients, opts RunOptions) error {
-	if opts.CreateKarpenterResources == CreateKarpenterResourcesNone {
-		return nil
-	}
-
+// inferNodePoolsSet infers the EC2NodeClasses and NodePools from the existing
+// nodes or node groups of the cluster, depending on opts.InferenceMethod.
+// When cluster-autoscaler runs on the cluster, its scaling policy is carried
+// over to the NodePools and returned along with them.
+func inferNodePoolsSet(ctx context.Context, cli *clients.Clients, opts RunOptions) (*karpenter.NodePoolsSet, *clusterautoscaler.Config, error) {
 	var nodePoolsSet *karpenter.NodePoolsSet
 	var err error
 
@@ -461,49 +389,40 @@ func createNodePoolResources(ctx context.Context, streams genericclioptions.IOSt
 	case InferenceMethodNodes:
 		nodePoolsSet, err = karpenter.GetNodesProperties(ctx, cli.K8sClientset, cli.EC2)
 		if err != nil {
-			return fmt.Errorf("failed to gather nodes properties: %w", err)
+			return nil, nil, fmt.Errorf("failed to gather nodes properties: %w", err)
 		}
 
 	case InferenceMethodNodeGroups:
 		nodePoolsSet, err = karpenter.GetNodeGroupsProperties(ctx, cli.EKS, cli.EC2, opts.ClusterName)
 		if err != nil {
-			return fmt.Errorf("failed to gather node groups properties: %w", err)
+			return nil, nil, fmt.Errorf("failed to gather node groups properties: %w", err)
 		}
-	}
 
-	if opts.Debug {
-		fmt.Fprintf(streams.Out, "Creating the following resources:\n %s\n", spew.Sdump(nodePoolsSet))
+	default:
+		return nil, nil, fmt.Errorf("unsupported inference method %q", opts.InferenceMethod)
 	}
 
-	if opts.CreateKarpenterResources == CreateKarpenterResourcesEC2NodeClass || opts.CreateKarpenterResources == CreateKarpenterResourcesAll {
-		for _, nc := range nodePoolsSet.GetEC2NodeClasses() {
-			if err = karpenter.CreateOrUpdateEC2NodeClass(ctx, cli.K8sClient, opts.ClusterName, nc); err != nil {
-				return fmt.Errorf("failed to create or update EC2NodeClass %s: %w", nc.GetName(), err)
-			}
-		}
+	caConfig, err := clusterautoscaler.FindConfig(ctx, cli.K8sClientset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the cluster-autoscaler configuration: %w", err)
 	}
-
-	if opts.CreateKarpenterResources == CreateKarpenterResourcesAll {
-		for _, np := range nodePoolsSet.GetNodePools() {
-			if err = karpenter.CreateOrUpdateNodePool(ctx, cli.K8sClient, np); err != nil {
-				return fmt.Errorf("failed to create or update NodePool %s: %w", np.GetName(), err)
-			}
-		}
+	if caConfig != nil {
+		nodePoolsSet.ApplyClusterAutoscalerConfig(caConfig)
 	}
 
-	return nil
+	return nodePoolsSet, caConfig, nil
 }
 
 // recordClusterInfo classifies every node by its current management method
 // and writes the snapshot to a ConfigMap. The information is consumed by the
 // follow-up migration step.
-func recordClusterInfo(ctx context.Context, cli *clients.Clients, discoveryClient discovery.DiscoveryInterface, clusterName, namespace string) error {
+func recordClusterInfo(ctx context.Context, cli *clients.Clients, clusterName, namespace string) error {
 	info, err := clusterinfo.Classify(ctx, clusterinfo.ClassifyInput{
 		K8sClient:   cli.K8sClientset,
 		CtrlClient:  cli.K8sClient,
 		Autoscaling: cli.Autoscaling,
 		EKS:         cli.EKS,
-		Discovery:   discoveryClient,
+		Discovery:   cli.K8sClientset.Discovery(),
 		ClusterName: clusterName,
 	})
 	if err != nil {
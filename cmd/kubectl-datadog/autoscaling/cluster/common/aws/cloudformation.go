@@ -59,15 +59,20 @@ func createOrUpdateStack(ctx context.Context, client *cloudformation.Client, sta
 	}
 }
 
-// buildTags returns the base tags (managed-by, version) plus any extra tags
+// StackTags returns the base tags (managed-by, version) plus any extra tags
 // passed by the caller. Base tags are passed last to lo.Assign so they
 // override any extra entry sharing the same key.
-func buildTags(extraTags map[string]string) []types.Tag {
+func StackTags(extraTags map[string]string) map[string]string {
 	base := map[string]string{
 		ManagedByTag: ManagedByTagValue,
 		"version":    version.GetVersion(),
 	}
-	return lo.MapToSlice(lo.Assign(extraTags, base), func(k, v string) types.Tag {
+	return lo.Assign(extraTags, base)
+}
+
+// buildTags returns StackTags as CloudFormation tags.
+func buildTags(extraTags map[string]string) []types.Tag {
+	return lo.MapToSlice(StackTags(extraTags), func(k, v string) types.Tag {
 		return types.Tag{Key: aws.String(k), Value: aws.String(v)}
 	})
 }
@@ -0,0 +1,264 @@
+package clusterautoscaler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	commonk8s "github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/k8s"
+)
+
+const (
+	// PriorityExpanderConfigMapName is the ConfigMap read by the priority
+	// expander, in the namespace of the cluster-autoscaler.
+	PriorityExpanderConfigMapName = "cluster-autoscaler-priority-expander"
+	priorityExpanderConfigMapKey  = "priorities"
+
+	// PriorityExpander is the name of the priority expander.
+	PriorityExpander = "priority"
+)
+
+// NodeGroup is a node group statically configured with `--nodes`.
+type NodeGroup struct {
+	Name    string
+	MinSize int32
+	MaxSize int32
+}
+
+// Config is the scaling policy configured on a cluster-autoscaler
+// Deployment. Optional settings are nil when the corresponding flag is not
+// set, so that only an explicit intent is carried over to Karpenter.
+type Config struct {
+	Namespace string
+	Name      string
+	// Expanders are the `--expander` strategies, in order.
+	Expanders                []string
+	BalanceSimilarNodeGroups bool
+	// NodeGroups are the node groups configured with `--nodes`.
+	NodeGroups              []NodeGroup
+	ScaleDownEnabled        *bool
+	ScaleDownUnneededTime   *time.Duration
+	MaxScaleDownParallelism *int
+	// Priorities are the node group name regular expressions of the priority
+	// expander, by priority. Nil when the priority expander is not used.
+	Priorities map[int][]*regexp.Regexp
+}
+
+// FindConfig returns the scaling policy of the cluster-autoscaler running on
+// the cluster, or nil if none. The priority expander ConfigMap is only read
+// when the priority expander is used.
+func FindConfig(ctx context.Context, clientset kubernetes.Interface) (*Config, error) {
+	dep, err := commonk8s.FindFirstDeployment(ctx, clientset, matchesDeployment)
+	if err != nil || dep == nil {
+		return nil, err
+	}
+
+	cfg, err := ParseArgs(deploymentArgs(dep))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the flags of Deployment %s/%s: %w", dep.Namespace, dep.Name, err)
+	}
+	cfg.Namespace = dep.Namespace
+	cfg.Name = dep.Name
+
+	if !slices.Contains(cfg.Expanders, PriorityExpander) {
+		return cfg, nil
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(dep.Namespace).Get(ctx, PriorityExpanderConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", dep.Namespace, PriorityExpanderConfigMapName, err)
+	}
+	if cfg.Priorities, err = parsePriorities(cm.Data[priorityExpanderConfigMapKey]); err != nil {
+		return nil, fmt.Errorf("failed to parse ConfigMap %s/%s: %w", dep.Namespace, PriorityExpanderConfigMapName, err)
+	}
+	return cfg, nil
+}
+
+// deploymentArgs returns the command line of the cluster-autoscaler
+// container, or of the first container when none matches.
+func deploymentArgs(d *appsv1.Deployment) []string {
+	containers := d.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return nil
+	}
+	i := max(slices.IndexFunc(containers, matchesContainer), 0)
+	return append(slices.Clone(containers[i].Command), containers[i].Args...)
+}
+
+// ParseArgs parses the scaling policy out of the cluster-autoscaler command
+// line. Flags may be written `-flag` or `--flag`, with their value after `=`
+// or as the next argument. Unknown flags and positional arguments are ignored.
+func ParseArgs(args []string) (*Config, error) {
+	cfg := &Config{Expanders: []string{"random"}}
+
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		// Non-boolean flags may take their value from the next argument.
+		next := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "expander":
+			v, err := next()
+			if err != nil {
+				return nil, err
+			}
+			cfg.Expanders = strings.Split(v, ",")
+
+		case "balance-similar-node-groups":
+			b, err := parseBool(name, value, hasValue)
+			if err != nil {
+				return nil, err
+			}
+			cfg.BalanceSimilarNodeGroups = b
+
+		case "nodes":
+			v, err := next()
+			if err != nil {
+				return nil, err
+			}
+			ng, err := parseNodeGroup(v)
+			if err != nil {
+				return nil, err
+			}
+			cfg.NodeGroups = append(cfg.NodeGroups, ng)
+
+		case "scale-down-enabled":
+			b, err := parseBool(name, value, hasValue)
+			if err != nil {
+				return nil, err
+			}
+			cfg.ScaleDownEnabled = &b
+
+		case "scale-down-unneeded-time":
+			v, err := next()
+			if err != nil {
+				return nil, err
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --%s value %q: %w", name, v, err)
+			}
+			cfg.ScaleDownUnneededTime = &d
+
+		case "max-scale-down-parallelism":
+			v, err := next()
+			if err != nil {
+				return nil, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --%s value %q: %w", name, v, err)
+			}
+			cfg.MaxScaleDownParallelism = &n
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseBool(name, value string, hasValue bool) (bool, error) {
+	if !hasValue {
+		return true, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid --%s value %q: %w", name, value, err)
+	}
+	return b, nil
+}
+
+// parseNodeGroup parses a `--nodes=<min>:<max>:<name>` value.
+func parseNodeGroup(value string) (NodeGroup, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return NodeGroup{}, fmt.Errorf("invalid --nodes value %q: expected <min>:<max>:<name>", value)
+	}
+	minSize, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return NodeGroup{}, fmt.Errorf("invalid minimum size in --nodes value %q: %w", value, err)
+	}
+	maxSize, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return NodeGroup{}, fmt.Errorf("invalid maximum size in --nodes value %q: %w", value, err)
+	}
+	return NodeGroup{Name: parts[2], MinSize: int32(minSize), MaxSize: int32(maxSize)}, nil
+}
+
+// parsePriorities parses the `priorities` key of the priority expander
+// ConfigMap: a YAML map of priorities to lists of node group name regular
+// expressions, compiled once here.
+func parsePriorities(data string) (map[int][]*regexp.Regexp, error) {
+	patternsByPriority := map[int][]string{}
+	if err := yaml.Unmarshal([]byte(data), &patternsByPriority); err != nil {
+		return nil, err
+	}
+	priorities := make(map[int][]*regexp.Regexp, len(patternsByPriority))
+	for priority, patterns := range patternsByPriority {
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q of priority %d: %w", pattern, priority, err)
+			}
+			priorities[priority] = append(priorities[priority], re)
+		}
+	}
+	return priorities, nil
+}
+
+// NodeGroup returns the node group configured with `--nodes` for the given
+// name, if any.
+func (c *Config) NodeGroup(name string) (NodeGroup, bool) {
+	i := slices.IndexFunc(c.NodeGroups, func(ng NodeGroup) bool { return ng.Name == name })
+	if i < 0 {
+		return NodeGroup{}, false
+	}
+	return c.NodeGroups[i], true
+}
+
+// Priority returns the priority the priority expander gives to the node group
+// with the given name: the highest priority with a regular expression
+// matching the name. Returns false when the priority expander is not used or
+// no regular expression matches.
+func (c *Config) Priority(nodeGroup string) (int, bool) {
+	if !slices.Contains(c.Expanders, PriorityExpander) {
+		return 0, false
+	}
+	found := false
+	var best int
+	for priority, patterns := range c.Priorities {
+		if found && priority <= best {
+			continue
+		}
+		if slices.ContainsFunc(patterns, func(pattern *regexp.Regexp) bool {
+			return pattern.MatchString(nodeGroup)
+		}) {
+			best, found = priority, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,137 @@
+package clusterautoscaler
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		want          *Config
+		errorContains string
+	}{
+		{
+			name: "defaults",
+			args: []string{"./cluster-autoscaler", "--v=4"},
+			want: &Config{Expanders: []string{"random"}},
+		},
+		{
+			name: "scaling policy",
+			args: []string{
+				"./cluster-autoscaler",
+				"--cloud-provider=aws",
+				"--expander=priority,least-waste",
+				"--balance-similar-node-groups",
+				"--nodes=1:10:asg-a",
+				"--nodes", "0:5:asg-b",
+				"-scale-down-enabled=false",
+				"--scale-down-unneeded-time", "15m",
+				"--max-scale-down-parallelism=3",
+			},
+			want: &Config{
+				Expanders:                []string{"priority", "least-waste"},
+				BalanceSimilarNodeGroups: true,
+				NodeGroups:               []NodeGroup{{Name: "asg-a", MinSize: 1, MaxSize: 10}, {Name: "asg-b", MinSize: 0, MaxSize: 5}},
+				ScaleDownEnabled:         new(false),
+				ScaleDownUnneededTime:    new(15 * time.Minute),
+				MaxScaleDownParallelism:  new(3),
+			},
+		},
+		{
+			name:          "invalid node group",
+			args:          []string{"--nodes=1:asg-a"},
+			errorContains: "expected <min>:<max>:<name>",
+		},
+		{
+			name:          "missing value",
+			args:          []string{"--expander"},
+			errorContains: "flag --expander needs a value",
+		},
+		{
+			name:          "invalid boolean",
+			args:          []string{"--balance-similar-node-groups=maybe"},
+			errorContains: "invalid --balance-similar-node-groups value",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseArgs(tc.args)
+
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	dep := deployment("kube-system", "cluster-autoscaler", nil, "registry.k8s.io/autoscaling/cluster-autoscaler:v1.30.0")
+	dep.Spec.Template.Spec.Containers[0].Command = []string{"./cluster-autoscaler", "--expander=priority"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: PriorityExpanderConfigMapName},
+		Data: map[string]string{"priorities": `10:
+  - .*
+50:
+  - .*spot.*
+`},
+	}
+
+	t.Run("reads the priority expander ConfigMap", func(t *testing.T) {
+		cfg, err := FindConfig(t.Context(), fake.NewSimpleClientset(dep, cm))
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+		assert.Equal(t, "kube-system", cfg.Namespace)
+		assert.Equal(t, map[int][]*regexp.Regexp{10: {regexp.MustCompile(".*")}, 50: {regexp.MustCompile(".*spot.*")}}, cfg.Priorities)
+	})
+
+	t.Run("tolerates a missing priority expander ConfigMap", func(t *testing.T) {
+		cfg, err := FindConfig(t.Context(), fake.NewSimpleClientset(dep))
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+		assert.Nil(t, cfg.Priorities)
+	})
+
+	t.Run("rejects invalid regular expressions", func(t *testing.T) {
+		invalid := cm.DeepCopy()
+		invalid.Data["priorities"] = "10:\n  - \"(\"\n"
+		_, err := FindConfig(t.Context(), fake.NewSimpleClientset(dep, invalid))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid regular expression")
+	})
+
+	t.Run("no cluster-autoscaler", func(t *testing.T) {
+		cfg, err := FindConfig(t.Context(), fake.NewSimpleClientset())
+		require.NoError(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestConfigPriority(t *testing.T) {
+	cfg := &Config{
+		Expanders:  []string{PriorityExpander},
+		Priorities: map[int][]*regexp.Regexp{10: {regexp.MustCompile(".*")}, 50: {regexp.MustCompile(".*spot.*")}},
+	}
+
+	p, ok := cfg.Priority("eks-spot-1234")
+	assert.True(t, ok)
+	assert.Equal(t, 50, p)
+
+	p, ok = cfg.Priority("eks-on-demand-1234")
+	assert.True(t, ok)
+	assert.Equal(t, 10, p)
+
+	_, ok = (&Config{Expanders: []string{"random"}, Priorities: cfg.Priorities}).Priority("eks-spot-1234")
+	assert.False(t, ok, "priorities are ignored when the priority expander is not used")
+}
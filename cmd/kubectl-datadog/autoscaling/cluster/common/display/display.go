@@ -42,3 +42,35 @@ func PrintBox(w io.Writer, lines ...string) {
 	}
 	fmt.Fprintln(w, "╰─"+strings.Repeat("─", maxWidth)+"─╯")
 }
+
+// PrintTable prints rows inside a Unicode box, below a header. Columns are
+// padded to the width of their widest cell, handling ANSI escape sequences and
+// wide Unicode characters like PrintBox.
+func PrintTable(w io.Writer, header []string, rows ...[]string) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i := range widths {
+			widths[i] = max(widths[i], visualWidth(lo.NthOr(row, i, "")))
+		}
+	}
+
+	printLine := func(left, middle, right string) {
+		fmt.Fprintln(w, left+"─"+strings.Join(lo.Map(widths, func(width, _ int) string {
+			return strings.Repeat("─", width)
+		}), "─"+middle+"─")+"─"+right)
+	}
+	printRow := func(cells []string) {
+		fmt.Fprintln(w, "│ "+strings.Join(lo.Map(widths, func(width, i int) string {
+			cell := lo.NthOr(cells, i, "")
+			return cell + strings.Repeat(" ", width-visualWidth(cell))
+		}), " │ ")+" │")
+	}
+
+	printLine("╭", "┬", "╮")
+	printRow(header)
+	printLine("├", "┼", "┤")
+	for _, row := range rows {
+		printRow(row)
+	}
+	printLine("╰", "┴", "╯")
+}
@@ -173,3 +173,20 @@ func TestPrintBox(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintTable(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTable(&buf, []string{"Name", "Value"},
+		[]string{"a", "\x1b[1mbold\x1b[0m"},
+		[]string{"longer name"},
+	)
+
+	expected := "" +
+		"╭─────────────┬───────╮\n" +
+		"│ Name        │ Value │\n" +
+		"├─────────────┼───────┤\n" +
+		"│ a           │ \x1b[1mbold\x1b[0m  │\n" +
+		"│ longer name │       │\n" +
+		"╰─────────────┴───────╯\n"
+	assert.Equal(t, expected, buf.String())
+}
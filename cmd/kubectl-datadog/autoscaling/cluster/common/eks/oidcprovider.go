@@ -30,11 +30,17 @@ func GetClusterOIDCIssuerURL(cluster *ekstypes.Cluster) (string, error) {
 	return issuer, nil
 }
 
+// OIDCProviderReader is the read-only subset of the IAM client used by
+// FindOIDCProvider.
+type OIDCProviderReader interface {
+	ListOpenIDConnectProviders(ctx context.Context, params *iam.ListOpenIDConnectProvidersInput, optFns ...func(*iam.Options)) (*iam.ListOpenIDConnectProvidersOutput, error)
+	GetOpenIDConnectProvider(ctx context.Context, params *iam.GetOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.GetOpenIDConnectProviderOutput, error)
+}
+
 // OIDCProviderAPI is the subset of the IAM client used by EnsureOIDCProvider.
 // Defined as an interface to allow mocking in tests.
 type OIDCProviderAPI interface {
-	ListOpenIDConnectProviders(ctx context.Context, params *iam.ListOpenIDConnectProvidersInput, optFns ...func(*iam.Options)) (*iam.ListOpenIDConnectProvidersOutput, error)
-	GetOpenIDConnectProvider(ctx context.Context, params *iam.GetOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.GetOpenIDConnectProviderOutput, error)
+	OIDCProviderReader
 	CreateOpenIDConnectProvider(ctx context.Context, params *iam.CreateOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.CreateOpenIDConnectProviderOutput, error)
 	AddClientIDToOpenIDConnectProvider(ctx context.Context, params *iam.AddClientIDToOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.AddClientIDToOpenIDConnectProviderOutput, error)
 }
@@ -50,39 +56,24 @@ type OIDCProviderAPI interface {
 // The provider is never deleted on uninstall because it may be shared with
 // other workloads in the cluster.
 func EnsureOIDCProvider(ctx context.Context, iamClient OIDCProviderAPI, issuerURL string) (string, error) {
-	targetURL := normalizeOIDCURL(issuerURL)
-
-	listOut, err := iamClient.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	providerArn, provider, err := findOIDCProvider(ctx, iamClient, issuerURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to list OIDC providers: %w", err)
+		return "", err
 	}
-
-	for _, provider := range listOut.OpenIDConnectProviderList {
-		providerArn := aws.ToString(provider.Arn)
-		if providerArn == "" {
-			continue
-		}
-		getOut, getErr := iamClient.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{
-			OpenIDConnectProviderArn: provider.Arn,
-		})
-		if getErr != nil {
-			return "", fmt.Errorf("failed to get OIDC provider %s: %w", providerArn, getErr)
-		}
-		if normalizeOIDCURL(aws.ToString(getOut.Url)) == targetURL {
-			// Ensure the STS audience is registered: the IRSA trust policy we
-			// install conditions on `aud = sts.amazonaws.com`, which requires
-			// that the provider itself lists that client ID. Providers
-			// bootstrapped by other tools (eksctl, Terraform) may omit it.
-			if !slices.Contains(getOut.ClientIDList, stsAudience) {
-				if _, addErr := iamClient.AddClientIDToOpenIDConnectProvider(ctx, &iam.AddClientIDToOpenIDConnectProviderInput{
-					OpenIDConnectProviderArn: provider.Arn,
-					ClientID:                 aws.String(stsAudience),
-				}); addErr != nil {
-					return "", fmt.Errorf("failed to add %s client ID to OIDC provider %s: %w", stsAudience, providerArn, addErr)
-				}
+	if provider != nil {
+		// Ensure the STS audience is registered: the IRSA trust policy we
+		// install conditions on `aud = sts.amazonaws.com`, which requires
+		// that the provider itself lists that client ID. Providers
+		// bootstrapped by other tools (eksctl, Terraform) may omit it.
+		if !slices.Contains(provider.ClientIDList, stsAudience) {
+			if _, addErr := iamClient.AddClientIDToOpenIDConnectProvider(ctx, &iam.AddClientIDToOpenIDConnectProviderInput{
+				OpenIDConnectProviderArn: aws.String(providerArn),
+				ClientID:                 aws.String(stsAudience),
+			}); addErr != nil {
+				return "", fmt.Errorf("failed to add %s client ID to OIDC provider %s: %w", stsAudience, providerArn, addErr)
 			}
-			return providerArn, nil
 		}
+		return providerArn, nil
 	}
 
 	createOut, err := iamClient.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
@@ -104,6 +95,43 @@ func EnsureOIDCProvider(ctx context.Context, iamClient OIDCProviderAPI, issuerUR
 	return arn, nil
 }
 
+// FindOIDCProvider returns the ARN of the IAM OIDC provider for the given
+// issuer URL, or an empty string if there is none. Unlike EnsureOIDCProvider,
+// it never modifies the provider, so it can be used to plan an installation.
+func FindOIDCProvider(ctx context.Context, iamClient OIDCProviderReader, issuerURL string) (string, error) {
+	providerArn, _, err := findOIDCProvider(ctx, iamClient, issuerURL)
+	return providerArn, err
+}
+
+// findOIDCProvider looks up the IAM OIDC provider whose URL matches the given
+// issuer URL. Returns an empty ARN and a nil provider when there is none.
+func findOIDCProvider(ctx context.Context, iamClient OIDCProviderReader, issuerURL string) (string, *iam.GetOpenIDConnectProviderOutput, error) {
+	targetURL := normalizeOIDCURL(issuerURL)
+
+	listOut, err := iamClient.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list OIDC providers: %w", err)
+	}
+
+	for _, provider := range listOut.OpenIDConnectProviderList {
+		providerArn := aws.ToString(provider.Arn)
+		if providerArn == "" {
+			continue
+		}
+		getOut, getErr := iamClient.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: provider.Arn,
+		})
+		if getErr != nil {
+			return "", nil, fmt.Errorf("failed to get OIDC provider %s: %w", providerArn, getErr)
+		}
+		if normalizeOIDCURL(aws.ToString(getOut.Url)) == targetURL {
+			return providerArn, getOut, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
 // waitForOIDCProviderReadable polls GetOpenIDConnectProvider until the given
 // provider ARN is readable, with a short bounded retry budget. IAM
 // CreateOpenIDConnectProvider is asynchronous from a read-consistency
@@ -199,6 +199,38 @@ func TestEnsureOIDCProvider(t *testing.T) {
 	}
 }
 
+func TestFindOIDCProvider(t *testing.T) {
+	const (
+		issuerURL   = "https://oidc.eks.eu-west-3.amazonaws.com/id/ABCDEF"
+		issuerStore = "oidc.eks.eu-west-3.amazonaws.com/id/ABCDEF"
+		issuerArn   = "arn:aws:iam::123456789012:oidc-provider/" + issuerStore
+	)
+
+	t.Run("returns the matching provider without patching it", func(t *testing.T) {
+		f := &fakeIAM{providers: map[string]*fakeProvider{issuerArn: {url: issuerStore, clientIDList: []string{"other.audience"}}}}
+
+		arn, err := FindOIDCProvider(t.Context(), f, issuerURL)
+		require.NoError(t, err)
+		assert.Equal(t, issuerArn, arn)
+		assert.Equal(t, 0, f.addClientIDCalls, "FindOIDCProvider must not modify the provider")
+	})
+
+	t.Run("returns an empty ARN without creating a provider", func(t *testing.T) {
+		f := &fakeIAM{providers: map[string]*fakeProvider{}}
+
+		arn, err := FindOIDCProvider(t.Context(), f, issuerURL)
+		require.NoError(t, err)
+		assert.Empty(t, arn)
+		assert.Equal(t, 0, f.createCalls, "FindOIDCProvider must not create a provider")
+	})
+
+	t.Run("list error propagates", func(t *testing.T) {
+		_, err := FindOIDCProvider(t.Context(), &fakeIAM{listErr: errors.New("api throttled")}, issuerURL)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list OIDC providers")
+	})
+}
+
 func TestGetClusterOIDCIssuerURL(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -31,6 +33,21 @@ func CreateOrUpgrade(ctx context.Context, ac *action.Configuration, releaseName,
 	}
 }
 
+// ResolveVersion returns the version of the OCI chart chartRef that Helm
+// installs for version: the highest stable version when version is empty,
+// the highest version matching it when it is a semver constraint.
+func ResolveVersion(rc *registry.Client, chartRef, version string) (string, error) {
+	tags, err := rc.Tags(strings.TrimPrefix(chartRef, "oci://"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions of chart %s: %w", chartRef, err)
+	}
+	resolved, err := registry.GetTagMatchingVersionOrConstraint(tags, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve version of chart %s: %w", chartRef, err)
+	}
+	return resolved, nil
+}
+
 func Exists(_ context.Context, ac *action.Configuration, releaseName string) (bool, error) {
 	historyAction := action.NewHistory(ac)
 	historyAction.Max = 1
@@ -13,7 +13,15 @@ import (
 	"github.com/DataDog/datadog-operator/pkg/version"
 )
 
+// CreateOrUpdateEC2NodeClass creates or updates the EC2NodeClass built by
+// NewEC2NodeClass.
 func CreateOrUpdateEC2NodeClass(ctx context.Context, client client.Client, clusterName string, nc EC2NodeClass) error {
+	return commonk8s.CreateOrUpdate(ctx, client, NewEC2NodeClass(clusterName, nc))
+}
+
+// NewEC2NodeClass returns the Karpenter EC2NodeClass object matching the
+// inferred nc properties.
+func NewEC2NodeClass(clusterName string, nc EC2NodeClass) *karpawsv1.EC2NodeClass {
 	var amiSelectorTerms []karpawsv1.AMISelectorTerm
 
 	if amiIDs := nc.GetAMIIDs(); len(amiIDs) > 0 {
@@ -36,7 +44,7 @@ func CreateOrUpdateEC2NodeClass(ctx context.Context, client client.Client, clust
 		}
 	}
 
-	return commonk8s.CreateOrUpdate(ctx, client, &karpawsv1.EC2NodeClass{
+	return &karpawsv1.EC2NodeClass{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "karpenter.k8s.aws/v1",
 			Kind:       "EC2NodeClass",
@@ -66,7 +74,7 @@ func CreateOrUpdateEC2NodeClass(ctx context.Context, client client.Client, clust
 			MetadataOptions:     convertMetadataOptions(nc.GetMetadataOptions()),
 			BlockDeviceMappings: convertBlockDeviceMappings(nc.GetBlockDeviceMappings()),
 		},
-	})
+	}
 }
 
 func amiFamilyToAlias(amiFamily string) string {
@@ -3,7 +3,9 @@ package karpenter
 import (
 	"testing"
 
+	karpawsv1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAmiFamilyToAlias(t *testing.T) {
@@ -25,3 +27,25 @@ func TestAmiFamilyToAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestNewEC2NodeClass(t *testing.T) {
+	nps := NewNodePoolsSet()
+	nps.Add(NodePoolsSetAddParams{
+		AMIFamily:        "Bottlerocket",
+		SubnetIDs:        []string{"subnet-b", "subnet-a"},
+		SecurityGroupIDs: []string{"sg-1"},
+	})
+	ncs := nps.GetEC2NodeClasses()
+	require.Len(t, ncs, 1)
+
+	obj := NewEC2NodeClass("my-cluster", ncs[0])
+
+	assert.Equal(t, "EC2NodeClass", obj.Kind)
+	assert.Equal(t, "karpenter.k8s.aws/v1", obj.APIVersion)
+	assert.Equal(t, ncs[0].GetName(), obj.Name)
+	assert.Equal(t, "kubectl-datadog", obj.Labels["app.kubernetes.io/managed-by"])
+	assert.Equal(t, "KarpenterNodeRole-my-cluster", obj.Spec.Role)
+	assert.Equal(t, []karpawsv1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}, obj.Spec.AMISelectorTerms)
+	assert.Equal(t, []karpawsv1.SubnetSelectorTerm{{ID: "subnet-a"}, {ID: "subnet-b"}}, obj.Spec.SubnetSelectorTerms)
+	assert.Equal(t, []karpawsv1.SecurityGroupSelectorTerm{{ID: "sg-1"}}, obj.Spec.SecurityGroupSelectorTerms)
+}
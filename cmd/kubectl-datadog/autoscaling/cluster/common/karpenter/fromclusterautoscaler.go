@@ -0,0 +1,93 @@
+package karpenter
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/samber/lo"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
+)
+
+// maxNodePoolWeight is the highest weight Karpenter accepts on a NodePool.
+const maxNodePoolWeight = 100
+
+// ApplyClusterAutoscalerConfig carries the scaling policy of cluster-autoscaler
+// over to the NodePools, based on the Auto Scaling groups they were inferred
+// from:
+//   - the priority expander priorities become NodePool weights, keeping their
+//     order;
+//   - the maximum sizes of the Auto Scaling groups, or the `--nodes` ones,
+//     become a `nodes` limit, when all of them are known;
+//   - `--scale-down-unneeded-time` becomes the consolidation delay;
+//   - `--scale-down-enabled=false` and `--max-scale-down-parallelism` become
+//     the disruption budget.
+func (nps *NodePoolsSet) ApplyClusterAutoscalerConfig(cfg *clusterautoscaler.Config) {
+	priorities := map[uint64]int{}
+
+	for h, np := range nps.nodePools {
+		np.policy = ScalingPolicy{
+			MaxNodes:         maxNodes(cfg, np.GetAutoscalingGroups()),
+			ConsolidateAfter: cfg.ScaleDownUnneededTime,
+		}
+		if cfg.ScaleDownEnabled != nil && !*cfg.ScaleDownEnabled {
+			np.policy.DisruptionBudget = "0"
+		} else if cfg.MaxScaleDownParallelism != nil {
+			np.policy.DisruptionBudget = strconv.Itoa(*cfg.MaxScaleDownParallelism)
+		}
+		nps.nodePools[h] = np
+
+		for _, asg := range np.GetAutoscalingGroups() {
+			if p, ok := cfg.Priority(asg.Name); ok && (!lo.HasKey(priorities, h) || p > priorities[h]) {
+				priorities[h] = p
+			}
+		}
+	}
+
+	weights := priorityWeights(slices.Collect(maps.Values(priorities)))
+	for h, p := range priorities {
+		np := nps.nodePools[h]
+		np.policy.Weight = new(weights[p])
+		nps.nodePools[h] = np
+	}
+}
+
+// maxNodes returns the sum of the maximum sizes of the given Auto Scaling
+// groups, or nil when there is none or one of them is unknown.
+func maxNodes(cfg *clusterautoscaler.Config, asgs []AutoscalingGroup) *int64 {
+	if len(asgs) == 0 {
+		return nil
+	}
+	var total int64
+	for _, asg := range asgs {
+		if ng, ok := cfg.NodeGroup(asg.Name); ok {
+			total += int64(ng.MaxSize)
+		} else if asg.MaxSize != nil {
+			total += int64(*asg.MaxSize)
+		} else {
+			return nil
+		}
+	}
+	return &total
+}
+
+// priorityWeights maps priorities to NodePool weights. Priorities already in
+// the range of weights are kept as is; otherwise, they are replaced by their
+// rank, capped to the highest weight.
+func priorityWeights(priorities []int) map[int]int32 {
+	distinct := lo.Uniq(priorities)
+	slices.Sort(distinct)
+
+	weights := make(map[int]int32, len(distinct))
+	if len(distinct) > 0 && distinct[0] >= 1 && distinct[len(distinct)-1] <= maxNodePoolWeight {
+		for _, p := range distinct {
+			weights[p] = int32(p)
+		}
+		return weights
+	}
+	for i, p := range distinct {
+		weights[p] = int32(min(i+1, maxNodePoolWeight))
+	}
+	return weights
+}
@@ -0,0 +1,96 @@
+package karpenter
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/clusterautoscaler"
+)
+
+func nodePoolByAutoscalingGroup(t *testing.T, nps *NodePoolsSet, asg string) NodePool {
+	t.Helper()
+	np, ok := lo.Find(nps.GetNodePools(), func(np NodePool) bool {
+		return lo.ContainsBy(np.GetAutoscalingGroups(), func(g AutoscalingGroup) bool { return g.Name == asg })
+	})
+	require.True(t, ok, "no NodePool inferred from %s", asg)
+	return np
+}
+
+func TestApplyClusterAutoscalerConfig(t *testing.T) {
+	nps := NewNodePoolsSet()
+	for _, p := range []struct {
+		asg     AutoscalingGroup
+		labels  map[string]string
+		subnets []string
+	}{
+		{asg: AutoscalingGroup{Name: "eks-spot-a", MaxSize: new(int32(10))}, labels: map[string]string{"pool": "spot"}, subnets: []string{"subnet-a"}},
+		{asg: AutoscalingGroup{Name: "eks-spot-b", MaxSize: new(int32(5))}, labels: map[string]string{"pool": "spot"}, subnets: []string{"subnet-b"}},
+		{asg: AutoscalingGroup{Name: "eks-on-demand", MaxSize: new(int32(20))}, labels: map[string]string{"pool": "on-demand"}, subnets: []string{"subnet-a"}},
+		{asg: AutoscalingGroup{Name: "eks-unknown-size"}, labels: map[string]string{"pool": "other"}, subnets: []string{"subnet-a"}},
+	} {
+		nps.Add(NodePoolsSetAddParams{
+			AMIFamily:         "AL2023",
+			SubnetIDs:         p.subnets,
+			Labels:            p.labels,
+			AutoscalingGroups: []AutoscalingGroup{p.asg},
+		})
+	}
+
+	nps.ApplyClusterAutoscalerConfig(&clusterautoscaler.Config{
+		Expanders:               []string{clusterautoscaler.PriorityExpander},
+		NodeGroups:              []clusterautoscaler.NodeGroup{{Name: "eks-on-demand", MinSize: 1, MaxSize: 30}},
+		ScaleDownUnneededTime:   new(15 * time.Minute),
+		MaxScaleDownParallelism: new(3),
+		Priorities:              map[int][]*regexp.Regexp{10: {regexp.MustCompile(".*")}, 50: {regexp.MustCompile(".*spot.*")}},
+	})
+
+	spot := nodePoolByAutoscalingGroup(t, nps, "eks-spot-a")
+	assert.Len(t, spot.GetAutoscalingGroups(), 2, "node groups differing only by subnet are merged")
+	assert.Equal(t, ScalingPolicy{
+		Weight:           new(int32(50)),
+		MaxNodes:         new(int64(15)),
+		ConsolidateAfter: new(15 * time.Minute),
+		DisruptionBudget: "3",
+	}, spot.GetScalingPolicy())
+
+	onDemand := nodePoolByAutoscalingGroup(t, nps, "eks-on-demand")
+	assert.Equal(t, new(int32(10)), onDemand.GetScalingPolicy().Weight)
+	assert.Equal(t, new(int64(30)), onDemand.GetScalingPolicy().MaxNodes, "--nodes overrides the Auto Scaling group size")
+
+	unknown := nodePoolByAutoscalingGroup(t, nps, "eks-unknown-size")
+	assert.Nil(t, unknown.GetScalingPolicy().MaxNodes)
+
+	obj := NewNodePool(spot)
+	assert.Equal(t, new(int32(50)), obj.Spec.Weight)
+	nodes := obj.Spec.Limits[resources.Node]
+	assert.Equal(t, int64(15), nodes.Value())
+	assert.Equal(t, []karpv1.Budget{{Nodes: "3"}}, obj.Spec.Disruption.Budgets)
+	assert.Equal(t, 15*time.Minute, *obj.Spec.Disruption.ConsolidateAfter.Duration)
+}
+
+func TestApplyClusterAutoscalerConfigScaleDownDisabled(t *testing.T) {
+	nps := NewNodePoolsSet()
+	nps.Add(NodePoolsSetAddParams{AMIFamily: "AL2023", SubnetIDs: []string{"subnet-a"}})
+
+	nps.ApplyClusterAutoscalerConfig(&clusterautoscaler.Config{
+		Expanders:               []string{"random"},
+		ScaleDownEnabled:        new(false),
+		MaxScaleDownParallelism: new(3),
+	})
+
+	np := nps.GetNodePools()[0]
+	assert.Equal(t, ScalingPolicy{DisruptionBudget: "0"}, np.GetScalingPolicy())
+}
+
+func TestPriorityWeights(t *testing.T) {
+	assert.Equal(t, map[int]int32{10: 10, 50: 50}, priorityWeights([]int{50, 10, 50}))
+	assert.Equal(t, map[int]int32{0: 1, 10: 2, 500: 3}, priorityWeights([]int{500, 0, 10}))
+	assert.Empty(t, priorityWeights(nil))
+}
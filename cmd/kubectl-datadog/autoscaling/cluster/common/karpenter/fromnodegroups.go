@@ -64,6 +64,20 @@ func GetNodeGroupsProperties(ctx context.Context, eksClient *eks.Client, ec2Clie
 				CapacityType:  convertCapacityType(ng.CapacityType),
 			}
 
+			if ng.Resources != nil {
+				params.AutoscalingGroups = lo.FilterMap(ng.Resources.AutoScalingGroups, func(asg ekstypes.AutoScalingGroup, _ int) (AutoscalingGroup, bool) {
+					if asg.Name == nil {
+						return AutoscalingGroup{}, false
+					}
+					out := AutoscalingGroup{Name: *asg.Name}
+					if ng.ScalingConfig != nil {
+						out.MinSize = ng.ScalingConfig.MinSize
+						out.MaxSize = ng.ScalingConfig.MaxSize
+					}
+					return out, true
+				})
+			}
+
 			if ng.LaunchTemplate != nil && ng.LaunchTemplate.Id != nil && ng.LaunchTemplate.Version != nil {
 				launchTemplate, err := ec2Client.DescribeLaunchTemplateVersions(ctx, &ec2.DescribeLaunchTemplateVersionsInput{
 					LaunchTemplateId: ng.LaunchTemplate.Id,
@@ -131,12 +131,25 @@ func processNodeBatch(ctx context.Context, ec2Client *ec2.Client, nps *NodePools
 				Zones:               extractZones(instance.Placement),
 				InstanceTypes:       []string{string(instance.InstanceType)},
 				CapacityType:        convertInstanceLifecycleType(instance.InstanceLifecycle),
+				AutoscalingGroups:   extractAutoscalingGroups(instance.Tags),
 			})
 		}
 	}
 	return nil
 }
 
+// extractAutoscalingGroups returns the Auto Scaling group of an instance, from
+// the tag EC2 Auto Scaling sets on the instances it launches. Its size is not
+// known from the instance.
+func extractAutoscalingGroups(tags []ec2types.Tag) []AutoscalingGroup {
+	for _, tag := range tags {
+		if lo.FromPtr(tag.Key) == "aws:autoscaling:groupName" && lo.FromPtr(tag.Value) != "" {
+			return []AutoscalingGroup{{Name: *tag.Value}}
+		}
+	}
+	return nil
+}
+
 func detectAMIFamilyFromImage(imageName string) string {
 	containsAny := func(s string, patterns ...string) bool {
 		return slices.ContainsFunc(patterns, func(pattern string) bool {
@@ -3,16 +3,26 @@ package karpenter
 import (
 	"context"
 
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
 
 	commonk8s "github.com/DataDog/datadog-operator/cmd/kubectl-datadog/autoscaling/cluster/common/k8s"
 	"github.com/DataDog/datadog-operator/pkg/version"
 )
 
+// CreateOrUpdateNodePool creates or updates the NodePool built by NewNodePool.
 func CreateOrUpdateNodePool(ctx context.Context, client client.Client, np NodePool) error {
+	return commonk8s.CreateOrUpdate(ctx, client, NewNodePool(np))
+}
+
+// NewNodePool returns the Karpenter NodePool object matching the inferred np
+// properties.
+func NewNodePool(np NodePool) *karpv1.NodePool {
 	requirements := []karpv1.NodeSelectorRequirementWithMinValues{}
 
 	if architectures := np.GetArchitectures(); len(architectures) > 0 {
@@ -47,7 +57,21 @@ func CreateOrUpdateNodePool(ctx context.Context, client client.Client, np NodePo
 		})
 	}
 
-	return commonk8s.CreateOrUpdate(ctx, client, &karpv1.NodePool{
+	policy := np.GetScalingPolicy()
+
+	consolidateAfter := karpv1.MustParseNillableDuration("5m")
+	if policy.ConsolidateAfter != nil {
+		consolidateAfter = karpv1.MustParseNillableDuration(policy.ConsolidateAfter.String())
+	}
+
+	var limits karpv1.Limits
+	if policy.MaxNodes != nil {
+		limits = karpv1.Limits{
+			resources.Node: *resource.NewQuantity(*policy.MaxNodes, resource.DecimalSI),
+		}
+	}
+
+	return &karpv1.NodePool{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "karpenter.sh/v1",
 			Kind:       "NodePool",
@@ -75,15 +99,17 @@ func CreateOrUpdateNodePool(ctx context.Context, client client.Client, np NodePo
 					Taints:       np.GetTaints(),
 				},
 			},
+			Limits: limits,
+			Weight: policy.Weight,
 			Disruption: karpv1.Disruption{
 				Budgets: []karpv1.Budget{
 					{
-						Nodes: "10%",
+						Nodes: lo.CoalesceOrEmpty(policy.DisruptionBudget, "10%"),
 					},
 				},
-				ConsolidateAfter:    karpv1.MustParseNillableDuration("5m"),
+				ConsolidateAfter:    consolidateAfter,
 				ConsolidationPolicy: karpv1.ConsolidationPolicyWhenEmptyOrUnderutilized,
 			},
 		},
-	})
+	}
 }
@@ -0,0 +1,43 @@
+package karpenter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+func TestNewNodePool(t *testing.T) {
+	nps := NewNodePoolsSet()
+	nps.Add(NodePoolsSetAddParams{
+		AMIFamily:     "AL2023",
+		SubnetIDs:     []string{"subnet-a"},
+		Labels:        map[string]string{"team": "web"},
+		Architecture:  "arm64",
+		Zones:         []string{"eu-west-3a"},
+		InstanceTypes: []string{"m7g.large"},
+		CapacityType:  "spot",
+	})
+	ncs := nps.GetEC2NodeClasses()
+	require.Len(t, ncs, 1)
+	pools := nps.GetNodePools()
+	require.Len(t, pools, 1)
+
+	obj := NewNodePool(pools[0])
+
+	assert.Equal(t, "NodePool", obj.Kind)
+	assert.Equal(t, "karpenter.sh/v1", obj.APIVersion)
+	assert.Equal(t, pools[0].GetName(), obj.Name)
+	assert.Equal(t, "true", obj.Labels["autoscaling.datadoghq.com/created"])
+	assert.Equal(t, map[string]string{"team": "web"}, obj.Spec.Template.Labels)
+	require.NotNil(t, obj.Spec.Template.Spec.NodeClassRef)
+	assert.Equal(t, ncs[0].GetName(), obj.Spec.Template.Spec.NodeClassRef.Name)
+	assert.Equal(t, []karpv1.NodeSelectorRequirementWithMinValues{
+		{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}},
+		{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-west-3a"}},
+		{Key: "karpenter.k8s.aws/instance-family", Operator: corev1.NodeSelectorOpIn, Values: []string{"m7g"}},
+		{Key: "karpenter.sh/capacity-type", Operator: corev1.NodeSelectorOpIn, Values: []string{"spot"}},
+	}, obj.Spec.Template.Spec.Requirements)
+}
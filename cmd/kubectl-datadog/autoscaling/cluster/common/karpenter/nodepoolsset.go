@@ -9,6 +9,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
@@ -169,6 +170,29 @@ type NodePool struct {
 	zones            map[string]struct{}
 	instanceFamilies map[string]struct{}
 	capacityTypes    map[string]struct{}
+	// autoscalingGroups are the Auto Scaling groups the NodePool was inferred
+	// from, by name.
+	autoscalingGroups map[string]AutoscalingGroup
+	policy            ScalingPolicy
+}
+
+// AutoscalingGroup is an Auto Scaling group backing the nodes a NodePool is
+// inferred from. MinSize and MaxSize are nil when unknown.
+type AutoscalingGroup struct {
+	Name    string
+	MinSize *int32
+	MaxSize *int32
+}
+
+// ScalingPolicy is the scaling policy of a NodePool. Nil or empty fields
+// leave the defaults of NewNodePool.
+type ScalingPolicy struct {
+	Weight           *int32
+	MaxNodes         *int64
+	ConsolidateAfter *time.Duration
+	// DisruptionBudget is the number or percentage of nodes that can be
+	// disrupted at once.
+	DisruptionBudget string
 }
 
 func (np *NodePool) GetName() string {
@@ -207,6 +231,18 @@ func (np *NodePool) GetCapacityTypes() []string {
 	return slices.Sorted(maps.Keys(np.capacityTypes))
 }
 
+// GetAutoscalingGroups returns the Auto Scaling groups the NodePool was
+// inferred from, sorted by name.
+func (np *NodePool) GetAutoscalingGroups() []AutoscalingGroup {
+	return lo.Map(slices.Sorted(maps.Keys(np.autoscalingGroups)), func(name string, _ int) AutoscalingGroup {
+		return np.autoscalingGroups[name]
+	})
+}
+
+func (np *NodePool) GetScalingPolicy() ScalingPolicy {
+	return np.policy
+}
+
 func (np *NodePool) sum64() uint64 {
 	h := fnv.New64()
 
@@ -251,6 +287,7 @@ type NodePoolsSetAddParams struct {
 	Zones               []string
 	InstanceTypes       []string
 	CapacityType        string
+	AutoscalingGroups   []AutoscalingGroup
 }
 
 func (nps *NodePoolsSet) Add(p NodePoolsSetAddParams) {
@@ -291,6 +328,9 @@ func (nps *NodePoolsSet) Add(p NodePoolsSetAddParams) {
 		zones:            lo.Keyify(p.Zones),
 		instanceFamilies: extractInstanceFamilies(p.InstanceTypes),
 		capacityTypes:    map[string]struct{}{p.CapacityType: {}},
+		autoscalingGroups: lo.KeyBy(p.AutoscalingGroups, func(asg AutoscalingGroup) string {
+			return asg.Name
+		}),
 	}
 
 	if p.Architecture != "" {
@@ -308,6 +348,9 @@ func (nps *NodePoolsSet) Add(p NodePoolsSetAddParams) {
 		maps.Copy(n.zones, lo.Keyify(p.Zones))
 		maps.Copy(n.instanceFamilies, extractInstanceFamilies(p.InstanceTypes))
 		n.capacityTypes[p.CapacityType] = struct{}{}
+		for _, asg := range p.AutoscalingGroups {
+			n.autoscalingGroups[asg.Name] = asg
+		}
 		nps.nodePools[h] = n
 	} else {
 		nps.nodePools[h] = np
@@ -55,6 +55,9 @@ func applyNodePoolDefaults(nodePools []NodePool) []NodePool {
 		if np.capacityTypes == nil {
 			np.capacityTypes = make(map[string]struct{})
 		}
+		if np.autoscalingGroups == nil {
+			np.autoscalingGroups = make(map[string]AutoscalingGroup)
+		}
 		return np
 	})
 }
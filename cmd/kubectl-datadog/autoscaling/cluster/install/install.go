@@ -23,6 +23,9 @@ import (
 var installExample = `
   # install autoscaling
   %[1]s install
+
+  # write the changes the installation would make to a directory, without making them
+  %[1]s install --output-dir karpenter-plan
 `
 
 type options struct {
@@ -38,6 +41,8 @@ type options struct {
 	createKarpenterResources apply.CreateKarpenterResources
 	inferenceMethod          apply.InferenceMethod
 	debug                    bool
+	dryRun                   bool
+	outputDir                string
 }
 
 func newOptions(streams genericclioptions.IOStreams) *options {
@@ -79,6 +84,8 @@ func New(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.Flags().Var(&o.createKarpenterResources, "create-karpenter-resources", "Which Karpenter resources to create: none, ec2nodeclass, all (default: all)")
 	cmd.Flags().Var(&o.inferenceMethod, "inference-method", "Method to infer EC2NodeClass and NodePool properties: nodes, nodegroups")
 	cmd.Flags().BoolVar(&o.debug, "debug", false, "Enable debug logs")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Print the changes the installation would make, without making them")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "Write the changes the installation would make to this directory, without making them")
 
 	o.ConfigFlags.AddFlags(cmd.Flags())
 
@@ -134,6 +141,8 @@ func (o *options) run() error {
 		CreateKarpenterResources: o.createKarpenterResources,
 		InferenceMethod:          o.inferenceMethod,
 		Debug:                    o.debug,
+		DryRun:                   o.dryRun,
+		OutputDir:                o.outputDir,
 		ActionLabel:              "Installing",
 	})
 }
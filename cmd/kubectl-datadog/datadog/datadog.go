@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/get"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/helm2dda"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/metrics"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/status"
 	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/validate/validate"
 )
 
@@ -41,6 +42,7 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 
 	// Operator commands
 	cmd.AddCommand(get.New(streams))
+	cmd.AddCommand(status.New(streams))
 	cmd.AddCommand(flare.New(streams))
 	cmd.AddCommand(validate.New(streams))
 
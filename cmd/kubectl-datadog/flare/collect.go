@@ -0,0 +1,246 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/renderer"
+	"github.com/DataDog/datadog-operator/pkg/scrubber"
+)
+
+const (
+	ddaiCRDName = "datadogagentinternals.datadoghq.com"
+
+	datadogAgentKind         = "DatadogAgent"
+	datadogAgentInternalKind = "DatadogAgentInternal"
+)
+
+// createDDAIFiles gets the DatadogAgentInternal objects generated by the operator
+func (o *options) createDDAIFiles(ctx context.Context, dir string, cmd *cobra.Command) error {
+	ddaiList := &v1alpha1.DatadogAgentInternalList{}
+	if err := o.Client.List(ctx, ddaiList, client.InNamespace(o.UserNamespace)); err != nil {
+		return err
+	}
+	if len(ddaiList.Items) == 0 {
+		return errors.New("DatadogAgentInternal resources not found")
+	}
+
+	out, err := k8syaml.Marshal(ddaiList.Items)
+	if err != nil {
+		return err
+	}
+
+	return o.redactAndSave(filepath.Join(dir, "datadog-agent-internals.yaml"), out, cmd)
+}
+
+// createControllerRevisionFiles gets the ControllerRevisions owned by the DatadogAgents
+func (o *options) createControllerRevisionFiles(ctx context.Context, dir string, cmd *cobra.Command) error {
+	revisionList := &appsv1.ControllerRevisionList{}
+	if err := o.Client.List(ctx, revisionList, client.InNamespace(o.UserNamespace)); err != nil {
+		return err
+	}
+
+	var revisions []appsv1.ControllerRevision
+	for _, revision := range revisionList.Items {
+		if isOwnedBy(revision.OwnerReferences, datadogAgentKind) {
+			revisions = append(revisions, revision)
+		}
+	}
+	if len(revisions) == 0 {
+		return errors.New("DatadogAgent ControllerRevisions not found")
+	}
+
+	out, err := k8syaml.Marshal(revisions)
+	if err != nil {
+		return err
+	}
+
+	return o.redactAndSave(filepath.Join(dir, "datadog-agent-controller-revisions.yaml"), out, cmd)
+}
+
+// createEventFiles gets the events of the DatadogAgents, the DatadogAgentInternals and their workloads
+func (o *options) createEventFiles(ctx context.Context, dir string, cmd *cobra.Command) error {
+	involvedObjects, err := o.getInvolvedObjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	eventList := &corev1.EventList{}
+	if err = o.Client.List(ctx, eventList, client.InNamespace(o.UserNamespace)); err != nil {
+		return err
+	}
+
+	var events []corev1.Event
+	for _, event := range eventList.Items {
+		if _, found := involvedObjects[involvedObjectKey(event.InvolvedObject.Kind, event.InvolvedObject.Name)]; found {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return errors.New("events not found")
+	}
+
+	out, err := k8syaml.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	return o.redactAndSave(filepath.Join(dir, "datadog-agent-events.yaml"), out, cmd)
+}
+
+// getInvolvedObjects returns the keys of the DatadogAgents, the DatadogAgentInternals and the workloads they manage
+func (o *options) getInvolvedObjects(ctx context.Context) (map[string]struct{}, error) {
+	involvedObjects := map[string]struct{}{}
+
+	ddaList := &v2alpha1.DatadogAgentList{}
+	if err := o.Client.List(ctx, ddaList, client.InNamespace(o.UserNamespace)); err != nil {
+		return nil, err
+	}
+	for _, dda := range ddaList.Items {
+		involvedObjects[involvedObjectKey(datadogAgentKind, dda.Name)] = struct{}{}
+	}
+
+	ddaiList := &v1alpha1.DatadogAgentInternalList{}
+	if err := o.Client.List(ctx, ddaiList, client.InNamespace(o.UserNamespace)); err != nil {
+		return nil, err
+	}
+	for _, ddai := range ddaiList.Items {
+		involvedObjects[involvedObjectKey(datadogAgentInternalKind, ddai.Name)] = struct{}{}
+		if ddai.Status.Agent != nil && ddai.Status.Agent.DaemonsetName != "" {
+			involvedObjects[involvedObjectKey("DaemonSet", ddai.Status.Agent.DaemonsetName)] = struct{}{}
+			involvedObjects[involvedObjectKey("ExtendedDaemonSet", ddai.Status.Agent.DaemonsetName)] = struct{}{}
+		}
+		for _, status := range []*v2alpha1.DeploymentStatus{ddai.Status.ClusterAgent, ddai.Status.ClusterChecksRunner, ddai.Status.OtelAgentGateway} {
+			if status != nil && status.DeploymentName != "" {
+				involvedObjects[involvedObjectKey("Deployment", status.DeploymentName)] = struct{}{}
+			}
+		}
+	}
+
+	return involvedObjects, nil
+}
+
+// createRenderedManifestFiles renders the resources the operator would create for each DatadogAgent,
+// so that they can be compared against the live state
+func (o *options) createRenderedManifestFiles(ctx context.Context, dir string, cmd *cobra.Command) error {
+	ddaList := &v2alpha1.DatadogAgentList{}
+	if err := o.Client.List(ctx, ddaList, client.InNamespace(o.UserNamespace)); err != nil {
+		return err
+	}
+	if len(ddaList.Items) == 0 {
+		return errors.New("custom resources not found")
+	}
+
+	// The DatadogAgentInternal CRD is read from the cluster: the renderer cannot load it
+	// from the source tree when running from a released binary.
+	crd, err := o.APIExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, ddaiCRDName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get the %s CRD: %w", ddaiCRDName, err)
+	}
+	cleanObjectMeta(&crd.ObjectMeta)
+
+	dapList := &v1alpha1.DatadogAgentProfileList{}
+	if err = o.Client.List(ctx, dapList, client.InNamespace(o.UserNamespace)); err != nil {
+		cmd.Println(fmt.Sprintf("Couldn't list DatadogAgentProfiles, rendering without profiles: %v", err))
+	}
+	daps := make([]*v1alpha1.DatadogAgentProfile, 0, len(dapList.Items))
+	for i := range dapList.Items {
+		dap := dapList.Items[i].DeepCopy()
+		cleanObjectMeta(&dap.ObjectMeta)
+		dap.Status = v1alpha1.DatadogAgentProfileStatus{}
+		daps = append(daps, dap)
+	}
+
+	for i := range ddaList.Items {
+		if err = o.renderDDA(&ddaList.Items[i], daps, crd, dir, cmd); err != nil {
+			cmd.Println(fmt.Sprintf("Couldn't render DatadogAgent %s: %v", ddaList.Items[i].Name, err))
+		}
+	}
+
+	return nil
+}
+
+// renderDDA renders a single DatadogAgent with operator-render and stores the sanitized output in a file
+func (o *options) renderDDA(dda *v2alpha1.DatadogAgent, daps []*v1alpha1.DatadogAgentProfile, crd *apiextensionsv1.CustomResourceDefinition, dir string, cmd *cobra.Command) error {
+	input := dda.DeepCopy()
+	cleanObjectMeta(&input.ObjectMeta)
+	input.Finalizers = nil
+	input.Status = v2alpha1.DatadogAgentStatus{}
+
+	resources, scheme, err := renderer.Render(renderer.Options{
+		DDA:            input,
+		DAPs:           daps,
+		ProfileEnabled: len(daps) > 0,
+		DDAICRD:        crd.DeepCopy(),
+	})
+	if err != nil {
+		return err
+	}
+	redactSecrets(resources)
+
+	out, err := renderer.Serialize(resources, scheme, "yaml", false)
+	if err != nil {
+		return err
+	}
+
+	return o.redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-rendered.yaml", dda.Name)), out, cmd)
+}
+
+// redactSecrets replaces the values of the rendered Secrets
+func redactSecrets(objs []client.Object) {
+	for _, obj := range objs {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			continue
+		}
+		if len(secret.Data) > 0 && secret.StringData == nil {
+			secret.StringData = make(map[string]string, len(secret.Data))
+		}
+		for key := range secret.Data {
+			secret.StringData[key] = scrubber.Redacted
+		}
+		for key := range secret.StringData {
+			secret.StringData[key] = scrubber.Redacted
+		}
+		secret.Data = nil
+	}
+}
+
+// cleanObjectMeta removes the server-populated metadata fields so that the object can be used as a render input
+func cleanObjectMeta(meta *metav1.ObjectMeta) {
+	meta.ResourceVersion = ""
+	meta.UID = ""
+	meta.Generation = 0
+	meta.ManagedFields = nil
+	meta.CreationTimestamp = metav1.Time{}
+}
+
+func isOwnedBy(refs []metav1.OwnerReference, kind string) bool {
+	for _, ref := range refs {
+		if ref.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func involvedObjectKey(kind, name string) string {
+	return kind + "/" + name
+}
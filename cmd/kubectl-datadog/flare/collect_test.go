@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+	"github.com/DataDog/datadog-operator/pkg/scrubber"
+)
+
+func newTestOptions(t *testing.T, objs ...client.Object) *options {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, v1alpha1.AddToScheme(s))
+	require.NoError(t, v2alpha1.AddToScheme(s))
+
+	return &options{
+		Options: common.Options{
+			Client:        fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+			UserNamespace: "datadog",
+		},
+		scrubber: scrubber.New(),
+	}
+}
+
+func newTestDDAI(name string) *v1alpha1.DatadogAgentInternal {
+	return &v1alpha1.DatadogAgentInternal{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: name},
+		Status: v1alpha1.DatadogAgentInternalStatus{
+			Agent:               &v2alpha1.DaemonSetStatus{DaemonsetName: name + "-agent"},
+			ClusterAgent:        &v2alpha1.DeploymentStatus{DeploymentName: name + "-cluster-agent"},
+			ClusterChecksRunner: &v2alpha1.DeploymentStatus{},
+		},
+	}
+}
+
+func newTestEvent(name, kind, involvedName string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "datadog", Name: name},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: involvedName},
+		Message:        "api_key: 0123456789abcdef0123456789abcdef",
+	}
+}
+
+func TestCreateDDAIFiles(t *testing.T) {
+	dir := t.TempDir()
+	otherNamespace := newTestDDAI("other")
+	otherNamespace.Namespace = "default"
+	o := newTestOptions(t, newTestDDAI("datadog"), otherNamespace)
+
+	require.NoError(t, o.createDDAIFiles(context.Background(), dir, &cobra.Command{}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "datadog-agent-internals.yaml"))
+	require.NoError(t, err)
+	var ddais []v1alpha1.DatadogAgentInternal
+	require.NoError(t, k8syaml.Unmarshal(data, &ddais))
+	require.Len(t, ddais, 1, "only the DatadogAgentInternals of the namespace are collected")
+	assert.Equal(t, "datadog", ddais[0].Name)
+
+	err = newTestOptions(t).createDDAIFiles(context.Background(), t.TempDir(), &cobra.Command{})
+	assert.EqualError(t, err, "DatadogAgentInternal resources not found")
+}
+
+func TestGetInvolvedObjects(t *testing.T) {
+	o := newTestOptions(t,
+		&v2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "datadog"}},
+		newTestDDAI("datadog"),
+	)
+
+	involvedObjects, err := o.getInvolvedObjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{
+		"DatadogAgent/datadog":             {},
+		"DatadogAgentInternal/datadog":     {},
+		"DaemonSet/datadog-agent":          {},
+		"ExtendedDaemonSet/datadog-agent":  {},
+		"Deployment/datadog-cluster-agent": {},
+	}, involvedObjects)
+}
+
+func TestCreateEventFiles(t *testing.T) {
+	dir := t.TempDir()
+	o := newTestOptions(t,
+		&v2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "datadog"}},
+		newTestDDAI("datadog"),
+		newTestEvent("dda-event", "DatadogAgent", "datadog"),
+		newTestEvent("daemonset-event", "DaemonSet", "datadog-agent"),
+		newTestEvent("unrelated-event", "Deployment", "web"),
+	)
+
+	require.NoError(t, o.createEventFiles(context.Background(), dir, &cobra.Command{}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "datadog-agent-events.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "0123456789abcdef0123456789abcdef", "the events are scrubbed")
+	var events []corev1.Event
+	require.NoError(t, k8syaml.Unmarshal(data, &events))
+	names := []string{}
+	for _, event := range events {
+		names = append(names, event.Name)
+	}
+	assert.ElementsMatch(t, []string{"dda-event", "daemonset-event"}, names)
+
+	o = newTestOptions(t, newTestEvent("unrelated-event", "Deployment", "web"))
+	err = o.createEventFiles(context.Background(), t.TempDir(), &cobra.Command{})
+	assert.EqualError(t, err, "events not found")
+}
+
+func TestRedactSecrets(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadog-secret"},
+		Data:       map[string][]byte{"api_key": []byte("0123456789abcdef0123456789abcdef")},
+		StringData: map[string]string{"token": "foo"},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadog-config"},
+		Data:       map[string]string{"datadog.yaml": "logs_enabled: true"},
+	}
+
+	redactSecrets([]client.Object{secret, configMap})
+
+	assert.Nil(t, secret.Data)
+	assert.Equal(t, map[string]string{"api_key": scrubber.Redacted, "token": scrubber.Redacted}, secret.StringData)
+	assert.Equal(t, map[string]string{"datadog.yaml": "logs_enabled: true"}, configMap.Data)
+}
@@ -34,6 +34,7 @@ import (
 
 	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+	"github.com/DataDog/datadog-operator/pkg/scrubber"
 	"github.com/DataDog/datadog-operator/pkg/version"
 )
 
@@ -43,15 +44,23 @@ const (
 )
 
 var (
-	email        string
-	apiKey       string
-	ddSite       string
-	flareExample = `
+	email         string
+	apiKey        string
+	ddSite        string
+	outputDir     string
+	scrubPatterns []string
+	flareExample  = `
   # send flare for an existing case 123 (api key from stdin)
   %[1]s flare 123 --email foo@bar.com
 
   # send flare and create a new case (email and api key from stdin)
   %[1]s flare
+
+  # create the flare archive in the current directory without uploading it
+  %[1]s flare --output-dir .
+
+  # also redact the values matching a custom pattern
+  %[1]s flare --scrub-pattern 'internal-[a-z0-9]+'
 `
 )
 
@@ -59,10 +68,11 @@ var (
 type options struct {
 	genericclioptions.IOStreams
 	common.Options
-	args   []string
-	zip    *archiver.Zip
-	site   string
-	caseID string
+	args     []string
+	zip      *archiver.Zip
+	scrubber *scrubber.Scrubber
+	site     string
+	caseID   string
 }
 
 // newOptions provides an instance of options with default values
@@ -70,6 +80,7 @@ func newOptions(streams genericclioptions.IOStreams) *options {
 	o := &options{
 		IOStreams: streams,
 		zip:       archiver.NewZip(),
+		scrubber:  scrubber.New(),
 	}
 	o.SetConfigFlags()
 	return o
@@ -97,6 +108,8 @@ func New(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVarP(&email, "email", "e", "", "Your email")
 	cmd.Flags().StringVarP(&apiKey, "apiKey", "k", "", "Your api key, could also be taken from stdin")
 	cmd.Flags().StringVarP(&ddSite, "ddSite", "d", "us", "Your Datadog site US or EU (default: US)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "", "", "Write the flare archive to this directory instead of uploading it to Datadog")
+	cmd.Flags().StringArrayVarP(&scrubPatterns, "scrub-pattern", "", nil, "Additional regular expression whose matches are redacted from the collected files, can be repeated")
 
 	o.ConfigFlags.AddFlags(cmd.Flags())
 
@@ -116,6 +129,15 @@ func (o *options) complete(cmd *cobra.Command, args []string) error {
 		o.caseID = args[0]
 	}
 
+	if err = o.scrubber.AddPatterns(scrubPatterns...); err != nil {
+		return err
+	}
+
+	// The flare is not uploaded, no need to ask for the upload information
+	if outputDir != "" {
+		return nil
+	}
+
 	if email == "" {
 		email, err = common.AskForInput("Please enter your email: ")
 		if err != nil {
@@ -146,6 +168,17 @@ func (o *options) validate() error {
 		return errors.New("either one or no arguments are allowed")
 	}
 
+	if outputDir != "" {
+		info, err := os.Stat(outputDir)
+		if err != nil {
+			return fmt.Errorf("invalid output directory: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("output directory %s is not a directory", outputDir)
+		}
+		return nil
+	}
+
 	if email == "" {
 		return errors.New("email is missing")
 	}
@@ -180,6 +213,26 @@ func (o *options) run(cmd *cobra.Command) error {
 		cmd.Println(fmt.Sprintf("Couldn't collect custom resources: %v", err))
 	}
 
+	// Collect the generated datadogagentinternal custom resources
+	if err := o.createDDAIFiles(ctx, baseDir, cmd); err != nil {
+		cmd.Println(fmt.Sprintf("Couldn't collect DatadogAgentInternal resources: %v", err))
+	}
+
+	// Collect the datadogagent controller revisions
+	if err := o.createControllerRevisionFiles(ctx, baseDir, cmd); err != nil {
+		cmd.Println(fmt.Sprintf("Couldn't collect controller revisions: %v", err))
+	}
+
+	// Collect the events of the datadogagent and its generated objects
+	if err := o.createEventFiles(ctx, baseDir, cmd); err != nil {
+		cmd.Println(fmt.Sprintf("Couldn't collect events: %v", err))
+	}
+
+	// Collect the resources the operator would render for the current datadogagent
+	if err := o.createRenderedManifestFiles(ctx, baseDir, cmd); err != nil {
+		cmd.Println(fmt.Sprintf("Couldn't render custom resources: %v", err))
+	}
+
 	// Collect logs from all operator pods
 	if err := o.createLogFiles(baseDir, cmd); err != nil {
 		cmd.Println(fmt.Sprintf("Couldn't collect log files: %v", err))
@@ -212,11 +265,16 @@ func (o *options) run(cmd *cobra.Command) error {
 	}
 
 	// Create zip with the collected files
-	zipFilePath := getArchivePath()
+	zipFilePath := getArchivePath(outputDir)
 	if err = o.zip.Archive([]string{baseDir}, zipFilePath); err != nil {
 		return err
 	}
 
+	if outputDir != "" {
+		cmd.Println("Flare archive successfully created:", zipFilePath)
+		return nil
+	}
+
 	// Get the operator version
 	version, err := o.getVersion(ctx, leaderPod)
 	if err != nil {
@@ -259,11 +317,11 @@ func (o *options) createCRFiles(dir string, cmd *cobra.Command) error {
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, "datadog-custom-resources.yaml"), template, cmd)
+	return o.redactAndSave(filepath.Join(dir, "datadog-custom-resources.yaml"), template, cmd)
 }
 
-// redactAndSave uses a redacting writer to write a new file
-func redactAndSave(filePath string, data []byte, cmd *cobra.Command) error {
+// redactAndSave uses a scrubbing writer to write a new file
+func (o *options) redactAndSave(filePath string, data []byte, cmd *cobra.Command) error {
 	file, err := createFile(filePath)
 	if err != nil {
 		return err
@@ -274,7 +332,7 @@ func redactAndSave(filePath string, data []byte, cmd *cobra.Command) error {
 		}
 	}()
 
-	writer := newRedactingWriter(file)
+	writer := scrubber.NewWriter(file, o.scrubber)
 
 	_, err = writer.Write(data)
 	return err
@@ -317,7 +375,7 @@ func (o *options) createDeploymentTemplate(dir string, cmd *cobra.Command) error
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, "datadog-operator-deployment.yaml"), template, cmd)
+	return o.redactAndSave(filepath.Join(dir, "datadog-operator-deployment.yaml"), template, cmd)
 }
 
 // createMetricsFile gets metrics payload and stores it in a file
@@ -340,7 +398,7 @@ func (o *options) createMetricsFile(pod *corev1.Pod, dir string, cmd *cobra.Comm
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-metrics.txt", pod.Name)), metrics, cmd)
+	return o.redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-metrics.txt", pod.Name)), metrics, cmd)
 }
 
 // createStatusFile gets status of a pod and stores it in a file
@@ -355,7 +413,7 @@ func (o *options) createStatusFile(pod *corev1.Pod, dir string, cmd *cobra.Comma
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-status.txt", pod.Name)), status, cmd)
+	return o.redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-status.txt", pod.Name)), status, cmd)
 }
 
 // createVersionFile gets the version from the operator pod and stores it in a file
@@ -371,7 +429,7 @@ func (o *options) createVersionFile(ctx context.Context, pod *corev1.Pod, dir st
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-version.txt", pod.Name)), version, cmd)
+	return o.redactAndSave(filepath.Join(dir, fmt.Sprintf("%s-version.txt", pod.Name)), version, cmd)
 }
 
 // getOperatorVersion gets the version from the operator pod
@@ -486,15 +544,18 @@ func (o *options) savePodLogs(pod corev1.Pod, dir string, cmd *cobra.Command) er
 		return err
 	}
 
-	return redactAndSave(filepath.Join(dir, fmt.Sprintf("%s.json", pod.Name)), logBytes, cmd)
+	return o.redactAndSave(filepath.Join(dir, fmt.Sprintf("%s.json", pod.Name)), logBytes, cmd)
 }
 
-// getArchivePath builds the zip file path in a temporary directory
-func getArchivePath() string {
+// getArchivePath builds the zip file path in dir, or in a temporary directory if dir is empty
+func getArchivePath(dir string) string {
 	timeString := time.Now().Format("2006-01-02-15-04-05")
 	fileName := strings.Join([]string{"datadog", "operator", timeString}, "-")
 	fileName = strings.Join([]string{fileName, "zip"}, ".")
-	return filepath.Join(os.TempDir(), fileName)
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fileName)
 }
 
 func createFile(path string) (*os.File, error) {
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+	"github.com/DataDog/datadog-operator/pkg/untaint"
+)
+
+// defaultProfileName is displayed for DatadogAgentInternals and nodes that are not bound to a DatadogAgentProfile.
+const defaultProfileName = "default"
+
+// report is the health view of one or several DatadogAgents.
+type report struct {
+	DatadogAgents []datadogAgentReport `json:"datadogAgents"`
+}
+
+// datadogAgentReport is the health view of a single DatadogAgent.
+type datadogAgentReport struct {
+	Namespace    string                              `json:"namespace"`
+	Name         string                              `json:"name"`
+	Conditions   []metav1.Condition                  `json:"conditions,omitempty"`
+	Internals    []internalReport                    `json:"datadogAgentInternals,omitempty"`
+	Nodes        []nodeReport                        `json:"nodes,omitempty"`
+	TaintedNodes []string                            `json:"taintedNodes,omitempty"`
+	RemoteConfig *v2alpha1.RemoteConfigConfiguration `json:"remoteConfig,omitempty"`
+	Experiment   *v2alpha1.ExperimentStatus          `json:"experiment,omitempty"`
+}
+
+// internalReport is the health view of a DatadogAgentInternal generated from a DatadogAgent.
+type internalReport struct {
+	Name                string                     `json:"name"`
+	Profile             string                     `json:"profile"`
+	Agent               *v2alpha1.DaemonSetStatus  `json:"agent,omitempty"`
+	ClusterAgent        *v2alpha1.DeploymentStatus `json:"clusterAgent,omitempty"`
+	ClusterChecksRunner *v2alpha1.DeploymentStatus `json:"clusterChecksRunner,omitempty"`
+}
+
+// nodeReport is the node Agent readiness on a single node.
+type nodeReport struct {
+	Name     string `json:"name"`
+	Profile  string `json:"profile"`
+	AgentPod string `json:"agentPod,omitempty"`
+	Ready    bool   `json:"ready"`
+	Tainted  bool   `json:"tainted"`
+}
+
+// buildReport collects the health view of the DatadogAgents in namespace, or of the DatadogAgent ddaName if set.
+func buildReport(ctx context.Context, c client.Client, namespace, ddaName string) (*report, error) {
+	ddaList := &v2alpha1.DatadogAgentList{}
+	if ddaName == "" {
+		if err := c.List(ctx, ddaList, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("unable to list DatadogAgent: %w", err)
+		}
+	} else {
+		dda := &v2alpha1.DatadogAgent{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ddaName}, dda)
+		if err != nil && apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("DatadogAgent %s/%s not found", namespace, ddaName)
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to get DatadogAgent: %w", err)
+		}
+		ddaList.Items = append(ddaList.Items, *dda)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := c.List(ctx, nodeList); err != nil {
+		return nil, fmt.Errorf("unable to list nodes: %w", err)
+	}
+
+	rep := &report{DatadogAgents: make([]datadogAgentReport, 0, len(ddaList.Items))}
+	for i := range ddaList.Items {
+		ddaReport, err := buildDatadogAgentReport(ctx, c, &ddaList.Items[i], nodeList.Items)
+		if err != nil {
+			return nil, err
+		}
+		rep.DatadogAgents = append(rep.DatadogAgents, *ddaReport)
+	}
+	return rep, nil
+}
+
+func buildDatadogAgentReport(ctx context.Context, c client.Client, dda *v2alpha1.DatadogAgent, nodes []corev1.Node) (*datadogAgentReport, error) {
+	ddaReport := &datadogAgentReport{
+		Namespace:    dda.Namespace,
+		Name:         dda.Name,
+		Conditions:   dda.Status.Conditions,
+		RemoteConfig: dda.Status.RemoteConfigConfiguration,
+		Experiment:   dda.Status.Experiment,
+	}
+
+	ddaiList := &v1alpha1.DatadogAgentInternalList{}
+	if err := c.List(ctx, ddaiList, client.InNamespace(dda.Namespace), client.MatchingLabels{apicommon.DatadogAgentNameLabelKey: dda.Name}); err != nil {
+		return nil, fmt.Errorf("unable to list DatadogAgentInternal: %w", err)
+	}
+	for _, ddai := range ddaiList.Items {
+		ddaReport.Internals = append(ddaReport.Internals, internalReport{
+			Name:                ddai.Name,
+			Profile:             profileName(ddai.Labels),
+			Agent:               ddai.Status.Agent,
+			ClusterAgent:        ddai.Status.ClusterAgent,
+			ClusterChecksRunner: ddai.Status.ClusterChecksRunner,
+		})
+	}
+	sort.Slice(ddaReport.Internals, func(i, j int) bool { return ddaReport.Internals[i].Name < ddaReport.Internals[j].Name })
+
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(dda.Namespace), client.MatchingLabels{
+		apicommon.AgentDeploymentComponentLabelKey: common.AgentLabelValue,
+		apicommon.AgentDeploymentNameLabelKey:      dda.Name,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to list Agent pods: %w", err)
+	}
+	podsByNode := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		// Prefer the Ready pod if several Agent pods run on the same node, e.g. during a rollout.
+		if existing, found := podsByNode[pod.Spec.NodeName]; found && common.IsPodReady(existing) {
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = pod
+	}
+
+	for _, node := range nodes {
+		nr := nodeReport{
+			Name:    node.Name,
+			Profile: profileName(node.Labels),
+			Tainted: hasAgentNotReadyTaint(&node),
+		}
+		if pod, found := podsByNode[node.Name]; found {
+			nr.AgentPod = pod.Name
+			nr.Ready = common.IsPodReady(pod)
+		}
+		if nr.Tainted {
+			ddaReport.TaintedNodes = append(ddaReport.TaintedNodes, node.Name)
+		}
+		ddaReport.Nodes = append(ddaReport.Nodes, nr)
+	}
+	sort.Slice(ddaReport.Nodes, func(i, j int) bool { return ddaReport.Nodes[i].Name < ddaReport.Nodes[j].Name })
+	sort.Strings(ddaReport.TaintedNodes)
+
+	return ddaReport, nil
+}
+
+// profileName returns the DatadogAgentProfile name from the profile label, or the default profile name.
+func profileName(labels map[string]string) string {
+	if name, found := labels[constants.ProfileLabelKey]; found && name != "" {
+		return name
+	}
+	return defaultProfileName
+}
+
+func hasAgentNotReadyTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == untaint.AgentNotReadyTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// render writes the report as a set of tables.
+func (r *report) render(out io.Writer) {
+	for i, dda := range r.DatadogAgents {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		dda.render(out)
+	}
+}
+
+func (r *datadogAgentReport) render(out io.Writer) {
+	fmt.Fprintf(out, "DatadogAgent %s/%s\n\n", r.Namespace, r.Name)
+
+	fmt.Fprintln(out, "Conditions:")
+	table := common.NewTable(out, "Type", "Status", "Reason", "Message")
+	for _, cond := range r.Conditions {
+		_ = table.Append([]string{cond.Type, string(cond.Status), cond.Reason, cond.Message})
+	}
+	_ = table.Render()
+
+	fmt.Fprintln(out, "\nDatadogAgentInternals:")
+	table = common.NewTable(out, "Name", "Profile", "Agent", "Desired", "Ready", "Up-To-Date", "Cluster-Agent", "Cluster-Checks-Runner")
+	for _, ddai := range r.Internals {
+		data := []string{ddai.Name, ddai.Profile, "", "", "", ""}
+		if ddai.Agent != nil {
+			data[2] = ddai.Agent.Status
+			data[3] = fmt.Sprint(ddai.Agent.Desired)
+			data[4] = fmt.Sprint(ddai.Agent.Ready)
+			data[5] = fmt.Sprint(ddai.Agent.UpToDate)
+		}
+		data = append(data, deploymentStatusString(ddai.ClusterAgent), deploymentStatusString(ddai.ClusterChecksRunner))
+		_ = table.Append(data)
+	}
+	_ = table.Render()
+
+	fmt.Fprintln(out, "\nNodes:")
+	table = common.NewTable(out, "Node", "Profile", "Agent-Pod", "Ready", "Not-Ready-Taint")
+	for _, node := range r.Nodes {
+		agentPod := node.AgentPod
+		if agentPod == "" {
+			agentPod = "<none>"
+		}
+		_ = table.Append([]string{node.Name, node.Profile, agentPod, fmt.Sprint(node.Ready), fmt.Sprint(node.Tainted)})
+	}
+	_ = table.Render()
+
+	if len(r.TaintedNodes) > 0 {
+		fmt.Fprintf(out, "\nNodes with the %s taint: %s\n", untaint.AgentNotReadyTaintKey, strings.Join(r.TaintedNodes, ", "))
+	}
+
+	fmt.Fprintln(out, "\nRemote Config:")
+	if r.RemoteConfig == nil || r.RemoteConfig.Features == nil {
+		fmt.Fprintln(out, "  no configuration received")
+	} else {
+		fmt.Fprintln(out, "  configuration received")
+	}
+
+	fmt.Fprintln(out, "\nExperiment:")
+	if r.Experiment == nil || r.Experiment.Phase == "" {
+		fmt.Fprintln(out, "  none")
+	} else {
+		fmt.Fprintf(out, "  phase: %s\n", r.Experiment.Phase)
+		fmt.Fprintf(out, "  id: %s\n", r.Experiment.ID)
+		if r.Experiment.StartedAt != nil {
+			fmt.Fprintf(out, "  started: %s\n", r.Experiment.StartedAt.Format("2006-01-02T15:04:05Z07:00"))
+		}
+		if r.Experiment.TerminationReason != "" {
+			fmt.Fprintf(out, "  termination reason: %s\n", r.Experiment.TerminationReason)
+		}
+	}
+}
+
+func deploymentStatusString(status *v2alpha1.DeploymentStatus) string {
+	if status == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%d/%d)", status.Status, status.ReadyReplicas, status.Replicas)
+}
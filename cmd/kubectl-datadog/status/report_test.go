@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/untaint"
+)
+
+func Test_buildReport(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, v1alpha1.AddToScheme(s))
+	require.NoError(t, v2alpha1.AddToScheme(s))
+
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "dda"},
+		Status: v2alpha1.DatadogAgentStatus{
+			Conditions: []metav1.Condition{{Type: "DatadogAgentReconcileError", Status: metav1.ConditionFalse}},
+			Experiment: &v2alpha1.ExperimentStatus{Phase: v2alpha1.ExperimentPhaseRunning, ID: "exp-1"},
+		},
+	}
+	defaultDDAI := &v1alpha1.DatadogAgentInternal{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "dda", Labels: map[string]string{apicommon.DatadogAgentNameLabelKey: "dda"}},
+		Status: v1alpha1.DatadogAgentInternalStatus{
+			Agent: &v2alpha1.DaemonSetStatus{Desired: 2, Ready: 1, UpToDate: 2, Status: "Progressing (2/1/2)"},
+		},
+	}
+	profileDDAI := &v1alpha1.DatadogAgentInternal{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "dda-profile-gpu", Labels: map[string]string{
+			apicommon.DatadogAgentNameLabelKey: "dda",
+			constants.ProfileLabelKey:          "gpu",
+		}},
+	}
+	otherDDAI := &v1alpha1.DatadogAgentInternal{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "other", Labels: map[string]string{apicommon.DatadogAgentNameLabelKey: "other"}},
+	}
+	nodes := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{constants.ProfileLabelKey: "gpu"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{untaint.AgentNotReadyTaint()}},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+	}
+	agentLabels := map[string]string{
+		apicommon.AgentDeploymentComponentLabelKey: "agent",
+		apicommon.AgentDeploymentNameLabelKey:      "dda",
+	}
+	pods := []client.Object{
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "agent-a", Labels: agentLabels},
+			Spec:       corev1.PodSpec{NodeName: "node-a"},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "agent-b", Labels: agentLabels},
+			Spec:       corev1.PodSpec{NodeName: "node-b"},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(dda, defaultDDAI, profileDDAI, otherDDAI).
+		WithObjects(nodes...).
+		WithObjects(pods...).
+		WithStatusSubresource(&v2alpha1.DatadogAgent{}, &v1alpha1.DatadogAgentInternal{}).
+		Build()
+
+	rep, err := buildReport(context.TODO(), c, "datadog", "dda")
+	require.NoError(t, err)
+	require.Len(t, rep.DatadogAgents, 1)
+
+	got := rep.DatadogAgents[0]
+	assert.Equal(t, "dda", got.Name)
+	assert.Len(t, got.Conditions, 1)
+	assert.Equal(t, v2alpha1.ExperimentPhaseRunning, got.Experiment.Phase)
+
+	require.Len(t, got.Internals, 2)
+	assert.Equal(t, internalReport{Name: "dda", Profile: defaultProfileName, Agent: defaultDDAI.Status.Agent}, got.Internals[0])
+	assert.Equal(t, internalReport{Name: "dda-profile-gpu", Profile: "gpu"}, got.Internals[1])
+
+	assert.Equal(t, []nodeReport{
+		{Name: "node-a", Profile: defaultProfileName, AgentPod: "agent-a", Ready: true},
+		{Name: "node-b", Profile: "gpu", AgentPod: "agent-b", Ready: false, Tainted: true},
+		{Name: "node-c", Profile: defaultProfileName},
+	}, got.Nodes)
+	assert.Equal(t, []string{"node-b"}, got.TaintedNodes)
+
+	var out bytes.Buffer
+	rep.render(&out)
+	assert.Contains(t, out.String(), "DatadogAgent datadog/dda")
+	assert.Contains(t, out.String(), "phase: running")
+
+	_, err = buildReport(context.TODO(), c, "datadog", "missing")
+	assert.EqualError(t, err, "DatadogAgent datadog/missing not found")
+}
@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var statusExample = `
+  # view the health of all DatadogAgents in the current namespace
+  %[1]s status
+
+  # view the health of DatadogAgent foo
+  %[1]s status foo
+
+  # view the health of DatadogAgent foo as JSON
+  %[1]s status foo -o json
+`
+
+// options provides information required by Datadog status command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args                 []string
+	userDatadogAgentName string
+	output               string
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "status" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "status [DatadogAgent name]",
+		Short:        "Show the health of DatadogAgent(s), their generated DatadogAgentInternals and the node Agents",
+		Example:      fmt.Sprintf(statusExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run(c)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", outputTable, "Output format. One of: table, json")
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	if len(args) > 0 {
+		o.userDatadogAgentName = args[0]
+	}
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.args) > 1 {
+		return errors.New("either one or no arguments are allowed")
+	}
+	if o.output != outputTable && o.output != outputJSON {
+		return fmt.Errorf("unsupported output format %q, must be one of: table, json", o.output)
+	}
+	return nil
+}
+
+// run runs the status command.
+func (o *options) run(cmd *cobra.Command) error {
+	rep, err := buildReport(cmd.Context(), o.Client, o.UserNamespace, o.userDatadogAgentName)
+	if err != nil {
+		return err
+	}
+
+	if o.output == outputJSON {
+		out, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode status: %w", err)
+		}
+		_, err = fmt.Fprintln(o.Out, string(out))
+		return err
+	}
+
+	rep.render(o.Out)
+	return nil
+}
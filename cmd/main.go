@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	apimversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
@@ -46,10 +48,12 @@ import (
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/internal/controller"
 	"github.com/DataDog/datadog-operator/internal/controller/metrics"
+	"github.com/DataDog/datadog-operator/internal/controller/prometheusmonitor"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/constants"
 	"github.com/DataDog/datadog-operator/pkg/controller/debug"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/metadata"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 	"github.com/DataDog/datadog-operator/pkg/fleet"
 	"github.com/DataDog/datadog-operator/pkg/introspection"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -68,6 +72,7 @@ const (
 	defaultDatadogGenericResourceMaxConcurrentReconciles = 1
 	defaultDatadogGenericResourceRequeuePeriod           = 60 * time.Second
 	podNamespaceEnvVar                                   = "POD_NAMESPACE"
+	monitorWebhookSecretEnvVar                           = "DD_MONITOR_WEBHOOK_SECRET"
 )
 
 var (
@@ -143,6 +148,7 @@ type options struct {
 	createControllerRevisions              bool
 	datadogMonitorEnabled                  bool
 	datadogSLOEnabled                      bool
+	datadogTemplateEnabled                 bool
 	operatorMetricsEnabled                 bool
 	maximumGoroutines                      int
 	introspectionEnabled                   bool
@@ -150,6 +156,7 @@ type options struct {
 	remoteConfigEnabled                    bool
 	remoteUpdatesEnabled                   bool
 	managedAgentInstallationEnabled        bool
+	fleetExperimentConfigMap               string
 	datadogDashboardEnabled                bool
 	datadogGenericResourceEnabled          bool
 	datadogGenericResourceMaxWorkers       int
@@ -158,6 +165,15 @@ type options struct {
 	untaintControllerEnabled               bool
 	untaintControllerWaitForCSIDriver      bool
 	rolloutOnConfigMapChangeEnabled        bool
+	prometheusMonitorTranslationEnabled    bool
+	prometheusMonitorConfigMap             string
+	watchNamespaces                        string
+	reconcileShards                        int
+	datadogAPIRateLimit                    int
+	datadogAPIRateLimitBurst               int
+	monitorWebhookBindAddress              string
+	monitorWorkloadEventsEnabled           bool
+	monitorWorkloadEventsAllNamespaces     bool
 
 	// Secret Backend options
 	secretBackendCommand  string
@@ -188,6 +204,7 @@ func (opts *options) Parse() {
 	flag.BoolVar(&opts.datadogAgentEnabled, "datadogAgentEnabled", true, "Enable the DatadogAgent controller")
 	flag.BoolVar(&opts.datadogMonitorEnabled, "datadogMonitorEnabled", false, "Enable the DatadogMonitor controller")
 	flag.BoolVar(&opts.datadogSLOEnabled, "datadogSLOEnabled", false, "Enable the DatadogSLO controller")
+	flag.BoolVar(&opts.datadogTemplateEnabled, "datadogTemplateEnabled", false, "Enable the DatadogMonitorTemplate and DatadogSLOTemplate controllers")
 	flag.BoolVar(&opts.operatorMetricsEnabled, "operatorMetricsEnabled", true, "Enable sending operator metrics to Datadog")
 	flag.IntVar(&opts.maximumGoroutines, "maximumGoroutines", defaultMaximumGoroutines, "Override health check threshold for maximum number of goroutines.")
 	flag.BoolVar(&opts.introspectionEnabled, "introspectionEnabled", false, "Enable introspection (beta)")
@@ -195,6 +212,8 @@ func (opts *options) Parse() {
 	flag.BoolVar(&opts.remoteConfigEnabled, "remoteConfigEnabled", false, "Enable RemoteConfig capabilities in the Operator (beta)")
 	flag.BoolVar(&opts.remoteUpdatesEnabled, "remoteUpdatesEnabled", false, "Enable Remote Updates capabilities in the Operator (beta)")
 	flag.BoolVar(&opts.managedAgentInstallationEnabled, "managedAgentInstallationEnabled", false, "Enable managed Agent installation intents")
+	flag.StringVar(&opts.fleetExperimentConfigMap, "fleetExperimentConfigMap", "",
+		"Read DatadogAgent experiment tasks from this ConfigMap (name or namespace/name) instead of Remote Config. Cannot be combined with --remoteUpdatesEnabled")
 	flag.BoolVar(&opts.datadogDashboardEnabled, "datadogDashboardEnabled", false, "Enable the DatadogDashboard controller")
 	flag.BoolVar(&opts.datadogGenericResourceEnabled, "datadogGenericResourceEnabled", false, "Enable the DatadogGenericResource controller")
 	flag.IntVar(&opts.datadogGenericResourceMaxWorkers, "datadogGenericResourceMaxConcurrentReconciles", defaultDatadogGenericResourceMaxConcurrentReconciles, "Maximum number of concurrent DatadogGenericResource reconciles")
@@ -205,6 +224,22 @@ func (opts *options) Parse() {
 		"When true (requires --untaintControllerEnabled), the Untaint controller removes the startup taint only after both the node Agent and Datadog CSI node-server pods are Ready. Requires Pod watch coverage of CSI namespaces (DD_CSIDRIVER_WATCH_NAMESPACE).")
 	flag.BoolVar(&opts.rolloutOnConfigMapChangeEnabled, "rolloutOnConfigMapChangeEnabled", true,
 		"Automatically roll out Agent/Cluster Agent/Cluster Check Runner/OTel Agent Gateway workloads when a ConfigMap referenced by their pod template changes content out-of-band")
+	flag.BoolVar(&opts.prometheusMonitorTranslationEnabled, "prometheusMonitorTranslationEnabled", false,
+		"Translate Prometheus Operator ServiceMonitors and PodMonitors into OpenMetrics cluster checks written to the --prometheusMonitorConfigMap ConfigMap")
+	flag.StringVar(&opts.prometheusMonitorConfigMap, "prometheusMonitorConfigMap", prometheusmonitor.DefaultCheckConfigsConfigMapName,
+		"ConfigMap, as name in the operator namespace or namespace/name, where the checks translated from Prometheus Operator monitors are written, to be mounted in the Cluster Agent")
+	flag.StringVar(&opts.watchNamespaces, "watchNamespaces", "",
+		"Comma-separated list of namespaces the operator is restricted to. Only the DatadogMonitor, DatadogSLO, DatadogDashboard and DatadogGenericResource controllers can be enabled, and the operator only needs namespace-scoped permissions")
+	flag.IntVar(&opts.reconcileShards, "reconcileShards", 0,
+		"Spread the DatadogMonitor and DatadogGenericResource reconciliation across the operator replicas in this number of shards, each held through a Lease. 0 or 1 disables sharding")
+	flag.IntVar(&opts.datadogAPIRateLimit, "datadogAPIRateLimit", datadogclient.DefaultRateLimit, "Maximum number of Datadog API requests per second sent by the operator. 0 disables the limit")
+	flag.IntVar(&opts.datadogAPIRateLimitBurst, "datadogAPIRateLimitBurst", datadogclient.DefaultRateLimitBurst, "Maximum burst of Datadog API requests sent by the operator")
+	flag.StringVar(&opts.monitorWebhookBindAddress, "monitorWebhookBindAddress", "",
+		"Address of the receiver of the Datadog webhook notifications updating the DatadogMonitor states, for example :8384. The secret shared with the webhook is read from "+monitorWebhookSecretEnvVar+". Empty disables the receiver")
+	flag.BoolVar(&opts.monitorWorkloadEventsEnabled, "monitorWorkloadEventsEnabled", true,
+		"Record events on the Deployments, Pods, Nodes and other Kubernetes objects named by the tags of the triggered and recovered DatadogMonitor groups")
+	flag.BoolVar(&opts.monitorWorkloadEventsAllNamespaces, "monitorWorkloadEventsAllNamespaces", false,
+		"Record the DatadogMonitor group events on the objects of all the namespaces and on the Nodes, instead of only on the objects of the namespace of the DatadogMonitor")
 
 	// DatadogAgentInternal
 	flag.BoolVar(&opts.createControllerRevisions, "createControllerRevisions", false, "Enable creation of ControllerRevision snapshots on each DDA spec change")
@@ -235,6 +270,7 @@ func (opts *options) Parse() {
 		boolEnv(&opts.datadogAgentEnabled, "DD_AGENT_CONTROLLER_ENABLED"),
 		boolEnv(&opts.datadogMonitorEnabled, "DD_MONITOR_CONTROLLER_ENABLED"),
 		boolEnv(&opts.datadogSLOEnabled, "DD_SLO_CONTROLLER_ENABLED"),
+		boolEnv(&opts.datadogTemplateEnabled, "DD_TEMPLATE_CONTROLLER_ENABLED"),
 		boolEnv(&opts.operatorMetricsEnabled, "DD_OPERATOR_METRICS_ENABLED"),
 		intEnv(&opts.maximumGoroutines, "DD_MAXIMUM_GOROUTINES"),
 		boolEnv(&opts.introspectionEnabled, "DD_INTROSPECTION_ENABLED"),
@@ -242,6 +278,7 @@ func (opts *options) Parse() {
 		boolEnv(&opts.remoteConfigEnabled, "DD_REMOTE_CONFIG_ENABLED"),
 		boolEnv(&opts.remoteUpdatesEnabled, "DD_REMOTE_UPDATES_ENABLED"),
 		boolEnv(&opts.managedAgentInstallationEnabled, "DD_MANAGED_AGENT_INSTALLATION_ENABLED"),
+		stringEnv(&opts.fleetExperimentConfigMap, "DD_FLEET_EXPERIMENT_CONFIGMAP"),
 		boolEnv(&opts.datadogDashboardEnabled, "DD_DASHBOARD_CONTROLLER_ENABLED"),
 		boolEnv(&opts.datadogGenericResourceEnabled, "DD_GENERIC_RESOURCE_CONTROLLER_ENABLED"),
 		intEnv(&opts.datadogGenericResourceMaxWorkers, "DD_GENERIC_RESOURCE_MAX_CONCURRENT_RECONCILES"),
@@ -251,6 +288,15 @@ func (opts *options) Parse() {
 		boolEnv(&opts.untaintControllerWaitForCSIDriver, "DD_UNTAINT_CONTROLLER_WAIT_FOR_CSI_DRIVER"),
 		boolEnv(&opts.createControllerRevisions, "DD_CREATE_CONTROLLER_REVISIONS"),
 		boolEnv(&opts.rolloutOnConfigMapChangeEnabled, "DD_ROLLOUT_ON_CONFIGMAP_CHANGE_ENABLED"),
+		boolEnv(&opts.prometheusMonitorTranslationEnabled, "DD_PROMETHEUS_MONITOR_TRANSLATION_ENABLED"),
+		stringEnv(&opts.prometheusMonitorConfigMap, "DD_PROMETHEUS_MONITOR_CONFIGMAP"),
+		stringEnv(&opts.watchNamespaces, "DD_WATCH_NAMESPACES"),
+		intEnv(&opts.reconcileShards, "DD_RECONCILE_SHARDS"),
+		intEnv(&opts.datadogAPIRateLimit, "DD_API_RATE_LIMIT"),
+		intEnv(&opts.datadogAPIRateLimitBurst, "DD_API_RATE_LIMIT_BURST"),
+		stringEnv(&opts.monitorWebhookBindAddress, "DD_MONITOR_WEBHOOK_BIND_ADDRESS"),
+		boolEnv(&opts.monitorWorkloadEventsEnabled, "DD_MONITOR_WORKLOAD_EVENTS_ENABLED"),
+		boolEnv(&opts.monitorWorkloadEventsAllNamespaces, "DD_MONITOR_WORKLOAD_EVENTS_ALL_NAMESPACES"),
 	})
 
 	// Parsing flags
@@ -336,6 +382,36 @@ func run(opts *options) error {
 		return setupErrorf(setupLog, fmt.Errorf("invalid flags"), "--untaintControllerWaitForCSIDriver requires --untaintControllerEnabled=true")
 	}
 
+	watchNamespaces := config.ParseNamespaces(opts.watchNamespaces)
+	if len(watchNamespaces) > 0 {
+		if err := opts.validateNamespaceScope(); err != nil {
+			return setupErrorf(setupLog, err, "Invalid flags for the namespace-scoped mode")
+		}
+		setupLog.Info("Running in namespace-scoped mode", "namespaces", watchNamespaces)
+	}
+
+	shardLeaseNamespace := strings.TrimSpace(os.Getenv(podNamespaceEnvVar))
+	if opts.reconcileShards > 1 && shardLeaseNamespace == "" {
+		return setupErrorf(setupLog, fmt.Errorf("%s is empty", podNamespaceEnvVar), "--reconcileShards requires the namespace of the operator to create its Leases")
+	}
+
+	monitorWebhookSecret := os.Getenv(monitorWebhookSecretEnvVar)
+	if err := opts.validateMonitorWebhook(monitorWebhookSecret); err != nil {
+		return setupErrorf(setupLog, err, "Invalid flags for the DatadogMonitor webhook receiver")
+	}
+
+	if err := opts.validateFleetExperimentConfigMap(); err != nil {
+		return setupErrorf(setupLog, err, "Invalid flags for the Fleet ConfigMap task source")
+	}
+
+	var prometheusMonitorConfigMap types.NamespacedName
+	if opts.prometheusMonitorTranslationEnabled {
+		var err error
+		if prometheusMonitorConfigMap, err = configMapKey(opts.prometheusMonitorConfigMap, os.Getenv(podNamespaceEnvVar)); err != nil {
+			return setupErrorf(setupLog, err, "Invalid --prometheusMonitorConfigMap")
+		}
+	}
+
 	// submits the maximum go routine setting as a metric
 	metrics.MaxGoroutines.Set(float64(opts.maximumGoroutines))
 
@@ -360,6 +436,7 @@ func run(opts *options) error {
 	// Dispatch CLI flags to each package
 	secrets.SetSecretBackendCommand(opts.secretBackendCommand)
 	secrets.SetSecretBackendArgs(opts.secretBackendArgs)
+	datadogclient.SetRateLimit(float64(opts.datadogAPIRateLimit), opts.datadogAPIRateLimitBurst)
 
 	renewDeadline := opts.leaderElectionLeaseDuration / 2
 	retryPeriod := opts.leaderElectionLeaseDuration / 4
@@ -419,6 +496,7 @@ func run(opts *options) error {
 			DatadogAgentEnabled:               opts.datadogAgentEnabled,
 			DatadogMonitorEnabled:             opts.datadogMonitorEnabled,
 			DatadogSLOEnabled:                 opts.datadogSLOEnabled,
+			DatadogTemplateEnabled:            opts.datadogTemplateEnabled,
 			DatadogAgentProfileEnabled:        opts.datadogAgentProfileEnabled,
 			IntrospectionEnabled:              opts.introspectionEnabled,
 			DatadogDashboardEnabled:           opts.datadogDashboardEnabled,
@@ -428,6 +506,7 @@ func run(opts *options) error {
 			UntaintControllerWaitForCSIDriver: opts.untaintControllerWaitForCSIDriver,
 			ManagedAgentInstallationEnabled:   managedAgentInstallationEnabled,
 			ManagedAgentInstallationNamespace: managedAgentInstallationNamespace,
+			WatchNamespaces:                   watchNamespaces,
 		}),
 		// UsePriorityQueue makes all controllers use the priority queue, which
 		// directly registers workqueue metrics into controller-runtime's metrics
@@ -502,6 +581,12 @@ func run(opts *options) error {
 		}()
 	}
 
+	if opts.fleetExperimentConfigMap != "" {
+		if err = setupFleetConfigMapSource(setupLog, mgr, opts); err != nil {
+			return setupErrorf(setupLog, err, "Unable to setup Fleet ConfigMap task source")
+		}
+	}
+
 	providerDetector, err := setupAndStartProviderDetector(setupLog, mgr,
 		opts.introspectionEnabled || opts.datadogAgentProfileEnabled || opts.untaintControllerEnabled)
 	if err != nil {
@@ -522,25 +607,35 @@ func run(opts *options) error {
 			CanaryAutoPauseMaxSlowStartDuration: opts.edsCanaryAutoPauseMaxSlowStartDuration,
 			MaxPodSchedulerFailure:              opts.edsMaxPodSchedulerFailure,
 		},
-		SupportCilium:                     opts.supportCilium,
-		CredsManager:                      credsManager,
-		DatadogAgentEnabled:               opts.datadogAgentEnabled,
-		CreateControllerRevisions:         opts.createControllerRevisions && opts.datadogAgentEnabled,
-		DatadogMonitorEnabled:             opts.datadogMonitorEnabled,
-		DatadogSLOEnabled:                 opts.datadogSLOEnabled,
-		OperatorMetricsEnabled:            opts.operatorMetricsEnabled,
-		V2APIEnabled:                      true,
-		IntrospectionEnabled:              opts.introspectionEnabled,
-		DatadogAgentProfileEnabled:        opts.datadogAgentProfileEnabled,
-		DatadogDashboardEnabled:           opts.datadogDashboardEnabled,
-		DatadogGenericResourceEnabled:     opts.datadogGenericResourceEnabled,
-		DatadogGenericResourceMaxWorkers:  opts.datadogGenericResourceMaxWorkers,
-		DatadogGenericResourceRequeue:     opts.datadogGenericResourceRequeuePeriod,
-		DatadogCSIDriverEnabled:           opts.datadogCSIDriverEnabled,
-		UntaintControllerEnabled:          opts.untaintControllerEnabled,
-		UntaintControllerWaitForCSIDriver: opts.untaintControllerWaitForCSIDriver,
-		RolloutOnConfigMapChangeEnabled:   opts.rolloutOnConfigMapChangeEnabled,
-		ClusterProviderDetector:           providerDetector,
+		SupportCilium:                       opts.supportCilium,
+		CredsManager:                        credsManager,
+		DatadogAgentEnabled:                 opts.datadogAgentEnabled,
+		CreateControllerRevisions:           opts.createControllerRevisions && opts.datadogAgentEnabled,
+		DatadogMonitorEnabled:               opts.datadogMonitorEnabled,
+		DatadogSLOEnabled:                   opts.datadogSLOEnabled,
+		DatadogTemplateEnabled:              opts.datadogTemplateEnabled,
+		OperatorMetricsEnabled:              opts.operatorMetricsEnabled,
+		V2APIEnabled:                        true,
+		IntrospectionEnabled:                opts.introspectionEnabled,
+		DatadogAgentProfileEnabled:          opts.datadogAgentProfileEnabled,
+		DatadogDashboardEnabled:             opts.datadogDashboardEnabled,
+		DatadogGenericResourceEnabled:       opts.datadogGenericResourceEnabled,
+		DatadogGenericResourceMaxWorkers:    opts.datadogGenericResourceMaxWorkers,
+		DatadogGenericResourceRequeue:       opts.datadogGenericResourceRequeuePeriod,
+		DatadogCSIDriverEnabled:             opts.datadogCSIDriverEnabled,
+		UntaintControllerEnabled:            opts.untaintControllerEnabled,
+		UntaintControllerWaitForCSIDriver:   opts.untaintControllerWaitForCSIDriver,
+		RolloutOnConfigMapChangeEnabled:     opts.rolloutOnConfigMapChangeEnabled,
+		PrometheusMonitorTranslationEnabled: opts.prometheusMonitorTranslationEnabled,
+		PrometheusMonitorConfigMap:          prometheusMonitorConfigMap,
+		ClusterProviderDetector:             providerDetector,
+		WatchNamespaces:                     watchNamespaces,
+		ReconcileShards:                     opts.reconcileShards,
+		ShardLeaseNamespace:                 shardLeaseNamespace,
+		MonitorWebhookBindAddress:           opts.monitorWebhookBindAddress,
+		MonitorWebhookSecret:                monitorWebhookSecret,
+		MonitorWorkloadEventsEnabled:        opts.monitorWorkloadEventsEnabled,
+		MonitorWorkloadEventsAllNamespaces:  opts.monitorWorkloadEventsAllNamespaces,
 	}
 
 	versionInfo, platformInfo, err := getVersionAndPlatformInfo(rest.CopyConfig(mgr.GetConfig()))
@@ -828,10 +923,98 @@ func setupFleetDaemon(logger logr.Logger, mgr manager.Manager, rcClient remoteco
 	return mgr.Add(daemon)
 }
 
+// setupFleetConfigMapSource runs the Fleet daemon with experiment tasks read
+// from a ConfigMap, for clusters that cannot use Remote Config. The flags are
+// checked by validateFleetExperimentConfigMap.
+func setupFleetConfigMapSource(logger logr.Logger, mgr manager.Manager, opts *options) error {
+	key, err := configMapKey(opts.fleetExperimentConfigMap, os.Getenv(podNamespaceEnvVar))
+	if err != nil {
+		return fmt.Errorf("invalid --fleetExperimentConfigMap: %w", err)
+	}
+	source := fleet.NewConfigMapTaskSource(mgr, key)
+	if err = mgr.Add(source); err != nil {
+		return err
+	}
+	logger.Info("Fleet experiment tasks read from ConfigMap", "configmap", key.String())
+	return setupFleetDaemon(logger, mgr, source, true, fleet.ManagedAgentInstallationIdentity{}, "", false)
+}
+
+// configMapKey parses a ConfigMap reference in the name or namespace/name
+// form. A bare name refers to a ConfigMap in the operator namespace.
+func configMapKey(ref, operatorNamespace string) (types.NamespacedName, error) {
+	namespace, name, found := strings.Cut(strings.TrimSpace(ref), "/")
+	if !found {
+		namespace, name = operatorNamespace, namespace
+	}
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("invalid ConfigMap %q: expected name or namespace/name, with %s set for a bare name", ref, podNamespaceEnvVar)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
 func (opts *options) operatorManagedAgentInstallationEnabled(identity fleet.ManagedAgentInstallationIdentity) bool {
 	return identity.Configured() && identity.Validate() == nil && opts.managedAgentInstallationEnabled && opts.remoteConfigEnabled && opts.remoteUpdatesEnabled && opts.datadogAgentEnabled && opts.datadogAgentProfileEnabled && opts.createControllerRevisions
 }
 
+// validateMonitorWebhook returns an error when the webhook receiver is enabled without the
+// DatadogMonitor controller or without a secret to authenticate the notifications.
+func (opts *options) validateMonitorWebhook(secret string) error {
+	if opts.monitorWebhookBindAddress == "" {
+		return nil
+	}
+	if !opts.datadogMonitorEnabled {
+		return errors.New("--monitorWebhookBindAddress requires --datadogMonitorEnabled")
+	}
+	if secret == "" {
+		return fmt.Errorf("--monitorWebhookBindAddress requires %s", monitorWebhookSecretEnvVar)
+	}
+	return nil
+}
+
+// validateFleetExperimentConfigMap returns an error when the Fleet ConfigMap task source
+// is combined with remote updates, as a single daemon owns the experiment state, or
+// enabled without the ControllerRevisions the experiments roll back to.
+func (opts *options) validateFleetExperimentConfigMap() error {
+	if opts.fleetExperimentConfigMap == "" {
+		return nil
+	}
+	if opts.remoteConfigEnabled && opts.remoteUpdatesEnabled {
+		return errors.New("--fleetExperimentConfigMap cannot be combined with --remoteUpdatesEnabled")
+	}
+	if !opts.createControllerRevisions || !opts.datadogAgentEnabled {
+		return errors.New("--fleetExperimentConfigMap requires --createControllerRevisions and --datadogAgentEnabled")
+	}
+	return nil
+}
+
+// validateNamespaceScope returns an error listing the enabled options that
+// require cluster-wide permissions, which the namespace-scoped mode does not grant.
+func (opts *options) validateNamespaceScope() error {
+	clusterScopedOptions := []struct {
+		flag    string
+		enabled bool
+	}{
+		{"datadogAgentEnabled", opts.datadogAgentEnabled},
+		{"datadogAgentProfileEnabled", opts.datadogAgentProfileEnabled},
+		{"datadogCSIDriverEnabled", opts.datadogCSIDriverEnabled},
+		{"untaintControllerEnabled", opts.untaintControllerEnabled},
+		{"introspectionEnabled", opts.introspectionEnabled},
+		{"remoteConfigEnabled", opts.remoteConfigEnabled},
+		{"fleetExperimentConfigMap", opts.fleetExperimentConfigMap != ""},
+		{"prometheusMonitorTranslationEnabled", opts.prometheusMonitorTranslationEnabled},
+	}
+	var enabled []string
+	for _, option := range clusterScopedOptions {
+		if option.enabled {
+			enabled = append(enabled, "--"+option.flag)
+		}
+	}
+	if len(enabled) > 0 {
+		return fmt.Errorf("--watchNamespaces only supports the DatadogMonitor, DatadogSLO, DatadogDashboard and DatadogGenericResource controllers, disable %s", strings.Join(enabled, ", "))
+	}
+	return nil
+}
+
 // setupAndStartProviderDetector registers the cluster-provider detector as a
 // leader-only manager Runnable. It runs only on the elected leader, after cache
 // sync, and never blocks startup: Stage-1 operator-node detection uses the uncached
@@ -20,6 +20,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestOperatorManagedAgentInstallationEnabled(t *testing.T) {
@@ -65,6 +66,33 @@ func TestOperatorManagedAgentInstallationEnabled(t *testing.T) {
 	}
 }
 
+func TestConfigMapKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		namespace string
+		want      types.NamespacedName
+		wantErr   bool
+	}{
+		{name: "bare name", ref: "fleet-experiments", namespace: "datadog", want: types.NamespacedName{Namespace: "datadog", Name: "fleet-experiments"}},
+		{name: "namespace and name", ref: "gitops/fleet-experiments", namespace: "datadog", want: types.NamespacedName{Namespace: "gitops", Name: "fleet-experiments"}},
+		{name: "bare name without operator namespace", ref: "fleet-experiments", wantErr: true},
+		{name: "empty name", ref: "gitops/", namespace: "datadog", wantErr: true},
+		{name: "too many segments", ref: "a/b/c", namespace: "datadog", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configMapKey(tt.ref, tt.namespace)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestOptionsParse_EnvOverridesDefaults(t *testing.T) {
 	resetCommandLine(t)
 	t.Setenv("DD_METRICS_ADDR", ":9090")
@@ -152,6 +180,78 @@ func TestOptionsParse_InvalidEnvLeavesDefault(t *testing.T) {
 	require.False(t, opts.datadogMonitorEnabled)
 }
 
+func TestOptionsParse_WatchNamespacesFromEnv(t *testing.T) {
+	resetCommandLine(t)
+	t.Setenv("DD_WATCH_NAMESPACES", "team-a,team-b")
+
+	var opts options
+	opts.Parse()
+
+	require.Equal(t, "team-a,team-b", opts.watchNamespaces)
+}
+
+func TestOptionsParse_ShardingAndRateLimitFromEnv(t *testing.T) {
+	resetCommandLine(t)
+	t.Setenv("DD_RECONCILE_SHARDS", "4")
+	t.Setenv("DD_API_RATE_LIMIT", "5")
+	t.Setenv("DD_API_RATE_LIMIT_BURST", "10")
+
+	var opts options
+	opts.Parse()
+
+	require.Equal(t, 4, opts.reconcileShards)
+	require.Equal(t, 5, opts.datadogAPIRateLimit)
+	require.Equal(t, 10, opts.datadogAPIRateLimitBurst)
+}
+
+func TestValidateMonitorWebhook(t *testing.T) {
+	require.NoError(t, (&options{}).validateMonitorWebhook(""), "the receiver is disabled")
+	require.NoError(t, (&options{monitorWebhookBindAddress: ":8384", datadogMonitorEnabled: true}).validateMonitorWebhook("secret"))
+	require.ErrorContains(t, (&options{monitorWebhookBindAddress: ":8384"}).validateMonitorWebhook("secret"), "--datadogMonitorEnabled")
+	require.ErrorContains(t, (&options{monitorWebhookBindAddress: ":8384", datadogMonitorEnabled: true}).validateMonitorWebhook(""), monitorWebhookSecretEnvVar)
+}
+
+func TestValidateFleetExperimentConfigMap(t *testing.T) {
+	require.NoError(t, (&options{}).validateFleetExperimentConfigMap(), "the ConfigMap source is disabled")
+	require.NoError(t, (&options{fleetExperimentConfigMap: "fleet", createControllerRevisions: true, datadogAgentEnabled: true}).validateFleetExperimentConfigMap())
+	require.ErrorContains(t, (&options{fleetExperimentConfigMap: "fleet", datadogAgentEnabled: true}).validateFleetExperimentConfigMap(), "--createControllerRevisions")
+	require.ErrorContains(t, (&options{fleetExperimentConfigMap: "fleet", createControllerRevisions: true}).validateFleetExperimentConfigMap(), "--datadogAgentEnabled")
+	require.ErrorContains(t, (&options{fleetExperimentConfigMap: "fleet", createControllerRevisions: true, datadogAgentEnabled: true, remoteConfigEnabled: true, remoteUpdatesEnabled: true}).validateFleetExperimentConfigMap(), "--remoteUpdatesEnabled")
+}
+
+func TestValidateNamespaceScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    options
+		wantErr string
+	}{
+		{
+			name: "namespaced controllers only",
+			opts: options{datadogMonitorEnabled: true, datadogSLOEnabled: true, datadogDashboardEnabled: true, datadogGenericResourceEnabled: true},
+		},
+		{
+			name:    "DatadogAgent controller enabled",
+			opts:    options{datadogAgentEnabled: true, datadogMonitorEnabled: true},
+			wantErr: "disable --datadogAgentEnabled",
+		},
+		{
+			name:    "several cluster-scoped options enabled",
+			opts:    options{introspectionEnabled: true, untaintControllerEnabled: true, fleetExperimentConfigMap: "experiments"},
+			wantErr: "disable --untaintControllerEnabled, --introspectionEnabled, --fleetExperimentConfigMap",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validateNamespaceScope()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
 func resetCommandLine(t *testing.T, args ...string) {
 	t.Helper()
 
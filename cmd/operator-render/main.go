@@ -15,7 +15,8 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
-	"github.com/DataDog/datadog-operator/internal/controller/testutils/renderer"
+	"github.com/DataDog/datadog-operator/pkg/renderer"
+	"github.com/DataDog/datadog-operator/pkg/scrubber"
 )
 
 func main() {
@@ -29,7 +30,10 @@ func main() {
 		k8sVersion     string
 	)
 
-	var dapFiles []string
+	var (
+		dapFiles      []string
+		scrubPatterns []string
+	)
 
 	flag.StringVar(&ddaFile, "dda", "", "Path to DatadogAgent YAML file (required)")
 	flag.StringVar(&outputFile, "output", "", "Output file path (default: stdout)")
@@ -42,6 +46,10 @@ func main() {
 		dapFiles = append(dapFiles, s)
 		return nil
 	})
+	flag.Func("scrub-pattern", "Additional regular expression redacted from the logs and the debug output (repeatable)", func(s string) error {
+		scrubPatterns = append(scrubPatterns, s)
+		return nil
+	})
 	flag.Parse()
 
 	if ddaFile == "" {
@@ -54,7 +62,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	log, err := buildLogger(logLevel)
+	s := scrubber.New()
+	if err := s.AddPatterns(scrubPatterns...); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	log, err := buildLogger(logLevel, s)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -92,6 +106,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The debug output includes the input statuses, which are not meant to be shared as is
+	if debug {
+		out, err = s.ScrubBytes(out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error scrubbing output: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if outputFile == "" {
 		os.Stdout.Write(out)
 	} else {
@@ -106,7 +129,8 @@ func main() {
 // "none" returns a discard logger (no output).
 // "info" logs at Info level to stderr.
 // "debug" logs at Debug level (V(1)+) to stderr.
-func buildLogger(level string) (logr.Logger, error) {
+// Log lines are scrubbed with s before being written.
+func buildLogger(level string, s *scrubber.Scrubber) (logr.Logger, error) {
 	if level == "none" {
 		return logr.Discard(), nil
 	}
@@ -118,11 +142,8 @@ func buildLogger(level string) (logr.Logger, error) {
 		return logr.Discard(), fmt.Errorf("--log-level must be none, info, or debug; got %q", level)
 	}
 
-	cfg := zap.NewDevelopmentConfig()
-	cfg.Level = zap.NewAtomicLevelAt(zapLevel)
-	z, err := cfg.Build(zap.WithCaller(false))
-	if err != nil {
-		return logr.Discard(), fmt.Errorf("building logger: %w", err)
-	}
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	sink := zapcore.Lock(zapcore.AddSync(scrubber.NewLogWriter(os.Stderr, s)))
+	z := zap.New(zapcore.NewCore(encoder, sink, zap.NewAtomicLevelAt(zapLevel)), zap.Development())
 	return zapr.NewLogger(z), nil
 }
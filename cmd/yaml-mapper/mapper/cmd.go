@@ -39,6 +39,8 @@ type Options struct {
 	Namespace   string
 	UpdateMap   bool
 	PrintOutput bool
+	Verify      bool
+	ChartPath   string
 }
 
 // NewOptions provides an instance of Options with default values.
@@ -85,6 +87,8 @@ func NewCmdMap(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVarP(&o.HeaderPath, "headerPath", "p", "", "Path to header YAML file. The content in this file will be prepended to the output.")
 	cmd.Flags().BoolVarP(&o.UpdateMap, "updateMap", "u", false, fmt.Sprintf("Update 'mappingPath' with provided 'sourcePath'. If set to 'true', default mappingPath is %s and default sourcePath is latest published Datadog chart values.yaml.", constants.DefaultDDAMappingPath))
 	cmd.Flags().BoolVarP(&o.PrintOutput, "printOutput", "o", true, "print mapped DDA output to stdout")
+	cmd.Flags().BoolVarP(&o.Verify, "verify", "", false, "Render the Helm chart with the source values and the operator with the mapped DDA, then report semantic differences and unmapped keys. Exits with a non-zero code if any are found.")
+	cmd.Flags().StringVarP(&o.ChartPath, "chartPath", "c", "", "Path to the datadog Helm chart directory or archive. Required with `--verify`.")
 	o.ConfigFlags.AddFlags(cmd.Flags())
 
 	// Hide default k8s cli-runtime flags from usage
@@ -116,6 +120,14 @@ func (o *Options) Validate() error {
 		return fmt.Errorf("`--sourcePath` flag is required")
 	}
 
+	if o.Verify && o.ChartPath == "" {
+		return fmt.Errorf("`--chartPath` flag is required with `--verify`")
+	}
+
+	if o.Verify && o.UpdateMap {
+		return fmt.Errorf("`--verify` cannot be used with `--updateMap`")
+	}
+
 	if len(o.Args) > 1 {
 		return fmt.Errorf("received %v arguments. Only 1 argument allowed", len(o.Args))
 	}
@@ -138,6 +150,13 @@ func (o *Options) Init(cmd *cobra.Command) error {
 		}
 	}
 
+	if o.ChartPath != "" {
+		o.ChartPath, err = ResolveFilePath(o.ChartPath)
+		if err != nil {
+			return fmt.Errorf("could not resolve chart path: %v: %w", o.ChartPath, err)
+		}
+	}
+
 	if o.DestPath != "" {
 		// Ignore the err since we will create the file later if it doesn't exist
 		destPath, err := ResolveFilePath(o.DestPath)
@@ -164,6 +183,8 @@ func (o *Options) Run() {
 		UpdateMap:   o.UpdateMap,
 		PrintOutput: o.PrintOutput,
 		HeaderPath:  o.HeaderPath,
+		Verify:      o.Verify,
+		ChartPath:   o.ChartPath,
 	}
 	newMapper := NewMapper(mapperConfig)
 	err := newMapper.Run()
@@ -56,12 +56,17 @@ type MapConfig struct {
 	UpdateMap   bool
 	PrintOutput bool
 	HeaderPath  string
+	Verify      bool
+	ChartPath   string
 }
 
 // Mapper Yaml mapper contains the mapper config and collection of mapping functions.
 type Mapper struct {
 	MapProcessors map[string]MappingRunFunc
 	MapConfig
+
+	// unmappedKeys Helm source keys that could not be mapped to the DDA during the last run.
+	unmappedKeys []string
 }
 
 // NewMapper Returns a new Mapper instance.
@@ -91,6 +96,17 @@ func (m *Mapper) Run() error {
 		return err
 	}
 
+	if config.Verify {
+		report, err := m.verify(sourceValues, dda)
+		if err != nil {
+			return err
+		}
+		report.Write(os.Stdout)
+		if report.Failed() {
+			return fmt.Errorf("%w: %d semantic difference(s), %d unmapped key(s)", ErrVerificationFailed, len(report.Differences), len(report.UnmappedKeys))
+		}
+	}
+
 	if errCount > 0 {
 		return fmt.Errorf("mapping completed with %d error(s): the mapped DDA may contain misconfigurations", errCount)
 	}
@@ -167,6 +183,7 @@ func (m *Mapper) loadInputs() (mappingValues chartutil.Values, sourceValues char
 func (m *Mapper) mapValues(sourceValues chartutil.Values, mappingValues chartutil.Values) (map[string]any, int) {
 	var errorCount int
 	var ddaName = m.MapConfig.DDAName
+	m.unmappedKeys = nil
 	var interim = map[string]any{}
 
 	if m.MapConfig.HeaderPath == "" {
@@ -213,6 +230,7 @@ func (m *Mapper) mapValues(sourceValues chartutil.Values, mappingValues chartuti
 		destKey, _ := mappingValues[sourceKey]
 		if (destKey == "" || destKey == nil) && !shouldSkipMappingKey(sourceKey) {
 			slog.Error("DDA destination key not found", "sourceKey", sourceKey)
+			m.unmappedKeys = append(m.unmappedKeys, sourceKey)
 			errorCount++
 			continue
 		}
@@ -271,6 +289,7 @@ func (m *Mapper) mapValues(sourceValues chartutil.Values, mappingValues chartuti
 		visited, ok := utils.GetPathBool(v, "visited")
 		if ok && !visited && !shouldSkipMappingKey(k) {
 			slog.Error("source value key was not found in mapping", "key", k)
+			m.unmappedKeys = append(m.unmappedKeys, k)
 			errorCount++
 		}
 	}
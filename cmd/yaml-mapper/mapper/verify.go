@@ -0,0 +1,443 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package mapper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/renderer"
+)
+
+// ErrVerificationFailed is returned when the verification report contains differences or unmapped keys.
+var ErrVerificationFailed = errors.New("mapping verification failed")
+
+const (
+	componentLabelKey = "agent.datadoghq.com/component"
+
+	nodeAgentComponent           = "agent"
+	clusterAgentComponent        = "cluster-agent"
+	clusterChecksRunnerComponent = "cluster-checks-runner"
+)
+
+// Difference category names used in the verification report.
+const (
+	diffCategoryWorkload  = "workload"
+	diffCategoryContainer = "container"
+	diffCategoryEnv       = "env"
+	diffCategoryPort      = "port"
+	diffCategoryVolume    = "volume"
+	diffCategoryRBAC      = "rbac"
+)
+
+// Difference describes a single semantic difference between the Helm-rendered and the operator-rendered resources.
+type Difference struct {
+	Category  string
+	Component string
+	Container string
+	Key       string
+	HelmValue string
+	DDAValue  string
+}
+
+// String returns a human readable representation of the difference.
+func (d Difference) String() string {
+	location := d.Component
+	if d.Container != "" {
+		location += "/" + d.Container
+	}
+	if location == "" {
+		location = "cluster"
+	}
+	return fmt.Sprintf("[%s] %s %s: helm=%q dda=%q", d.Category, location, d.Key, d.HelmValue, d.DDAValue)
+}
+
+// VerifyReport is the result of a round-trip verification of a mapped DDA.
+type VerifyReport struct {
+	UnmappedKeys []string
+	Differences  []Difference
+}
+
+// Failed returns true if the report contains differences or unmapped keys.
+func (r *VerifyReport) Failed() bool {
+	return len(r.UnmappedKeys) > 0 || len(r.Differences) > 0
+}
+
+// Write writes the report in a human readable format.
+func (r *VerifyReport) Write(w io.Writer) {
+	fmt.Fprintf(w, "\nVerification report:\n\n")
+	fmt.Fprintf(w, "Unmapped Helm keys (%d):\n", len(r.UnmappedKeys))
+	for _, k := range r.UnmappedKeys {
+		fmt.Fprintf(w, "  - %s\n", k)
+	}
+	fmt.Fprintf(w, "\nSemantic differences (%d):\n", len(r.Differences))
+	for _, d := range r.Differences {
+		fmt.Fprintf(w, "  - %s\n", d)
+	}
+	fmt.Fprintln(w)
+}
+
+// verify renders the Helm chart with the source values and the operator with the mapped DDA,
+// then compares the pod templates and RBAC rules of both renders.
+func (m *Mapper) verify(sourceValues chartutil.Values, dda map[string]any) (*VerifyReport, error) {
+	ddaObj, err := decodeDDA(dda)
+	if err != nil {
+		return nil, err
+	}
+	if ddaObj.Namespace == "" {
+		ddaObj.Namespace = "default"
+	}
+
+	helmObjs, err := renderHelmChart(m.MapConfig.ChartPath, ddaObj.Name, ddaObj.Namespace, sourceValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Helm chart: %w", err)
+	}
+	ddaObjs, err := renderDDA(ddaObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render DatadogAgent: %w", err)
+	}
+
+	report := &VerifyReport{
+		UnmappedKeys: append([]string{}, m.unmappedKeys...),
+		Differences:  compareRenders(helmObjs, ddaObjs),
+	}
+	sort.Strings(report.UnmappedKeys)
+
+	return report, nil
+}
+
+// decodeDDA converts the mapped DDA map into a typed DatadogAgent.
+func decodeDDA(dda map[string]any) (*v2alpha1.DatadogAgent, error) {
+	out, err := yaml.Marshal(dda)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding DDA object: %w", err)
+	}
+	ddaObj := &v2alpha1.DatadogAgent{}
+	if err = yaml.UnmarshalStrict(out, ddaObj); err != nil {
+		return nil, fmt.Errorf("mapped DDA does not match the DatadogAgent schema: %w", err)
+	}
+	return ddaObj, nil
+}
+
+// renderHelmChart renders the chart located at chartPath, client side only, with the provided values.
+func renderHelmChart(chartPath, releaseName, namespace string, values chartutil.Values) ([]*unstructured.Unstructured, error) {
+	if chartPath == "" {
+		return nil, fmt.Errorf("a chart path is required to render the Helm chart")
+	}
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading chart %s: %w", chartPath, err)
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...any) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = false
+	install.ReleaseName = releaseName
+	install.Namespace = namespace
+
+	rel, err := install.Run(chart, values)
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifests([]byte(rel.Manifest))
+}
+
+// renderDDA renders the resources the operator would create for the provided DDA.
+func renderDDA(dda *v2alpha1.DatadogAgent) ([]*unstructured.Unstructured, error) {
+	resources, scheme, err := renderer.Render(renderer.Options{DDA: dda})
+	if err != nil {
+		return nil, err
+	}
+	out, err := renderer.Serialize(resources, scheme, "yaml", false)
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifests(out)
+}
+
+// decodeManifests decodes a multi-document YAML stream into unstructured objects.
+func decodeManifests(manifests []byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		obj := map[string]any{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error decoding manifest: %w", err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: obj})
+	}
+	return objs, nil
+}
+
+// renderedResources holds the parts of a render that are compared during verification.
+type renderedResources struct {
+	podTemplates map[string]*corev1.PodTemplateSpec
+	rbacRules    map[string]struct{}
+}
+
+// compareRenders returns the semantic differences between the Helm-rendered and the operator-rendered objects.
+func compareRenders(helmObjs, ddaObjs []*unstructured.Unstructured) []Difference {
+	helm := collectRenderedResources(helmObjs)
+	dda := collectRenderedResources(ddaObjs)
+
+	var diffs []Difference
+	for _, component := range unionKeys(helm.podTemplates, dda.podTemplates) {
+		helmTpl, ddaTpl := helm.podTemplates[component], dda.podTemplates[component]
+		switch {
+		case helmTpl == nil:
+			diffs = append(diffs, Difference{Category: diffCategoryWorkload, Component: component, Key: "present", HelmValue: "false", DDAValue: "true"})
+		case ddaTpl == nil:
+			diffs = append(diffs, Difference{Category: diffCategoryWorkload, Component: component, Key: "present", HelmValue: "true", DDAValue: "false"})
+		default:
+			diffs = append(diffs, comparePodTemplates(component, helmTpl, ddaTpl)...)
+		}
+	}
+
+	for _, rule := range unionKeys(helm.rbacRules, dda.rbacRules) {
+		_, inHelm := helm.rbacRules[rule]
+		_, inDDA := dda.rbacRules[rule]
+		if inHelm != inDDA {
+			diffs = append(diffs, Difference{Category: diffCategoryRBAC, Key: rule, HelmValue: fmt.Sprint(inHelm), DDAValue: fmt.Sprint(inDDA)})
+		}
+	}
+
+	return diffs
+}
+
+// collectRenderedResources extracts the pod templates of the agent workloads and the RBAC rules from a render.
+func collectRenderedResources(objs []*unstructured.Unstructured) renderedResources {
+	res := renderedResources{
+		podTemplates: map[string]*corev1.PodTemplateSpec{},
+		rbacRules:    map[string]struct{}{},
+	}
+	for _, obj := range objs {
+		switch obj.GetKind() {
+		case "DaemonSet", "Deployment", "ExtendedDaemonSet":
+			component := workloadComponent(obj)
+			if component == "" {
+				continue
+			}
+			rawTpl, found, err := unstructured.NestedMap(obj.Object, "spec", "template")
+			if err != nil || !found {
+				continue
+			}
+			tpl := &corev1.PodTemplateSpec{}
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawTpl, tpl); err != nil {
+				continue
+			}
+			res.podTemplates[component] = tpl
+		case "ClusterRole", "Role":
+			rawRules, found, err := unstructured.NestedSlice(obj.Object, "rules")
+			if err != nil || !found {
+				continue
+			}
+			for _, rawRule := range rawRules {
+				ruleMap, ok := rawRule.(map[string]any)
+				if !ok {
+					continue
+				}
+				rule := rbacv1.PolicyRule{}
+				if err = runtime.DefaultUnstructuredConverter.FromUnstructured(ruleMap, &rule); err != nil {
+					continue
+				}
+				for _, key := range flattenPolicyRule(obj.GetKind(), rule) {
+					res.rbacRules[key] = struct{}{}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// workloadComponent returns the agent component a workload belongs to, or an empty string if it is not an agent workload.
+// The operator labels its workloads with the component name; Helm chart workloads are identified by kind and name suffix.
+func workloadComponent(obj *unstructured.Unstructured) string {
+	if component, ok := obj.GetLabels()[componentLabelKey]; ok {
+		return component
+	}
+	name := obj.GetName()
+	switch {
+	case obj.GetKind() == "DaemonSet":
+		return nodeAgentComponent
+	case strings.HasSuffix(name, "-cluster-agent"):
+		return clusterAgentComponent
+	case strings.HasSuffix(name, "-clusterchecks"):
+		return clusterChecksRunnerComponent
+	}
+	return ""
+}
+
+// flattenPolicyRule expands a policy rule into one key per kind, API group, resource and verb.
+func flattenPolicyRule(kind string, rule rbacv1.PolicyRule) []string {
+	var keys []string
+	for _, url := range rule.NonResourceURLs {
+		for _, verb := range rule.Verbs {
+			keys = append(keys, fmt.Sprintf("%s nonResourceURL=%s verb=%s", kind, url, verb))
+		}
+	}
+	groups := rule.APIGroups
+	if len(groups) == 0 {
+		groups = []string{""}
+	}
+	for _, group := range groups {
+		for _, resource := range rule.Resources {
+			for _, verb := range rule.Verbs {
+				keys = append(keys, fmt.Sprintf("%s apiGroup=%q resource=%s verb=%s", kind, group, resource, verb))
+			}
+		}
+	}
+	return keys
+}
+
+// comparePodTemplates compares the containers, env vars, ports and volumes of two pod templates.
+func comparePodTemplates(component string, helmTpl, ddaTpl *corev1.PodTemplateSpec) []Difference {
+	var diffs []Difference
+
+	helmContainers := containersByName(helmTpl.Spec)
+	ddaContainers := containersByName(ddaTpl.Spec)
+	for _, name := range unionKeys(helmContainers, ddaContainers) {
+		hc, dc := helmContainers[name], ddaContainers[name]
+		if hc == nil || dc == nil {
+			diffs = append(diffs, Difference{Category: diffCategoryContainer, Component: component, Container: name, Key: "present", HelmValue: fmt.Sprint(hc != nil), DDAValue: fmt.Sprint(dc != nil)})
+			continue
+		}
+		diffs = append(diffs, compareValueMaps(diffCategoryEnv, component, name, envValues(hc.Env), envValues(dc.Env))...)
+		diffs = append(diffs, compareValueMaps(diffCategoryPort, component, name, portValues(hc.Ports), portValues(dc.Ports))...)
+		diffs = append(diffs, compareValueMaps(diffCategoryVolume, component, name, mountValues(hc.VolumeMounts), mountValues(dc.VolumeMounts))...)
+	}
+
+	diffs = append(diffs, compareValueMaps(diffCategoryVolume, component, "", volumeValues(helmTpl.Spec.Volumes), volumeValues(ddaTpl.Spec.Volumes))...)
+
+	return diffs
+}
+
+// compareValueMaps reports keys whose values differ between both maps, including keys that only exist in one of them.
+func compareValueMaps(category, component, container string, helm, dda map[string]string) []Difference {
+	var diffs []Difference
+	for _, key := range unionKeys(helm, dda) {
+		hv, inHelm := helm[key]
+		dv, inDDA := dda[key]
+		if inHelm && inDDA && hv == dv {
+			continue
+		}
+		if !inHelm {
+			hv = "<unset>"
+		}
+		if !inDDA {
+			dv = "<unset>"
+		}
+		diffs = append(diffs, Difference{Category: category, Component: component, Container: container, Key: key, HelmValue: hv, DDAValue: dv})
+	}
+	return diffs
+}
+
+// containersByName indexes the containers and init containers of a pod spec by name.
+func containersByName(spec corev1.PodSpec) map[string]*corev1.Container {
+	containers := map[string]*corev1.Container{}
+	for i := range spec.InitContainers {
+		containers["init:"+spec.InitContainers[i].Name] = &spec.InitContainers[i]
+	}
+	for i := range spec.Containers {
+		containers[spec.Containers[i].Name] = &spec.Containers[i]
+	}
+	return containers
+}
+
+// envValues returns the env vars of a container keyed by name.
+// References are compared by their source key rather than by the referenced object name,
+// since Helm and the operator name secrets and config maps differently.
+func envValues(env []corev1.EnvVar) map[string]string {
+	values := make(map[string]string, len(env))
+	for _, e := range env {
+		switch {
+		case e.ValueFrom == nil:
+			values[e.Name] = e.Value
+		case e.ValueFrom.SecretKeyRef != nil:
+			values[e.Name] = "secretKeyRef:" + e.ValueFrom.SecretKeyRef.Key
+		case e.ValueFrom.ConfigMapKeyRef != nil:
+			values[e.Name] = "configMapKeyRef:" + e.ValueFrom.ConfigMapKeyRef.Key
+		case e.ValueFrom.FieldRef != nil:
+			values[e.Name] = "fieldRef:" + e.ValueFrom.FieldRef.FieldPath
+		case e.ValueFrom.ResourceFieldRef != nil:
+			values[e.Name] = "resourceFieldRef:" + e.ValueFrom.ResourceFieldRef.Resource
+		default:
+			values[e.Name] = "valueFrom"
+		}
+	}
+	return values
+}
+
+// portValues returns the ports of a container keyed by container port and protocol.
+func portValues(ports []corev1.ContainerPort) map[string]string {
+	values := make(map[string]string, len(ports))
+	for _, p := range ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		values[fmt.Sprintf("%d/%s", p.ContainerPort, protocol)] = fmt.Sprintf("hostPort=%d", p.HostPort)
+	}
+	return values
+}
+
+// mountValues returns the volume mounts of a container keyed by mount path.
+func mountValues(mounts []corev1.VolumeMount) map[string]string {
+	values := make(map[string]string, len(mounts))
+	for _, vm := range mounts {
+		values["mount:"+vm.MountPath] = fmt.Sprintf("readOnly=%t", vm.ReadOnly)
+	}
+	return values
+}
+
+// volumeValues returns the host path volumes of a pod keyed by host path.
+// Other volume types are compared through the container mount paths, since their names are implementation details.
+func volumeValues(volumes []corev1.Volume) map[string]string {
+	values := map[string]string{}
+	for _, v := range volumes {
+		if v.HostPath != nil {
+			values["hostPath:"+v.HostPath.Path] = "present"
+		}
+	}
+	return values
+}
+
+// unionKeys returns the sorted union of the keys of both maps.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package mapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helmManifests = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: datadog
+spec:
+  template:
+    spec:
+      containers:
+      - name: agent
+        env:
+        - name: DD_SITE
+          value: datadoghq.eu
+        - name: DD_API_KEY
+          valueFrom:
+            secretKeyRef:
+              name: datadog
+              key: api-key
+        - name: DD_LOGS_ENABLED
+          value: "true"
+        ports:
+        - containerPort: 8125
+          protocol: UDP
+        volumeMounts:
+        - name: procdir
+          mountPath: /host/proc
+          readOnly: true
+      volumes:
+      - name: procdir
+        hostPath:
+          path: /proc
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: datadog-cluster-agent
+spec:
+  template:
+    spec:
+      containers:
+      - name: cluster-agent
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: datadog-kube-state-metrics
+spec:
+  template:
+    spec:
+      containers:
+      - name: kube-state-metrics
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: datadog
+rules:
+- apiGroups: [""]
+  resources: ["nodes"]
+  verbs: ["get", "list"]
+`
+
+const ddaManifests = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: datadog-agent
+  labels:
+    agent.datadoghq.com/component: agent
+spec:
+  template:
+    spec:
+      containers:
+      - name: agent
+        env:
+        - name: DD_SITE
+          value: datadoghq.eu
+        - name: DD_API_KEY
+          valueFrom:
+            secretKeyRef:
+              name: datadog-secret
+              key: api-key
+        ports:
+        - containerPort: 8125
+          protocol: UDP
+        volumeMounts:
+        - name: procdir
+          mountPath: /host/proc
+          readOnly: true
+      volumes:
+      - name: procdir
+        hostPath:
+          path: /proc
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: datadog-cluster-agent
+  labels:
+    agent.datadoghq.com/component: cluster-agent
+spec:
+  template:
+    spec:
+      containers:
+      - name: cluster-agent
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: datadog-agent
+rules:
+- apiGroups: [""]
+  resources: ["nodes"]
+  verbs: ["get"]
+`
+
+func TestCompareRenders(t *testing.T) {
+	helmObjs, err := decodeManifests([]byte(helmManifests))
+	require.NoError(t, err)
+	ddaObjs, err := decodeManifests([]byte(ddaManifests))
+	require.NoError(t, err)
+
+	diffs := compareRenders(helmObjs, ddaObjs)
+
+	assert.Equal(t, []Difference{
+		{Category: diffCategoryEnv, Component: "agent", Container: "agent", Key: "DD_LOGS_ENABLED", HelmValue: "true", DDAValue: "<unset>"},
+		{Category: diffCategoryRBAC, Key: `ClusterRole apiGroup="" resource=nodes verb=list`, HelmValue: "true", DDAValue: "false"},
+	}, diffs)
+}
+
+func TestVerifyReportFailed(t *testing.T) {
+	assert.False(t, (&VerifyReport{}).Failed())
+	assert.True(t, (&VerifyReport{UnmappedKeys: []string{"datadog.foo"}}).Failed())
+	assert.True(t, (&VerifyReport{Differences: []Difference{{Category: diffCategoryEnv}}}).Failed())
+}
+
+func TestMapValuesUnmappedKeys(t *testing.T) {
+	mapper := NewMapper(MapConfig{})
+	source := map[string]any{
+		"datadog": map[string]any{
+			"site":       "datadoghq.eu",
+			"unknownKey": "value",
+		},
+	}
+	mapping := map[string]any{
+		"datadog.site": "spec.global.site",
+	}
+
+	_, errCount := mapper.mapValues(source, mapping)
+
+	assert.Equal(t, 1, errCount)
+	assert.Equal(t, []string{"datadog.unknownKey"}, mapper.unmappedKeys)
+}
@@ -61,6 +61,12 @@ const (
 	ClusterProviderDetectedConditionType = "ClusterProviderDetected"
 	// FeatureNotSupportedOnProviderConditionType reports that an enabled feature is not supported on the detected provider
 	FeatureNotSupportedOnProviderConditionType = "FeatureNotSupportedOnProvider"
+	// KSMCustomResourcesValidConditionType reports whether the custom resources collected by the kube-state-metrics core check are served and match their schema
+	KSMCustomResourcesValidConditionType = "KubeStateMetricsCustomResourcesValid"
+	// RolloutDowntimeActiveConditionType reports whether a Datadog downtime scheduled by the rolloutDowntime policy is active
+	RolloutDowntimeActiveConditionType = "RolloutDowntimeActive"
+	// ResourceRecommendationsAvailableConditionType reports whether the resource recommender published recommendations for the Agent containers
+	ResourceRecommendationsAvailableConditionType = "ResourceRecommendationsAvailable"
 )
 
 const (
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+// socketReadinessProbe returns a readiness probe succeeding once a unix socket exists at path.
+func socketReadinessProbe(path string) *corev1.Probe {
+	return &corev1.Probe{
+		InitialDelaySeconds: constants.DefaultReadinessProbeInitialDelaySeconds,
+		PeriodSeconds:       constants.DefaultReadinessProbePeriodSeconds,
+		TimeoutSeconds:      constants.DefaultReadinessProbeTimeoutSeconds,
+		SuccessThreshold:    constants.DefaultReadinessProbeSuccessThreshold,
+		FailureThreshold:    constants.DefaultReadinessProbeFailureThreshold,
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"test", "-S", path},
+			},
+		},
+	}
+}
+
+// ApplyUntaintSocketReadinessProbes adds a readiness probe checking the socket of every
+// untaint readiness rule with a host path socket to the container creating it, so that
+// the untaint controller observes the socket through the container Ready status.
+// Containers missing from the pod are ignored. DatadogAgent validation rejects sockets in containers which
+// already define a readiness probe; such containers, if any, keep their own probe.
+func ApplyUntaintSocketReadinessProbes(logger logr.Logger, spec *corev1.PodSpec, untaintConfig *v2alpha1.UntaintConfig) {
+	if untaintConfig == nil {
+		return
+	}
+	for _, rule := range untaintConfig.ReadinessRules {
+		if rule.HostPathSocket == nil {
+			continue
+		}
+		for i := range spec.Containers {
+			container := &spec.Containers[i]
+			if container.Name != string(rule.HostPathSocket.Container) {
+				continue
+			}
+			if container.ReadinessProbe != nil {
+				logger.Info("Container already has a readiness probe, not adding the untaint socket probe",
+					"container", container.Name, "rule", rule.Name)
+				break
+			}
+			container.ReadinessProbe = socketReadinessProbe(rule.HostPathSocket.Path)
+			break
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+func apmSocketUntaintConfig() *v2alpha1.UntaintConfig {
+	return &v2alpha1.UntaintConfig{
+		ReadinessRules: []v2alpha1.UntaintReadinessRule{
+			{
+				Name: "apm-socket",
+				HostPathSocket: &v2alpha1.UntaintHostPathSocket{
+					Container: apicommon.TraceAgentContainerName,
+					Path:      "/var/run/datadog/apm.socket",
+				},
+			},
+		},
+	}
+}
+
+func TestApplyUntaintSocketReadinessProbes_addsProbe(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: string(apicommon.CoreAgentContainerName), ReadinessProbe: constants.GetDefaultReadinessProbe()},
+			{Name: string(apicommon.TraceAgentContainerName), LivenessProbe: constants.GetDefaultTraceAgentProbe()},
+		},
+	}
+
+	ApplyUntaintSocketReadinessProbes(logr.Discard(), spec, apmSocketUntaintConfig())
+
+	require.Equal(t, constants.GetDefaultReadinessProbe(), spec.Containers[0].ReadinessProbe)
+	probe := spec.Containers[1].ReadinessProbe
+	require.NotNil(t, probe)
+	require.NotNil(t, probe.Exec)
+	require.Equal(t, []string{"test", "-S", "/var/run/datadog/apm.socket"}, probe.Exec.Command)
+}
+
+func TestApplyUntaintSocketReadinessProbes_keepsExistingProbe(t *testing.T) {
+	existing := constants.GetDefaultReadinessProbe()
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: string(apicommon.TraceAgentContainerName), ReadinessProbe: existing},
+		},
+	}
+
+	ApplyUntaintSocketReadinessProbes(logr.Discard(), spec, apmSocketUntaintConfig())
+
+	require.Same(t, existing, spec.Containers[0].ReadinessProbe)
+}
+
+func TestApplyUntaintSocketReadinessProbes_missingContainer(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: string(apicommon.CoreAgentContainerName)},
+		},
+	}
+
+	ApplyUntaintSocketReadinessProbes(logr.Discard(), spec, apmSocketUntaintConfig())
+	ApplyUntaintSocketReadinessProbes(logr.Discard(), spec, nil)
+
+	require.Len(t, spec.Containers, 1)
+	require.Nil(t, spec.Containers[0].ReadinessProbe)
+}
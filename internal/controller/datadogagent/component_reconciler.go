@@ -210,6 +210,10 @@ func (r *ComponentRegistry) reconcileComponent(ctx context.Context, params *Reco
 	if componentOverride, ok := params.DDA.Spec.Override[component.Name()]; ok {
 		override.PodTemplateSpec(deploymentLogger, podManagers, componentOverride, component.Name(), params.DDA.Name)
 		override.Deployment(deployment, componentOverride)
+		if err := override.ExtraContainers(podManagers, componentOverride); err != nil {
+			condition.UpdateDatadogAgentStatusConditions(params.Status, now, component.GetConditionType(), metav1.ConditionFalse, override.ExtraContainersConflictReason, err.Error(), true)
+			return result, err
+		}
 	}
 
 	if errs := global.ValidateFIPSVersions(podManagers); len(errs) > 0 {
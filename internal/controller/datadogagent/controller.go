@@ -18,6 +18,7 @@ import (
 
 	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	componentagent "github.com/DataDog/datadog-operator/internal/controller/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/recommender"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 
@@ -103,6 +104,12 @@ type ReconcilerOptions struct {
 	// ClusterProviderDetector supplies the detected cluster provider. Nil disables
 	// provider detection (reconcile behaves as before: empty provider).
 	ClusterProviderDetector ProviderReader
+	// DowntimeClient schedules the downtimes of the rolloutDowntime policy. Nil
+	// means the operator has no Datadog credentials: the policy is not applied.
+	DowntimeClient DowntimeClient
+	// ResourceUsageSource reads the resource usage of the Agent pods for the
+	// resourceRecommender configuration. Nil disables the resource recommender.
+	ResourceUsageSource recommender.UsageSource
 }
 
 // Reconciler is the internal reconciler for Datadog Agent
@@ -116,6 +123,7 @@ type Reconciler struct {
 	forwarders        datadog.MetricsForwardersManager
 	fieldManager      *managedfields.FieldManager
 	componentRegistry *ComponentRegistry
+	recommender       *recommender.Recommender
 }
 
 func (r *Reconciler) initializeComponentRegistry() {
@@ -143,6 +151,10 @@ func NewReconciler(options ReconcilerOptions, client client.Client, platformInfo
 	// Initialize component registry
 	r.initializeComponentRegistry()
 
+	if options.ResourceUsageSource != nil {
+		r.recommender = recommender.NewRecommender(options.ResourceUsageSource)
+	}
+
 	return r, nil
 }
 
@@ -34,6 +34,7 @@ func (r *Reconciler) setupDDADependenciesStore(instance *v2alpha1.DatadogAgent,
 		Logger:               logger,
 		Scheme:               r.scheme,
 		IsDDAControllerStore: true,
+		Patches:              instance.Spec.Patches,
 	}
 	depsStore := store.NewStore(instance, storeOptions)
 	resourceManagers := feature.NewResourceManagers(depsStore)
@@ -83,6 +84,7 @@ func (r *Reconciler) manageDDADependenciesWithDDAI(ctx context.Context, logger l
 	if err := depsStore.Apply(ctx, r.client); err != nil {
 		return utilerrors.NewAggregate(err)
 	}
+	newDDAStatus.Patches = depsStore.PatchStatuses()
 
 	// Cleanup unused DDA controller dependencies.
 	// Pass false since we want to clean up DDA-managed resources (this is the DDA controller).
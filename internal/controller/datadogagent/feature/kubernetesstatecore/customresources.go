@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetesstatecore
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+)
+
+const (
+	wildcard = "*"
+	rootName = "the object"
+)
+
+// ResolveCustomResources checks the custom resources collected by the
+// kube-state-metrics core check against the API server:
+//   - the GroupVersionKind of each resource must be served, its resource name
+//     found through discovery is set in ResourcePlural;
+//   - the paths of the metrics and labels must exist in the OpenAPI schema of
+//     the CRD defining the resource, when there is one.
+//
+// Resources using a wildcard are not checked. The problems found are returned
+// as human-readable messages; an error is only returned when the API server
+// could not be queried.
+func ResolveCustomResources(ctx context.Context, c client.Client, resources []v2alpha1.Resource) ([]string, error) {
+	var problems []string
+	for i := range resources {
+		res := &resources[i]
+		gvk := schema.GroupVersionKind{
+			Group:   res.GroupVersionKind.Group,
+			Version: res.GroupVersionKind.Version,
+			Kind:    res.GroupVersionKind.Kind,
+		}
+		if gvk.Group == wildcard || gvk.Version == wildcard || gvk.Kind == wildcard {
+			continue
+		}
+
+		mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				problems = append(problems, fmt.Sprintf("%s: resource is not served by the API server", gvk))
+				continue
+			}
+			return nil, err
+		}
+		plural := mapping.Resource.Resource
+		if res.ResourcePlural != "" && res.ResourcePlural != plural {
+			problems = append(problems, fmt.Sprintf("%s: resourcePlural %q does not match the served resource %q", gvk, res.ResourcePlural, plural))
+		}
+		res.ResourcePlural = plural
+
+		if gvk.Group == "" {
+			// Core resources are not defined by a CRD.
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: plural + "." + gvk.Group}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				// Served by an aggregated API or built into the API server.
+				continue
+			}
+			return nil, err
+		}
+		crdSchema := crdVersionSchema(crd, gvk.Version)
+		if crdSchema == nil {
+			continue
+		}
+		for _, problem := range validateResourcePaths(crdSchema, res) {
+			problems = append(problems, fmt.Sprintf("%s: %s", gvk, problem))
+		}
+	}
+	return problems, nil
+}
+
+// crdVersionSchema returns the OpenAPI schema of a CRD version, if any.
+func crdVersionSchema(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.JSONSchemaProps {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			return v.Schema.OpenAPIV3Schema
+		}
+	}
+	return nil
+}
+
+// validateResourcePaths checks the paths of a resource against its schema.
+// Resource and metric labelsFromPath are relative to the object root, the
+// labelsFromPath and valueFrom of a metric are relative to its path.
+func validateResourcePaths(root *apiextensionsv1.JSONSchemaProps, res *v2alpha1.Resource) []string {
+	var problems []string
+	check := func(s *apiextensionsv1.JSONSchemaProps, base string, path []string, field string) *apiextensionsv1.JSONSchemaProps {
+		target, err := lookupSchemaPath(s, base, path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %v: %v", field, path, err))
+		}
+		return target
+	}
+	checkLabels := func(s *apiextensionsv1.JSONSchemaProps, base string, labels map[string][]string, field string) {
+		for _, name := range slices.Sorted(maps.Keys(labels)) {
+			check(s, base, labels[name], fmt.Sprintf("%s.labelsFromPath[%s]", field, name))
+		}
+	}
+
+	checkLabels(root, rootName, res.LabelsFromPath, "resource")
+	for _, gen := range res.Metrics {
+		field := fmt.Sprintf("metric %q", gen.Name)
+		checkLabels(root, rootName, gen.LabelsFromPath, field)
+
+		var metricMeta *v2alpha1.MetricMeta
+		var valueFrom []string
+		switch {
+		case gen.Each.Gauge != nil:
+			metricMeta, valueFrom = &gen.Each.Gauge.MetricMeta, gen.Each.Gauge.ValueFrom
+		case gen.Each.StateSet != nil:
+			metricMeta, valueFrom = &gen.Each.StateSet.MetricMeta, gen.Each.StateSet.ValueFrom
+		case gen.Each.Info != nil:
+			metricMeta = &gen.Each.Info.MetricMeta
+		default:
+			continue
+		}
+		target := check(root, rootName, metricMeta.Path, field+" path")
+		if target == nil {
+			// The path is invalid or its content is not described by the schema.
+			continue
+		}
+		element, elementName := elementSchema(target), fmt.Sprintf("%v", metricMeta.Path)
+		checkLabels(element, elementName, metricMeta.LabelsFromPath, field)
+		if len(valueFrom) > 0 {
+			check(element, elementName, valueFrom, field+" valueFrom")
+		}
+	}
+	return problems
+}
+
+// lookupSchemaPath returns the schema of the field at path, relative to the
+// schema s of base. A nil schema is
+// returned without error when the path goes through a part of the object that
+// is not described by the schema, like metadata or fields preserving unknown
+// fields.
+func lookupSchemaPath(s *apiextensionsv1.JSONSchemaProps, base string, path []string) (*apiextensionsv1.JSONSchemaProps, error) {
+	for i, elem := range path {
+		if s == nil {
+			return nil, nil
+		}
+		parent := base
+		if i > 0 {
+			parent = fmt.Sprintf("%q", path[i-1])
+		}
+		switch {
+		case s.Type == "array":
+			if !isArraySelector(elem) {
+				return nil, fmt.Errorf("%s is an array, %q is neither an index nor a key=value selector", parent, elem)
+			}
+			if s.Items == nil || s.Items.Schema == nil {
+				return nil, nil
+			}
+			s = s.Items.Schema
+		case len(s.Properties) > 0:
+			prop, found := s.Properties[elem]
+			if !found {
+				if s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("%q is not a field of %s", elem, parent)
+			}
+			s = &prop
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+			s = s.AdditionalProperties.Schema
+		case s.XIntOrString:
+			return nil, fmt.Errorf("%s is a scalar value, it has no field %q", parent, elem)
+		case s.Type == "object" || s.Type == "":
+			// Free-form object.
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("%s is of type %s, it has no field %q", parent, s.Type, elem)
+		}
+	}
+	return s, nil
+}
+
+// elementSchema returns the schema the paths relative to a metric path are
+// resolved against: metrics are generated for each element of arrays and maps.
+func elementSchema(s *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	switch {
+	case s.Type == "array" && s.Items != nil:
+		return s.Items.Schema
+	case len(s.Properties) == 0 && s.AdditionalProperties != nil:
+		return s.AdditionalProperties.Schema
+	}
+	return s
+}
+
+// isArraySelector reports whether a path element selects array elements,
+// either by index or with a key=value selector.
+func isArraySelector(elem string) bool {
+	if _, err := strconv.Atoi(elem); err == nil {
+		return true
+	}
+	return strings.Contains(elem, "=")
+}
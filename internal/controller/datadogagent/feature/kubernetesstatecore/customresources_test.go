@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kubernetesstatecore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func widgetCRD() *apiextensionsv1.CustomResourceDefinition {
+	str := apiextensionsv1.JSONSchemaProps{Type: "string"}
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgetz.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:   "v1",
+				Served: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"metadata": {Type: "object"},
+						"spec": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"size":     {Type: "integer"},
+							"template": {Type: "object", XPreserveUnknownFields: ptr.To(true)},
+						}},
+						"status": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"replicas": {Type: "integer"},
+							"conditions": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"type":   str,
+									"status": str,
+								},
+							}}},
+							"shards": {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{
+								Type:       "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{"ready": {Type: "boolean"}},
+							}}},
+						}},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func gauge(path []string, valueFrom []string, labelsFromPath map[string][]string) v2alpha1.Metric {
+	return v2alpha1.Metric{
+		Type: v2alpha1.Gauge,
+		Gauge: &v2alpha1.MetricGauge{
+			MetricMeta: v2alpha1.MetricMeta{Path: path, LabelsFromPath: labelsFromPath},
+			ValueFrom:  valueFrom,
+		},
+	}
+}
+
+func TestResolveCustomResources(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	// The plural of the resource does not follow the Kind
+	mapper.AddSpecific(widgetGVK, widgetGVK.GroupVersion().WithResource("widgetz"), widgetGVK.GroupVersion().WithResource("widget"), meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+	s := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(widgetCRD()).Build()
+
+	widget := v2alpha1.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	resources := []v2alpha1.Resource{
+		{
+			GroupVersionKind: widget,
+			Labels:           v2alpha1.Labels{LabelsFromPath: map[string][]string{"name": {"metadata", "name"}}},
+			Metrics: []v2alpha1.Generator{
+				{Name: "size", Each: gauge([]string{"spec", "size"}, nil, nil)},
+				{Name: "template", Each: gauge([]string{"spec", "template", "replicas"}, nil, nil)},
+				{Name: "ready", Each: gauge([]string{"status", "conditions"}, []string{"status"}, map[string][]string{"type": {"type"}})},
+				{Name: "shard_ready", Each: gauge([]string{"status", "shards"}, []string{"ready"}, nil)},
+				{Name: "available", Each: gauge([]string{"status", "conditions", "type=Available"}, []string{"status"}, nil)},
+			},
+		},
+		{GroupVersionKind: v2alpha1.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+		{GroupVersionKind: v2alpha1.GroupVersionKind{Group: "example.com", Version: "*", Kind: "*"}},
+	}
+
+	problems, err := ResolveCustomResources(context.Background(), c, resources)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	assert.Equal(t, "widgetz", resources[0].ResourcePlural)
+	assert.Equal(t, "pods", resources[1].ResourcePlural)
+	assert.Empty(t, resources[2].ResourcePlural)
+
+	invalid := []v2alpha1.Resource{
+		{
+			GroupVersionKind: widget,
+			ResourcePlural:   "widgets",
+			Labels:           v2alpha1.Labels{LabelsFromPath: map[string][]string{"owner": {"spec", "owner"}}},
+			Metrics: []v2alpha1.Generator{
+				{Name: "replicas", Each: gauge([]string{"status", "replica"}, nil, nil)},
+				{Name: "ready", Each: gauge([]string{"status", "conditions"}, []string{"value"}, map[string][]string{"reason": {"reason"}})},
+				{Name: "first", Each: gauge([]string{"status", "conditions", "first"}, nil, nil)},
+				{Name: "size", Each: gauge([]string{"spec", "size", "value"}, nil, nil)},
+			},
+		},
+		{GroupVersionKind: v2alpha1.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Widget"}},
+	}
+	problems, err = ResolveCustomResources(context.Background(), c, invalid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`example.com/v1, Kind=Widget: resourcePlural "widgets" does not match the served resource "widgetz"`,
+		`example.com/v1, Kind=Widget: resource.labelsFromPath[owner] [spec owner]: "owner" is not a field of "spec"`,
+		`example.com/v1, Kind=Widget: metric "replicas" path [status replica]: "replica" is not a field of "status"`,
+		`example.com/v1, Kind=Widget: metric "ready".labelsFromPath[reason] [reason]: "reason" is not a field of [status conditions]`,
+		`example.com/v1, Kind=Widget: metric "ready" valueFrom [value]: "value" is not a field of [status conditions]`,
+		`example.com/v1, Kind=Widget: metric "first" path [status conditions first]: "conditions" is an array, "first" is neither an index nor a key=value selector`,
+		`example.com/v1, Kind=Widget: metric "size" path [spec size value]: "size" is of type integer, it has no field "value"`,
+		`example.com/v2, Kind=Widget: resource is not served by the API server`,
+	}, problems)
+	assert.Equal(t, "widgetz", invalid[0].ResourcePlural)
+}
@@ -6,11 +6,13 @@
 package kubernetesstatecore
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/gobuffalo/flect"
 	rbacv1 "k8s.io/api/rbac/v1"
 
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes/rbac"
 )
 
@@ -139,26 +141,49 @@ func getRBACPolicyRules(collectorOpts collectorOptions) []rbacv1.PolicyRule {
 		rbacRules[i].Verbs = commonVerbs
 	}
 
-	// Add permissions for custom resources
-	if len(collectorOpts.customResources) > 0 {
-		for _, cr := range collectorOpts.customResources {
-			// Don't pluralize if the kind is a wildcard
-			resourceName := "*"
-			if cr.GroupVersionKind.Kind != "*" {
-				// Use the resource plural if specified, otherwise derive it from the Kind
-				resourceName = cr.ResourcePlural
-				if resourceName == "" {
-					resourceName = strings.ToLower(flect.Pluralize(cr.GroupVersionKind.Kind))
-				}
+	// Add permissions for custom resources, with a single rule per API group
+	rbacRules = append(rbacRules, getCustomResourcesPolicyRules(collectorOpts.customResources, commonVerbs)...)
+
+	return rbacRules
+}
+
+// getCustomResourcesPolicyRules returns the rules allowing to list and watch
+// the custom resources collected by the check, grouping the resources of the
+// same API group and ignoring duplicates.
+func getCustomResourcesPolicyRules(customResources []v2alpha1.Resource, verbs []string) []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	ruleIndex := map[string]int{}
+	for _, cr := range customResources {
+		group := cr.GroupVersionKind.Group
+		// Don't pluralize if the kind is a wildcard
+		resourceName := "*"
+		if cr.GroupVersionKind.Kind != "*" {
+			// Use the resource plural if specified, otherwise derive it from the Kind
+			resourceName = cr.ResourcePlural
+			if resourceName == "" {
+				resourceName = strings.ToLower(flect.Pluralize(cr.GroupVersionKind.Kind))
 			}
+		}
 
-			rbacRules = append(rbacRules, rbacv1.PolicyRule{
-				APIGroups: []string{cr.GroupVersionKind.Group},
+		i, found := ruleIndex[group]
+		if !found {
+			ruleIndex[group] = len(rules)
+			rules = append(rules, rbacv1.PolicyRule{
+				APIGroups: []string{group},
 				Resources: []string{resourceName},
-				Verbs:     commonVerbs,
+				Verbs:     verbs,
 			})
+			continue
+		}
+		switch {
+		case slices.Contains(rules[i].Resources, "*"):
+			// Already covered by a wildcard
+		case resourceName == "*":
+			// A wildcard covers all the resources of the group
+			rules[i].Resources = []string{"*"}
+		case !slices.Contains(rules[i].Resources, resourceName):
+			rules[i].Resources = append(rules[i].Resources, resourceName)
 		}
 	}
-
-	return rbacRules
+	return rules
 }
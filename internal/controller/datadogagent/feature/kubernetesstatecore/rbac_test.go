@@ -120,12 +120,7 @@ func TestGetRBACPolicyRules(t *testing.T) {
 			expectedExtraRules: []rbacv1.PolicyRule{
 				{
 					APIGroups: []string{"kafka.strimzi.io"},
-					Resources: []string{"kafkas"},
-					Verbs:     []string{rbac.ListVerb, rbac.WatchVerb},
-				},
-				{
-					APIGroups: []string{"kafka.strimzi.io"},
-					Resources: []string{"kafkatopics"},
+					Resources: []string{"kafkas", "kafkatopics"},
 					Verbs:     []string{rbac.ListVerb, rbac.WatchVerb},
 				},
 			},
@@ -211,11 +206,6 @@ func TestGetRBACPolicyRules(t *testing.T) {
 					Resources: []string{"certificates"},
 					Verbs:     []string{rbac.ListVerb, rbac.WatchVerb},
 				},
-				{
-					APIGroups: []string{"cert-manager.io"},
-					Resources: []string{"certificates"},
-					Verbs:     []string{rbac.ListVerb, rbac.WatchVerb},
-				},
 			},
 		},
 		{
@@ -297,3 +287,29 @@ func TestGetRBACPolicyRules(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCustomResourcesPolicyRules(t *testing.T) {
+	resource := func(group, kind, plural string) v2alpha1.Resource {
+		return v2alpha1.Resource{
+			GroupVersionKind: v2alpha1.GroupVersionKind{Group: group, Version: "v1", Kind: kind},
+			ResourcePlural:   plural,
+		}
+	}
+	verbs := []string{rbac.ListVerb, rbac.WatchVerb}
+
+	rules := getCustomResourcesPolicyRules([]v2alpha1.Resource{
+		resource("cert-manager.io", "Certificate", ""),
+		resource("acme.cert-manager.io", "Challenge", ""),
+		resource("cert-manager.io", "Issuer", "issuers"),
+		resource("cert-manager.io", "Certificate", "certificates"),
+		resource("example.com", "Widget", "widgets"),
+		resource("example.com", "*", ""),
+		resource("example.com", "Gadget", ""),
+	}, verbs)
+
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{"cert-manager.io"}, Resources: []string{"certificates", "issuers"}, Verbs: verbs},
+		{APIGroups: []string{"acme.cert-manager.io"}, Resources: []string{"challenges"}, Verbs: verbs},
+		{APIGroups: []string{"example.com"}, Resources: []string{"*"}, Verbs: verbs},
+	}, rules)
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logcollection
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+)
+
+// Prefixes of the Agent container filters.
+const (
+	namespaceFilterPrefix = "kube_namespace:"
+	nameFilterPrefix      = "name:"
+	imageFilterPrefix     = "image:"
+)
+
+// processingRule is the Agent configuration of a log processing rule.
+type processingRule struct {
+	Type               string `json:"type"`
+	Name               string `json:"name"`
+	ReplacePlaceholder string `json:"replace_placeholder,omitempty"`
+	Pattern            string `json:"pattern"`
+}
+
+// processingRulesConfig returns the JSON value of logs_config.processing_rules.
+func processingRulesConfig(rules []v2alpha1.LogProcessingRule) (string, error) {
+	agentRules := make([]processingRule, 0, len(rules))
+	for _, rule := range rules {
+		r := processingRule{
+			Type:    string(rule.Type),
+			Name:    rule.Name,
+			Pattern: rule.Pattern,
+		}
+		if rule.ReplacePlaceholder != nil {
+			r.ReplacePlaceholder = *rule.ReplacePlaceholder
+		}
+		agentRules = append(agentRules, r)
+	}
+	value, err := json.Marshal(agentRules)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// containerFilterConfig returns the Agent container filter selecting the
+// containers of a LogContainerFilter, as a space separated list.
+func containerFilterConfig(filter *v2alpha1.LogContainerFilter) string {
+	if filter == nil {
+		return ""
+	}
+	var entries []string
+	for _, namespace := range filter.Namespaces {
+		entries = append(entries, namespaceFilterPrefix+namespace)
+	}
+	for _, name := range filter.Names {
+		entries = append(entries, nameFilterPrefix+name)
+	}
+	for _, image := range filter.Images {
+		entries = append(entries, imageFilterPrefix+image)
+	}
+	return strings.Join(entries, " ")
+}
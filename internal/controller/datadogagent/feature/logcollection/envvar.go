@@ -10,4 +10,7 @@ const (
 	DDLogsConfigOpenFilesLimit         = "DD_LOGS_CONFIG_OPEN_FILES_LIMIT"
 	DDLogsContainerCollectUsingFiles   = "DD_LOGS_CONFIG_K8S_CONTAINER_USE_FILE"
 	DDLogsConfigAutoMultiLineDetection = "DD_LOGS_CONFIG_AUTO_MULTI_LINE_DETECTION"
+	DDLogsConfigProcessingRules        = "DD_LOGS_CONFIG_PROCESSING_RULES"
+	DDContainerIncludeLogs             = "DD_CONTAINER_INCLUDE_LOGS"
+	DDContainerExcludeLogs             = "DD_CONTAINER_EXCLUDE_LOGS"
 )
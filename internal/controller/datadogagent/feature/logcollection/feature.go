@@ -44,6 +44,9 @@ type logCollectionFeature struct {
 	tempStoragePath            string
 	openFilesLimit             int32
 	autoMultiLineDetection     *bool
+	processingRules            []v2alpha1.LogProcessingRule
+	containerInclude           string
+	containerExclude           string
 }
 
 // ID returns the ID of the Feature
@@ -73,6 +76,9 @@ func (f *logCollectionFeature) Configure(_ metav1.Object, ddaSpec *v2alpha1.Data
 			f.openFilesLimit = *logCollection.OpenFilesLimit
 		}
 		f.autoMultiLineDetection = logCollection.AutoMultiLineDetection
+		f.processingRules = logCollection.ProcessingRules
+		f.containerInclude = containerFilterConfig(logCollection.ContainerInclude)
+		f.containerExclude = containerFilterConfig(logCollection.ContainerExclude)
 
 		reqComp = feature.RequiredComponents{
 			Agent: feature.RequiredComponent{
@@ -102,15 +108,13 @@ func (f *logCollectionFeature) ManageClusterAgent(managers feature.PodTemplateMa
 // if SingleContainerStrategy is enabled and can be used with the configured feature set.
 // It should do nothing if the feature doesn't need to configure it.
 func (f *logCollectionFeature) ManageSingleContainerNodeAgent(managers feature.PodTemplateManagers) error {
-	f.manageNodeAgent(apicommon.UnprivilegedSingleAgentContainerName, managers)
-	return nil
+	return f.manageNodeAgent(apicommon.UnprivilegedSingleAgentContainerName, managers)
 }
 
 // ManageNodeAgent allows a feature to configure the Node Agent's corev1.PodTemplateSpec
 // It should do nothing if the feature doesn't need to configure it.
 func (f *logCollectionFeature) ManageNodeAgent(managers feature.PodTemplateManagers) error {
-	f.manageNodeAgent(apicommon.CoreAgentContainerName, managers)
-	return nil
+	return f.manageNodeAgent(apicommon.CoreAgentContainerName, managers)
 }
 
 // NodeAgentProviderCapabilities returns provider-conditional pod-template mutations.
@@ -182,6 +186,28 @@ func (f *logCollectionFeature) manageNodeAgent(agentContainerName apicommon.Agen
 			Value: strconv.FormatBool(*f.autoMultiLineDetection),
 		})
 	}
+	if len(f.processingRules) > 0 {
+		processingRules, err := processingRulesConfig(f.processingRules)
+		if err != nil {
+			return err
+		}
+		managers.EnvVar().AddEnvVarToContainer(agentContainerName, &corev1.EnvVar{
+			Name:  DDLogsConfigProcessingRules,
+			Value: processingRules,
+		})
+	}
+	if f.containerInclude != "" {
+		managers.EnvVar().AddEnvVarToContainer(agentContainerName, &corev1.EnvVar{
+			Name:  DDContainerIncludeLogs,
+			Value: f.containerInclude,
+		})
+	}
+	if f.containerExclude != "" {
+		managers.EnvVar().AddEnvVarToContainer(agentContainerName, &corev1.EnvVar{
+			Name:  DDContainerExcludeLogs,
+			Value: f.containerExclude,
+		})
+	}
 
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	apiutils "github.com/DataDog/datadog-operator/api/utils"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature"
@@ -19,6 +20,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 func Test_LogCollectionFeature_Configure(t *testing.T) {
@@ -140,6 +142,41 @@ func Test_LogCollectionFeature_Configure(t *testing.T) {
 				},
 			),
 		},
+		{
+			Name: "processing rules and container filters",
+			DDA: testutils.NewDatadogAgentBuilder().
+				WithLogCollectionEnabled(true).
+				WithLogCollectionProcessingRules(
+					v2alpha1.LogProcessingRule{Type: v2alpha1.LogProcessingRuleExcludeAtMatch, Name: "exclude_healthchecks", Pattern: "GET /healthz"},
+					v2alpha1.LogProcessingRule{Type: v2alpha1.LogProcessingRuleMaskSequences, Name: "mask_tokens", Pattern: `token=\w+`, ReplacePlaceholder: ptr.To("token=[REDACTED]")},
+				).
+				WithLogCollectionContainerFilters(
+					&v2alpha1.LogContainerFilter{Namespaces: []string{"^payments$"}},
+					&v2alpha1.LogContainerFilter{Namespaces: []string{".*"}, Names: []string{"istio-proxy"}, Images: []string{"^busybox"}},
+				).
+				BuildWithDefaults(),
+			WantConfigure: true,
+			Agent: test.NewDefaultComponentTest().WithWantFunc(
+				func(t testing.TB, mgrInterface feature.PodTemplateManagers) {
+					wantEnvVars := createEnvVars("true", "false", "true")
+					wantEnvVars = append(wantEnvVars,
+						&corev1.EnvVar{
+							Name:  DDLogsConfigProcessingRules,
+							Value: `[{"type":"exclude_at_match","name":"exclude_healthchecks","pattern":"GET /healthz"},{"type":"mask_sequences","name":"mask_tokens","replace_placeholder":"token=[REDACTED]","pattern":"token=\\w+"}]`,
+						},
+						&corev1.EnvVar{
+							Name:  DDContainerIncludeLogs,
+							Value: "kube_namespace:^payments$",
+						},
+						&corev1.EnvVar{
+							Name:  DDContainerExcludeLogs,
+							Value: "kube_namespace:.* name:istio-proxy image:^busybox",
+						},
+					)
+					assertWants(t, mgrInterface, getWantVolumeMounts(), getWantVolumes(), wantEnvVars)
+				},
+			),
+		},
 		{
 			Name: "custom volumes",
 			DDA: testutils.NewDatadogAgentBuilder().
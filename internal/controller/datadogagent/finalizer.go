@@ -11,8 +11,11 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/internal/controller/finalizer"
 	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/constants"
@@ -37,6 +40,15 @@ func (r *Reconciler) finalizeDad(reqLogger logr.Logger, obj client.Object) error
 		return err
 	}
 
+	// The downtime ends on its own, do not block the deletion if it cannot be canceled.
+	if dda, ok := obj.(*v2alpha1.DatadogAgent); ok && dda.Status.RolloutDowntime != nil {
+		_ = r.cancelRolloutDowntime(reqLogger, dda, dda.Status.RolloutDowntime, metav1.Now())
+	}
+
+	if r.recommender != nil {
+		r.recommender.Forget(types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	}
+
 	reqLogger.Info("Successfully finalized DatadogAgent")
 	return nil
 }
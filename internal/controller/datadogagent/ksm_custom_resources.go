@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/api/utils"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature/kubernetesstatecore"
+	"github.com/DataDog/datadog-operator/pkg/condition"
+)
+
+const (
+	ksmCustomResourcesValidReason        = "CustomResourcesValid"
+	ksmCustomResourcesInvalidReason      = "CustomResourcesInvalid"
+	ksmCustomResourcesNotValidatedReason = "CustomResourcesNotValidated"
+)
+
+// resolveKSMCustomResources checks the custom resources collected by the
+// kube-state-metrics core check against the API server, and reports the result
+// in the KubeStateMetricsCustomResourcesValid condition. The resource plurals
+// found through discovery are set on the spec, so that the ClusterRole and the
+// check configuration generated from it use the names served by the API server.
+func (r *Reconciler) resolveKSMCustomResources(ctx context.Context, logger logr.Logger, spec *datadoghqv2alpha1.DatadogAgentSpec, status *datadoghqv2alpha1.DatadogAgentStatus, now metav1.Time) {
+	if spec.Features == nil || spec.Features.KubeStateMetricsCore == nil ||
+		!apiutils.BoolValue(spec.Features.KubeStateMetricsCore.Enabled) ||
+		len(spec.Features.KubeStateMetricsCore.CollectCrMetrics) == 0 {
+		return
+	}
+
+	problems, err := kubernetesstatecore.ResolveCustomResources(ctx, r.client, spec.Features.KubeStateMetricsCore.CollectCrMetrics)
+	switch {
+	case err != nil:
+		logger.Error(err, "Unable to validate the kube-state-metrics custom resources")
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.KSMCustomResourcesValidConditionType, metav1.ConditionUnknown, ksmCustomResourcesNotValidatedReason, err.Error(), true)
+	case len(problems) > 0:
+		logger.Info("Invalid kube-state-metrics custom resources", "problems", problems)
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.KSMCustomResourcesValidConditionType, metav1.ConditionFalse, ksmCustomResourcesInvalidReason, strings.Join(problems, "; "), true)
+	default:
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.KSMCustomResourcesValidConditionType, metav1.ConditionTrue, ksmCustomResourcesValidReason, "All custom resources are served and match their schema", true)
+	}
+}
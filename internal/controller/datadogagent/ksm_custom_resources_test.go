@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+)
+
+func TestResolveKSMCustomResources(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+	s := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).Build(),
+	}
+	now := metav1.Now()
+	specWith := func(kinds ...string) *v2alpha1.DatadogAgentSpec {
+		ksm := &v2alpha1.KubeStateMetricsCoreFeatureConfig{Enabled: ptr.To(true)}
+		for _, kind := range kinds {
+			ksm.CollectCrMetrics = append(ksm.CollectCrMetrics, v2alpha1.Resource{
+				GroupVersionKind: v2alpha1.GroupVersionKind{Group: "example.com", Version: "v1", Kind: kind},
+			})
+		}
+		return &v2alpha1.DatadogAgentSpec{Features: &v2alpha1.DatadogFeatures{KubeStateMetricsCore: ksm}}
+	}
+
+	t.Run("no custom resources", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		r.resolveKSMCustomResources(context.Background(), logf.Log, specWith(), status, now)
+		assert.Empty(t, status.Conditions)
+	})
+
+	t.Run("valid custom resources", func(t *testing.T) {
+		spec := specWith("Widget")
+		status := &v2alpha1.DatadogAgentStatus{}
+		r.resolveKSMCustomResources(context.Background(), logf.Log, spec, status, now)
+
+		cond := meta.FindStatusCondition(status.Conditions, common.KSMCustomResourcesValidConditionType)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "widgets", spec.Features.KubeStateMetricsCore.CollectCrMetrics[0].ResourcePlural)
+	})
+
+	t.Run("missing custom resource", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		r.resolveKSMCustomResources(context.Background(), logf.Log, specWith("Widget", "Gadget"), status, now)
+
+		cond := meta.FindStatusCondition(status.Conditions, common.KSMCustomResourcesValidConditionType)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, ksmCustomResourcesInvalidReason, cond.Reason)
+		assert.Equal(t, "example.com/v1, Kind=Gadget: resource is not served by the API server", cond.Message)
+	})
+}
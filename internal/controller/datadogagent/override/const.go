@@ -21,4 +21,8 @@ const (
 	FIPSProxyCustomConfigFileName   = "datadog-fips-proxy.cfg"
 	FIPSProxyCustomConfigMapName    = "%s-fips-config"
 	FIPSProxyCustomConfigMountPath  = "/etc/datadog-fips-proxy/datadog-fips-proxy.cfg"
+
+	// ExtraContainersConflictReason is the reason of the reconcile condition of a component
+	// whose extra containers conflict with the pod generated by the operator.
+	ExtraContainersConflictReason = "ExtraContainersConflict"
 )
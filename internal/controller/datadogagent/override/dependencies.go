@@ -45,6 +45,8 @@ func Dependencies(logger logr.Logger, manager feature.ResourceManagers, ddaMeta
 		errs = append(errs, overrideExtraConfigs(manager, override.ExtraChecksd, namespace, checksdCMName, false)...)
 
 		errs = append(errs, overridePodDisruptionBudget(logger, manager, ddaMeta, ddaSpec, override.CreatePodDisruptionBudget, component)...)
+
+		errs = append(errs, overrideVerticalPodAutoscaler(logger, manager, ddaMeta, ddaSpec, override, component)...)
 	}
 
 	return errs
@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature"
+)
+
+// ExtraContainers adds the extra containers and init containers of the override to the pod template.
+// It must run after the other overrides, so that the extra containers are left as the user defined them.
+// When they conflict with the containers, ports or volumes of the pod, the pod template is left unchanged
+// and an error describing all the conflicts is returned.
+func ExtraContainers(manager feature.PodTemplateManagers, override *v2alpha1.DatadogAgentComponentOverride) error {
+	if override == nil || (len(override.ExtraContainers) == 0 && len(override.ExtraInitContainers) == 0) {
+		return nil
+	}
+
+	podSpec := &manager.PodTemplateSpec().Spec
+	if errs := extraContainersConflicts(podSpec, override); len(errs) > 0 {
+		return fmt.Errorf("extra containers conflict with the pod of the operator: %w", utilerrors.NewAggregate(errs))
+	}
+
+	for _, container := range override.ExtraContainers {
+		podSpec.Containers = append(podSpec.Containers, *container.DeepCopy())
+	}
+
+	extraInitContainers := make([]corev1.Container, 0, len(override.ExtraInitContainers))
+	for _, container := range override.ExtraInitContainers {
+		extraInitContainers = append(extraInitContainers, *container.DeepCopy())
+	}
+	if override.ExtraInitContainersPosition != nil && *override.ExtraInitContainersPosition == v2alpha1.ExtraInitContainersBefore {
+		podSpec.InitContainers = append(extraInitContainers, podSpec.InitContainers...)
+	} else {
+		podSpec.InitContainers = append(podSpec.InitContainers, extraInitContainers...)
+	}
+
+	return nil
+}
+
+func extraContainersConflicts(podSpec *corev1.PodSpec, override *v2alpha1.DatadogAgentComponentOverride) []error {
+	var errs []error
+
+	names := map[string]struct{}{}
+	for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+		names[container.Name] = struct{}{}
+	}
+	volumes := map[string]struct{}{}
+	for _, volume := range podSpec.Volumes {
+		volumes[volume.Name] = struct{}{}
+	}
+	// The containers of a pod share its network namespace: their ports must be distinct.
+	// Init containers run before the other containers, their ports are not checked.
+	ports := newPortOwners()
+	for _, container := range podSpec.Containers {
+		ports.add(container)
+	}
+
+	check := func(kind string, container corev1.Container, checkPorts bool) {
+		if _, found := names[container.Name]; found {
+			errs = append(errs, fmt.Errorf("%s %q: a container with the same name already exists", kind, container.Name))
+		}
+		names[container.Name] = struct{}{}
+
+		for _, mount := range container.VolumeMounts {
+			if _, found := volumes[mount.Name]; !found {
+				errs = append(errs, fmt.Errorf("%s %q: volume mount %q refers to no volume of the pod, add it to the volumes of the override", kind, container.Name, mount.Name))
+			}
+		}
+
+		if checkPorts {
+			errs = append(errs, ports.conflicts(kind, container)...)
+			ports.add(container)
+		}
+	}
+	for _, container := range override.ExtraInitContainers {
+		check("extra init container", container, false)
+	}
+	for _, container := range override.ExtraContainers {
+		check("extra container", container, true)
+	}
+
+	return errs
+}
+
+// portOwners indexes the container owning each port of a pod, by port name, container port and host port.
+type portOwners struct {
+	names     map[string]string
+	container map[string]string
+	host      map[string]string
+}
+
+func newPortOwners() *portOwners {
+	return &portOwners{names: map[string]string{}, container: map[string]string{}, host: map[string]string{}}
+}
+
+func portKey(port int32, protocol corev1.Protocol) string {
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	return fmt.Sprintf("%d/%s", port, protocol)
+}
+
+func (p *portOwners) add(container corev1.Container) {
+	for _, port := range container.Ports {
+		if port.Name != "" {
+			p.names[port.Name] = container.Name
+		}
+		p.container[portKey(port.ContainerPort, port.Protocol)] = container.Name
+		if port.HostPort != 0 {
+			p.host[portKey(port.HostPort, port.Protocol)] = container.Name
+		}
+	}
+}
+
+func (p *portOwners) conflicts(kind string, container corev1.Container) []error {
+	var errs []error
+	for _, port := range container.Ports {
+		if owner, found := p.names[port.Name]; found && port.Name != "" {
+			errs = append(errs, fmt.Errorf("%s %q: port name %q is already used by container %q", kind, container.Name, port.Name, owner))
+		}
+		if owner, found := p.container[portKey(port.ContainerPort, port.Protocol)]; found {
+			errs = append(errs, fmt.Errorf("%s %q: port %s is already used by container %q", kind, container.Name, portKey(port.ContainerPort, port.Protocol), owner))
+		}
+		if owner, found := p.host[portKey(port.HostPort, port.Protocol)]; found && port.HostPort != 0 {
+			errs = append(errs, fmt.Errorf("%s %q: host port %s is already used by container %q", kind, container.Name, portKey(port.HostPort, port.Protocol), owner))
+		}
+	}
+	return errs
+}
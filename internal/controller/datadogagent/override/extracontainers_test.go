@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature/fake"
+)
+
+func operatorPodTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init-volume"}, {Name: "init-config"}},
+			Containers: []corev1.Container{
+				{Name: "agent", Ports: []corev1.ContainerPort{{Name: "dogstatsdport", ContainerPort: 8125, HostPort: 8125, Protocol: corev1.ProtocolUDP}}},
+				{Name: "trace-agent", Ports: []corev1.ContainerPort{{Name: "traceport", ContainerPort: 8126}}},
+			},
+			Volumes: []corev1.Volume{{Name: "logdatadog"}, {Name: "config"}},
+		},
+	}
+}
+
+func containerNames(containers []corev1.Container) []string {
+	names := make([]string, 0, len(containers))
+	for _, container := range containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+func TestExtraContainers(t *testing.T) {
+	tests := []struct {
+		name               string
+		override           *v2alpha1.DatadogAgentComponentOverride
+		wantErr            []string
+		wantContainers     []string
+		wantInitContainers []string
+	}{
+		{
+			name:               "no override",
+			wantContainers:     []string{"agent", "trace-agent"},
+			wantInitContainers: []string{"init-volume", "init-config"},
+		},
+		{
+			name: "extra containers and init containers are added after the ones of the operator",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				ExtraContainers: []corev1.Container{{
+					Name:         "log-shipper",
+					Ports:        []corev1.ContainerPort{{Name: "metrics", ContainerPort: 8125}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "logdatadog", MountPath: "/var/log/datadog"}},
+				}},
+				ExtraInitContainers: []corev1.Container{{Name: "fetch-certs"}},
+			},
+			wantContainers:     []string{"agent", "trace-agent", "log-shipper"},
+			wantInitContainers: []string{"init-volume", "init-config", "fetch-certs"},
+		},
+		{
+			name: "extra init containers run before the ones of the operator",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				ExtraInitContainers:         []corev1.Container{{Name: "vault-agent-init"}, {Name: "fetch-certs"}},
+				ExtraInitContainersPosition: ptr.To(v2alpha1.ExtraInitContainersBefore),
+			},
+			wantContainers:     []string{"agent", "trace-agent"},
+			wantInitContainers: []string{"vault-agent-init", "fetch-certs", "init-volume", "init-config"},
+		},
+		{
+			name: "conflicts are rejected",
+			override: &v2alpha1.DatadogAgentComponentOverride{
+				ExtraContainers: []corev1.Container{
+					{Name: "trace-agent"},
+					{Name: "statsd", Ports: []corev1.ContainerPort{{Name: "dogstatsdport", ContainerPort: 9125, HostPort: 8125, Protocol: corev1.ProtocolUDP}}},
+					{Name: "tracer", Ports: []corev1.ContainerPort{{ContainerPort: 8126}}},
+					{Name: "vault-agent", VolumeMounts: []corev1.VolumeMount{{Name: "vault-token", MountPath: "/vault"}}},
+				},
+				ExtraInitContainers: []corev1.Container{{Name: "init-config"}},
+			},
+			wantErr: []string{
+				`extra init container "init-config": a container with the same name already exists`,
+				`extra container "trace-agent": a container with the same name already exists`,
+				`extra container "statsd": port name "dogstatsdport" is already used by container "agent"`,
+				`extra container "statsd": host port 8125/UDP is already used by container "agent"`,
+				`extra container "tracer": port 8126/TCP is already used by container "trace-agent"`,
+				`extra container "vault-agent": volume mount "vault-token" refers to no volume of the pod`,
+			},
+			wantContainers:     []string{"agent", "trace-agent"},
+			wantInitContainers: []string{"init-volume", "init-config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := fake.NewPodTemplateManagers(t, operatorPodTemplate())
+
+			err := ExtraContainers(manager, tt.override)
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+				for _, msg := range tt.wantErr {
+					assert.Contains(t, err.Error(), msg)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantContainers, containerNames(manager.PodTemplateSpec().Spec.Containers))
+			assert.Equal(t, tt.wantInitContainers, containerNames(manager.PodTemplateSpec().Spec.InitContainers))
+		})
+	}
+}
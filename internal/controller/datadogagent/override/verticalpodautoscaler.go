@@ -0,0 +1,164 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/api/utils"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/component"
+	componentccr "github.com/DataDog/datadog-operator/internal/controller/datadogagent/component/clusterchecksrunner"
+	componentotelagentgateway "github.com/DataDog/datadog-operator/internal/controller/datadogagent/component/otelagentgateway"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	vpav1 "github.com/DataDog/datadog-operator/pkg/vpa/v1"
+)
+
+const verticalPodAutoscalerResource = "VerticalPodAutoscaler"
+
+// defaultVerticalAutoscalingControlledResources are the resources controlled by the VerticalPodAutoscaler when controlledResources is not set.
+var defaultVerticalAutoscalingControlledResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// VerticalAutoscaling returns the vertical autoscaling configuration of the component when it is enabled, nil otherwise.
+func VerticalAutoscaling(override *v2alpha1.DatadogAgentComponentOverride) *v2alpha1.VerticalAutoscalingConfig {
+	if override == nil || override.Autoscaling == nil || override.Autoscaling.Vertical == nil || !apiutils.BoolValue(override.Autoscaling.Vertical.Enabled) {
+		return nil
+	}
+	return override.Autoscaling.Vertical
+}
+
+// IsVerticalAutoscalingActive returns true if a VerticalPodAutoscaler manages the resources of the component:
+// vertical autoscaling is enabled and the VerticalPodAutoscaler is installed in the cluster.
+func IsVerticalAutoscalingActive(override *v2alpha1.DatadogAgentComponentOverride, platformInfo *kubernetes.PlatformInfo) bool {
+	return VerticalAutoscaling(override) != nil && platformInfo.IsResourceSupported(verticalPodAutoscalerResource)
+}
+
+// WithoutVerticallyAutoscaledResources returns a copy of the Deployment spec without the container resources
+// controlled by the VerticalPodAutoscaler. It is used to compute the hash of the Deployment, so that a change of
+// the initial resources of the containers does not roll out the pods the VerticalPodAutoscaler already sized.
+func WithoutVerticallyAutoscaledResources(spec *appsv1.DeploymentSpec, config *v2alpha1.VerticalAutoscalingConfig) *appsv1.DeploymentSpec {
+	spec = spec.DeepCopy()
+	controlledResources := config.ControlledResources
+	if len(controlledResources) == 0 {
+		controlledResources = defaultVerticalAutoscalingControlledResources
+	}
+	requestsOnly := config.ControlledValues != nil && *config.ControlledValues == v2alpha1.VerticalAutoscalingControlledRequestsOnly
+	for i := range spec.Template.Spec.Containers {
+		resources := &spec.Template.Spec.Containers[i].Resources
+		for _, name := range controlledResources {
+			delete(resources.Requests, name)
+			if !requestsOnly {
+				delete(resources.Limits, name)
+			}
+		}
+	}
+	return spec
+}
+
+func overrideVerticalPodAutoscaler(logger logr.Logger, manager feature.ResourceManagers, ddaMeta metav1.Object, ddaSpec *v2alpha1.DatadogAgentSpec, override *v2alpha1.DatadogAgentComponentOverride, componentName v2alpha1.ComponentName) (errs []error) {
+	config := VerticalAutoscaling(override)
+	if config == nil || apiutils.BoolValue(override.Disabled) {
+		return nil
+	}
+	platformInfo := manager.Store().GetPlatformInfo()
+	if !platformInfo.IsResourceSupported(verticalPodAutoscalerResource) {
+		logger.Info("Vertical autoscaling is enabled but the VerticalPodAutoscaler API is not available, the VerticalPodAutoscaler is not created", "component", componentName)
+		return nil
+	}
+
+	var deploymentName string
+	switch componentName {
+	case v2alpha1.ClusterAgentComponentName:
+		deploymentName = component.GetDeploymentNameFromDatadogAgent(ddaMeta, ddaSpec)
+	case v2alpha1.ClusterChecksRunnerComponentName:
+		if ddaSpec.Features != nil && ddaSpec.Features.ClusterChecks != nil &&
+			ddaSpec.Features.ClusterChecks.UseClusterChecksRunners != nil && !*ddaSpec.Features.ClusterChecks.UseClusterChecksRunners {
+			return nil
+		}
+		deploymentName = componentccr.GetClusterChecksRunnerName(ddaMeta)
+	case v2alpha1.OtelAgentGatewayComponentName:
+		if ddaSpec.Features == nil || ddaSpec.Features.OtelAgentGateway == nil || !apiutils.BoolValue(ddaSpec.Features.OtelAgentGateway.Enabled) {
+			return nil
+		}
+		deploymentName = componentotelagentgateway.GetOtelAgentGatewayName(ddaMeta)
+	default:
+		return nil
+	}
+	if override.Name != nil && *override.Name != "" {
+		deploymentName = *override.Name
+	}
+
+	vpa, err := newVerticalPodAutoscaler(deploymentName, ddaMeta.GetNamespace(), config)
+	if err != nil {
+		return []error{err}
+	}
+	if err := manager.Store().AddOrUpdate(kubernetes.VerticalPodAutoscalersKind, vpa); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// newVerticalPodAutoscaler returns the VerticalPodAutoscaler of the Deployment, as an unstructured object.
+func newVerticalPodAutoscaler(deploymentName, namespace string, config *v2alpha1.VerticalAutoscalingConfig) (*unstructured.Unstructured, error) {
+	updateMode := v2alpha1.VerticalAutoscalingUpdateModeRecreate
+	if config.UpdateMode != nil {
+		updateMode = *config.UpdateMode
+	}
+	controlledValues := v2alpha1.VerticalAutoscalingControlledRequestsAndLimits
+	if config.ControlledValues != nil {
+		controlledValues = *config.ControlledValues
+	}
+	controlledResources := config.ControlledResources
+	if len(controlledResources) == 0 {
+		controlledResources = defaultVerticalAutoscalingControlledResources
+	}
+
+	typedVPA := vpav1.VerticalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: namespace,
+		},
+		Spec: vpav1.VerticalPodAutoscalerSpec{
+			TargetRef: &autoscalingv1.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       deploymentName,
+			},
+			UpdatePolicy: &vpav1.PodUpdatePolicy{
+				UpdateMode: string(updateMode),
+			},
+			ResourcePolicy: &vpav1.PodResourcePolicy{
+				ContainerPolicies: []vpav1.ContainerResourcePolicy{
+					{
+						ContainerName:       vpav1.ContainerNameAll,
+						MinAllowed:          config.MinAllowed.DeepCopy(),
+						MaxAllowed:          config.MaxAllowed.DeepCopy(),
+						ControlledResources: controlledResources,
+						ControlledValues:    string(controlledValues),
+					},
+				},
+			},
+		},
+	}
+
+	vpa := &unstructured.Unstructured{}
+	var err error
+	vpa.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(&typedVPA)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert vertical pod autoscaler %s/%s to unstructured object, err: %w", namespace, deploymentName, err)
+	}
+	vpa.SetGroupVersionKind(vpav1.GroupVersionVerticalPodAutoscalerKind())
+	return vpa, nil
+}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/store"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestOverrideVerticalPodAutoscaler(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	withVPA := kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"VerticalPodAutoscaler": "autoscaling.k8s.io/v1"}, nil)
+	withoutVPA := kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{}, nil)
+
+	vertical := func(config v2alpha1.VerticalAutoscalingConfig) *v2alpha1.ComponentAutoscalingConfig {
+		return &v2alpha1.ComponentAutoscalingConfig{Vertical: &config}
+	}
+
+	tests := []struct {
+		name         string
+		platformInfo kubernetes.PlatformInfo
+		spec         v2alpha1.DatadogAgentSpec
+		wantVPAs     map[string]map[string]any
+	}{
+		{
+			name:         "cluster agent with the default configuration",
+			platformInfo: withVPA,
+			spec: v2alpha1.DatadogAgentSpec{
+				Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+					v2alpha1.ClusterAgentComponentName: {Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)})},
+				},
+			},
+			wantVPAs: map[string]map[string]any{
+				"datadog-cluster-agent": {
+					"targetRef":    map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "datadog-cluster-agent"},
+					"updatePolicy": map[string]any{"updateMode": "Recreate"},
+					"resourcePolicy": map[string]any{"containerPolicies": []any{
+						map[string]any{"containerName": "*", "controlledResources": []any{"cpu", "memory"}, "controlledValues": "RequestsAndLimits"},
+					}},
+				},
+			},
+		},
+		{
+			name:         "renamed cluster checks runner with bounds",
+			platformInfo: withVPA,
+			spec: v2alpha1.DatadogAgentSpec{
+				Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+					v2alpha1.ClusterChecksRunnerComponentName: {
+						Name: ptr.To("checks-runner"),
+						Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{
+							Enabled:             ptr.To(true),
+							UpdateMode:          ptr.To(v2alpha1.VerticalAutoscalingUpdateModeInitial),
+							MinAllowed:          corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+							MaxAllowed:          corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+							ControlledResources: []corev1.ResourceName{corev1.ResourceMemory},
+							ControlledValues:    ptr.To(v2alpha1.VerticalAutoscalingControlledRequestsOnly),
+						}),
+					},
+				},
+			},
+			wantVPAs: map[string]map[string]any{
+				"checks-runner": {
+					"targetRef":    map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "checks-runner"},
+					"updatePolicy": map[string]any{"updateMode": "Initial"},
+					"resourcePolicy": map[string]any{"containerPolicies": []any{
+						map[string]any{
+							"containerName":       "*",
+							"minAllowed":          map[string]any{"memory": "128Mi"},
+							"maxAllowed":          map[string]any{"memory": "1Gi"},
+							"controlledResources": []any{"memory"},
+							"controlledValues":    "RequestsOnly",
+						},
+					}},
+				},
+			},
+		},
+		{
+			name:         "VerticalPodAutoscaler API not available",
+			platformInfo: withoutVPA,
+			spec: v2alpha1.DatadogAgentSpec{
+				Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+					v2alpha1.ClusterAgentComponentName: {Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)})},
+				},
+			},
+		},
+		{
+			name:         "disabled vertical autoscaling, disabled component and disabled features",
+			platformInfo: withVPA,
+			spec: v2alpha1.DatadogAgentSpec{
+				Features: &v2alpha1.DatadogFeatures{
+					ClusterChecks: &v2alpha1.ClusterChecksFeatureConfig{UseClusterChecksRunners: ptr.To(false)},
+				},
+				Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+					v2alpha1.ClusterAgentComponentName: {
+						Disabled:    ptr.To(true),
+						Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)}),
+					},
+					v2alpha1.ClusterChecksRunnerComponentName: {Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)})},
+					v2alpha1.OtelAgentGatewayComponentName:    {Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)})},
+					v2alpha1.NodeAgentComponentName:           {Autoscaling: vertical(v2alpha1.VerticalAutoscalingConfig{})},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dda := &v2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "datadog"}, Spec: test.spec}
+			s := store.NewStore(dda, &store.StoreOptions{Scheme: testScheme, PlatformInfo: test.platformInfo})
+
+			errs := Dependencies(logf.Log, feature.NewResourceManagers(s), dda.GetObjectMeta(), &dda.Spec)
+			assert.Empty(t, errs)
+
+			for _, name := range []string{"datadog-cluster-agent", "datadog-cluster-checks-runner", "checks-runner", "datadog-otel-agent-gateway"} {
+				obj, found := s.Get(kubernetes.VerticalPodAutoscalersKind, "datadog", name)
+				wantSpec, wantFound := test.wantVPAs[name]
+				require.Equal(t, wantFound, found, name)
+				if !found {
+					continue
+				}
+				vpa, ok := obj.(*unstructured.Unstructured)
+				require.True(t, ok)
+				assert.Equal(t, "autoscaling.k8s.io/v1", vpa.GetAPIVersion())
+				assert.Equal(t, "VerticalPodAutoscaler", vpa.GetKind())
+				assert.Equal(t, wantSpec, vpa.Object["spec"])
+			}
+		})
+	}
+}
+
+func TestWithoutVerticallyAutoscaledResources(t *testing.T) {
+	resources := func() corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("256Mi"), corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}
+	}
+	spec := &appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Resources: resources()}},
+		Containers:     []corev1.Container{{Name: "cluster-agent", Resources: resources()}},
+	}}}
+
+	t.Run("requests and limits of cpu and memory", func(t *testing.T) {
+		got := WithoutVerticallyAutoscaledResources(spec, &v2alpha1.VerticalAutoscalingConfig{})
+		assert.Equal(t, corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{},
+		}, got.Template.Spec.Containers[0].Resources)
+		assert.Equal(t, resources(), got.Template.Spec.InitContainers[0].Resources, "the init containers are not autoscaled")
+		assert.Equal(t, resources(), spec.Template.Spec.Containers[0].Resources, "the spec is not modified")
+	})
+
+	t.Run("requests of memory", func(t *testing.T) {
+		got := WithoutVerticallyAutoscaledResources(spec, &v2alpha1.VerticalAutoscalingConfig{
+			ControlledResources: []corev1.ResourceName{corev1.ResourceMemory},
+			ControlledValues:    ptr.To(v2alpha1.VerticalAutoscalingControlledRequestsOnly),
+		})
+		assert.Equal(t, corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}, got.Template.Spec.Containers[0].Resources)
+	})
+}
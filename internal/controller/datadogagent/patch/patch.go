@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package patch applies the user-provided spec.patches of a DatadogAgent to
+// the objects generated by the operator, just before they are applied.
+package patch
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+// unmatchedMessage is the status message of a patch that targets no object.
+const unmatchedMessage = "no object generated by the operator matches the target"
+
+// componentLabelValues are the values of the component label of the objects
+// of each component.
+var componentLabelValues = map[v2alpha1.ComponentName]string{
+	v2alpha1.NodeAgentComponentName:           constants.DefaultAgentResourceSuffix,
+	v2alpha1.ClusterAgentComponentName:        constants.DefaultClusterAgentResourceSuffix,
+	v2alpha1.ClusterChecksRunnerComponentName: constants.DefaultClusterChecksRunnerResourceSuffix,
+	v2alpha1.OtelAgentGatewayComponentName:    constants.DefaultOtelAgentGatewayResourceSuffix,
+}
+
+// Apply applies, in order, the patches targeting obj, and returns the status of
+// each of them. A patch that cannot be applied is skipped, so that obj only
+// holds the patches that were applied.
+func Apply(patches []v2alpha1.ObjectPatch, obj client.Object) []v2alpha1.ObjectPatchStatus {
+	kind := Kind(obj)
+	var statuses []v2alpha1.ObjectPatchStatus
+	for i, p := range patches {
+		if !Matches(p.Target, kind, obj) {
+			continue
+		}
+		status := v2alpha1.ObjectPatchStatus{
+			Index:     int32(i),
+			Kind:      kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Applied:   true,
+		}
+		if err := apply(p, obj); err != nil {
+			status.Applied = false
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Kind returns the kind of obj: the kind of its TypeMeta when set, like on
+// unstructured objects, or else the name of its Go type.
+func Kind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
+
+// Matches returns true if the object of the given kind is selected by target.
+func Matches(target v2alpha1.ObjectPatchTarget, kind string, obj client.Object) bool {
+	if target.Kind != kind {
+		return false
+	}
+	if target.Name != "" && target.Name != obj.GetName() {
+		return false
+	}
+	if target.Component != "" {
+		value, found := obj.GetLabels()[apicommon.AgentDeploymentComponentLabelKey]
+		if !found || value != componentLabelValues[target.Component] {
+			return false
+		}
+	}
+	return true
+}
+
+func apply(p v2alpha1.ObjectPatch, obj client.Object) error {
+	patchJSON, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case v2alpha1.ObjectPatchJSONPatch:
+		var decoded jsonpatch.Patch
+		if decoded, err = jsonpatch.DecodePatch(patchJSON); err != nil {
+			return fmt.Errorf("invalid patch: %w", err)
+		}
+		patched, err = decoded.Apply(original)
+	case v2alpha1.ObjectPatchStrategicMerge:
+		// Unstructured objects carry no patch strategy: they are merged as JSON merge patches.
+		if _, ok := obj.(runtime.Unstructured); ok {
+			patched, err = jsonpatch.MergePatch(original, patchJSON)
+		} else {
+			patched, err = strategicpatch.StrategicMergePatch(original, patchJSON, obj)
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %q", p.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to apply the patch: %w", err)
+	}
+
+	// Decode into a new object: decoding into obj would keep the fields the patch removed.
+	result := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	if _, ok := obj.(runtime.Unstructured); !ok {
+		decoder.DisallowUnknownFields()
+	}
+	if err = decoder.Decode(result); err != nil {
+		return fmt.Errorf("the patched object is invalid: %w", err)
+	}
+	if result.GetName() != obj.GetName() || result.GetNamespace() != obj.GetNamespace() {
+		return fmt.Errorf("the patch must not change the name or the namespace of the object")
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(result).Elem())
+	return nil
+}
+
+// SortStatuses sorts the statuses by patch index, then by object.
+func SortStatuses(statuses []v2alpha1.ObjectPatchStatus) {
+	slices.SortFunc(statuses, func(a, b v2alpha1.ObjectPatchStatus) int {
+		return cmp.Or(
+			cmp.Compare(a.Index, b.Index),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// Summarize returns the statuses reported for the patches by the different
+// controllers, sorted and without duplicates, along with a status for each
+// patch that targets no object. Statuses of patches that no longer exist are
+// dropped.
+func Summarize(patches []v2alpha1.ObjectPatch, statuses []v2alpha1.ObjectPatchStatus) []v2alpha1.ObjectPatchStatus {
+	summary := slices.DeleteFunc(slices.Clone(statuses), func(s v2alpha1.ObjectPatchStatus) bool {
+		return int(s.Index) >= len(patches)
+	})
+	for i, p := range patches {
+		if !slices.ContainsFunc(summary, func(s v2alpha1.ObjectPatchStatus) bool { return s.Index == int32(i) }) {
+			summary = append(summary, v2alpha1.ObjectPatchStatus{
+				Index:   int32(i),
+				Kind:    p.Target.Kind,
+				Name:    p.Target.Name,
+				Message: unmatchedMessage,
+			})
+		}
+	}
+	SortStatuses(summary)
+	return slices.Compact(summary)
+}
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+)
+
+func testDaemonSet() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "datadog-agent",
+			Namespace: "datadog",
+			Labels:    map[string]string{apicommon.AgentDeploymentComponentLabelKey: "agent"},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					HostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"intake"}}},
+					Containers: []corev1.Container{
+						{Name: "agent", Image: "agent:7"},
+						{Name: "trace-agent", Image: "agent:7"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		patches  []v2alpha1.ObjectPatch
+		want     func(*testing.T, *appsv1.DaemonSet)
+		statuses []v2alpha1.ObjectPatchStatus
+	}{
+		{
+			name: "JSON patch",
+			patches: []v2alpha1.ObjectPatch{{
+				Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"},
+				Type:   v2alpha1.ObjectPatchJSONPatch,
+				Patch:  "- op: add\n  path: /spec/template/spec/terminationGracePeriodSeconds\n  value: 60\n- op: remove\n  path: /spec/template/spec/hostAliases",
+			}},
+			want: func(t *testing.T, ds *appsv1.DaemonSet) {
+				assert.Equal(t, ptr.To[int64](60), ds.Spec.Template.Spec.TerminationGracePeriodSeconds)
+				assert.Empty(t, ds.Spec.Template.Spec.HostAliases)
+			},
+			statuses: []v2alpha1.ObjectPatchStatus{{Index: 0, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Applied: true}},
+		},
+		{
+			name: "strategic merge patch merges containers by name",
+			patches: []v2alpha1.ObjectPatch{{
+				Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet", Name: "datadog-agent", Component: v2alpha1.NodeAgentComponentName},
+				Type:   v2alpha1.ObjectPatchStrategicMerge,
+				Patch:  "spec:\n  template:\n    spec:\n      containers:\n      - name: trace-agent\n        imagePullPolicy: Always",
+			}},
+			want: func(t *testing.T, ds *appsv1.DaemonSet) {
+				require.Len(t, ds.Spec.Template.Spec.Containers, 2)
+				assert.Empty(t, ds.Spec.Template.Spec.Containers[0].ImagePullPolicy)
+				assert.Equal(t, corev1.PullAlways, ds.Spec.Template.Spec.Containers[1].ImagePullPolicy)
+				assert.Equal(t, "agent:7", ds.Spec.Template.Spec.Containers[1].Image)
+			},
+			statuses: []v2alpha1.ObjectPatchStatus{{Index: 0, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Applied: true}},
+		},
+		{
+			name: "patches targeting other objects are ignored",
+			patches: []v2alpha1.ObjectPatch{
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "Deployment"}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "spec: {minReadySeconds: 1}"},
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet", Name: "other"}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "spec: {minReadySeconds: 1}"},
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet", Component: v2alpha1.ClusterAgentComponentName}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "spec: {minReadySeconds: 1}"},
+			},
+			want: func(t *testing.T, ds *appsv1.DaemonSet) {
+				assert.Zero(t, ds.Spec.MinReadySeconds)
+			},
+		},
+		{
+			name: "failing patches are skipped",
+			patches: []v2alpha1.ObjectPatch{
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"}, Type: v2alpha1.ObjectPatchJSONPatch, Patch: `[{"op": "replace", "path": "/spec/unknown/field", "value": 1}]`},
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "spec: {minReadySeconds: 5}"},
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "spec: {minReadySecond: 10}"},
+				{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"}, Type: v2alpha1.ObjectPatchStrategicMerge, Patch: "metadata: {name: renamed}"},
+			},
+			want: func(t *testing.T, ds *appsv1.DaemonSet) {
+				assert.Equal(t, int32(5), ds.Spec.MinReadySeconds)
+				assert.Equal(t, "datadog-agent", ds.Name)
+			},
+			statuses: []v2alpha1.ObjectPatchStatus{
+				{Index: 0, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Message: "unable to apply the patch: replace operation does not apply: doc is missing path: /spec/unknown/field: missing value"},
+				{Index: 1, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Applied: true},
+				{Index: 2, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Message: "the patched object is invalid: json: unknown field \"minReadySecond\""},
+				{Index: 3, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Message: "the patch must not change the name or the namespace of the object"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := testDaemonSet()
+			statuses := Apply(tt.patches, ds)
+			assert.Equal(t, tt.statuses, statuses)
+			tt.want(t, ds)
+		})
+	}
+}
+
+func TestApplyUnstructured(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"metadata":   map[string]any{"name": "datadog-agent", "namespace": "datadog"},
+		"specs":      []any{map[string]any{"description": "agent"}},
+	}}
+	statuses := Apply([]v2alpha1.ObjectPatch{{
+		Target: v2alpha1.ObjectPatchTarget{Kind: "CiliumNetworkPolicy"},
+		Type:   v2alpha1.ObjectPatchStrategicMerge,
+		Patch:  "metadata: {annotations: {team: agent}}",
+	}}, obj)
+
+	assert.Equal(t, []v2alpha1.ObjectPatchStatus{{Index: 0, Kind: "CiliumNetworkPolicy", Namespace: "datadog", Name: "datadog-agent", Applied: true}}, statuses)
+	assert.Equal(t, map[string]string{"team": "agent"}, obj.GetAnnotations())
+	assert.NotEmpty(t, obj.Object["specs"])
+}
+
+func TestSummarize(t *testing.T) {
+	patches := []v2alpha1.ObjectPatch{
+		{Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet"}},
+		{Target: v2alpha1.ObjectPatchTarget{Kind: "Service", Name: "missing"}},
+	}
+	applied := v2alpha1.ObjectPatchStatus{Index: 0, Kind: "DaemonSet", Namespace: "datadog", Name: "datadog-agent", Applied: true}
+	statuses := []v2alpha1.ObjectPatchStatus{
+		applied,
+		{Index: 2, Kind: "Deployment", Namespace: "datadog", Name: "stale", Applied: true},
+		applied,
+	}
+
+	assert.Equal(t, []v2alpha1.ObjectPatchStatus{
+		applied,
+		{Index: 1, Kind: "Service", Name: "missing", Message: unmatchedMessage},
+	}, Summarize(patches, statuses))
+}
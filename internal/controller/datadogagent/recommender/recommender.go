@@ -0,0 +1,257 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package recommender suggests requests and limits for the Agent containers from their observed usage.
+package recommender
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+const (
+	// SampleInterval is the minimum time between two samples of the usage of the pods of a DatadogAgent.
+	SampleInterval = time.Minute
+	// MaxSamples is the number of samples kept for each container: a week of samples, the oldest being dropped first.
+	// A sample is the highest usage of the container across the pods, so that the pod count does not change the
+	// period covered by the samples.
+	MaxSamples = 7 * 24 * 60
+	// MinSamples is the number of samples required before a recommendation is published for a container.
+	MinSamples = 60
+
+	// DefaultRequestPercentile is used when the configuration does not set requestPercentile.
+	DefaultRequestPercentile = 90
+	// DefaultLimitPercentile is used when the configuration does not set limitPercentile.
+	DefaultLimitPercentile = 99
+	// DefaultLimitMarginPercent is used when the configuration does not set limitMarginPercent.
+	DefaultLimitMarginPercent = 20
+
+	mebibyte = 1024 * 1024
+)
+
+// componentsByLabel maps the value of the component label of the pods to the components they belong to.
+var componentsByLabel = map[string]v2alpha1.ComponentName{
+	constants.DefaultAgentResourceSuffix:               v2alpha1.NodeAgentComponentName,
+	constants.DefaultClusterAgentResourceSuffix:        v2alpha1.ClusterAgentComponentName,
+	constants.DefaultClusterChecksRunnerResourceSuffix: v2alpha1.ClusterChecksRunnerComponentName,
+}
+
+// Recommender samples the usage of the containers of the pods of each DatadogAgent, and computes
+// percentile-based resource recommendations from it. It is safe for concurrent use.
+type Recommender struct {
+	source UsageSource
+	now    func() time.Time
+
+	mutex  sync.Mutex
+	agents map[types.NamespacedName]*agentUsage
+}
+
+type agentUsage struct {
+	lastSample time.Time
+	lastErr    error
+	containers map[containerKey]*containerUsage
+}
+
+type containerKey struct {
+	profile   string
+	component v2alpha1.ComponentName
+	container string
+}
+
+type containerUsage struct {
+	// cpu is in millicores, memory in bytes.
+	cpu    ring
+	memory ring
+}
+
+// containerSample is the usage of a container at a sample, in millicores and bytes.
+type containerSample struct {
+	cpu    int64
+	memory int64
+}
+
+// ring keeps the last MaxSamples values added to it.
+type ring struct {
+	values []int64
+	next   int
+}
+
+func (r *ring) add(value int64) {
+	if len(r.values) < MaxSamples {
+		r.values = append(r.values, value)
+		return
+	}
+	r.values[r.next] = value
+	r.next = (r.next + 1) % MaxSamples
+}
+
+// NewRecommender returns a Recommender sampling the usage of the pods from the source.
+func NewRecommender(source UsageSource) *Recommender {
+	return &Recommender{
+		source: source,
+		now:    time.Now,
+		agents: map[types.NamespacedName]*agentUsage{},
+	}
+}
+
+// Sample records the current usage of the containers of the pods of the DatadogAgent.
+// When the previous sample is more recent than SampleInterval, it only returns the error of that sample.
+func (r *Recommender) Sample(ctx context.Context, dda types.NamespacedName) error {
+	r.mutex.Lock()
+	agent, found := r.agents[dda]
+	if !found {
+		agent = &agentUsage{containers: map[containerKey]*containerUsage{}}
+		r.agents[dda] = agent
+	}
+	now := r.now()
+	if now.Sub(agent.lastSample) < SampleInterval {
+		err := agent.lastErr
+		r.mutex.Unlock()
+		return err
+	}
+	agent.lastSample = now
+	r.mutex.Unlock()
+
+	selector := labels.SelectorFromSet(labels.Set{apicommon.AgentDeploymentNameLabelKey: dda.Name})
+	pods, err := r.source.PodUsage(ctx, dda.Namespace, selector)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	agent.lastErr = err
+	// The DatadogAgent may have been forgotten while the usage was read.
+	if err != nil || r.agents[dda] != agent {
+		return err
+	}
+	// The pods of a profile and component share their resources: each sample keeps the highest usage.
+	samples := map[containerKey]*containerSample{}
+	for _, pod := range pods {
+		component, found := componentsByLabel[pod.Labels[apicommon.AgentDeploymentComponentLabelKey]]
+		if !found {
+			continue
+		}
+		profile := ""
+		if component == v2alpha1.NodeAgentComponentName {
+			profile = pod.Labels[constants.ProfileLabelKey]
+		}
+		for name, usage := range pod.Containers {
+			key := containerKey{profile: profile, component: component, container: name}
+			cpu, memory := usage.Cpu().MilliValue(), usage.Memory().Value()
+			if sample, found := samples[key]; found {
+				sample.cpu = max(sample.cpu, cpu)
+				sample.memory = max(sample.memory, memory)
+				continue
+			}
+			samples[key] = &containerSample{cpu: cpu, memory: memory}
+		}
+	}
+	for key, sample := range samples {
+		container, found := agent.containers[key]
+		if !found {
+			container = &containerUsage{}
+			agent.containers[key] = container
+		}
+		container.cpu.add(sample.cpu)
+		container.memory.add(sample.memory)
+	}
+	return nil
+}
+
+// Forget drops the samples of the DatadogAgent.
+func (r *Recommender) Forget(dda types.NamespacedName) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.agents, dda)
+}
+
+// Recommendations returns the recommendations for the containers of the DatadogAgent that have at least
+// MinSamples samples, sorted by profile, component and container.
+func (r *Recommender) Recommendations(dda types.NamespacedName, config *v2alpha1.ResourceRecommenderConfig) []v2alpha1.ResourceRecommendation {
+	requestPercentile, limitPercentile, limitMargin := int32(DefaultRequestPercentile), int32(DefaultLimitPercentile), int32(DefaultLimitMarginPercent)
+	if config != nil {
+		if config.RequestPercentile != nil {
+			requestPercentile = *config.RequestPercentile
+		}
+		if config.LimitPercentile != nil {
+			limitPercentile = *config.LimitPercentile
+		}
+		if config.LimitMarginPercent != nil {
+			limitMargin = *config.LimitMarginPercent
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	agent, found := r.agents[dda]
+	if !found {
+		return nil
+	}
+
+	var recommendations []v2alpha1.ResourceRecommendation
+	for key, usage := range agent.containers {
+		if len(usage.cpu.values) < MinSamples {
+			continue
+		}
+		cpu := slices.Sorted(slices.Values(usage.cpu.values))
+		memory := slices.Sorted(slices.Values(usage.memory.values))
+		recommendations = append(recommendations, v2alpha1.ResourceRecommendation{
+			Profile:   key.profile,
+			Component: key.component,
+			Container: key.container,
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    cpuQuantity(percentile(cpu, requestPercentile)),
+				corev1.ResourceMemory: memoryQuantity(percentile(memory, requestPercentile)),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    cpuQuantity(withMargin(percentile(cpu, limitPercentile), limitMargin)),
+				corev1.ResourceMemory: memoryQuantity(withMargin(percentile(memory, limitPercentile), limitMargin)),
+			},
+			Samples: int32(len(usage.cpu.values)),
+		})
+	}
+	slices.SortFunc(recommendations, func(a, b v2alpha1.ResourceRecommendation) int {
+		if c := strings.Compare(a.Profile, b.Profile); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Component), string(b.Component)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Container, b.Container)
+	})
+	return recommendations
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []int64, p int32) int64 {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
+func withMargin(value int64, marginPercent int32) int64 {
+	return int64(math.Ceil(float64(value) * (1 + float64(marginPercent)/100)))
+}
+
+// cpuQuantity returns the millicores as a quantity, of at least 1m.
+func cpuQuantity(milliCores int64) resource.Quantity {
+	return *resource.NewMilliQuantity(max(milliCores, 1), resource.DecimalSI)
+}
+
+// memoryQuantity returns the bytes rounded up to the mebibyte, of at least 1Mi.
+func memoryQuantity(bytes int64) resource.Quantity {
+	mebibytes := max((bytes+mebibyte-1)/mebibyte, 1)
+	return *resource.NewQuantity(mebibytes*mebibyte, resource.BinarySI)
+}
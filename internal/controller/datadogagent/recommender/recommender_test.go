@@ -0,0 +1,181 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recommender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+type fakeUsageSource struct {
+	pods      func(sample int) []PodUsage
+	err       error
+	samples   int
+	selectors []string
+}
+
+func (s *fakeUsageSource) PodUsage(_ context.Context, _ string, selector labels.Selector) ([]PodUsage, error) {
+	s.selectors = append(s.selectors, selector.String())
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.samples++
+	return s.pods(s.samples), nil
+}
+
+func podUsage(component, profile string, containers map[string]corev1.ResourceList) PodUsage {
+	podLabels := map[string]string{
+		apicommon.AgentDeploymentNameLabelKey:      "datadog",
+		apicommon.AgentDeploymentComponentLabelKey: component,
+	}
+	if profile != "" {
+		podLabels[constants.ProfileLabelKey] = profile
+	}
+	return PodUsage{Labels: podLabels, Containers: containers}
+}
+
+func usage(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+// summary renders a recommendation with its quantities as strings, to compare it regardless of their internal format.
+func summary(r v2alpha1.ResourceRecommendation) string {
+	return fmt.Sprintf("%s/%s/%s requests=%s,%s limits=%s,%s samples=%d", r.Profile, r.Component, r.Container,
+		r.Requests.Cpu(), r.Requests.Memory(), r.Limits.Cpu(), r.Limits.Memory(), r.Samples)
+}
+
+// sampleN takes n samples, one per SampleInterval.
+func sampleN(t *testing.T, r *Recommender, clock *time.Time, dda types.NamespacedName, n int) {
+	for range n {
+		*clock = clock.Add(SampleInterval)
+		require.NoError(t, r.Sample(context.Background(), dda))
+	}
+}
+
+func TestRecommender(t *testing.T) {
+	dda := types.NamespacedName{Namespace: "datadog", Name: "datadog"}
+	source := &fakeUsageSource{pods: func(sample int) []PodUsage {
+		// The agent uses 1m to 100m of CPU, and 1Mi to 100Mi of memory.
+		value := (sample-1)%100 + 1
+		return []PodUsage{
+			podUsage(constants.DefaultAgentResourceSuffix, "", map[string]corev1.ResourceList{
+				"agent":       usage(resource.NewMilliQuantity(int64(value), resource.DecimalSI).String(), resource.NewQuantity(int64(value)*mebibyte, resource.BinarySI).String()),
+				"trace-agent": usage("10m", "50Mi"),
+			}),
+			podUsage(constants.DefaultAgentResourceSuffix, "gpu-nodes", map[string]corev1.ResourceList{"agent": usage("200m", "300Mi")}),
+			podUsage(constants.DefaultClusterAgentResourceSuffix, "", map[string]corev1.ResourceList{"cluster-agent": usage("20m", "100000000")}),
+			podUsage("unknown", "", map[string]corev1.ResourceList{"foo": usage("1", "1Gi")}),
+		}
+	}}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecommender(source)
+	r.now = func() time.Time { return clock }
+
+	sampleN(t, r, &clock, dda, MinSamples-1)
+	assert.Empty(t, r.Recommendations(dda, nil), "no recommendation before MinSamples samples")
+	assert.Equal(t, "agent.datadoghq.com/name=datadog", source.selectors[0])
+
+	// Samples are rate limited.
+	require.NoError(t, r.Sample(context.Background(), dda))
+	assert.Equal(t, MinSamples-1, source.samples)
+
+	sampleN(t, r, &clock, dda, 100-MinSamples+1)
+	recommendations := r.Recommendations(dda, nil)
+	summaries := make([]string, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		summaries = append(summaries, summary(recommendation))
+	}
+	assert.Equal(t, []string{
+		// 100000000 bytes are rounded up to 96Mi
+		"/clusterAgent/cluster-agent requests=20m,96Mi limits=24m,115Mi samples=100",
+		// The limit is the 99th percentile plus 20%: 99 * 1.2 = 118.8
+		"/nodeAgent/agent requests=90m,90Mi limits=119m,119Mi samples=100",
+		"/nodeAgent/trace-agent requests=10m,50Mi limits=12m,60Mi samples=100",
+		"gpu-nodes/nodeAgent/agent requests=200m,300Mi limits=240m,360Mi samples=100",
+	}, summaries)
+
+	recommendations = r.Recommendations(dda, &v2alpha1.ResourceRecommenderConfig{
+		RequestPercentile:  ptr.To[int32](50),
+		LimitPercentile:    ptr.To[int32](100),
+		LimitMarginPercent: ptr.To[int32](0),
+	})
+	assert.Equal(t, "/nodeAgent/agent requests=50m,50Mi limits=100m,100Mi samples=100", summary(recommendations[1]))
+
+	r.Forget(dda)
+	assert.Empty(t, r.Recommendations(dda, nil))
+}
+
+func TestRecommenderSamplesPerInterval(t *testing.T) {
+	dda := types.NamespacedName{Namespace: "datadog", Name: "datadog"}
+	source := &fakeUsageSource{pods: func(int) []PodUsage {
+		pods := make([]PodUsage, 0, 10)
+		for i := range 10 {
+			pods = append(pods, podUsage(constants.DefaultAgentResourceSuffix, "", map[string]corev1.ResourceList{
+				"agent": usage(fmt.Sprintf("%dm", 10*(i+1)), fmt.Sprintf("%dMi", 10*(i+1))),
+			}))
+		}
+		return pods
+	}}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecommender(source)
+	r.now = func() time.Time { return clock }
+
+	// The number of pods does not shorten the delay before the first recommendation.
+	sampleN(t, r, &clock, dda, MinSamples-1)
+	assert.Empty(t, r.Recommendations(dda, nil), "no recommendation before MinSamples intervals")
+
+	sampleN(t, r, &clock, dda, 1)
+	recommendations := r.Recommendations(dda, nil)
+	require.Len(t, recommendations, 1)
+	// Each sample is the highest usage across the pods.
+	assert.Equal(t, "/nodeAgent/agent requests=100m,100Mi limits=120m,120Mi samples=60", summary(recommendations[0]))
+}
+
+func TestRecommenderKeepsMaxSamples(t *testing.T) {
+	dda := types.NamespacedName{Namespace: "datadog", Name: "datadog"}
+	source := &fakeUsageSource{pods: func(sample int) []PodUsage {
+		// The usage drops after the first week of samples.
+		cpu, memory := "500m", "500Mi"
+		if sample > MaxSamples {
+			cpu, memory = "100m", "100Mi"
+		}
+		return []PodUsage{podUsage(constants.DefaultAgentResourceSuffix, "", map[string]corev1.ResourceList{"agent": usage(cpu, memory)})}
+	}}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecommender(source)
+	r.now = func() time.Time { return clock }
+
+	sampleN(t, r, &clock, dda, 2*MaxSamples)
+	recommendations := r.Recommendations(dda, nil)
+	require.Len(t, recommendations, 1)
+	// The samples of the first week are dropped.
+	assert.Equal(t, "/nodeAgent/agent requests=100m,100Mi limits=120m,120Mi samples=10080", summary(recommendations[0]))
+}
+
+func TestRecommenderSourceError(t *testing.T) {
+	dda := types.NamespacedName{Namespace: "datadog", Name: "datadog"}
+	r := NewRecommender(&fakeUsageSource{err: errors.New("the server could not find the requested resource")})
+
+	assert.Error(t, r.Sample(context.Background(), dda))
+	// The error is reported until the next sample.
+	assert.Error(t, r.Sample(context.Background(), dda))
+	assert.Empty(t, r.Recommendations(dda, nil))
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recommender
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodUsage is the resource usage of the containers of a pod.
+type PodUsage struct {
+	// Labels are the labels of the pod.
+	Labels map[string]string
+	// Containers is the usage of each container, by container name.
+	Containers map[string]corev1.ResourceList
+}
+
+// UsageSource returns the current resource usage of pods.
+type UsageSource interface {
+	// PodUsage returns the usage of the pods of the namespace matching the selector.
+	PodUsage(ctx context.Context, namespace string, selector labels.Selector) ([]PodUsage, error)
+}
+
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+type metricsUsageSource struct {
+	client client.Reader
+}
+
+// NewMetricsUsageSource returns a UsageSource reading the metrics.k8s.io API, usually served by the metrics-server.
+// The PodMetrics are read as unstructured objects, so that the operator does not depend on the metrics API types.
+func NewMetricsUsageSource(c client.Reader) UsageSource {
+	return &metricsUsageSource{client: c}
+}
+
+func (s *metricsUsageSource) PodUsage(ctx context.Context, namespace string, selector labels.Selector) ([]PodUsage, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := s.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list the pod metrics: %w", err)
+	}
+
+	usages := make([]PodUsage, 0, len(list.Items))
+	for _, item := range list.Items {
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics of pod %s: %w", item.GetName(), err)
+		}
+		usage := PodUsage{Labels: item.GetLabels(), Containers: map[string]corev1.ResourceList{}}
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			values, _, _ := unstructured.NestedStringMap(container, "usage")
+			resources := corev1.ResourceList{}
+			for resourceName, value := range values {
+				quantity, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s usage of container %s of pod %s: %w", resourceName, name, item.GetName(), err)
+				}
+				resources[corev1.ResourceName(resourceName)] = quantity
+			}
+			usage.Containers[name] = resources
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recommender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func podMetrics(name string, podLabels map[string]any, containers ...any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]any{"name": name, "namespace": "datadog", "labels": podLabels},
+		"timestamp":  "2026-01-01T00:00:00Z",
+		"window":     "15s",
+		"containers": containers,
+	}}
+}
+
+func TestMetricsUsageSource(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(podMetricsListGVK, &unstructured.UnstructuredList{})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		podMetrics("datadog-agent-abcde", map[string]any{"agent.datadoghq.com/name": "datadog"},
+			map[string]any{"name": "agent", "usage": map[string]any{"cpu": "12345678n", "memory": "200Mi"}},
+			map[string]any{"name": "trace-agent", "usage": map[string]any{"cpu": "2m", "memory": "30000Ki"}},
+		),
+		podMetrics("other-agent-abcde", map[string]any{"agent.datadoghq.com/name": "other"},
+			map[string]any{"name": "agent", "usage": map[string]any{"cpu": "1", "memory": "1Gi"}},
+		),
+	).Build()
+
+	usages, err := NewMetricsUsageSource(c).PodUsage(context.Background(), "datadog", labels.SelectorFromSet(labels.Set{"agent.datadoghq.com/name": "datadog"}))
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, map[string]string{"agent.datadoghq.com/name": "datadog"}, usages[0].Labels)
+	require.Len(t, usages[0].Containers, 2)
+	agent := usages[0].Containers["agent"]
+	assert.Equal(t, int64(13), agent.Cpu().MilliValue())
+	assert.Equal(t, int64(200*mebibyte), agent.Memory().Value())
+	traceAgent := usages[0].Containers["trace-agent"]
+	assert.Equal(t, int64(2), traceAgent.Cpu().MilliValue())
+	assert.Equal(t, int64(30000*1024), traceAgent.Memory().Value())
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/component"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/defaults"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/experimental"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/patch"
 	"github.com/DataDog/datadog-operator/internal/controller/finalizer"
 	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/condition"
@@ -126,6 +127,8 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, logger logr.Logger,
 		}
 	}
 
+	r.resolveKSMCustomResources(ctx, logger, &instance.Spec, newDDAStatus, now)
+
 	// Generate default DDAI object from DDA
 	ddai, err := r.generateDDAIFromDDA(instance, provider)
 	if err != nil {
@@ -164,6 +167,19 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, logger logr.Logger,
 		return r.updateStatusIfNeeded(logger, instance, ddaStatusCopy, result, err, now)
 	}
 
+	// Sample the usage of the Agent pods, and apply the resource recommendations to the DDAIs in Apply mode
+	r.manageResourceRecommendations(ctx, logger, instance, newDDAStatus, ddais, now)
+
+	// Schedule the rollout downtime before the DDAIs are updated, and persist it first
+	if r.startRolloutDowntime(ctx, logger, instance, newDDAStatus, ddais, now) {
+		for _, ddai := range ddais {
+			if e := r.addDDAIStatusToDDAStatus(newDDAStatus, ddai.ObjectMeta, now); e != nil {
+				return r.updateStatusIfNeeded(logger, instance, ddaStatusCopy, result, e, now)
+			}
+		}
+		return r.updateStatusIfNeeded(logger, instance, newDDAStatus, reconcile.Result{RequeueAfter: time.Second}, nil, now)
+	}
+
 	// Create or update the DDAI object in k8s
 	for _, ddai := range ddais {
 		if e := r.createOrUpdateDDAI(ddai); e != nil {
@@ -186,6 +202,12 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, logger logr.Logger,
 		return r.updateStatusIfNeeded(logger, instance, ddaStatusCopy, result, e, now)
 	}
 
+	// Report the patches, now that the DDA and DDAI controllers applied them
+	newDDAStatus.Patches = patch.Summarize(instance.Spec.Patches, newDDAStatus.Patches)
+
+	// Schedule or cancel the rollout downtime now that the status of the components is known
+	r.manageRolloutDowntime(logger, instance, newDDAStatus, ddaiSpecHash(ddais), now)
+
 	// Prevent the reconcile loop from stopping by requeueing the DDAI object after a period of time
 	result.RequeueAfter = defaultRequeuePeriod
 	return r.updateStatusIfNeeded(logger, instance, newDDAStatus, result, err, now)
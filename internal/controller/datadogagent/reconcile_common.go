@@ -201,6 +201,7 @@ func (r *Reconciler) addDDAIStatusToDDAStatus(status *v2alpha1.DatadogAgentStatu
 	status.Agent = condition.CombineDaemonSetStatus(status.Agent, currentDDAI.Status.Agent)
 	status.ClusterAgent = condition.CombineDeploymentStatus(status.ClusterAgent, currentDDAI.Status.ClusterAgent)
 	status.ClusterChecksRunner = condition.CombineDeploymentStatus(status.ClusterChecksRunner, currentDDAI.Status.ClusterChecksRunner)
+	status.Patches = append(status.Patches, currentDDAI.Status.Patches...)
 
 	// Only the default DDAI runs dependency management (e.g. RBAC-gated
 	// resources), so it's the only DDAI whose reconcile error is surfaced on
@@ -323,7 +324,9 @@ func IsEqualStatus(current *v2alpha1.DatadogAgentStatus, newStatus *v2alpha1.Dat
 		return false
 	}
 
-	if !apiequality.Semantic.DeepEqual(current.Experiment, newStatus.Experiment) {
+	if !apiequality.Semantic.DeepEqual(current.Experiment, newStatus.Experiment) ||
+		!apiequality.Semantic.DeepEqual(current.Patches, newStatus.Patches) ||
+		!apiequality.Semantic.DeepEqual(current.ResourceRecommendations, newStatus.ResourceRecommendations) {
 		return false
 	}
 
@@ -36,6 +36,7 @@ func generateNewStatusFromDDA(ddaStatus *datadoghqv2alpha1.DatadogAgentStatus) *
 			status.RemoteConfigConfiguration = ddaStatus.RemoteConfigConfiguration
 		}
 		status.Experiment = ddaStatus.Experiment.DeepCopy()
+		status.RolloutDowntime = ddaStatus.RolloutDowntime.DeepCopy()
 	}
 	return status
 }
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/api/utils"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/override"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/recommender"
+	"github.com/DataDog/datadog-operator/pkg/condition"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+const (
+	resourceRecommendationsAvailableReason          = "RecommendationsAvailable"
+	resourceRecommendationsCollectingReason         = "CollectingSamples"
+	resourceRecommendationsMetricsUnavailableReason = "MetricsUnavailable"
+	resourceRecommendationsNoUsageSourceReason      = "UsageSourceNotConfigured"
+)
+
+// manageResourceRecommendations samples the usage of the Agent pods, following
+// the resourceRecommender configuration, and reports the recommendations in the
+// status. In Apply mode, the recommendations are also set on the DDAIs, for the
+// containers whose resources are not set in the overrides. It must run after the
+// spec hash of the DDAIs is computed: the recommendations alone do not update the
+// DDAIs, they are applied the next time the Agents are rolled out.
+func (r *Reconciler) manageResourceRecommendations(ctx context.Context, logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, status *datadoghqv2alpha1.DatadogAgentStatus, ddais []*datadoghqv1alpha1.DatadogAgentInternal, now metav1.Time) {
+	key := types.NamespacedName{Namespace: dda.Namespace, Name: dda.Name}
+	var config *datadoghqv2alpha1.ResourceRecommenderConfig
+	if dda.Spec.Global != nil {
+		config = dda.Spec.Global.ResourceRecommender
+	}
+
+	if config == nil || !apiutils.BoolValue(config.Enabled) {
+		if r.recommender != nil {
+			r.recommender.Forget(key)
+		}
+		return
+	}
+	if r.recommender == nil {
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.ResourceRecommendationsAvailableConditionType, metav1.ConditionFalse,
+			resourceRecommendationsNoUsageSourceReason, "The operator is not configured to read the resource usage of the pods", true)
+		return
+	}
+
+	sampleErr := r.recommender.Sample(ctx, key)
+	recommendations := r.recommender.Recommendations(key, config)
+	if len(recommendations) == 0 {
+		// The samples are kept in memory: keep the recommendations published before
+		// a restart of the operator until enough samples are collected again.
+		recommendations = dda.Status.ResourceRecommendations
+	}
+	status.ResourceRecommendations = recommendations
+
+	switch {
+	case sampleErr != nil:
+		logger.V(1).Info("Unable to sample the resource usage of the Agent pods", "error", sampleErr)
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.ResourceRecommendationsAvailableConditionType, metav1.ConditionFalse,
+			resourceRecommendationsMetricsUnavailableReason, sampleErr.Error(), true)
+	case len(recommendations) == 0:
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.ResourceRecommendationsAvailableConditionType, metav1.ConditionFalse,
+			resourceRecommendationsCollectingReason, fmt.Sprintf("Recommendations are computed from at least %d samples of the resource usage", recommender.MinSamples), true)
+	default:
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.ResourceRecommendationsAvailableConditionType, metav1.ConditionTrue,
+			resourceRecommendationsAvailableReason, fmt.Sprintf("%d containers have a resource recommendation", len(recommendations)), true)
+	}
+
+	if config.Mode != nil && *config.Mode == datadoghqv2alpha1.ResourceRecommenderModeApply {
+		for _, ddai := range ddais {
+			applyResourceRecommendations(ddai, recommendations)
+		}
+	}
+}
+
+// applyResourceRecommendations sets the recommendations matching the profile of
+// the DDAI as the resources of the containers, unless they are set in the overrides
+// or managed by a VerticalPodAutoscaler.
+func applyResourceRecommendations(ddai *datadoghqv1alpha1.DatadogAgentInternal, recommendations []datadoghqv2alpha1.ResourceRecommendation) {
+	profile := ddai.Labels[constants.ProfileLabelKey]
+	for _, recommendation := range recommendations {
+		// The cluster-level components only run with the default profile.
+		if recommendation.Profile != profile {
+			continue
+		}
+
+		// The resources of the vertically autoscaled components are managed by their VerticalPodAutoscaler.
+		if override.VerticalAutoscaling(ddai.Spec.Override[recommendation.Component]) != nil {
+			continue
+		}
+
+		componentOverride := ddai.Spec.Override[recommendation.Component].DeepCopy()
+		if componentOverride == nil {
+			componentOverride = &datadoghqv2alpha1.DatadogAgentComponentOverride{}
+		}
+		containerName := apicommon.AgentContainerName(recommendation.Container)
+		container := componentOverride.Containers[containerName]
+		if container == nil {
+			container = &datadoghqv2alpha1.DatadogAgentGenericContainer{}
+		}
+		if container.Resources != nil {
+			continue
+		}
+		container.Resources = &corev1.ResourceRequirements{
+			Requests: recommendation.Requests.DeepCopy(),
+			Limits:   recommendation.Limits.DeepCopy(),
+		}
+
+		if componentOverride.Containers == nil {
+			componentOverride.Containers = map[apicommon.AgentContainerName]*datadoghqv2alpha1.DatadogAgentGenericContainer{}
+		}
+		componentOverride.Containers[containerName] = container
+		if ddai.Spec.Override == nil {
+			ddai.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{}
+		}
+		ddai.Spec.Override[recommendation.Component] = componentOverride
+	}
+}
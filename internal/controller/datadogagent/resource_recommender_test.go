@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/recommender"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+type fakeUsageSource struct {
+	err error
+}
+
+func (s *fakeUsageSource) PodUsage(context.Context, string, labels.Selector) ([]recommender.PodUsage, error) {
+	return nil, s.err
+}
+
+func recommendedResources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func TestManageResourceRecommendations(t *testing.T) {
+	now := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	published := []v2alpha1.ResourceRecommendation{
+		{Component: v2alpha1.ClusterAgentComponentName, Container: "cluster-agent", Requests: recommendedResources("20m", "96Mi"), Limits: recommendedResources("24m", "115Mi"), Samples: 100},
+		{Component: v2alpha1.NodeAgentComponentName, Container: "agent", Requests: recommendedResources("90m", "90Mi"), Limits: recommendedResources("119m", "119Mi"), Samples: 100},
+		{Component: v2alpha1.NodeAgentComponentName, Container: "trace-agent", Requests: recommendedResources("10m", "50Mi"), Limits: recommendedResources("12m", "60Mi"), Samples: 100},
+		{Profile: "gpu-nodes", Component: v2alpha1.NodeAgentComponentName, Container: "agent", Requests: recommendedResources("200m", "300Mi"), Limits: recommendedResources("240m", "360Mi"), Samples: 100},
+	}
+	newDDA := func(config *v2alpha1.ResourceRecommenderConfig) *v2alpha1.DatadogAgent {
+		return &v2alpha1.DatadogAgent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "datadog"},
+			Spec:       v2alpha1.DatadogAgentSpec{Global: &v2alpha1.GlobalConfig{ResourceRecommender: config}},
+			Status:     v2alpha1.DatadogAgentStatus{ResourceRecommendations: published},
+		}
+	}
+	newReconciler := func(source recommender.UsageSource) *Reconciler {
+		r := &Reconciler{}
+		if source != nil {
+			r.recommender = recommender.NewRecommender(source)
+		}
+		return r
+	}
+	availableCondition := func(status *v2alpha1.DatadogAgentStatus) *metav1.Condition {
+		return meta.FindStatusCondition(status.Conditions, common.ResourceRecommendationsAvailableConditionType)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		newReconciler(&fakeUsageSource{}).manageResourceRecommendations(context.Background(), logf.Log, newDDA(nil), status, nil, now)
+		assert.Empty(t, status.ResourceRecommendations)
+		assert.Nil(t, availableCondition(status))
+	})
+
+	t.Run("no usage source", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		newReconciler(nil).manageResourceRecommendations(context.Background(), logf.Log, newDDA(&v2alpha1.ResourceRecommenderConfig{Enabled: ptr.To(true)}), status, nil, now)
+		require.NotNil(t, availableCondition(status))
+		assert.Equal(t, resourceRecommendationsNoUsageSourceReason, availableCondition(status).Reason)
+	})
+
+	t.Run("metrics API unavailable", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		r := newReconciler(&fakeUsageSource{err: errors.New("no matches for kind \"PodMetricsList\" in version \"metrics.k8s.io/v1beta1\"")})
+		r.manageResourceRecommendations(context.Background(), logf.Log, newDDA(&v2alpha1.ResourceRecommenderConfig{Enabled: ptr.To(true)}), status, nil, now)
+		require.NotNil(t, availableCondition(status))
+		assert.Equal(t, metav1.ConditionFalse, availableCondition(status).Status)
+		assert.Equal(t, resourceRecommendationsMetricsUnavailableReason, availableCondition(status).Reason)
+	})
+
+	t.Run("collecting samples", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		dda := newDDA(&v2alpha1.ResourceRecommenderConfig{Enabled: ptr.To(true)})
+		dda.Status.ResourceRecommendations = nil
+		newReconciler(&fakeUsageSource{}).manageResourceRecommendations(context.Background(), logf.Log, dda, status, nil, now)
+		require.NotNil(t, availableCondition(status))
+		assert.Equal(t, resourceRecommendationsCollectingReason, availableCondition(status).Reason)
+	})
+
+	t.Run("recommendations are kept until enough samples are collected, and applied in Apply mode", func(t *testing.T) {
+		status := &v2alpha1.DatadogAgentStatus{}
+		defaultDDAI := &v1alpha1.DatadogAgentInternal{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadog"},
+			Spec: v2alpha1.DatadogAgentSpec{Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.NodeAgentComponentName: {Containers: map[apicommon.AgentContainerName]*v2alpha1.DatadogAgentGenericContainer{
+					apicommon.TraceAgentContainerName: {Resources: &corev1.ResourceRequirements{Limits: recommendedResources("1", "1Gi")}},
+				}},
+			}},
+		}
+		profileDDAI := &v1alpha1.DatadogAgentInternal{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadog-gpu-nodes", Labels: map[string]string{constants.ProfileLabelKey: "gpu-nodes"}},
+			Spec: v2alpha1.DatadogAgentSpec{Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterAgentComponentName: {Disabled: ptr.To(true)},
+			}},
+		}
+
+		dda := newDDA(&v2alpha1.ResourceRecommenderConfig{Enabled: ptr.To(true), Mode: ptr.To(v2alpha1.ResourceRecommenderModeApply)})
+		newReconciler(&fakeUsageSource{}).manageResourceRecommendations(context.Background(), logf.Log, dda, status, []*v1alpha1.DatadogAgentInternal{defaultDDAI, profileDDAI}, now)
+
+		assert.Equal(t, published, status.ResourceRecommendations)
+		require.NotNil(t, availableCondition(status))
+		assert.Equal(t, metav1.ConditionTrue, availableCondition(status).Status)
+
+		defaultOverride := defaultDDAI.Spec.Override
+		assert.Equal(t, &corev1.ResourceRequirements{Requests: recommendedResources("90m", "90Mi"), Limits: recommendedResources("119m", "119Mi")},
+			defaultOverride[v2alpha1.NodeAgentComponentName].Containers[apicommon.CoreAgentContainerName].Resources)
+		assert.Equal(t, &corev1.ResourceRequirements{Limits: recommendedResources("1", "1Gi")},
+			defaultOverride[v2alpha1.NodeAgentComponentName].Containers[apicommon.TraceAgentContainerName].Resources, "resources set in the overrides are kept")
+		assert.Equal(t, &corev1.ResourceRequirements{Requests: recommendedResources("20m", "96Mi"), Limits: recommendedResources("24m", "115Mi")},
+			defaultOverride[v2alpha1.ClusterAgentComponentName].Containers[apicommon.ClusterAgentContainerName].Resources)
+
+		profileOverride := profileDDAI.Spec.Override
+		assert.Equal(t, &corev1.ResourceRequirements{Requests: recommendedResources("200m", "300Mi"), Limits: recommendedResources("240m", "360Mi")},
+			profileOverride[v2alpha1.NodeAgentComponentName].Containers[apicommon.CoreAgentContainerName].Resources)
+		assert.Empty(t, profileOverride[v2alpha1.ClusterAgentComponentName].Containers, "the cluster agent does not run with the profile")
+	})
+
+	t.Run("recommendations are not applied to vertically autoscaled components", func(t *testing.T) {
+		ddai := &v1alpha1.DatadogAgentInternal{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadog"},
+			Spec: v2alpha1.DatadogAgentSpec{Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterAgentComponentName: {Autoscaling: &v2alpha1.ComponentAutoscalingConfig{Vertical: &v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)}}},
+			}},
+		}
+
+		applyResourceRecommendations(ddai, published)
+
+		assert.Empty(t, ddai.Spec.Override[v2alpha1.ClusterAgentComponentName].Containers)
+		assert.NotNil(t, ddai.Spec.Override[v2alpha1.NodeAgentComponentName].Containers[apicommon.CoreAgentContainerName].Resources)
+	})
+}
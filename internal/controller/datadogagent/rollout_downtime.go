@@ -0,0 +1,381 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	"github.com/DataDog/datadog-operator/pkg/condition"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	// defaultRolloutDowntimeMaxDuration is used when the policy does not set maxDuration.
+	defaultRolloutDowntimeMaxDuration = time.Hour
+	// defaultRolloutDowntimeScope mutes all the groups of the selected monitors.
+	defaultRolloutDowntimeScope = "*"
+	// rolloutDowntimeSettleDelay is the time left to the DaemonSet and Deployment
+	// controllers to report the update of the pods, before a rollout started by a
+	// DatadogAgentInternal update is considered complete.
+	rolloutDowntimeSettleDelay = time.Minute
+
+	rolloutDowntimeTriggerRollout    = "Rollout"
+	rolloutDowntimeTriggerExperiment = "Experiment"
+
+	rolloutDowntimeScheduledReason          = "DowntimeScheduled"
+	rolloutDowntimeNoRolloutReason          = "NoRolloutInProgress"
+	rolloutDowntimeMaxDurationReason        = "MaxDurationReached"
+	rolloutDowntimeErrorReason              = "DowntimeError"
+	rolloutDowntimeCredentialsMissingReason = "CredentialsNotConfigured"
+
+	eventReasonRolloutDowntimeScheduled = "RolloutDowntimeScheduled"
+	eventReasonRolloutDowntimeCanceled  = "RolloutDowntimeCanceled"
+	eventReasonRolloutDowntimeExpired   = "RolloutDowntimeExpired"
+)
+
+// DowntimeClient schedules and cancels Datadog downtimes.
+type DowntimeClient interface {
+	// CreateDowntime schedules a downtime and returns its ID.
+	CreateDowntime(attributes datadogV2.DowntimeCreateRequestAttributes) (string, error)
+	// FindActiveDowntime returns the ID and the end of an active or scheduled
+	// downtime whose message contains marker. The ID is empty when there is none.
+	FindActiveDowntime(marker string) (string, time.Time, error)
+	// CancelDowntime cancels a downtime. Canceling a downtime that no longer exists is not an error.
+	CancelDowntime(id string) error
+}
+
+type downtimeClient struct {
+	credsManager *config.CredentialManager
+	api          *datadogV2.DowntimesApi
+}
+
+// NewDowntimeClient returns a DowntimeClient authenticating with the credentials of the operator.
+func NewDowntimeClient(credsManager *config.CredentialManager) DowntimeClient {
+	return &downtimeClient{
+		credsManager: credsManager,
+		api:          datadogclient.InitGenericClients().DowntimesClient,
+	}
+}
+
+func (c *downtimeClient) CreateDowntime(attributes datadogV2.DowntimeCreateRequestAttributes) (string, error) {
+	auth, err := c.credsManager.GetAuth()
+	if err != nil {
+		return "", fmt.Errorf("unable to get credentials: %w", err)
+	}
+	body := datadogV2.NewDowntimeCreateRequest(*datadogV2.NewDowntimeCreateRequestData(attributes, datadogV2.DOWNTIMERESOURCETYPE_DOWNTIME))
+	downtime, _, err := c.api.CreateDowntime(auth, *body)
+	if err != nil {
+		return "", fmt.Errorf("error creating downtime: %w", err)
+	}
+	return downtime.Data.GetId(), nil
+}
+
+func (c *downtimeClient) FindActiveDowntime(marker string) (string, time.Time, error) {
+	auth, err := c.credsManager.GetAuth()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to get credentials: %w", err)
+	}
+	downtimes, cancel := c.api.ListDowntimesWithPagination(auth, *datadogV2.NewListDowntimesOptionalParameters().WithCurrentOnly(true))
+	defer cancel()
+	for result := range downtimes {
+		if result.Error != nil {
+			return "", time.Time{}, fmt.Errorf("error listing downtimes: %w", result.Error)
+		}
+		attributes := result.Item.GetAttributes()
+		if !strings.Contains(attributes.GetMessage(), marker) {
+			continue
+		}
+		if status := attributes.GetStatus(); status != datadogV2.DOWNTIMESTATUS_ACTIVE && status != datadogV2.DOWNTIMESTATUS_SCHEDULED {
+			continue
+		}
+		schedule := attributes.GetSchedule()
+		if schedule.DowntimeScheduleOneTimeResponse == nil {
+			continue
+		}
+		return result.Item.GetId(), schedule.DowntimeScheduleOneTimeResponse.GetEnd(), nil
+	}
+	return "", time.Time{}, nil
+}
+
+func (c *downtimeClient) CancelDowntime(id string) error {
+	auth, err := c.credsManager.GetAuth()
+	if err != nil {
+		return fmt.Errorf("unable to get credentials: %w", err)
+	}
+	httpResponse, err := c.api.CancelDowntime(auth, id)
+	if err != nil {
+		// The downtime was already removed in Datadog.
+		if httpResponse != nil && httpResponse.StatusCode == 404 {
+			return nil
+		}
+		return fmt.Errorf("error canceling downtime %s: %w", id, err)
+	}
+	return nil
+}
+
+// startRolloutDowntime schedules a Datadog downtime, following the
+// rolloutDowntime policy, before the DatadogAgentInternal objects are updated
+// with a new spec: the rollout is detected from the spec change rather than
+// from the status of the components, which only reports it once the pods are
+// being replaced. It returns true when a downtime was scheduled; its ID must
+// then be persisted in the status before the DatadogAgentInternal objects are
+// updated, so that a failed status update does not lose track of it.
+func (r *Reconciler) startRolloutDowntime(ctx context.Context, logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, status *datadoghqv2alpha1.DatadogAgentStatus, ddais []*v1alpha1.DatadogAgentInternal, now metav1.Time) bool {
+	if dda.Spec.Global == nil || dda.Spec.Global.RolloutDowntime == nil || r.options.DowntimeClient == nil {
+		return false
+	}
+	if !r.ddaiSpecChanged(ctx, logger, ddais) {
+		return false
+	}
+	trigger := rolloutDowntimeTriggerRollout
+	if status.Experiment != nil && status.Experiment.Phase == datadoghqv2alpha1.ExperimentPhaseRunning {
+		trigger = rolloutDowntimeTriggerExperiment
+	}
+	specHash := ddaiSpecHash(ddais)
+
+	current := status.RolloutDowntime
+	switch {
+	case current != nil && current.SpecHash == specHash:
+		// The rollout of this spec is already covered.
+		return false
+	case current != nil && current.ID != "" && now.Before(current.End):
+		// The active downtime now covers the rollout of the new spec.
+		current.SpecHash = specHash
+		current.Trigger = trigger
+		return false
+	}
+
+	downtime, err := r.scheduleRolloutDowntime(dda, dda.Spec.Global.RolloutDowntime, trigger, specHash, now)
+	if err != nil {
+		// The rollout is not held back: the downtime is scheduled again from the
+		// status of the components by manageRolloutDowntime.
+		logger.Error(err, "Unable to schedule the rollout downtime")
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse, rolloutDowntimeErrorReason, err.Error(), true)
+		return false
+	}
+	r.recordRolloutDowntimeScheduled(logger, dda, downtime)
+	status.RolloutDowntime = downtime
+	setRolloutDowntimeActiveCondition(status, downtime, now)
+	return true
+}
+
+// ddaiSpecChanged returns true when the spec of an existing
+// DatadogAgentInternal differs from the generated one.
+func (r *Reconciler) ddaiSpecChanged(ctx context.Context, logger logr.Logger, ddais []*v1alpha1.DatadogAgentInternal) bool {
+	for _, ddai := range ddais {
+		current := &v1alpha1.DatadogAgentInternal{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(ddai), current); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "Unable to get the DatadogAgentInternal", "name", ddai.Name)
+			}
+			continue
+		}
+		if current.Annotations[constants.MD5DDAIDeploymentAnnotationKey] != ddai.Annotations[constants.MD5DDAIDeploymentAnnotationKey] {
+			return true
+		}
+	}
+	return false
+}
+
+// ddaiSpecHash combines the spec hashes of the DatadogAgentInternal objects.
+func ddaiSpecHash(ddais []*v1alpha1.DatadogAgentInternal) string {
+	hashes := make(map[string]string, len(ddais))
+	for _, ddai := range ddais {
+		hashes[ddai.Name] = ddai.Annotations[constants.MD5DDAIDeploymentAnnotationKey]
+	}
+	// A map of strings is always encoded.
+	hash, _ := comparison.GenerateMD5ForSpec(hashes)
+	return hash
+}
+
+// manageRolloutDowntime follows the downtime scheduled by startRolloutDowntime
+// once the status of the components is known. The downtime is canceled when the
+// rollout completes, and ends on its own once the maximum duration of the
+// policy is reached. A downtime is also scheduled when the components are
+// rolled out without a spec change, for example when nodes are added. Only one
+// downtime is scheduled per rollout: the status is kept until the rollout
+// completes. Errors are reported in the RolloutDowntimeActive condition and
+// retried on the next reconcile.
+func (r *Reconciler) manageRolloutDowntime(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, status *datadoghqv2alpha1.DatadogAgentStatus, specHash string, now metav1.Time) {
+	var policy *datadoghqv2alpha1.RolloutDowntimeConfig
+	if dda.Spec.Global != nil {
+		policy = dda.Spec.Global.RolloutDowntime
+	}
+	current := status.RolloutDowntime
+
+	if policy == nil {
+		// The policy was removed: cancel the downtime it scheduled.
+		if current != nil && r.cancelRolloutDowntime(logger, dda, current, now) == nil {
+			status.RolloutDowntime = nil
+		}
+		return
+	}
+	if r.options.DowntimeClient == nil {
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse,
+			rolloutDowntimeCredentialsMissingReason, "The operator is not configured with Datadog credentials, downtimes cannot be scheduled", true)
+		return
+	}
+
+	trigger := rolloutTrigger(status)
+	if trigger == "" && current != nil && current.Start != nil && now.Sub(current.Start.Time) < rolloutDowntimeSettleDelay {
+		// The spec was just updated: the components may not report the rollout yet.
+		trigger = current.Trigger
+	}
+	switch {
+	case trigger == "":
+		if current != nil {
+			if err := r.cancelRolloutDowntime(logger, dda, current, now); err != nil {
+				condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse, rolloutDowntimeErrorReason, err.Error(), true)
+				return
+			}
+		}
+		status.RolloutDowntime = nil
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse,
+			rolloutDowntimeNoRolloutReason, "No rollout or experiment in progress", true)
+	case current == nil:
+		downtime, err := r.scheduleRolloutDowntime(dda, policy, trigger, specHash, now)
+		if err != nil {
+			logger.Error(err, "Unable to schedule the rollout downtime")
+			condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse, rolloutDowntimeErrorReason, err.Error(), true)
+			return
+		}
+		r.recordRolloutDowntimeScheduled(logger, dda, downtime)
+		status.RolloutDowntime = downtime
+		setRolloutDowntimeActiveCondition(status, downtime, now)
+	case current.ID != "" && !now.Before(current.End):
+		// The downtime ended on its own, it is not renewed for the same rollout.
+		logger.Info("Rollout downtime reached its maximum duration", "downtimeID", current.ID)
+		r.recorder.Eventf(dda, corev1.EventTypeWarning, eventReasonRolloutDowntimeExpired,
+			"Downtime %s reached its maximum duration before the end of the rollout", current.ID)
+		current.ID = ""
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse,
+			rolloutDowntimeMaxDurationReason, "The downtime reached its maximum duration before the end of the rollout", true)
+	case current.ID != "":
+		setRolloutDowntimeActiveCondition(status, current, now)
+	default:
+		condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionFalse,
+			rolloutDowntimeMaxDurationReason, "The downtime reached its maximum duration before the end of the rollout", true)
+	}
+}
+
+// rolloutTrigger returns what requires a downtime: a running experiment or a
+// component with pods not yet updated. It is empty when the Agents are up to date.
+func rolloutTrigger(status *datadoghqv2alpha1.DatadogAgentStatus) string {
+	if status.Experiment != nil && status.Experiment.Phase == datadoghqv2alpha1.ExperimentPhaseRunning {
+		return rolloutDowntimeTriggerExperiment
+	}
+	if status.Agent != nil && status.Agent.UpToDate < status.Agent.Desired {
+		return rolloutDowntimeTriggerRollout
+	}
+	for _, deployment := range []*datadoghqv2alpha1.DeploymentStatus{status.ClusterAgent, status.ClusterChecksRunner, status.OtelAgentGateway} {
+		if deployment != nil && deployment.UpdatedReplicas < deployment.Replicas {
+			return rolloutDowntimeTriggerRollout
+		}
+	}
+	return ""
+}
+
+// rolloutDowntimeMarker identifies the downtimes scheduled for a DatadogAgent
+// in their message.
+func rolloutDowntimeMarker(dda *datadoghqv2alpha1.DatadogAgent) string {
+	return fmt.Sprintf("datadogagent_uid:%s", dda.UID)
+}
+
+// scheduleRolloutDowntime creates a downtime, unless one scheduled for the
+// DatadogAgent is still active: its ID may not have been recorded in the status.
+func (r *Reconciler) scheduleRolloutDowntime(dda *datadoghqv2alpha1.DatadogAgent, policy *datadoghqv2alpha1.RolloutDowntimeConfig, trigger, specHash string, now metav1.Time) (*datadoghqv2alpha1.RolloutDowntimeStatus, error) {
+	marker := rolloutDowntimeMarker(dda)
+	id, existingEnd, err := r.options.DowntimeClient.FindActiveDowntime(marker)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		end := metav1.NewTime(existingEnd)
+		return &datadoghqv2alpha1.RolloutDowntimeStatus{
+			ID:       id,
+			Trigger:  trigger,
+			SpecHash: specHash,
+			Start:    &now,
+			End:      &end,
+		}, nil
+	}
+
+	maxDuration := defaultRolloutDowntimeMaxDuration
+	if policy.MaxDuration != nil {
+		maxDuration = policy.MaxDuration.Duration
+	}
+	scope := defaultRolloutDowntimeScope
+	if policy.Scope != nil {
+		scope = *policy.Scope
+	}
+	end := metav1.NewTime(now.Add(maxDuration))
+
+	schedule := datadogV2.NewDowntimeScheduleOneTimeCreateUpdateRequest()
+	schedule.SetEnd(end.UTC())
+	attributes := datadogV2.NewDowntimeCreateRequestAttributes(
+		datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(datadogV2.NewDowntimeMonitorIdentifierTags(policy.MonitorTags)),
+		scope,
+	)
+	attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeCreateUpdateRequestAsDowntimeScheduleCreateRequest(schedule))
+	attributes.SetMessage(fmt.Sprintf("Scheduled by the Datadog Operator during the %s of DatadogAgent %s/%s (%s).", strings.ToLower(trigger), dda.Namespace, dda.Name, marker))
+
+	id, err = r.options.DowntimeClient.CreateDowntime(*attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &datadoghqv2alpha1.RolloutDowntimeStatus{
+		ID:       id,
+		Trigger:  trigger,
+		SpecHash: specHash,
+		Start:    &now,
+		End:      &end,
+	}, nil
+}
+
+func (r *Reconciler) recordRolloutDowntimeScheduled(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, downtime *datadoghqv2alpha1.RolloutDowntimeStatus) {
+	logger.Info("Scheduled rollout downtime", "downtimeID", downtime.ID, "trigger", downtime.Trigger, "end", downtime.End)
+	r.recorder.Eventf(dda, corev1.EventTypeNormal, eventReasonRolloutDowntimeScheduled,
+		"Scheduled downtime %s for monitors tagged %s until %s", downtime.ID, strings.Join(dda.Spec.Global.RolloutDowntime.MonitorTags, ","), downtime.End.UTC().Format(time.RFC3339))
+}
+
+// cancelRolloutDowntime cancels the downtime recorded in status, unless it
+// already ended.
+func (r *Reconciler) cancelRolloutDowntime(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, downtime *datadoghqv2alpha1.RolloutDowntimeStatus, now metav1.Time) error {
+	if downtime.ID == "" || (downtime.End != nil && !now.Before(downtime.End)) {
+		return nil
+	}
+	if r.options.DowntimeClient == nil {
+		return fmt.Errorf("the operator is not configured with Datadog credentials, downtime %s cannot be canceled", downtime.ID)
+	}
+	if err := r.options.DowntimeClient.CancelDowntime(downtime.ID); err != nil {
+		logger.Error(err, "Unable to cancel the rollout downtime", "downtimeID", downtime.ID)
+		return err
+	}
+	logger.Info("Canceled rollout downtime", "downtimeID", downtime.ID)
+	r.recorder.Eventf(dda, corev1.EventTypeNormal, eventReasonRolloutDowntimeCanceled, "Canceled downtime %s", downtime.ID)
+	return nil
+}
+
+func setRolloutDowntimeActiveCondition(status *datadoghqv2alpha1.DatadogAgentStatus, downtime *datadoghqv2alpha1.RolloutDowntimeStatus, now metav1.Time) {
+	condition.UpdateDatadogAgentStatusConditions(status, now, common.RolloutDowntimeActiveConditionType, metav1.ConditionTrue, rolloutDowntimeScheduledReason,
+		fmt.Sprintf("Downtime %s mutes the selected monitors until %s", downtime.ID, downtime.End.UTC().Format(time.RFC3339)), true)
+}
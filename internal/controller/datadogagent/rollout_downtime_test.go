@@ -0,0 +1,319 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	agenttestutils "github.com/DataDog/datadog-operator/internal/controller/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+)
+
+type fakeDowntimeClient struct {
+	created   []datadogV2.DowntimeCreateRequestAttributes
+	canceled  []string
+	createErr error
+	cancelErr error
+}
+
+func (c *fakeDowntimeClient) CreateDowntime(attributes datadogV2.DowntimeCreateRequestAttributes) (string, error) {
+	if c.createErr != nil {
+		return "", c.createErr
+	}
+	c.created = append(c.created, attributes)
+	return fmt.Sprintf("downtime-%d", len(c.created)), nil
+}
+
+// FindActiveDowntime looks up the created downtimes that were not canceled.
+func (c *fakeDowntimeClient) FindActiveDowntime(marker string) (string, time.Time, error) {
+	for i, attributes := range c.created {
+		id := fmt.Sprintf("downtime-%d", i+1)
+		if strings.Contains(attributes.GetMessage(), marker) && !slices.Contains(c.canceled, id) {
+			return id, *attributes.Schedule.DowntimeScheduleOneTimeCreateUpdateRequest.End.Get(), nil
+		}
+	}
+	return "", time.Time{}, nil
+}
+
+func (c *fakeDowntimeClient) CancelDowntime(id string) error {
+	if c.cancelErr != nil {
+		return c.cancelErr
+	}
+	c.canceled = append(c.canceled, id)
+	return nil
+}
+
+func TestManageRolloutDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "agent"},
+		Spec: v2alpha1.DatadogAgentSpec{Global: &v2alpha1.GlobalConfig{
+			RolloutDowntime: &v2alpha1.RolloutDowntimeConfig{
+				MonitorTags: []string{"team:agent"},
+				MaxDuration: &metav1.Duration{Duration: 30 * time.Minute},
+			},
+		}},
+	}
+	rollingOut := func() *v2alpha1.DatadogAgentStatus {
+		return &v2alpha1.DatadogAgentStatus{Agent: &v2alpha1.DaemonSetStatus{Desired: 3, UpToDate: 1}}
+	}
+	upToDate := func() *v2alpha1.DatadogAgentStatus {
+		return &v2alpha1.DatadogAgentStatus{Agent: &v2alpha1.DaemonSetStatus{Desired: 3, UpToDate: 3}}
+	}
+	newReconciler := func(client DowntimeClient) *Reconciler {
+		return &Reconciler{
+			options:  ReconcilerOptions{DowntimeClient: client},
+			recorder: record.NewFakeRecorder(10),
+		}
+	}
+	activeCondition := func(status *v2alpha1.DatadogAgentStatus) *metav1.Condition {
+		return meta.FindStatusCondition(status.Conditions, common.RolloutDowntimeActiveConditionType)
+	}
+
+	t.Run("downtime lifecycle", func(t *testing.T) {
+		client := &fakeDowntimeClient{}
+		r := newReconciler(client)
+
+		// A rollout starts.
+		status := rollingOut()
+		r.manageRolloutDowntime(logf.Log, dda, status, "", start)
+		require.Len(t, client.created, 1)
+		attributes := client.created[0]
+		assert.Equal(t, "*", attributes.Scope)
+		assert.Equal(t, []string{"team:agent"}, attributes.MonitorIdentifier.DowntimeMonitorIdentifierTags.MonitorTags)
+		assert.Equal(t, start.Add(30*time.Minute), *attributes.Schedule.DowntimeScheduleOneTimeCreateUpdateRequest.End.Get())
+		require.NotNil(t, status.RolloutDowntime)
+		assert.Equal(t, "downtime-1", status.RolloutDowntime.ID)
+		assert.Equal(t, rolloutDowntimeTriggerRollout, status.RolloutDowntime.Trigger)
+		assert.Equal(t, metav1.ConditionTrue, activeCondition(status).Status)
+
+		// The rollout goes on: the downtime is kept.
+		next := rollingOut()
+		next.RolloutDowntime = status.RolloutDowntime.DeepCopy()
+		r.manageRolloutDowntime(logf.Log, dda, next, "", metav1.NewTime(start.Add(time.Minute)))
+		assert.Len(t, client.created, 1)
+		assert.Equal(t, "downtime-1", next.RolloutDowntime.ID)
+
+		// The rollout completes: the downtime is canceled.
+		done := upToDate()
+		done.RolloutDowntime = next.RolloutDowntime.DeepCopy()
+		r.manageRolloutDowntime(logf.Log, dda, done, "", metav1.NewTime(start.Add(2*time.Minute)))
+		assert.Equal(t, []string{"downtime-1"}, client.canceled)
+		assert.Nil(t, done.RolloutDowntime)
+		assert.Equal(t, rolloutDowntimeNoRolloutReason, activeCondition(done).Reason)
+	})
+
+	t.Run("max duration reached", func(t *testing.T) {
+		client := &fakeDowntimeClient{}
+		r := newReconciler(client)
+		status := rollingOut()
+		r.manageRolloutDowntime(logf.Log, dda, status, "", start)
+
+		expired := rollingOut()
+		expired.RolloutDowntime = status.RolloutDowntime.DeepCopy()
+		r.manageRolloutDowntime(logf.Log, dda, expired, "", metav1.NewTime(start.Add(time.Hour)))
+		require.NotNil(t, expired.RolloutDowntime)
+		assert.Empty(t, expired.RolloutDowntime.ID)
+		assert.Equal(t, rolloutDowntimeMaxDurationReason, activeCondition(expired).Reason)
+
+		// No new downtime is scheduled for the same rollout, and the ended
+		// downtime is not canceled.
+		later := rollingOut()
+		later.RolloutDowntime = expired.RolloutDowntime.DeepCopy()
+		r.manageRolloutDowntime(logf.Log, dda, later, "", metav1.NewTime(start.Add(2*time.Hour)))
+		assert.Len(t, client.created, 1)
+		done := upToDate()
+		done.RolloutDowntime = later.RolloutDowntime.DeepCopy()
+		r.manageRolloutDowntime(logf.Log, dda, done, "", metav1.NewTime(start.Add(3*time.Hour)))
+		assert.Empty(t, client.canceled)
+		assert.Nil(t, done.RolloutDowntime)
+	})
+
+	t.Run("experiment", func(t *testing.T) {
+		client := &fakeDowntimeClient{}
+		r := newReconciler(client)
+		status := upToDate()
+		status.Experiment = &v2alpha1.ExperimentStatus{Phase: v2alpha1.ExperimentPhaseRunning}
+		withScope := dda.DeepCopy()
+		withScope.Spec.Global.RolloutDowntime.Scope = ptr.To("kube_cluster_name:prod")
+		r.manageRolloutDowntime(logf.Log, withScope, status, "", start)
+		require.Len(t, client.created, 1)
+		assert.Equal(t, "kube_cluster_name:prod", client.created[0].Scope)
+		assert.Equal(t, rolloutDowntimeTriggerExperiment, status.RolloutDowntime.Trigger)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		client := &fakeDowntimeClient{createErr: errors.New("forbidden")}
+		r := newReconciler(client)
+		status := rollingOut()
+		r.manageRolloutDowntime(logf.Log, dda, status, "", start)
+		assert.Nil(t, status.RolloutDowntime)
+		cond := activeCondition(status)
+		assert.Equal(t, rolloutDowntimeErrorReason, cond.Reason)
+		assert.Equal(t, "forbidden", cond.Message)
+
+		// A failed cancellation keeps the downtime in status to retry.
+		client.cancelErr = errors.New("unavailable")
+		done := upToDate()
+		done.RolloutDowntime = &v2alpha1.RolloutDowntimeStatus{ID: "downtime-0", End: ptr.To(metav1.NewTime(start.Add(time.Hour)))}
+		r.manageRolloutDowntime(logf.Log, dda, done, "", start)
+		assert.Equal(t, "downtime-0", done.RolloutDowntime.ID)
+		assert.Equal(t, rolloutDowntimeErrorReason, activeCondition(done).Reason)
+	})
+
+	t.Run("policy removed", func(t *testing.T) {
+		client := &fakeDowntimeClient{}
+		r := newReconciler(client)
+		status := rollingOut()
+		status.RolloutDowntime = &v2alpha1.RolloutDowntimeStatus{ID: "downtime-0", End: ptr.To(metav1.NewTime(start.Add(time.Hour)))}
+		r.manageRolloutDowntime(logf.Log, &v2alpha1.DatadogAgent{Spec: v2alpha1.DatadogAgentSpec{Global: &v2alpha1.GlobalConfig{}}}, status, "", start)
+		assert.Equal(t, []string{"downtime-0"}, client.canceled)
+		assert.Nil(t, status.RolloutDowntime)
+		assert.Nil(t, activeCondition(status))
+	})
+
+	t.Run("spec update not yet reported", func(t *testing.T) {
+		client := &fakeDowntimeClient{}
+		r := newReconciler(client)
+		status := upToDate()
+		status.RolloutDowntime = &v2alpha1.RolloutDowntimeStatus{
+			ID:      "downtime-0",
+			Trigger: rolloutDowntimeTriggerRollout,
+			Start:   ptr.To(start),
+			End:     ptr.To(metav1.NewTime(start.Add(time.Hour))),
+		}
+
+		// The components still report the previous revision as up to date.
+		r.manageRolloutDowntime(logf.Log, dda, status, "", metav1.NewTime(start.Add(10*time.Second)))
+		assert.Empty(t, client.canceled)
+		assert.Equal(t, metav1.ConditionTrue, activeCondition(status).Status)
+
+		r.manageRolloutDowntime(logf.Log, dda, status, "", metav1.NewTime(start.Add(rolloutDowntimeSettleDelay)))
+		assert.Equal(t, []string{"downtime-0"}, client.canceled)
+		assert.Nil(t, status.RolloutDowntime)
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		r := newReconciler(nil)
+		status := rollingOut()
+		r.manageRolloutDowntime(logf.Log, dda, status, "", start)
+		assert.Nil(t, status.RolloutDowntime)
+		assert.Equal(t, rolloutDowntimeCredentialsMissingReason, activeCondition(status).Reason)
+	})
+}
+
+func TestRolloutTrigger(t *testing.T) {
+	assert.Empty(t, rolloutTrigger(&v2alpha1.DatadogAgentStatus{}))
+	assert.Empty(t, rolloutTrigger(&v2alpha1.DatadogAgentStatus{
+		Agent:        &v2alpha1.DaemonSetStatus{Desired: 2, UpToDate: 2},
+		ClusterAgent: &v2alpha1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2},
+		Experiment:   &v2alpha1.ExperimentStatus{Phase: v2alpha1.ExperimentPhasePromoted},
+	}))
+	assert.Equal(t, rolloutDowntimeTriggerRollout, rolloutTrigger(&v2alpha1.DatadogAgentStatus{
+		ClusterChecksRunner: &v2alpha1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1},
+	}))
+}
+
+func TestStartRolloutDowntime(t *testing.T) {
+	now := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "datadog", Name: "agent", UID: "1234"},
+		Spec: v2alpha1.DatadogAgentSpec{Global: &v2alpha1.GlobalConfig{
+			RolloutDowntime: &v2alpha1.RolloutDowntimeConfig{MonitorTags: []string{"team:agent"}},
+		}},
+	}
+	newDDAI := func(hash string) *v1alpha1.DatadogAgentInternal {
+		return &v1alpha1.DatadogAgentInternal{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "datadog",
+			Name:        "agent",
+			Annotations: map[string]string{constants.MD5DDAIDeploymentAnnotationKey: hash},
+		}}
+	}
+	newReconciler := func(downtimes DowntimeClient, existing ...*v1alpha1.DatadogAgentInternal) *Reconciler {
+		builder := fake.NewClientBuilder().WithScheme(agenttestutils.TestScheme())
+		for _, ddai := range existing {
+			builder.WithObjects(ddai)
+		}
+		return &Reconciler{
+			client:   builder.Build(),
+			options:  ReconcilerOptions{DowntimeClient: downtimes},
+			recorder: record.NewFakeRecorder(10),
+		}
+	}
+	ddais := []*v1alpha1.DatadogAgentInternal{newDDAI("new")}
+
+	t.Run("spec change", func(t *testing.T) {
+		downtimes := &fakeDowntimeClient{}
+		r := newReconciler(downtimes, newDDAI("old"))
+
+		// The components do not report the rollout yet.
+		status := &v2alpha1.DatadogAgentStatus{Agent: &v2alpha1.DaemonSetStatus{Desired: 3, UpToDate: 3}}
+		assert.True(t, r.startRolloutDowntime(context.TODO(), logf.Log, dda, status, ddais, now))
+		require.Len(t, downtimes.created, 1)
+		assert.Contains(t, downtimes.created[0].GetMessage(), "datadogagent_uid:1234")
+		require.NotNil(t, status.RolloutDowntime)
+		assert.Equal(t, "downtime-1", status.RolloutDowntime.ID)
+		assert.Equal(t, ddaiSpecHash(ddais), status.RolloutDowntime.SpecHash)
+
+		// The rollout of the spec is already covered.
+		assert.False(t, r.startRolloutDowntime(context.TODO(), logf.Log, dda, status, ddais, now))
+		assert.Len(t, downtimes.created, 1)
+
+		// The status update failed: the downtime is found again instead of being duplicated.
+		lost := &v2alpha1.DatadogAgentStatus{}
+		assert.True(t, r.startRolloutDowntime(context.TODO(), logf.Log, dda, lost, ddais, now))
+		assert.Len(t, downtimes.created, 1)
+		assert.Equal(t, "downtime-1", lost.RolloutDowntime.ID)
+		assert.Equal(t, status.RolloutDowntime.End.UTC(), lost.RolloutDowntime.End.UTC())
+	})
+
+	t.Run("new spec during an active downtime", func(t *testing.T) {
+		downtimes := &fakeDowntimeClient{}
+		r := newReconciler(downtimes, newDDAI("old"))
+		status := &v2alpha1.DatadogAgentStatus{RolloutDowntime: &v2alpha1.RolloutDowntimeStatus{
+			ID:       "downtime-0",
+			SpecHash: "previous",
+			End:      ptr.To(metav1.NewTime(now.Add(time.Hour))),
+		}}
+		assert.False(t, r.startRolloutDowntime(context.TODO(), logf.Log, dda, status, ddais, now))
+		assert.Empty(t, downtimes.created)
+		assert.Equal(t, "downtime-0", status.RolloutDowntime.ID)
+		assert.Equal(t, ddaiSpecHash(ddais), status.RolloutDowntime.SpecHash)
+	})
+
+	t.Run("no spec change", func(t *testing.T) {
+		downtimes := &fakeDowntimeClient{}
+		for _, r := range []*Reconciler{
+			newReconciler(downtimes, newDDAI("new")),
+			// The DDAI is created.
+			newReconciler(downtimes),
+		} {
+			status := &v2alpha1.DatadogAgentStatus{}
+			assert.False(t, r.startRolloutDowntime(context.TODO(), logf.Log, dda, status, ddais, now))
+			assert.Nil(t, status.RolloutDowntime)
+		}
+		assert.Empty(t, downtimes.created)
+	})
+}
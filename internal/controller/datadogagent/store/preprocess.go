@@ -22,12 +22,13 @@ import (
 type preprocessorFunc func(objStore, objAPIServer client.Object) (client.Object, error)
 
 var preprocessorRegistry = map[kubernetes.ObjectKind]preprocessorFunc{
-	kubernetes.ClusterRolesKind:          preprocessClusterRole,
-	kubernetes.RolesKind:                 preprocessRole,
-	kubernetes.ServicesKind:              preprocessService,
-	kubernetes.APIServiceKind:            preprocessResourceVersion,
-	kubernetes.CiliumNetworkPoliciesKind: preprocessResourceVersion,
-	kubernetes.PodDisruptionBudgetsKind:  preprocessResourceVersion,
+	kubernetes.ClusterRolesKind:           preprocessClusterRole,
+	kubernetes.RolesKind:                  preprocessRole,
+	kubernetes.ServicesKind:               preprocessService,
+	kubernetes.APIServiceKind:             preprocessResourceVersion,
+	kubernetes.CiliumNetworkPoliciesKind:  preprocessResourceVersion,
+	kubernetes.PodDisruptionBudgetsKind:   preprocessResourceVersion,
+	kubernetes.VerticalPodAutoscalersKind: preprocessResourceVersion,
 }
 
 // applyPreprocessing applies registered preprocessor for the given kind, if any
@@ -87,7 +88,7 @@ func preprocessService(objStore, objAPIServer client.Object) (client.Object, err
 }
 
 // preprocessResourceVersion sets the resource version from the API server object if it exists
-// Required for APIService, CiliumNetworkPolicies, and PodDisruptionBudgets
+// Required for APIService, CiliumNetworkPolicies, PodDisruptionBudgets, and VerticalPodAutoscalers
 func preprocessResourceVersion(objStore, objAPIServer client.Object) (client.Object, error) {
 	if objAPIServer != nil {
 		objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
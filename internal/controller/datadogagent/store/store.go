@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 
@@ -22,8 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/object"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/patch"
 	"github.com/DataDog/datadog-operator/pkg/equality"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 )
@@ -60,6 +63,7 @@ func NewStore(owner metav1.Object, options *StoreOptions) *Store {
 		store.logger = options.Logger
 		store.scheme = options.Scheme
 		store.isDDAControllerStore = options.IsDDAControllerStore
+		store.patches = options.Patches
 	}
 
 	return store
@@ -75,6 +79,9 @@ type Store struct {
 	platformInfo         kubernetes.PlatformInfo
 	isDDAControllerStore bool
 
+	patches       []v2alpha1.ObjectPatch
+	patchStatuses []v2alpha1.ObjectPatchStatus
+
 	scheme *runtime.Scheme
 	logger logr.Logger
 	owner  metav1.Object
@@ -92,6 +99,9 @@ type StoreOptions struct {
 	// Resources created by this store will be labeled with ManagedByDDAControllerLabelKey
 	// so they won't be cleaned up by the DDAI controller.
 	IsDDAControllerStore bool
+
+	// Patches are applied to the objects of the store when they are applied.
+	Patches []v2alpha1.ObjectPatch
 }
 
 // AddOrUpdate used to add or update an object in the Store
@@ -206,9 +216,11 @@ func (ds *Store) Delete(kind kubernetes.ObjectKind, namespace string, name strin
 
 // Apply use to create/update resources in the api-server
 func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
-	ds.mutex.RLock()
-	defer ds.mutex.RUnlock()
+	// Write lock: the result of the patches is recorded in the store.
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
 
+	ds.patchStatuses = nil
 	var errs []error
 	var objsToCreate []client.Object
 	var objsToUpdate []client.Object
@@ -224,6 +236,12 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 				continue
 			}
 
+			// Apply the user patches on a copy, so that the store keeps the generated object
+			if len(ds.patches) > 0 {
+				objStore = objStore.DeepCopyObject().(client.Object)
+				ds.patchStatuses = append(ds.patchStatuses, patch.Apply(ds.patches, objStore)...)
+			}
+
 			// Apply preprocessing for each object kind
 			objStore, err = ds.applyPreprocessing(kind, objStore, objAPIServer)
 			if err != nil {
@@ -311,6 +329,17 @@ func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client, excludeDD
 	return errs
 }
 
+// PatchStatuses returns the result of the patches on the objects of the store
+// during the last Apply.
+func (ds *Store) PatchStatuses() []v2alpha1.ObjectPatchStatus {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	statuses := slices.Clone(ds.patchStatuses)
+	patch.SortStatuses(statuses)
+	return statuses
+}
+
 // GetPlatformInfo returns api-resources info
 func (ds *Store) GetPlatformInfo() kubernetes.PlatformInfo {
 	return ds.platformInfo
@@ -391,6 +391,43 @@ func TestStore_Apply(t *testing.T) {
 	}
 }
 
+func TestStore_ApplyPatches(t *testing.T) {
+	generated := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+	}
+	ds := &Store{
+		deps: map[kubernetes.ObjectKind]map[string]client.Object{
+			kubernetes.ServicesKind: {"bar/foo": generated},
+		},
+		logger: logf.Log.WithName(t.Name()),
+		patches: []v2alpha1.ObjectPatch{
+			{
+				Target: v2alpha1.ObjectPatchTarget{Kind: "Service", Name: "foo"},
+				Type:   v2alpha1.ObjectPatchStrategicMerge,
+				Patch:  "spec: {externalTrafficPolicy: Local}",
+			},
+			{
+				Target: v2alpha1.ObjectPatchTarget{Kind: "ConfigMap"},
+				Type:   v2alpha1.ObjectPatchStrategicMerge,
+				Patch:  "data: {foo: bar}",
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().Build()
+
+	assert.Empty(t, ds.Apply(context.TODO(), k8sClient))
+
+	svc := &corev1.Service{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "foo"}, svc))
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyLocal, svc.Spec.ExternalTrafficPolicy)
+	assert.Empty(t, generated.Spec.ExternalTrafficPolicy, "the store must keep the generated object")
+	assert.Equal(t, []v2alpha1.ObjectPatchStatus{{Index: 0, Kind: "Service", Namespace: "bar", Name: "foo", Applied: true}}, ds.PatchStatuses())
+}
+
 func TestStore_Cleanup(t *testing.T) {
 	dummyConfigMap1 := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
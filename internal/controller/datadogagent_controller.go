@@ -84,6 +84,9 @@ type DatadogAgentReconciler struct {
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,resourceNames=k8s.csi.datadoghq.com,verbs=get
 
+// Resource recommender
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+
 // Configure External Metrics server
 // +kubebuilder:rbac:groups=apiregistration.k8s.io,resources=apiservices,verbs=*
 // +kubebuilder:rbac:groups=datadoghq.com,resources=watermarkpodautoscalers,verbs=get;list;watch
@@ -117,6 +120,9 @@ type DatadogAgentReconciler struct {
 // Use CiliumNetworkPolicy
 // +kubebuilder:rbac:groups=cilium.io,resources=ciliumnetworkpolicies,verbs=get;list;watch;create;update;patch;delete
 
+// Use VerticalPodAutoscaler
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+
 // Configure Appsec Gateway Integration
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=create
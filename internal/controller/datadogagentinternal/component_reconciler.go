@@ -222,6 +222,10 @@ func (r *ComponentRegistry) reconcileComponent(ctx context.Context, params *Reco
 	if componentOverride, ok := params.DDAI.Spec.Override[component.Name()]; ok {
 		override.PodTemplateSpec(objLogger, podManagers, componentOverride, component.Name(), params.DDAI.Name)
 		override.Deployment(deployment, componentOverride)
+		if err := override.ExtraContainers(podManagers, componentOverride); err != nil {
+			condition.UpdateDatadogAgentInternalStatusConditions(params.Status, now, component.GetConditionType(), metav1.ConditionFalse, override.ExtraContainersConflictReason, err.Error(), true)
+			return result, err
+		}
 	}
 
 	if errs := global.ValidateFIPSVersions(podManagers); len(errs) > 0 {
@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/defaults"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/patch"
 	"github.com/DataDog/datadog-operator/internal/controller/finalizer"
 	"github.com/DataDog/datadog-operator/pkg/condition"
 	"github.com/DataDog/datadog-operator/pkg/constants"
@@ -57,6 +58,8 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, instance *v1alpha1.D
 	logger := ctrl.LoggerFrom(ctx)
 	var result reconcile.Result
 	newStatus := instance.Status.DeepCopy()
+	// The patches are reported again by the dependencies and the workloads they are applied to.
+	newStatus.Patches = nil
 	now := metav1.NewTime(time.Now())
 
 	configuredFeatures, enabledFeatures, requiredComponents, unsupportedFeatures := feature.BuildFeatures(instance, &instance.Spec, instance.Status.RemoteConfigConfiguration, r.reconcilerOptionsToFeatureOptions(ctx))
@@ -87,7 +90,9 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, instance *v1alpha1.D
 			return r.updateStatusIfNeeded(ctx, instance, newStatus, reconcile.Result{}, err, now)
 		}
 		// 1. Apply and cleanup dependencies before reconciling components to ensure deps exist at reconciliation time.
-		if err = r.applyAndCleanupDependencies(ctx, depsStore); err != nil {
+		err = r.applyAndCleanupDependencies(ctx, depsStore)
+		newStatus.Patches = append(newStatus.Patches, depsStore.PatchStatuses()...)
+		if err != nil {
 			return r.updateStatusIfNeeded(ctx, instance, newStatus, reconcile.Result{}, err, now)
 		}
 	}
@@ -187,6 +192,7 @@ func (r *Reconciler) updateStatusIfNeeded(ctx context.Context, agentdeployment *
 	}
 
 	r.setMetricsForwarderStatus(ctx, agentdeployment, newStatus)
+	patch.SortStatuses(newStatus.Patches)
 
 	if !IsEqualStatus(&agentdeployment.Status, newStatus) {
 		updateAgentDeployment := agentdeployment.DeepCopy()
@@ -103,12 +103,19 @@ func (r *Reconciler) reconcileV2Agent(ctx context.Context, requiredComponents fe
 			}
 			override.PodTemplateSpec(objLogger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, ddai.Name)
 			override.ExtendedDaemonSet(eds, componentOverride)
+			if err := override.ExtraContainers(podManagers, componentOverride); err != nil {
+				condition.UpdateDatadogAgentInternalStatusConditions(newStatus, metav1.NewTime(time.Now()), common.AgentReconcileConditionType, metav1.ConditionFalse, override.ExtraContainersConflictReason, err.Error(), true)
+				return result, err
+			}
 		}
 
 		experimental.ApplyExperimentalOverrides(objLogger, ddai, podManagers)
 
 		if r.options.UntaintControllerEnabled {
 			componentagent.EnsureAgentNotReadyStartupToleration(objLogger, &podManagers.PodTemplateSpec().Spec)
+			if ddai.Spec.Global != nil {
+				componentagent.ApplyUntaintSocketReadinessProbes(objLogger, &podManagers.PodTemplateSpec().Spec, ddai.Spec.Global.Untaint)
+			}
 		}
 
 		if disabledByOverride {
@@ -194,12 +201,19 @@ func (r *Reconciler) reconcileV2Agent(ctx context.Context, requiredComponents fe
 		}
 		override.PodTemplateSpec(objLogger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, ddai.Name)
 		override.DaemonSet(daemonset, componentOverride)
+		if err := override.ExtraContainers(podManagers, componentOverride); err != nil {
+			condition.UpdateDatadogAgentInternalStatusConditions(newStatus, metav1.NewTime(time.Now()), common.AgentReconcileConditionType, metav1.ConditionFalse, override.ExtraContainersConflictReason, err.Error(), true)
+			return result, err
+		}
 	}
 
 	experimental.ApplyExperimentalOverrides(objLogger, ddai, podManagers)
 
 	if r.options.UntaintControllerEnabled {
 		componentagent.EnsureAgentNotReadyStartupToleration(objLogger, &podManagers.PodTemplateSpec().Spec)
+		if ddai.Spec.Global != nil {
+			componentagent.ApplyUntaintSocketReadinessProbes(objLogger, &podManagers.PodTemplateSpec().Spec, ddai.Spec.Global.Untaint)
+		}
 	}
 
 	// Windows profile (DatadogAgentProfile targeting Windows nodes): the DaemonSet was built,
@@ -24,9 +24,13 @@ import (
 
 	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
 	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	controllercommon "github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/override"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/patch"
 	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/condition"
+	"github.com/DataDog/datadog-operator/pkg/constants"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -38,6 +42,13 @@ const (
 	patchSucceeded  = "PatchSucceeded"
 )
 
+// deploymentComponents maps the component label of the Deployments to their component.
+var deploymentComponents = map[string]v2alpha1.ComponentName{
+	constants.DefaultClusterAgentResourceSuffix:        v2alpha1.ClusterAgentComponentName,
+	constants.DefaultClusterChecksRunnerResourceSuffix: v2alpha1.ClusterChecksRunnerComponentName,
+	constants.DefaultOtelAgentGatewayResourceSuffix:    v2alpha1.OtelAgentGatewayComponentName,
+}
+
 type updateDepStatusComponentFunc func(deployment *appsv1.Deployment, newStatus *v1alpha1.DatadogAgentInternalStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string)
 type updateDSStatusComponentFunc func(daemonsetName string, daemonset *appsv1.DaemonSet, newStatus *v1alpha1.DatadogAgentInternalStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string)
 type updateEDSStatusComponentFunc func(eds *edsv1alpha1.ExtendedDaemonSet, newStatus *v1alpha1.DatadogAgentInternalStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string)
@@ -53,10 +64,18 @@ func (r *Reconciler) createOrUpdateDeployment(ctx context.Context, ddai *v1alpha
 	}
 	// Finalize Kubernetes-version-dependent Pod template compatibility after all mutations and before hashing.
 	controllercommon.FinalizeAppArmorProfile(&deployment.Spec.Template, r.platformInfo)
+	// Apply the user patches last, so that they can change anything the operator generated.
+	newStatus.Patches = append(newStatus.Patches, patch.Apply(ddai.Spec.Patches, deployment)...)
 
 	// From here the PodTemplateSpec should be ready, we can generate the hash that will be used to compare this deployment with the current one (if it exists).
+	// The resources controlled by a VerticalPodAutoscaler are left out of the hash: they are only the initial resources of the pods.
 	var hash string
-	hash, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&deployment.ObjectMeta, deployment.Spec)
+	hashedSpec := &deployment.Spec
+	componentOverride := ddai.Spec.Override[deploymentComponents[deployment.GetLabels()[apicommon.AgentDeploymentComponentLabelKey]]]
+	if override.IsVerticalAutoscalingActive(componentOverride, &r.platformInfo) {
+		hashedSpec = override.WithoutVerticallyAutoscaledResources(hashedSpec, override.VerticalAutoscaling(componentOverride))
+	}
+	hash, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&deployment.ObjectMeta, *hashedSpec)
 	if err != nil {
 		return result, err
 	}
@@ -168,6 +187,8 @@ func (r *Reconciler) createOrUpdateDaemonset(ctx context.Context, ddai *v1alpha1
 	}
 	// Finalize Kubernetes-version-dependent Pod template compatibility after all mutations and before hashing.
 	controllercommon.FinalizeAppArmorProfile(&daemonset.Spec.Template, r.platformInfo)
+	// Apply the user patches last, so that they can change anything the operator generated.
+	newStatus.Patches = append(newStatus.Patches, patch.Apply(ddai.Spec.Patches, daemonset)...)
 
 	// Get the current daemonset and compare
 	nsName := types.NamespacedName{
@@ -330,6 +351,8 @@ func (r *Reconciler) createOrUpdateExtendedDaemonset(ctx context.Context, ddai *
 	}
 	// Finalize Kubernetes-version-dependent Pod template compatibility after all mutations and before hashing.
 	controllercommon.FinalizeAppArmorProfile(&eds.Spec.Template, r.platformInfo)
+	// Apply the user patches last, so that they can change anything the operator generated.
+	newStatus.Patches = append(newStatus.Patches, patch.Apply(ddai.Spec.Patches, eds)...)
 
 	// From here the PodTemplateSpec should be ready, we can generate the hash that will be used to compare this extendeddaemonset with the current one (if it exists).
 	var hash string
@@ -470,6 +493,10 @@ func IsEqualStatus(current *v1alpha1.DatadogAgentInternalStatus, newStatus *v1al
 		return false
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Patches, newStatus.Patches) {
+		return false
+	}
+
 	return condition.IsEqualConditions(current.Conditions, newStatus.Conditions)
 }
 
@@ -6,16 +6,24 @@ import (
 	"testing"
 
 	assert "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	apicommon "github.com/DataDog/datadog-operator/api/datadoghq/common"
 	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/common"
 	"github.com/DataDog/datadog-operator/pkg/condition"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 )
 
 func Test_ensureSelectorInPodTemplateLabels(t *testing.T) {
@@ -189,3 +197,84 @@ func Test_updateStatusIfNeeded_ReconcileErrorCondition(t *testing.T) {
 		})
 	}
 }
+
+func Test_createOrUpdateDaemonset_Patches(t *testing.T) {
+	r := newRolloutTestReconciler(false)
+	ddai := newRolloutTestDDAI()
+	ddai.Spec.Patches = []v2alpha1.ObjectPatch{
+		{
+			Target: v2alpha1.ObjectPatchTarget{Kind: "DaemonSet", Name: "dda-foo-agent"},
+			Type:   v2alpha1.ObjectPatchJSONPatch,
+			Patch:  `[{"op": "add", "path": "/spec/template/spec/terminationGracePeriodSeconds", "value": 60}]`,
+		},
+		{
+			Target: v2alpha1.ObjectPatchTarget{Kind: "Deployment"},
+			Type:   v2alpha1.ObjectPatchStrategicMerge,
+			Patch:  "spec: {minReadySeconds: 10}",
+		},
+	}
+	newStatus := &v1alpha1.DatadogAgentInternalStatus{}
+
+	_, err := r.createOrUpdateDaemonset(context.Background(), ddai, newRolloutTestDaemonSet(), newStatus, updateDSStatusV2WithAgent)
+	assert.NoError(t, err)
+
+	ds := &appsv1.DaemonSet{}
+	assert.NoError(t, r.client.Get(context.Background(), client.ObjectKey{Namespace: "ns-1", Name: "dda-foo-agent"}, ds))
+	assert.Equal(t, int64(60), *ds.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	assert.Equal(t, []v2alpha1.ObjectPatchStatus{{Index: 0, Kind: "DaemonSet", Namespace: "ns-1", Name: "dda-foo-agent", Applied: true}}, newStatus.Patches)
+}
+
+func Test_createOrUpdateDeployment_VerticalAutoscaling(t *testing.T) {
+	newDeployment := func(image, memory string) *appsv1.Deployment {
+		deployment := newRolloutTestClusterAgentDeployment()
+		deployment.Labels = map[string]string{apicommon.AgentDeploymentComponentLabelKey: constants.DefaultClusterAgentResourceSuffix}
+		deployment.Spec.Template.Spec.Containers[0].Image = image
+		deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)}
+		return deployment
+	}
+	ddai := newRolloutTestDDAI()
+	ddai.Spec.Override = map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+		v2alpha1.ClusterAgentComponentName: {Autoscaling: &v2alpha1.ComponentAutoscalingConfig{Vertical: &v2alpha1.VerticalAutoscalingConfig{Enabled: ptr.To(true)}}},
+	}
+
+	tests := []struct {
+		name                     string
+		platformInfo             kubernetes.PlatformInfo
+		wantUpdateOnResourceOnly bool
+	}{
+		{
+			name:                     "VerticalPodAutoscaler available: a change of the resources alone does not update the Deployment",
+			platformInfo:             kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"VerticalPodAutoscaler": "autoscaling.k8s.io/v1"}, nil),
+			wantUpdateOnResourceOnly: false,
+		},
+		{
+			name:                     "VerticalPodAutoscaler not available: a change of the resources updates the Deployment",
+			platformInfo:             kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{}, nil),
+			wantUpdateOnResourceOnly: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRolloutTestReconciler(false)
+			r.platformInfo = tt.platformInfo
+			getResourceVersion := func() string {
+				deployment := &appsv1.Deployment{}
+				assert.NoError(t, r.client.Get(context.Background(), client.ObjectKey{Namespace: "ns-1", Name: "dda-foo-cluster-agent"}, deployment))
+				return deployment.ResourceVersion
+			}
+
+			_, err := r.createOrUpdateDeployment(context.Background(), ddai, newDeployment("cluster-agent:1", "256Mi"), &v1alpha1.DatadogAgentInternalStatus{}, noopUpdateDepStatus)
+			assert.NoError(t, err)
+			firstVersion := getResourceVersion()
+
+			_, err = r.createOrUpdateDeployment(context.Background(), ddai, newDeployment("cluster-agent:1", "512Mi"), &v1alpha1.DatadogAgentInternalStatus{}, noopUpdateDepStatus)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUpdateOnResourceOnly, firstVersion != getResourceVersion())
+
+			secondVersion := getResourceVersion()
+			_, err = r.createOrUpdateDeployment(context.Background(), ddai, newDeployment("cluster-agent:2", "512Mi"), &v1alpha1.DatadogAgentInternalStatus{}, noopUpdateDepStatus)
+			assert.NoError(t, err)
+			assert.NotEqual(t, secondVersion, getResourceVersion(), "other changes update the Deployment")
+		})
+	}
+}
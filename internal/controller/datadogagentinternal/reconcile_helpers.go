@@ -37,6 +37,7 @@ func (r *Reconciler) setupDependencies(ctx context.Context, instance *datadoghqv
 		PlatformInfo:  r.platformInfo,
 		Logger:        ctrl.LoggerFrom(ctx),
 		Scheme:        r.scheme,
+		Patches:       instance.Spec.Patches,
 	}
 	depsStore := store.NewStore(instance, storeOptions)
 	resourceManagers := feature.NewResourceManagers(depsStore)
@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagentinternal"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	vpav1 "github.com/DataDog/datadog-operator/pkg/vpa/v1"
 )
 
 // DatadogAgentInternalReconciler reconciles a DatadogAgentInternal object.
@@ -91,6 +92,10 @@ func (r *DatadogAgentInternalReconciler) SetupWithManager(mgr ctrl.Manager, metr
 		builder = builder.Owns(policy)
 	}
 
+	if r.PlatformInfo.IsResourceSupported("VerticalPodAutoscaler") {
+		builder = builder.Owns(vpav1.EmptyUnstructuredVerticalPodAutoscaler())
+	}
+
 	var builderOptions []ctrlbuilder.ForOption
 	if r.Options.OperatorMetricsEnabled {
 		builderOptions = append(builderOptions, ctrlbuilder.WithPredicates(predicate.Funcs{
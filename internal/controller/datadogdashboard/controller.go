@@ -114,6 +114,15 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		return r.updateStatusIfNeeded(logger, instance, status, result)
 	}
 
+	// The widgets the Datadog API client can't parse are synced without being validated
+	widgets, _ := instance.Spec.DatadogWidgets()
+	if unparsedTypes := v1alpha1.UnparsedWidgetTypes(widgets); len(unparsedTypes) > 0 {
+		condition.UpdateStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeWarning, metav1.ConditionTrue, "UnparsedWidgets",
+			fmt.Sprintf("widgets not matching the models known to the operator are not validated: %s", strings.Join(unparsedTypes, ", ")))
+	} else {
+		condition.RemoveStatusCondition(&status.Conditions, condition.DatadogConditionTypeWarning)
+	}
+
 	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
 
 	if err != nil {
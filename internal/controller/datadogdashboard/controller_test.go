@@ -12,6 +12,7 @@ import (
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -26,6 +27,7 @@ import (
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
 )
 
 const (
@@ -141,6 +143,31 @@ func TestReconcileDatadogDashboard_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "DatadogDashboard with a widget of an unknown type",
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				firstAction: func(c client.Client) {
+					db := genericDatadogDashboard()
+					db.Spec.Widgets = `[{"definition": {"type": "future_widget"}}]`
+					_ = c.Create(context.TODO(), db)
+				},
+				firstReconcileCount: 2,
+			},
+			wantResult: reconcile.Result{RequeueAfter: defaultRequeuePeriod},
+			wantFunc: func(c client.Client) error {
+				db := &datadoghqv1alpha1.DatadogDashboard{}
+				if err := c.Get(context.TODO(), types.NamespacedName{Name: resourcesName, Namespace: resourcesNamespace}, db); err != nil {
+					return err
+				}
+				warning := meta.FindStatusCondition(db.Status.Conditions, string(condition.DatadogConditionTypeWarning))
+				if assert.NotNil(t, warning) {
+					assert.Equal(t, "UnparsedWidgets", warning.Reason)
+					assert.Contains(t, warning.Message, "future_widget")
+				}
+				return nil
+			},
+		},
 		{
 			name: "DatadogDashboard exists, needs delete",
 			args: args{
@@ -2,7 +2,6 @@ package datadogdashboard
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -18,10 +17,10 @@ import (
 // Transform v1alpha1 dashboard into a datadogV1 Dashboard
 func buildDashboard(logger logr.Logger, ddb *v1alpha1.DatadogDashboard) *datadogV1.Dashboard {
 	layoutType := ddb.Spec.LayoutType
-	widgetList := &[]datadogV1.Widget{}
-	json.Unmarshal([]byte(ddb.Spec.Widgets), widgetList)
+	// The widgets are validated by IsValidDatadogDashboard before the dashboard is built
+	widgetList, _ := ddb.Spec.DatadogWidgets()
 
-	dashboard := datadogV1.NewDashboard(layoutType, ddb.Spec.Title, *widgetList)
+	dashboard := datadogV1.NewDashboard(layoutType, ddb.Spec.Title, widgetList)
 	// isReadOnly is deprecated
 	// TODO: remove once NewDashboard in datadog-api-client-go is updated
 	dashboard.IsReadOnly = nil
@@ -39,7 +38,7 @@ func buildDashboard(logger logr.Logger, ddb *v1alpha1.DatadogDashboard) *datadog
 		dashboard.SetTemplateVariablePresets(dbTemplateVariablePresets)
 	}
 
-	dashboard.SetWidgets(*widgetList)
+	dashboard.SetWidgets(widgetList)
 
 	tags := ddb.Spec.Tags
 	sort.Strings(tags)
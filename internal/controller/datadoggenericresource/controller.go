@@ -41,6 +41,10 @@ const (
 	ddGenericResourceForceSyncPeriodEnvVar = "DD_GENERIC_RESOURCE_FORCE_SYNC_PERIOD"
 )
 
+// MonitorStateStalePeriod is the minimum period after which the reconciler gets the state of a
+// monitor itself, as the periodic bulk refresh only writes the states that changed.
+const MonitorStateStalePeriod = 5 * time.Minute
+
 type Reconciler struct {
 	client          client.Client
 	credsManager    *config.CredentialManager
@@ -165,7 +169,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, instance *v1alpha1.DatadogGe
 			} else {
 				shouldUpdate = true
 			}
-		} else if instance.Status.StateLastUpdateTime == nil || ((r.requeuePeriod - now.Sub(instance.Status.StateLastUpdateTime.Time)) <= 0) {
+		} else if instance.Status.StateLastUpdateTime == nil || ((r.stateStalePeriod(instance) - now.Sub(instance.Status.StateLastUpdateTime.Time)) <= 0) {
 			// Idle tick: refresh Datadog-side state for resource types that expose it.
 			// No-op for resource types without live state.
 			shouldRefreshStatus = true
@@ -281,6 +285,15 @@ func updateErrStatus(status *v1alpha1.DatadogGenericResourceStatus, now metav1.T
 // applyResourceState merges a refreshed Datadog-side state into the CR status,
 // bumping StateLastTransitionTime only when the state value actually changes,
 // and always advancing StateLastUpdateTime to now.
+// stateStalePeriod returns the period after which the reconciler refreshes the state of a
+// resource itself. The states of the monitors are kept up to date by the bulk refresh.
+func (r *Reconciler) stateStalePeriod(instance *v1alpha1.DatadogGenericResource) time.Duration {
+	if instance.Spec.Type == v1alpha1.Monitor {
+		return max(r.requeuePeriod, MonitorStateStalePeriod)
+	}
+	return r.requeuePeriod
+}
+
 func applyResourceState(state string, status *v1alpha1.DatadogGenericResourceStatus, now metav1.Time) {
 	oldState := status.State
 	status.State = state
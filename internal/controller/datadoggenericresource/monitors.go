@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
 )
 
 type MonitorHandler struct {
@@ -44,7 +47,8 @@ func (h *MonitorHandler) deleteResource(auth context.Context, instance *v1alpha1
 // (OK, Alert, Warn, No Data, Skipped, Ignored, Unknown). Per-group TriggeredState
 // is intentionally not surfaced in DDGR (see internal/controller/datadogmonitor/
 // controller.go::convertStateToStatus for the per-group detail available on the
-// dedicated DatadogMonitor CRD).
+// dedicated DatadogMonitor CRD). It is only called for the monitors missing from the
+// periodic bulk refresh, which applies their state with ApplyMonitorState.
 func (h *MonitorHandler) refreshState(auth context.Context, instance *v1alpha1.DatadogGenericResource) (*string, error) {
 	m, err := getMonitor(auth, h.client, instance.Status.Id)
 	if err != nil {
@@ -54,6 +58,19 @@ func (h *MonitorHandler) refreshState(auth context.Context, instance *v1alpha1.D
 	return &state, nil
 }
 
+// ApplyMonitorState updates the state of a DatadogGenericResource monitor from the monitor
+// listed by the operator, as refreshState does. It returns whether the state or the StateSynced
+// condition changed, regardless of their timestamps.
+func ApplyMonitorState(m datadogV1.Monitor, now metav1.Time, status *v1alpha1.DatadogGenericResourceStatus) bool {
+	previousState := status.State
+	previousSynced := meta.FindStatusCondition(status.Conditions, string(condition.DatadogConditionTypeStateSynced))
+	changed := previousState != string(m.GetOverallState()) || previousSynced == nil ||
+		previousSynced.Status != metav1.ConditionTrue || previousSynced.Reason != "Synced" || previousSynced.Message != "State refreshed from Datadog"
+	applyResourceState(string(m.GetOverallState()), status, now)
+	condition.UpdateStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeStateSynced, metav1.ConditionTrue, "Synced", "State refreshed from Datadog")
+	return changed
+}
+
 func getMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorStringID string) (datadogV1.Monitor, error) {
 	monitorID, err := resourceStringToInt64ID(monitorStringID)
 	if err != nil {
@@ -89,6 +106,8 @@ func createMonitor(auth context.Context, client *datadogV1.MonitorsApi, instance
 	if err := json.Unmarshal([]byte(instance.Spec.JsonSpec), monitorBody); err != nil {
 		return datadogV1.Monitor{}, translateClientError(err, "error unmarshalling monitor spec")
 	}
+	// The required tag lets the operator list the states of its monitors in bulk.
+	monitorBody.Tags = append(monitorBody.Tags, utils.GetTagsToAdd(monitorBody.Tags)...)
 	monitor, _, err := client.CreateMonitor(auth, *monitorBody)
 	if err != nil {
 		return datadogV1.Monitor{}, translateClientError(err, "error creating monitor")
@@ -101,6 +120,7 @@ func updateMonitor(auth context.Context, client *datadogV1.MonitorsApi, instance
 	if err := json.Unmarshal([]byte(instance.Spec.JsonSpec), monitorUpdateData); err != nil {
 		return datadogV1.Monitor{}, translateClientError(err, "error unmarshalling monitor spec")
 	}
+	monitorUpdateData.Tags = append(monitorUpdateData.Tags, utils.GetTagsToAdd(monitorUpdateData.Tags)...)
 	monitorID, err := resourceStringToInt64ID(instance.Status.Id)
 	if err != nil {
 		return datadogV1.Monitor{}, err
@@ -6,13 +6,20 @@
 package datadoggenericresource
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
 )
 
 func Test_applyResourceState(t *testing.T) {
@@ -92,3 +99,63 @@ func Test_applyResourceState(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyMonitorState(t *testing.T) {
+	earlier := metav1.NewTime(time.Unix(1612244495, 0))
+	now := metav1.NewTime(time.Unix(1612244795, 0))
+	m := datadogV1.Monitor{}
+	m.SetOverallState(datadogV1.MONITOROVERALLSTATES_OK)
+
+	status := &v1alpha1.DatadogGenericResourceStatus{}
+	assert.True(t, ApplyMonitorState(m, earlier, status), "the first state is a change")
+	assert.Equal(t, string(datadogV1.MONITOROVERALLSTATES_OK), status.State)
+
+	assert.False(t, ApplyMonitorState(m, now, status), "only the timestamps are updated")
+	assert.Equal(t, now, *status.StateLastUpdateTime)
+
+	condition.UpdateStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeStateSynced, metav1.ConditionFalse, "GetError", "error getting monitor")
+	assert.True(t, ApplyMonitorState(m, now, status), "the StateSynced condition is restored")
+
+	m.SetOverallState(datadogV1.MONITOROVERALLSTATES_ALERT)
+	assert.True(t, ApplyMonitorState(m, now, status))
+}
+
+func Test_monitorRequiredTag(t *testing.T) {
+	var sentTags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Tags []string `json:"tags"`
+		}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		sentTags = body.Tags
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 123, "type": "metric alert", "query": "avg(last_5m):avg:system.cpu.user{*} > 90"}`))
+	}))
+	defer server.Close()
+
+	cfg := datadogapi.NewConfiguration()
+	cfg.HTTPClient = server.Client()
+	client := datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(cfg))
+	auth := setupTestAuth(server.URL)
+
+	instance := &v1alpha1.DatadogGenericResource{
+		Spec: v1alpha1.DatadogGenericResourceSpec{
+			Type:     v1alpha1.Monitor,
+			JsonSpec: `{"name": "test", "type": "metric alert", "query": "avg(last_5m):avg:system.cpu.user{*} > 90", "message": "test", "tags": ["team:a"]}`,
+		},
+		Status: v1alpha1.DatadogGenericResourceStatus{Id: "123"},
+	}
+
+	_, err := createMonitor(auth, client, instance)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team:a", "generated:kubernetes"}, sentTags, "the required tag is added on creation")
+
+	_, err = updateMonitor(auth, client, instance)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team:a", "generated:kubernetes"}, sentTags, "the required tag is added on update")
+
+	instance.Spec.JsonSpec = `{"name": "test", "type": "metric alert", "query": "avg(last_5m):avg:system.cpu.user{*} > 90", "message": "test", "tags": ["generated:kubernetes"]}`
+	_, err = updateMonitor(auth, client, instance)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"generated:kubernetes"}, sentTags, "the required tag is not duplicated")
+}
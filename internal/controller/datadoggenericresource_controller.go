@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
 	ddgr "github.com/DataDog/datadog-operator/internal/controller/datadoggenericresource"
 	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/sharding"
 )
 
 // DatadogGenericResourceReconciler reconciles a DatadogGenericResource object
@@ -31,7 +32,9 @@ type DatadogGenericResourceReconciler struct {
 	Scheme       *runtime.Scheme
 	Recorder     record.EventRecorder
 	Options      DatadogGenericResourceReconcilerOptions
-	internal     *ddgr.Reconciler
+	// Sharder, when set, restricts the reconciliation to the DatadogGenericResources of the shards held by the replica.
+	Sharder  sharding.Sharder
+	internal *ddgr.Reconciler
 }
 
 type DatadogGenericResourceReconcilerOptions struct {
@@ -44,7 +47,9 @@ type DatadogGenericResourceReconcilerOptions struct {
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadoggenericresources/finalizers,verbs=get;list;watch;create;update;patch;delete
 
 func (r *DatadogGenericResourceReconciler) Reconcile(ctx context.Context, instance *v1alpha1.DatadogGenericResource) (ctrl.Result, error) {
-	return r.internal.Reconcile(ctx, instance)
+	return reconcileIfOwned(r.Sharder, instance, func() (ctrl.Result, error) {
+		return r.internal.Reconcile(ctx, instance)
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -57,9 +62,9 @@ func (r *DatadogGenericResourceReconciler) SetupWithManager(mgr ctrl.Manager) er
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.DatadogGenericResource{}).
 		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		WithOptions(ctrlcontroller.Options{
+		WithOptions(shardedControllerOptions(r.Sharder, ctrlcontroller.Options{
 			MaxConcurrentReconciles: r.Options.MaxConcurrentReconciles,
-		}).
+		})).
 		// WithLogConstructor replaces the default log constructor. The default one adds
 		// both a nested "DatadogGenericResource":{name, namespace} object AND flat
 		// "namespace"/"name" fields, causing duplication. This constructor emits only
@@ -48,6 +48,10 @@ const (
 	DDMonitorForceSyncPeriodEnvVar = "DD_MONITOR_FORCE_SYNC_PERIOD"
 )
 
+// MonitorStateStalePeriod is the period after which the reconciler gets the state of a monitor
+// listed by the periodic bulk refresh itself, as the refresh only writes the states that changed.
+const MonitorStateStalePeriod = 5 * time.Minute
+
 var supportedMonitorTypes = map[string]bool{
 	string(datadogV1.MONITORTYPE_METRIC_ALERT):          true,
 	string(datadogV1.MONITORTYPE_QUERY_ALERT):           true,
@@ -75,10 +79,11 @@ type Reconciler struct {
 	recorder               record.EventRecorder
 	operatorMetricsEnabled bool
 	forwarders             pkgutils.MetricsForwardersManager
+	workloads              *WorkloadNotifier
 }
 
 // NewReconciler returns a new Reconciler object
-func NewReconciler(client client.Client, credsManager *config.CredentialManager, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder, operatorMetricsEnabled bool, metricForwardersMgr pkgutils.MetricsForwardersManager) *Reconciler {
+func NewReconciler(client client.Client, credsManager *config.CredentialManager, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder, operatorMetricsEnabled bool, metricForwardersMgr pkgutils.MetricsForwardersManager, workloads *WorkloadNotifier) *Reconciler {
 	return &Reconciler{
 		client:                 client,
 		datadogClient:          datadogclient.InitMonitorClient(),
@@ -88,6 +93,7 @@ func NewReconciler(client client.Client, credsManager *config.CredentialManager,
 		recorder:               recorder,
 		operatorMetricsEnabled: operatorMetricsEnabled,
 		forwarders:             metricForwardersMgr,
+		workloads:              workloads,
 	}
 }
 
@@ -186,8 +192,8 @@ func (r *Reconciler) internalReconcile(ctx context.Context, instance *datadoghqv
 			} else {
 				shouldUpdate = true
 			}
-		} else if instance.Status.MonitorStateLastUpdateTime == nil || (defaultRequeuePeriod-now.Sub(instance.Status.MonitorStateLastUpdateTime.Time)) <= 0 {
-			// If other conditions aren't met, and we have passed the defaultRequeuePeriod, then update monitor state
+		} else if instance.Status.MonitorStateLastUpdateTime == nil || (monitorStateStalePeriod(instance)-now.Sub(instance.Status.MonitorStateLastUpdateTime.Time)) <= 0 {
+			// If other conditions aren't met, and the monitor state is stale, then update monitor state
 			// Get monitor to make sure it exists before trying any updates. If it doesn't, set shouldCreate
 			m, err = r.get(auth, instance, newStatus)
 			if err != nil {
@@ -196,7 +202,7 @@ func (r *Reconciler) internalReconcile(ctx context.Context, instance *datadoghqv
 					shouldCreate = true
 				}
 			}
-			updateMonitorState(m, now, newStatus)
+			ApplyMonitorState(m, now, newStatus)
 		}
 	}
 
@@ -323,10 +329,24 @@ func (r *Reconciler) get(auth context.Context, datadogMonitor *datadoghqv1alpha1
 	return m, nil
 }
 
-func updateMonitorState(m datadogV1.Monitor, now metav1.Time, status *datadoghqv1alpha1.DatadogMonitorStatus) {
+// ApplyMonitorState updates the state of a DatadogMonitor status from its monitor and reports
+// whether the state or the triggered groups changed, MonitorStateLastUpdateTime aside.
+func ApplyMonitorState(m datadogV1.Monitor, now metav1.Time, status *datadoghqv1alpha1.DatadogMonitorStatus) bool {
+	previous := status.DeepCopy()
 	convertStateToStatus(m, status, now)
-	status.MonitorStateLastUpdateTime = &now
 	status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusOK
+	changed := !apiequality.Semantic.DeepEqual(previous, status)
+	status.MonitorStateLastUpdateTime = &now
+	return changed
+}
+
+// monitorStateStalePeriod returns the period after which the reconciler gets the state of a
+// monitor itself. The monitors without the required tags are missing from the bulk refresh.
+func monitorStateStalePeriod(instance *datadoghqv1alpha1.DatadogMonitor) time.Duration {
+	if apiutils.BoolValue(instance.Spec.ControllerOptions.DisableRequiredTags) {
+		return defaultRequeuePeriod
+	}
+	return MonitorStateStalePeriod
 }
 
 func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, now metav1.Time, status *datadoghqv1alpha1.DatadogMonitorStatus, currentErr error, result ctrl.Result) (ctrl.Result, error) {
@@ -334,6 +354,10 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 	condition.SetErrorActiveConditions(status, now, currentErr)
 
 	if !apiequality.Semantic.DeepEqual(&datadogMonitor.Status, status) {
+		// The transitions of the monitor groups are recorded once the status is written, unless
+		// another writer already wrote the same status.
+		previousTriggeredState := datadogMonitor.Status.TriggeredState
+		notify := true
 		desiredStatus := status.DeepCopy()
 		datadogMonitor.Status = *desiredStatus.DeepCopy()
 		err := r.client.Status().Update(context.TODO(), datadogMonitor)
@@ -351,9 +375,11 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 				if apiequality.Semantic.DeepEqual(&latest.Status, desiredStatus) {
 					datadogMonitor.ResourceVersion = latest.ResourceVersion
 					datadogMonitor.Status = latest.Status
+					notify = false
 					return nil
 				}
 
+				previousTriggeredState = latest.Status.TriggeredState
 				latest.Status = *desiredStatus.DeepCopy()
 				if updateErr := r.client.Status().Update(context.TODO(), latest); updateErr != nil {
 					return updateErr
@@ -373,6 +399,9 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 
 			return ctrl.Result{}, err
 		}
+		if notify {
+			r.workloads.Notify(context.TODO(), datadogMonitor, previousTriggeredState, datadogMonitor.Status.TriggeredState)
+		}
 		// This is brittle; typically if a Spec or Status is updated in the API, the result gets requeued without additional action.
 		// However, sometimes apiequality.Semantic.DeepEqual() is false even when the API thinks they are equal (and no update is made).
 		// Thus, the result does not get requeued after entering this `if` block. To safeguard this, we will always requeue the result
@@ -28,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -153,6 +154,44 @@ func TestReconcileDatadogMonitor_RetriesCreatedStatusAfterConflict(t *testing.T)
 	assert.Equal(t, int32(1), createCount.Load(), "a status conflict must not cause a second Datadog create")
 }
 
+func TestReconcileDatadogMonitor_NotifiesWorkloadsAfterStatusWrite(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	workloadRecorder := record.NewFakeRecorder(10)
+	workloads := NewWorkloadNotifier(fake.NewClientBuilder().WithObjects(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "web-0"}},
+	).Build(), workloadRecorder, logf.Log, nil, false)
+	triggered := []datadoghqv1alpha1.DatadogMonitorTriggeredState{{
+		MonitorGroup: "kube_namespace:" + resourcesNamespace + ",pod_name:web-0",
+		State:        datadoghqv1alpha1.DatadogMonitorStateAlert,
+	}}
+
+	var failStatusWrites atomic.Bool
+	c := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(&datadoghqv1alpha1.DatadogMonitor{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if failStatusWrites.Load() {
+					return fmt.Errorf("injected status update error")
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).Build()
+	r := &Reconciler{client: c, workloads: workloads, log: logf.Log}
+	now := metav1.Now()
+
+	dm := genericDatadogMonitor(c)
+	failStatusWrites.Store(true)
+	_, err := r.updateStatusIfNeeded(r.log, dm, now, &datadoghqv1alpha1.DatadogMonitorStatus{TriggeredState: triggered}, nil, ctrl.Result{})
+	assert.Error(t, err)
+	assert.Empty(t, workloadRecorder.Events, "the transitions are not recorded when the status is not written")
+
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(dm), dm))
+	failStatusWrites.Store(false)
+	_, err = r.updateStatusIfNeeded(r.log, dm, now, &datadoghqv1alpha1.DatadogMonitorStatus{TriggeredState: triggered}, nil, ctrl.Result{})
+	assert.NoError(t, err)
+	assert.Len(t, workloadRecorder.Events, 1, "the transitions are recorded once the status is written")
+}
+
 func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 	eventBroadcaster := record.NewBroadcaster()
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestReconcileDatadogMonitor_Reconcile"})
@@ -1197,6 +1236,28 @@ func testErrorTrackingMonitor(c client.Client) *datadoghqv1alpha1.DatadogMonitor
 	return dm
 }
 
+func TestApplyMonitorState(t *testing.T) {
+	now := metav1.Unix(1612244495, 0)
+	alertState := datadogV1.MONITOROVERALLSTATES_ALERT
+	m := genericMonitor(12345)
+	m.OverallState = &alertState
+	m.State = &datadogV1.MonitorState{Groups: map[string]datadogV1.MonitorStateGroup{
+		"host:a": {Status: &alertState},
+	}}
+
+	status := &datadoghqv1alpha1.DatadogMonitorStatus{MonitorState: datadoghqv1alpha1.DatadogMonitorStateOK}
+	assert.True(t, ApplyMonitorState(m, now, status), "the state changed")
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.MonitorState)
+	assert.Equal(t, &now, status.MonitorStateLastUpdateTime)
+
+	later := metav1.Unix(now.Unix()+30, 0)
+	assert.False(t, ApplyMonitorState(m, later, status), "the state did not change")
+	assert.Equal(t, &later, status.MonitorStateLastUpdateTime)
+
+	m.State.Groups["host:b"] = datadogV1.MonitorStateGroup{Status: &alertState}
+	assert.True(t, ApplyMonitorState(m, later, status), "a group triggered")
+}
+
 // TestReconcileDatadogMonitor_RecreatesOnOutOfBandDelete reproduces CONS-8333:
 // when a monitor is deleted out-of-band (e.g. from the Datadog UI) and the CR
 // spec then changes, the controller should detect the 404 and recreate the
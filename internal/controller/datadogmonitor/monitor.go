@@ -251,6 +251,29 @@ func getMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorID i
 	return m, nil
 }
 
+// monitorListPageSize is the number of monitors listed per request, the maximum allowed by the API.
+const monitorListPageSize = 1000
+
+// ListManagedMonitors lists, in pages, the monitors tagged as created by the operator with the
+// state of all their groups, indexed by their ID.
+func ListManagedMonitors(auth context.Context, client *datadogV1.MonitorsApi) (map[int64]datadogV1.Monitor, error) {
+	params := datadogV1.NewListMonitorsOptionalParameters().
+		WithGroupStates("all").
+		WithMonitorTags(requiredTag).
+		WithPageSize(monitorListPageSize)
+	items, cancel := client.ListMonitorsWithPagination(auth, *params)
+	defer cancel()
+
+	monitors := map[int64]datadogV1.Monitor{}
+	for item := range items {
+		if item.Error != nil {
+			return nil, translateClientError(item.Error, "error listing monitors")
+		}
+		monitors[item.Item.GetId()] = item.Item
+	}
+	return monitors, nil
+}
+
 func validateMonitor(auth context.Context, logger logr.Logger, client *datadogV1.MonitorsApi, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	m, _ := buildMonitor(logger, dm)
 	if _, _, err := client.ValidateMonitor(auth, *m); err != nil {
@@ -0,0 +1,257 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+const (
+	// WebhookPath is the path on which the webhook notifications of the monitors are received.
+	WebhookPath = "/monitor-notifications"
+	// WebhookSecretHeader is the header carrying the secret shared with the Datadog webhook.
+	WebhookSecretHeader = "X-Datadog-Operator-Webhook-Secret"
+
+	webhookMaxBodySize = 1 << 20
+	// webhookMaxDateSkew is how far the $DATE of a notification can be from the time it is
+	// received. The periodic state refresh catches up with the older transitions.
+	webhookMaxDateSkew = 10 * time.Minute
+
+	eventReasonMonitorTriggered = "MonitorTriggered"
+	eventReasonMonitorRecovered = "MonitorRecovered"
+)
+
+// webhookNotification is the payload of the Datadog webhook, filled with the $ALERT_ID,
+// $ALERT_TRANSITION, $ALERT_SCOPE and $DATE variables of the webhook integration.
+type webhookNotification struct {
+	MonitorID  string `json:"monitor_id"`
+	Transition string `json:"transition"`
+	Scope      string `json:"scope"`
+	Date       string `json:"date"`
+}
+
+// WebhookReceiver updates the state of the DatadogMonitors from the notifications sent by the
+// Datadog webhook integration, as soon as their monitor transitions. The periodic state refresh
+// still reconciles the state with the Datadog API.
+type WebhookReceiver struct {
+	client    client.Client
+	recorder  record.EventRecorder
+	workloads *WorkloadNotifier
+	secret    []byte
+	log       logr.Logger
+	now       func() time.Time
+}
+
+// NewWebhookReceiver returns a new WebhookReceiver authenticating the notifications with secret.
+func NewWebhookReceiver(client client.Client, recorder record.EventRecorder, workloads *WorkloadNotifier, log logr.Logger, secret string) *WebhookReceiver {
+	return &WebhookReceiver{
+		client:    client,
+		recorder:  recorder,
+		workloads: workloads,
+		secret:    []byte(secret),
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// ServeHTTP handles a webhook notification.
+func (w *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(w.secret) == 0 || subtle.ConstantTimeCompare([]byte(req.Header.Get(WebhookSecretHeader)), w.secret) != 1 {
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	notification := webhookNotification{}
+	if err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, webhookMaxBodySize)).Decode(&notification); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid notification: %v", err), http.StatusBadRequest)
+		return
+	}
+	monitorID, err := strconv.Atoi(notification.MonitorID)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid monitor_id %q", notification.MonitorID), http.StatusBadRequest)
+		return
+	}
+	now := metav1.NewTime(w.now())
+	at, err := notificationTime(notification.Date, now)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger := w.log.WithValues("Monitor ID", monitorID, "transition", notification.Transition, "scope", notification.Scope)
+
+	state, ok := transitionState(notification.Transition)
+	if !ok {
+		logger.V(1).Info("Ignoring monitor notification")
+		rw.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	list := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err = w.client.List(req.Context(), list); err != nil {
+		logger.Error(err, "Unable to list DatadogMonitors")
+		http.Error(rw, "unable to list DatadogMonitors", http.StatusInternalServerError)
+		return
+	}
+
+	group := notificationGroup(notification.Scope)
+	for i := range list.Items {
+		instance := &list.Items[i]
+		if instance.Status.ID != monitorID {
+			continue
+		}
+		previous := instance.DeepCopy()
+		patch := client.MergeFrom(previous)
+		if !applyNotification(&instance.Status, state, group, at, now) {
+			continue
+		}
+		if err = w.client.Status().Patch(req.Context(), instance, patch); err != nil {
+			logger.Error(err, "Unable to update DatadogMonitor state", "datadogmonitor", client.ObjectKeyFromObject(instance))
+			http.Error(rw, "unable to update DatadogMonitor", http.StatusInternalServerError)
+			return
+		}
+		w.recordTransition(instance, state, group)
+		w.workloads.Notify(req.Context(), instance, previous.Status.TriggeredState, instance.Status.TriggeredState)
+		logger.V(1).Info("Updated DatadogMonitor state from notification", "datadogmonitor", client.ObjectKeyFromObject(instance))
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *WebhookReceiver) recordTransition(dm *datadoghqv1alpha1.DatadogMonitor, state datadoghqv1alpha1.DatadogMonitorState, group string) {
+	scope := "the monitor"
+	if group != "" {
+		scope = group
+	}
+	if isTriggered(string(state)) {
+		w.recorder.Eventf(dm, corev1.EventTypeWarning, eventReasonMonitorTriggered, "Monitor %d is %s for %s", dm.Status.ID, state, scope)
+		return
+	}
+	w.recorder.Eventf(dm, corev1.EventTypeNormal, eventReasonMonitorRecovered, "Monitor %d recovered for %s", dm.Status.ID, scope)
+}
+
+// transitionState returns the state of a monitor group after an $ALERT_TRANSITION.
+func transitionState(transition string) (datadoghqv1alpha1.DatadogMonitorState, bool) {
+	switch strings.TrimPrefix(transition, "Re-") {
+	case "Triggered":
+		return datadoghqv1alpha1.DatadogMonitorStateAlert, true
+	case "Warn":
+		return datadoghqv1alpha1.DatadogMonitorStateWarn, true
+	case "No Data":
+		return datadoghqv1alpha1.DatadogMonitorStateNoData, true
+	case "Recovered":
+		return datadoghqv1alpha1.DatadogMonitorStateOK, true
+	default:
+		return "", false
+	}
+}
+
+// notificationGroup returns the monitor group of an $ALERT_SCOPE, empty for a simple alert.
+func notificationGroup(scope string) string {
+	scope = strings.TrimSpace(scope)
+	if scope == "*" {
+		return ""
+	}
+	return scope
+}
+
+// notificationTime parses the $DATE of a notification, in milliseconds since the epoch, and
+// rejects the dates further than webhookMaxDateSkew from now. It defaults to now.
+func notificationTime(date string, now metav1.Time) (metav1.Time, error) {
+	if date == "" {
+		return now, nil
+	}
+	ms, err := strconv.ParseInt(date, 10, 64)
+	if err != nil || ms <= 0 {
+		return now, fmt.Errorf("invalid date %q", date)
+	}
+	at := metav1.NewTime(time.UnixMilli(ms))
+	if skew := now.Sub(at.Time).Abs(); skew > webhookMaxDateSkew {
+		return now, fmt.Errorf("date %q is %s away from now", date, skew)
+	}
+	return at, nil
+}
+
+// applyNotification applies the transition of a monitor group to the status, and returns
+// whether the state of the group changed. The transitions older than the last transition of
+// the group are ignored, as the notifications may be delivered out of order.
+func applyNotification(status *datadoghqv1alpha1.DatadogMonitorStatus, state datadoghqv1alpha1.DatadogMonitorState, group string, at, now metav1.Time) bool {
+	overallState := state
+	if group != "" {
+		index := -1
+		for i := range status.TriggeredState {
+			if status.TriggeredState[i].MonitorGroup == group {
+				index = i
+				break
+			}
+		}
+		switch {
+		case index >= 0 && (status.TriggeredState[index].State == state || at.Before(&status.TriggeredState[index].LastTransitionTime)):
+			return false
+		case isTriggered(string(state)) && index >= 0:
+			status.TriggeredState[index].State = state
+			status.TriggeredState[index].LastTransitionTime = at
+		case isTriggered(string(state)):
+			status.TriggeredState = append(status.TriggeredState, datadoghqv1alpha1.DatadogMonitorTriggeredState{
+				MonitorGroup:       group,
+				State:              state,
+				LastTransitionTime: at,
+			})
+		case index >= 0:
+			status.TriggeredState = append(status.TriggeredState[:index], status.TriggeredState[index+1:]...)
+		default:
+			return false
+		}
+		sort.SliceStable(status.TriggeredState, func(i, j int) bool {
+			return status.TriggeredState[i].MonitorGroup < status.TriggeredState[j].MonitorGroup
+		})
+		if len(status.TriggeredState) > maxTriggeredStateGroups {
+			status.TriggeredState = status.TriggeredState[0:maxTriggeredStateGroups]
+		}
+		overallState = worstState(status.TriggeredState)
+	} else if status.MonitorState == state {
+		return false
+	}
+
+	if status.MonitorState != overallState {
+		status.MonitorState = overallState
+		status.MonitorStateLastTransitionTime = &now
+	}
+	return true
+}
+
+// worstState returns the overall state of a monitor from the states of its triggered groups.
+func worstState(triggeredStates []datadoghqv1alpha1.DatadogMonitorTriggeredState) datadoghqv1alpha1.DatadogMonitorState {
+	overallState := datadoghqv1alpha1.DatadogMonitorStateOK
+	for _, triggeredState := range triggeredStates {
+		switch {
+		case triggeredState.State == datadoghqv1alpha1.DatadogMonitorStateAlert:
+			return datadoghqv1alpha1.DatadogMonitorStateAlert
+		case triggeredState.State == datadoghqv1alpha1.DatadogMonitorStateWarn:
+			overallState = datadoghqv1alpha1.DatadogMonitorStateWarn
+		case overallState == datadoghqv1alpha1.DatadogMonitorStateOK:
+			overallState = triggeredState.State
+		}
+	}
+	return overallState
+}
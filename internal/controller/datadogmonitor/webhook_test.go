@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func TestWebhookReceiver(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, datadoghqv1alpha1.AddToScheme(s))
+	dm := &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "monitor"},
+		Status: datadoghqv1alpha1.DatadogMonitorStatus{
+			ID:           12345,
+			MonitorState: datadoghqv1alpha1.DatadogMonitorStateOK,
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(dm).WithStatusSubresource(dm).Build()
+	recorder := record.NewFakeRecorder(10)
+	receiver := NewWebhookReceiver(c, recorder, nil, logf.Log, "secret")
+	receiver.now = func() time.Time { return time.Unix(1700000100, 0) }
+
+	send := func(secret, body string) int {
+		req := httptest.NewRequest(http.MethodPost, WebhookPath, strings.NewReader(body))
+		req.Header.Set(WebhookSecretHeader, secret)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	getStatus := func() datadoghqv1alpha1.DatadogMonitorStatus {
+		latest := &datadoghqv1alpha1.DatadogMonitor{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(dm), latest))
+		return latest.Status
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("", `{"monitor_id": "12345", "transition": "Triggered"}`))
+	assert.Equal(t, http.StatusUnauthorized, send("wrong", `{"monitor_id": "12345", "transition": "Triggered"}`))
+	assert.Equal(t, http.StatusBadRequest, send("secret", `{"monitor_id": "abc"}`))
+	assert.Equal(t, http.StatusBadRequest, send("secret", `{"monitor_id": "12345", "transition": "Triggered", "date": "yesterday"}`))
+	assert.Equal(t, http.StatusBadRequest, send("secret", `{"monitor_id": "12345", "transition": "Triggered", "date": "1690000000000"}`), "date far in the past")
+	assert.Equal(t, http.StatusBadRequest, send("secret", `{"monitor_id": "12345", "transition": "Triggered", "date": "1710000000000"}`), "date far in the future")
+	assert.Equal(t, http.StatusAccepted, send("secret", `{"monitor_id": "12345", "transition": "Renotify"}`))
+	assert.Empty(t, recorder.Events)
+
+	assert.Equal(t, http.StatusNoContent, send("secret", `{"monitor_id": "12345", "transition": "Triggered", "scope": "kube_namespace:default,pod_name:web-0", "date": "1700000000000"}`))
+	status := getStatus()
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.MonitorState)
+	require.Len(t, status.TriggeredState, 1)
+	assert.Equal(t, "kube_namespace:default,pod_name:web-0", status.TriggeredState[0].MonitorGroup)
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.TriggeredState[0].State)
+	assert.Equal(t, int64(1700000000), status.TriggeredState[0].LastTransitionTime.Unix())
+	assert.Equal(t, int64(1700000100), status.MonitorStateLastTransitionTime.Unix())
+	assert.Equal(t, "Warning MonitorTriggered Monitor 12345 is Alert for kube_namespace:default,pod_name:web-0", <-recorder.Events)
+
+	assert.Equal(t, http.StatusNoContent, send("secret", `{"monitor_id": "12345", "transition": "Re-Triggered", "scope": "kube_namespace:default,pod_name:web-0"}`))
+	assert.Empty(t, recorder.Events, "renotifications don't change the state")
+
+	assert.Equal(t, http.StatusNoContent, send("secret", `{"monitor_id": "12345", "transition": "Recovered", "scope": "kube_namespace:default,pod_name:web-0", "date": "1699999990000"}`))
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, getStatus().MonitorState, "the recoveries older than the trigger are ignored")
+	assert.Empty(t, recorder.Events)
+
+	assert.Equal(t, http.StatusNoContent, send("secret", `{"monitor_id": "12345", "transition": "Recovered", "scope": "kube_namespace:default,pod_name:web-0"}`))
+	status = getStatus()
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateOK, status.MonitorState)
+	assert.Empty(t, status.TriggeredState)
+	assert.Equal(t, "Normal MonitorRecovered Monitor 12345 recovered for kube_namespace:default,pod_name:web-0", <-recorder.Events)
+
+	assert.Equal(t, http.StatusNoContent, send("secret", `{"monitor_id": "67890", "transition": "Triggered"}`))
+	assert.Empty(t, recorder.Events, "notifications of other monitors are ignored")
+}
+
+func Test_applyNotification(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1700000100, 0))
+	at := metav1.NewTime(time.Unix(1700000000, 0))
+
+	status := &datadoghqv1alpha1.DatadogMonitorStatus{MonitorState: datadoghqv1alpha1.DatadogMonitorStateOK}
+	assert.True(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateWarn, "", at, now), "simple alert")
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateWarn, status.MonitorState)
+	assert.False(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateWarn, "", at, now))
+
+	status = &datadoghqv1alpha1.DatadogMonitorStatus{MonitorState: datadoghqv1alpha1.DatadogMonitorStateOK}
+	assert.True(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateWarn, "host:b", at, now))
+	assert.True(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateAlert, "host:a", at, now))
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.MonitorState, "the overall state is the worst group state")
+	assert.Equal(t, "host:a", status.TriggeredState[0].MonitorGroup, "groups are sorted")
+	assert.True(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateOK, "host:a", at, now))
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateWarn, status.MonitorState)
+	assert.False(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateOK, "host:c", at, now), "recovery of a group that wasn't triggered")
+	assert.False(t, applyNotification(status, datadoghqv1alpha1.DatadogMonitorStateOK, "host:b", metav1.NewTime(at.Add(-time.Second)), now), "recovery older than the trigger of the group")
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateWarn, status.MonitorState)
+}
@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+const namespaceTag = "kube_namespace"
+
+// workloadTags maps the monitor group tags to the kind of the Kubernetes objects they name.
+var workloadTags = map[string]schema.GroupVersionKind{
+	"kube_deployment":   {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"kube_stateful_set": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"kube_daemon_set":   {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	"kube_replica_set":  {Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	"kube_job":          {Group: "batch", Version: "v1", Kind: "Job"},
+	"kube_cronjob":      {Group: "batch", Version: "v1", Kind: "CronJob"},
+	"kube_service":      {Version: "v1", Kind: "Service"},
+	"pod_name":          {Version: "v1", Kind: "Pod"},
+}
+
+// clusterWorkloadTags maps the monitor group tags to the kind of the cluster-scoped objects they name.
+var clusterWorkloadTags = map[string]schema.GroupVersionKind{
+	"kube_node": {Version: "v1", Kind: "Node"},
+}
+
+// WorkloadNotifier records events on the Kubernetes objects named by the tags of the monitor
+// groups, when a group triggers or recovers, so that describing a workload shows the monitors
+// alerting on it.
+type WorkloadNotifier struct {
+	reader   client.Reader
+	recorder record.EventRecorder
+	log      logr.Logger
+	// namespaces restricts the objects to these namespaces in namespace-scoped mode, where the
+	// cluster-scoped objects can't be read either.
+	namespaces []string
+	// allNamespaces resolves the objects of other namespaces than the one of the DatadogMonitor,
+	// and the cluster-scoped objects.
+	allNamespaces bool
+}
+
+// NewWorkloadNotifier returns a new WorkloadNotifier. The objects are read with reader, without
+// caching them. Only the objects of the namespace of the DatadogMonitor are resolved, unless
+// allNamespaces is set. When namespaces is not empty, only the objects of these namespaces are
+// resolved.
+func NewWorkloadNotifier(reader client.Reader, recorder record.EventRecorder, log logr.Logger, namespaces []string, allNamespaces bool) *WorkloadNotifier {
+	return &WorkloadNotifier{
+		reader:        reader,
+		recorder:      recorder,
+		log:           log,
+		namespaces:    namespaces,
+		allNamespaces: allNamespaces,
+	}
+}
+
+// Notify records the transitions of the monitor groups from previous to current. A nil
+// WorkloadNotifier doesn't record anything.
+func (n *WorkloadNotifier) Notify(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor, previous, current []datadoghqv1alpha1.DatadogMonitorTriggeredState) {
+	if n == nil {
+		return
+	}
+	previousStates := make(map[string]datadoghqv1alpha1.DatadogMonitorState, len(previous))
+	for _, triggeredState := range previous {
+		previousStates[triggeredState.MonitorGroup] = triggeredState.State
+	}
+	currentStates := make(map[string]datadoghqv1alpha1.DatadogMonitorState, len(current))
+	for _, triggeredState := range current {
+		currentStates[triggeredState.MonitorGroup] = triggeredState.State
+		if previousStates[triggeredState.MonitorGroup] != triggeredState.State {
+			n.record(ctx, dm, triggeredState.MonitorGroup, corev1.EventTypeWarning, eventReasonMonitorTriggered,
+				"Datadog monitor %s (%d) is %s for %s", dm.Spec.Name, dm.Status.ID, triggeredState.State, triggeredState.MonitorGroup)
+		}
+	}
+	if len(current) >= maxTriggeredStateGroups {
+		// The groups beyond the cap of the TriggeredState are missing, but not recovered.
+		return
+	}
+	for _, triggeredState := range previous {
+		if _, found := currentStates[triggeredState.MonitorGroup]; !found {
+			n.record(ctx, dm, triggeredState.MonitorGroup, corev1.EventTypeNormal, eventReasonMonitorRecovered,
+				"Datadog monitor %s (%d) recovered for %s", dm.Spec.Name, dm.Status.ID, triggeredState.MonitorGroup)
+		}
+	}
+}
+
+func (n *WorkloadNotifier) record(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor, group, eventType, reason, messageFmt string, args ...any) {
+	for _, obj := range n.resolve(ctx, dm.Namespace, group) {
+		n.recorder.AnnotatedEventf(obj, map[string]string{"datadogmonitor": dm.Namespace + "/" + dm.Name}, eventType, reason, messageFmt, args...)
+	}
+}
+
+// resolve returns the existing Kubernetes objects named by the tags of a monitor group of a
+// DatadogMonitor of monitorNamespace.
+func (n *WorkloadNotifier) resolve(ctx context.Context, monitorNamespace, group string) []*metav1.PartialObjectMetadata {
+	tags := parseGroupTags(group)
+	var objects []*metav1.PartialObjectMetadata
+	get := func(gvk schema.GroupVersionKind, key client.ObjectKey) {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		if err := n.reader.Get(ctx, key, obj); err != nil {
+			n.log.V(1).Info("Unable to get the object of a monitor group", "kind", gvk.Kind, "object", key, "error", err)
+			return
+		}
+		obj.SetGroupVersionKind(gvk)
+		objects = append(objects, obj)
+	}
+
+	if namespace := tags[namespaceTag]; n.resolvesNamespace(monitorNamespace, namespace) {
+		for tag, gvk := range workloadTags {
+			if name := tags[tag]; name != "" {
+				get(gvk, client.ObjectKey{Namespace: namespace, Name: name})
+			}
+		}
+	}
+	if !n.allNamespaces || len(n.namespaces) > 0 {
+		return objects
+	}
+	for tag, gvk := range clusterWorkloadTags {
+		if name := tags[tag]; name != "" {
+			get(gvk, client.ObjectKey{Name: name})
+		}
+	}
+	return objects
+}
+
+// resolvesNamespace returns whether the objects of namespace are resolved for a DatadogMonitor
+// of monitorNamespace.
+func (n *WorkloadNotifier) resolvesNamespace(monitorNamespace, namespace string) bool {
+	if namespace == "" || (!n.allNamespaces && namespace != monitorNamespace) {
+		return false
+	}
+	return len(n.namespaces) == 0 || slices.Contains(n.namespaces, namespace)
+}
+
+// parseGroupTags parses the tags of a monitor group, such as
+// "kube_namespace:default,kube_deployment:web".
+func parseGroupTags(group string) map[string]string {
+	tags := map[string]string{}
+	for _, tag := range strings.Split(group, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(tag), ":")
+		if found && value != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func TestWorkloadNotifier(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-0"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	).Build()
+	recorder := record.NewFakeRecorder(20)
+	recorder.IncludeObject = true
+	notifier := NewWorkloadNotifier(c, recorder, logf.Log, nil, true)
+	dm := &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "web-errors"},
+		Spec:       datadoghqv1alpha1.DatadogMonitorSpec{Name: "Web errors"},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: 12345},
+	}
+	events := func() []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+	triggered := func(group string, state datadoghqv1alpha1.DatadogMonitorState) datadoghqv1alpha1.DatadogMonitorTriggeredState {
+		return datadoghqv1alpha1.DatadogMonitorTriggeredState{MonitorGroup: group, State: state}
+	}
+	const webGroup = "kube_deployment:web,kube_namespace:default,pod_name:web-0"
+
+	alerting := []datadoghqv1alpha1.DatadogMonitorTriggeredState{triggered(webGroup, datadoghqv1alpha1.DatadogMonitorStateAlert)}
+	notifier.Notify(context.Background(), dm, nil, alerting)
+	recorded := events()
+	require.Len(t, recorded, 2, "events are recorded on the Deployment and the Pod")
+	for _, event := range recorded {
+		assert.Contains(t, event, "Warning MonitorTriggered Datadog monitor Web errors (12345) is Alert for "+webGroup)
+		assert.Contains(t, event, "datadogmonitor:monitoring/web-errors")
+	}
+	assert.ElementsMatch(t, []string{"Deployment", "Pod"}, []string{involvedKind(recorded[0]), involvedKind(recorded[1])})
+
+	notifier.Notify(context.Background(), dm, alerting, alerting)
+	assert.Empty(t, events(), "groups that don't transition are not recorded")
+
+	notifier.Notify(context.Background(), dm, alerting, nil)
+	recorded = events()
+	require.Len(t, recorded, 2)
+	assert.Contains(t, recorded[0], "Normal MonitorRecovered Datadog monitor Web errors (12345) recovered for "+webGroup)
+
+	notifier.Notify(context.Background(), dm, nil, []datadoghqv1alpha1.DatadogMonitorTriggeredState{
+		triggered("kube_node:node-1", datadoghqv1alpha1.DatadogMonitorStateNoData),
+		triggered("kube_deployment:api,kube_namespace:default", datadoghqv1alpha1.DatadogMonitorStateWarn),
+		triggered("kube_deployment:web", datadoghqv1alpha1.DatadogMonitorStateWarn),
+	})
+	recorded = events()
+	require.Len(t, recorded, 1, "objects that don't exist or without namespace are skipped")
+	assert.Equal(t, "Node", involvedKind(recorded[0]))
+
+	capped := make([]datadoghqv1alpha1.DatadogMonitorTriggeredState, maxTriggeredStateGroups)
+	for i := range capped {
+		capped[i] = triggered(fmt.Sprintf("host:%d", i), datadoghqv1alpha1.DatadogMonitorStateAlert)
+	}
+	notifier.Notify(context.Background(), dm, append(capped, alerting...), capped)
+	assert.Empty(t, events(), "groups beyond the cap of the TriggeredState are not recovered")
+
+	var nilNotifier *WorkloadNotifier
+	nilNotifier.Notify(context.Background(), dm, nil, alerting)
+}
+
+func TestWorkloadNotifierNamespaceScoped(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "web"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	).Build()
+	notifier := NewWorkloadNotifier(c, record.NewFakeRecorder(10), logf.Log, []string{"team-a"}, true)
+
+	objects := notifier.resolve(context.Background(), "team-b", "kube_namespace:team-a,kube_deployment:web,kube_node:node-1")
+	require.Len(t, objects, 1, "cluster-scoped objects are not resolved in namespace-scoped mode")
+	assert.Equal(t, "team-a", objects[0].Namespace)
+
+	assert.Empty(t, notifier.resolve(context.Background(), "team-b", "kube_namespace:team-b,kube_deployment:web"),
+		"objects outside of the watched namespaces are not resolved")
+}
+
+func TestWorkloadNotifierMonitorNamespace(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "web"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	).Build()
+	notifier := NewWorkloadNotifier(c, record.NewFakeRecorder(10), logf.Log, nil, false)
+
+	objects := notifier.resolve(context.Background(), "team-a", "kube_namespace:team-a,kube_deployment:web,kube_node:node-1")
+	require.Len(t, objects, 1, "cluster-scoped objects are only resolved for all the namespaces")
+	assert.Equal(t, "team-a", objects[0].Namespace)
+
+	assert.Empty(t, notifier.resolve(context.Background(), "team-a", "kube_namespace:team-b,kube_deployment:web"),
+		"objects of other namespaces than the one of the DatadogMonitor are not resolved")
+}
+
+func involvedKind(event string) string {
+	for _, kind := range []string{"Deployment", "Pod", "Node"} {
+		if strings.Contains(event, "kind="+kind+",") {
+			return kind
+		}
+	}
+	return ""
+}
+
+func Test_parseGroupTags(t *testing.T) {
+	assert.Equal(t, map[string]string{"kube_namespace": "default", "kube_deployment": "web"}, parseGroupTags("kube_namespace:default, kube_deployment:web"))
+	assert.Equal(t, map[string]string{"image": "nginx:1.27"}, parseGroupTags("image:nginx:1.27"))
+	assert.Empty(t, parseGroupTags("*"))
+}
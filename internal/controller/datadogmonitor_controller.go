@@ -14,6 +14,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -22,16 +23,22 @@ import (
 	"github.com/DataDog/datadog-operator/internal/controller/datadogmonitor"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/sharding"
 )
 
 // DatadogMonitorReconciler reconciles a DatadogMonitor object.
 type DatadogMonitorReconciler struct {
-	Client                 client.Client
-	CredsManager           *config.CredentialManager
-	Log                    logr.Logger
-	Scheme                 *runtime.Scheme
-	Recorder               record.EventRecorder
+	Client       client.Client
+	CredsManager *config.CredentialManager
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
+	// Sharder, when set, restricts the reconciliation to the DatadogMonitors of the shards held by the replica.
+	Sharder sharding.Sharder
+	// WorkloadNotifier, when set, records events on the Kubernetes objects of the triggered monitor groups.
+	WorkloadNotifier       *datadogmonitor.WorkloadNotifier
 	operatorMetricsEnabled bool
+	forwarders             datadog.MetricsForwardersManager
 	internal               *datadogmonitor.Reconciler
 }
 
@@ -41,17 +48,38 @@ type DatadogMonitorReconciler struct {
 
 // Reconcile loop for DatadogMonitor.
 func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, instance *datadoghqv1alpha1.DatadogMonitor) (ctrl.Result, error) {
-	return r.internal.Reconcile(ctx, instance)
+	r.registerShardedForwarder(instance)
+	return reconcileIfOwned(r.Sharder, instance, func() (ctrl.Result, error) {
+		return r.internal.Reconcile(ctx, instance)
+	})
+}
+
+// registerShardedForwarder starts the metrics forwarder of a DatadogMonitor once its shard is
+// held by the replica, and stops it once the shard is held by another replica. The objects of
+// the other shards are requeued periodically, so the forwarders follow the shard Leases.
+// Without sharding, the forwarders are registered when the DatadogMonitors are created.
+func (r *DatadogMonitorReconciler) registerShardedForwarder(instance *datadoghqv1alpha1.DatadogMonitor) {
+	if r.Sharder == nil || r.forwarders == nil {
+		return
+	}
+	if !r.Sharder.Owns(instance) {
+		r.forwarders.Unregister(instance)
+	} else if instance.DeletionTimestamp.IsZero() {
+		r.forwarders.Register(instance)
+	}
 }
 
 // SetupWithManager creates a new DatadogMonitor controller.
 func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager, metricForwardersMgr datadog.MetricsForwardersManager) error {
-	r.internal = datadogmonitor.NewReconciler(r.Client, r.CredsManager, r.Scheme, r.Log, r.Recorder, r.operatorMetricsEnabled, metricForwardersMgr)
+	r.internal = datadogmonitor.NewReconciler(r.Client, r.CredsManager, r.Scheme, r.Log, r.Recorder, r.operatorMetricsEnabled, metricForwardersMgr, r.WorkloadNotifier)
 
 	builder := ctrl.NewControllerManagedBy(mgr)
 
 	var builderOptions []ctrlbuilder.ForOption
-	if r.operatorMetricsEnabled {
+	if r.operatorMetricsEnabled && r.Sharder != nil {
+		// The shards are not held yet when the DatadogMonitors are listed at startup.
+		r.forwarders = metricForwardersMgr
+	} else if r.operatorMetricsEnabled {
 		builderOptions = append(builderOptions, ctrlbuilder.WithPredicates(predicate.Funcs{
 			// On `DatadogMonitor` object creation, we register a metrics forwarder for it.
 			CreateFunc: func(e event.CreateEvent) bool {
@@ -61,7 +89,10 @@ func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager, metricForw
 		}))
 	}
 	or := reconcile.AsReconciler[*datadoghqv1alpha1.DatadogMonitor](r.Client, r)
-	if err := builder.For(&datadoghqv1alpha1.DatadogMonitor{}, builderOptions...).WithEventFilter(predicate.GenerationChangedPredicate{}).Complete(or); err != nil {
+	if err := builder.For(&datadoghqv1alpha1.DatadogMonitor{}, builderOptions...).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(shardedControllerOptions(r.Sharder, ctrlcontroller.Options{})).
+		Complete(or); err != nil {
 		return err
 	}
 
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+// registeredForwarders records the DatadogMonitors with a registered metrics forwarder.
+type registeredForwarders struct {
+	datadog.MetricsForwardersManager
+	names map[string]bool
+}
+
+func (f *registeredForwarders) Register(obj client.Object) {
+	f.names[obj.GetName()] = true
+}
+
+func (f *registeredForwarders) Unregister(obj client.Object) {
+	delete(f.names, obj.GetName())
+}
+
+func TestDatadogMonitorReconciler_registerShardedForwarder(t *testing.T) {
+	forwarders := &registeredForwarders{names: map[string]bool{}}
+	sharder := ownedNames{}
+	r := &DatadogMonitorReconciler{Sharder: sharder, forwarders: forwarders}
+	dm := &v1alpha1.DatadogMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "monitor"}}
+
+	r.registerShardedForwarder(dm)
+	assert.Empty(t, forwarders.names, "forwarders are not registered before the shard is acquired")
+
+	sharder["monitor"] = true
+	r.registerShardedForwarder(dm)
+	assert.True(t, forwarders.names["monitor"], "forwarders are registered once the shard is acquired")
+
+	delete(sharder, "monitor")
+	r.registerShardedForwarder(dm)
+	assert.Empty(t, forwarders.names, "forwarders are unregistered once the shard is released")
+
+	sharder["monitor"] = true
+	dm.DeletionTimestamp = ptr.To(metav1.Now())
+	r.registerShardedForwarder(dm)
+	assert.Empty(t, forwarders.names, "forwarders are not registered for deleted DatadogMonitors")
+}
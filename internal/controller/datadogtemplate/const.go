@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+const (
+	// TemplateUIDLabelKey is set on the generated resources to the UID of their
+	// template, so that they can be listed when the template is reconciled.
+	TemplateUIDLabelKey = "datadoghq.com/template-uid"
+
+	// leftDelimiter and rightDelimiter delimit the actions of the template fields.
+	// They differ from the Go template defaults, which Datadog messages already use.
+	leftDelimiter  = "[["
+	rightDelimiter = "]]"
+
+	// maxNameLength is the maximum length of a generated resource name.
+	maxNameLength = 253
+)
+
+// Reasons of the Ready condition and of the events recorded on the templates.
+const (
+	ReasonInstantiated        = "Instantiated"
+	ReasonInstantiationFailed = "InstantiationFailed"
+	ReasonInvalidTarget       = "InvalidTarget"
+)
@@ -0,0 +1,322 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+// Reconciler instantiates the templates of a Kind: it generates a resource for
+// each namespace or workload a template selects, updates the resources when
+// the template or the targets change, and deletes the resources whose target
+// is no longer selected. The generated resources are owned by their template,
+// so they are garbage-collected with it.
+//
+// Namespaces and workloads are read from the manager cache, workloads through
+// metadata-only informers. The generated resources are read through the API
+// reader, so that a resource created by a previous reconcile is not created
+// again before the cache observes it.
+type Reconciler struct {
+	client     client.Client
+	reader     client.Reader
+	log        logr.Logger
+	recorder   record.EventRecorder
+	kind       Kind
+	namespaces map[string]cache.Config
+}
+
+// NewReconciler returns a Reconciler for the templates of a Kind. Resources are
+// only generated in the namespaces watched by the controller of the generated kind.
+func NewReconciler(c client.Client, reader client.Reader, log logr.Logger, recorder record.EventRecorder, kind Kind, namespaces map[string]cache.Config) *Reconciler {
+	return &Reconciler{
+		client:     c,
+		reader:     reader,
+		log:        log,
+		recorder:   recorder,
+		kind:       kind,
+		namespaces: namespaces,
+	}
+}
+
+// Reconcile instantiates a template. The template is reconciled again when the
+// namespaces and workloads it selects change, see NamespaceRequests and WorkloadRequests.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues(strings.ToLower(r.kind.TemplateKind), req.Name)
+
+	tmpl := r.kind.newTemplate()
+	if err := r.client.Get(ctx, req.NamespacedName, tmpl); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The generated resources are garbage-collected.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !tmpl.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	newStatus := r.kind.status(tmpl).DeepCopy()
+	newStatus.ObservedGeneration = tmpl.GetGeneration()
+
+	targets, err := r.listTargets(ctx, r.kind.target(tmpl))
+	if err != nil {
+		if !isInvalidTarget(err) {
+			return ctrl.Result{}, err
+		}
+		log.V(1).Info("Template cannot be instantiated", "error", err.Error())
+		// The template is reconciled again when its spec changes.
+		setReadyCondition(newStatus, metav1.ConditionFalse, ReasonInvalidTarget, err.Error(), tmpl.GetGeneration())
+		return ctrl.Result{}, r.updateStatusIfNeeded(ctx, tmpl, newStatus)
+	}
+
+	keep := map[types.NamespacedName]struct{}{}
+	var failures []string
+	for _, target := range targets {
+		key := types.NamespacedName{Namespace: target.Namespace.Name, Name: childName(tmpl.GetName(), target)}
+		msg, applyErr := r.applyChild(ctx, tmpl, key, target)
+		if applyErr != nil {
+			return ctrl.Result{}, applyErr
+		}
+		if msg != "" {
+			failures = append(failures, msg)
+		}
+		// A resource generated before a rendering failure is kept until the template is fixed.
+		keep[key] = struct{}{}
+	}
+	instances, err := r.prune(ctx, tmpl, keep)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	newStatus.Targets = int32(len(targets))
+	newStatus.Instances = int32(instances)
+	if len(failures) > 0 {
+		slices.Sort(failures)
+		setReadyCondition(newStatus, metav1.ConditionFalse, ReasonInstantiationFailed, strings.Join(failures, "; "), tmpl.GetGeneration())
+	} else {
+		setReadyCondition(newStatus, metav1.ConditionTrue, ReasonInstantiated,
+			fmt.Sprintf("Generated %d %s(s) for %d target(s)", instances, r.kind.ChildKind, len(targets)), tmpl.GetGeneration())
+	}
+	return ctrl.Result{}, r.updateStatusIfNeeded(ctx, tmpl, newStatus)
+}
+
+type invalidTargetError struct {
+	error
+}
+
+func isInvalidTarget(err error) bool {
+	return errors.As(err, &invalidTargetError{})
+}
+
+// listTargets lists the namespaces, or the workloads of the namespaces, selected
+// by a template. Terminating and unwatched namespaces are skipped.
+func (r *Reconciler) listTargets(ctx context.Context, target *v1alpha1.DatadogTemplateTarget) ([]TemplateData, error) {
+	nsSelector, err := metav1.LabelSelectorAsSelector(&target.NamespaceSelector)
+	if err != nil {
+		return nil, invalidTargetError{fmt.Errorf("invalid namespace selector: %w", err)}
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err = r.client.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var workloadSelector labels.Selector
+	if target.Workloads != nil {
+		workloadSelector = labels.Everything()
+		if target.Workloads.Selector != nil {
+			if workloadSelector, err = metav1.LabelSelectorAsSelector(target.Workloads.Selector); err != nil {
+				return nil, invalidTargetError{fmt.Errorf("invalid workload selector: %w", err)}
+			}
+		}
+	}
+
+	var targets []TemplateData
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if ns.Status.Phase == corev1.NamespaceTerminating || !r.watches(ns.Name) {
+			continue
+		}
+		nsData := objectData("Namespace", ns)
+		if target.Workloads == nil {
+			targets = append(targets, TemplateData{Namespace: nsData})
+			continue
+		}
+		workloads, listErr := r.listWorkloads(ctx, target.Workloads.Kind, ns.Name, workloadSelector)
+		if listErr != nil {
+			return nil, listErr
+		}
+		for _, workload := range workloads {
+			workloadData := objectData(string(target.Workloads.Kind), workload)
+			targets = append(targets, TemplateData{Namespace: nsData, Workload: &workloadData})
+		}
+	}
+	return targets, nil
+}
+
+func (r *Reconciler) watches(namespace string) bool {
+	if len(r.namespaces) == 0 {
+		return true
+	}
+	if _, all := r.namespaces[cache.AllNamespaces]; all {
+		return true
+	}
+	_, watched := r.namespaces[namespace]
+	return watched
+}
+
+// listWorkloads lists the metadata of the workloads of a kind in a namespace.
+func (r *Reconciler) listWorkloads(ctx context.Context, kind v1alpha1.DatadogTemplateWorkloadKind, namespace string, selector labels.Selector) ([]client.Object, error) {
+	gvk, ok := workloadGVKs[kind]
+	if !ok {
+		return nil, invalidTargetError{fmt.Errorf("unsupported workload kind %q", kind)}
+	}
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list %s workloads: %w", kind, err)
+	}
+	workloads := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		workloads = append(workloads, &list.Items[i])
+	}
+	return workloads, nil
+}
+
+// applyChild creates or updates the resource generated for a target. A message
+// is returned instead of an error when the template cannot be rendered for the
+// target, or when a resource not generated by the template already has the name.
+func (r *Reconciler) applyChild(ctx context.Context, tmpl client.Object, key types.NamespacedName, target TemplateData) (string, error) {
+	desired := r.kind.newChild()
+	if err := renderSpec(r.kind.specTemplate(tmpl), target, r.kind.childSpec(desired)); err != nil {
+		return fmt.Sprintf("%s %s: %s", strings.ToLower(r.kind.ChildKind), key, err), nil
+	}
+	if err := r.kind.validate(desired); err != nil {
+		return fmt.Sprintf("%s %s: %s", strings.ToLower(r.kind.ChildKind), key, err), nil
+	}
+
+	current := r.kind.newChild()
+	err := r.reader.Get(ctx, key, current)
+	if apierrors.IsNotFound(err) {
+		desired.SetNamespace(key.Namespace)
+		desired.SetName(key.Name)
+		desired.SetLabels(map[string]string{TemplateUIDLabelKey: string(tmpl.GetUID())})
+		desired.SetOwnerReferences([]metav1.OwnerReference{r.ownerReference(tmpl)})
+		if err = r.client.Create(ctx, desired); err != nil {
+			return "", fmt.Errorf("failed to create %s %s: %w", strings.ToLower(r.kind.ChildKind), key, err)
+		}
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", strings.ToLower(r.kind.ChildKind), key, err)
+	}
+	if !metav1.IsControlledBy(current, tmpl) {
+		return fmt.Sprintf("%s %s already exists and is not generated by this template", strings.ToLower(r.kind.ChildKind), key), nil
+	}
+
+	if equality.Semantic.DeepEqual(r.kind.childSpec(current), r.kind.childSpec(desired)) &&
+		current.GetLabels()[TemplateUIDLabelKey] == string(tmpl.GetUID()) {
+		return "", nil
+	}
+	updated := current.DeepCopyObject().(client.Object)
+	reflect.ValueOf(r.kind.childSpec(updated)).Elem().Set(reflect.ValueOf(r.kind.childSpec(desired)).Elem())
+	objLabels := updated.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[TemplateUIDLabelKey] = string(tmpl.GetUID())
+	updated.SetLabels(objLabels)
+	if err = r.client.Update(ctx, updated); err != nil {
+		return "", fmt.Errorf("failed to update %s %s: %w", strings.ToLower(r.kind.ChildKind), key, err)
+	}
+	return "", nil
+}
+
+func (r *Reconciler) ownerReference(tmpl client.Object) metav1.OwnerReference {
+	return *metav1.NewControllerRef(tmpl, v1alpha1.GroupVersion.WithKind(r.kind.TemplateKind))
+}
+
+// prune deletes the resources generated by a template that are not in keep,
+// and returns the number of resources left.
+func (r *Reconciler) prune(ctx context.Context, tmpl client.Object, keep map[types.NamespacedName]struct{}) (int, error) {
+	list := r.kind.newChildList()
+	if err := r.reader.List(ctx, list, client.MatchingLabels{TemplateUIDLabelKey: string(tmpl.GetUID())}); err != nil {
+		return 0, fmt.Errorf("failed to list %s resources: %w", r.kind.ChildKind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return 0, err
+	}
+	instances := 0
+	for _, item := range items {
+		child := item.(client.Object)
+		if !metav1.IsControlledBy(child, tmpl) {
+			continue
+		}
+		if _, ok := keep[client.ObjectKeyFromObject(child)]; ok {
+			instances++
+			continue
+		}
+		if err = r.client.Delete(ctx, child); err != nil && !apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete %s %s: %w", strings.ToLower(r.kind.ChildKind), client.ObjectKeyFromObject(child), err)
+		}
+	}
+	return instances, nil
+}
+
+func setReadyCondition(status *v1alpha1.DatadogTemplateStatus, conditionStatus metav1.ConditionStatus, reason, message string, generation int64) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1alpha1.DatadogTemplateConditionTypeReady,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// updateStatusIfNeeded writes the status of a template and records an event
+// when its Ready condition changes.
+func (r *Reconciler) updateStatusIfNeeded(ctx context.Context, tmpl client.Object, newStatus *v1alpha1.DatadogTemplateStatus) error {
+	status := r.kind.status(tmpl)
+	if equality.Semantic.DeepEqual(status, newStatus) {
+		return nil
+	}
+	oldReady := meta.FindStatusCondition(status.Conditions, v1alpha1.DatadogTemplateConditionTypeReady)
+	newReady := meta.FindStatusCondition(newStatus.Conditions, v1alpha1.DatadogTemplateConditionTypeReady)
+
+	*status = *newStatus
+	if err := r.client.Status().Update(ctx, tmpl); err != nil {
+		return fmt.Errorf("failed to update %s status: %w", r.kind.TemplateKind, err)
+	}
+
+	if newReady != nil && (oldReady == nil || oldReady.Reason != newReady.Reason || oldReady.Message != newReady.Message) {
+		eventType := corev1.EventTypeNormal
+		if newReady.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recorder.Event(tmpl, eventType, newReady.Reason, newReady.Message)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func testScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = appsv1.AddToScheme(s)
+	_ = v1alpha1.AddToScheme(s)
+	return s
+}
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newDeployment(namespace, name string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+func newMonitorTemplate(target v1alpha1.DatadogTemplateTarget) *v1alpha1.DatadogMonitorTemplate {
+	return &v1alpha1.DatadogMonitorTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "restarts", UID: "template-uid", Generation: 1},
+		Spec: v1alpha1.DatadogMonitorTemplateSpec{
+			Target: target,
+			Template: v1alpha1.DatadogMonitorSpec{
+				Name:    "Restarts in [[ .Namespace.Name ]]",
+				Message: "{{#is_alert}}Too many restarts{{/is_alert}}",
+				Query:   "change(sum(last_5m),last_5m):sum:kubernetes.containers.restarts{kube_namespace:[[ .Namespace.Name ]]} > 5",
+				Type:    v1alpha1.DatadogMonitorTypeQuery,
+			},
+		},
+	}
+}
+
+func newTestReconciler(kind Kind, namespaces map[string]cache.Config, objs ...client.Object) (*Reconciler, client.Client) {
+	c := fake.NewClientBuilder().
+		WithScheme(testScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.DatadogMonitorTemplate{}, &v1alpha1.DatadogSLOTemplate{}).
+		Build()
+	return NewReconciler(c, c, log.Log.WithName("test"), record.NewFakeRecorder(16), kind, namespaces), c
+}
+
+func reconcileTemplate(t *testing.T, r *Reconciler, name string) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	require.NoError(t, err)
+	return result
+}
+
+func listMonitors(t *testing.T, c client.Client) map[string]v1alpha1.DatadogMonitor {
+	t.Helper()
+	list := &v1alpha1.DatadogMonitorList{}
+	require.NoError(t, c.List(context.Background(), list))
+	monitors := map[string]v1alpha1.DatadogMonitor{}
+	for _, m := range list.Items {
+		monitors[m.Namespace+"/"+m.Name] = m
+	}
+	return monitors
+}
+
+func getMonitorTemplate(t *testing.T, c client.Client, name string) *v1alpha1.DatadogMonitorTemplate {
+	t.Helper()
+	tmpl := &v1alpha1.DatadogMonitorTemplate{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name}, tmpl))
+	return tmpl
+}
+
+func TestReconcileNamespaces(t *testing.T) {
+	tmpl := newMonitorTemplate(v1alpha1.DatadogTemplateTarget{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"monitored": "true"}},
+	})
+	terminating := newNamespace("leaving", map[string]string{"monitored": "true"})
+	terminating.Status.Phase = corev1.NamespaceTerminating
+	r, c := newTestReconciler(MonitorKind, nil,
+		tmpl,
+		newNamespace("payments", map[string]string{"monitored": "true"}),
+		newNamespace("checkout", map[string]string{"monitored": "true"}),
+		newNamespace("kube-system", nil),
+		terminating,
+	)
+
+	result := reconcileTemplate(t, r, "restarts")
+	assert.Zero(t, result.RequeueAfter, "the template is reconciled again when its targets change")
+
+	monitors := listMonitors(t, c)
+	require.Len(t, monitors, 2)
+	payments := monitors["payments/restarts"]
+	assert.Equal(t, "Restarts in payments", payments.Spec.Name)
+	assert.Equal(t, "change(sum(last_5m),last_5m):sum:kubernetes.containers.restarts{kube_namespace:payments} > 5", payments.Spec.Query)
+	assert.Equal(t, "{{#is_alert}}Too many restarts{{/is_alert}}", payments.Spec.Message)
+	assert.Equal(t, "template-uid", payments.Labels[TemplateUIDLabelKey])
+	assert.True(t, metav1.IsControlledBy(&payments, tmpl))
+	assert.Contains(t, monitors, "checkout/restarts")
+
+	status := getMonitorTemplate(t, c, "restarts").Status
+	assert.Equal(t, int32(2), status.Targets)
+	assert.Equal(t, int32(2), status.Instances)
+	assert.Equal(t, int64(1), status.ObservedGeneration)
+	ready := meta.FindStatusCondition(status.Conditions, v1alpha1.DatadogTemplateConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, ReasonInstantiated, ready.Reason)
+
+	// A namespace is no longer selected: its monitor is deleted.
+	ns := &corev1.Namespace{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "checkout"}, ns))
+	ns.Labels = nil
+	require.NoError(t, c.Update(context.Background(), ns))
+	// The template is updated: the remaining monitor follows.
+	updated := getMonitorTemplate(t, c, "restarts")
+	updated.Spec.Template.Message = "{{#is_alert}}Check the pods{{/is_alert}}"
+	require.NoError(t, c.Update(context.Background(), updated))
+
+	reconcileTemplate(t, r, "restarts")
+	monitors = listMonitors(t, c)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, "{{#is_alert}}Check the pods{{/is_alert}}", monitors["payments/restarts"].Spec.Message)
+	assert.Equal(t, int32(1), getMonitorTemplate(t, c, "restarts").Status.Instances)
+}
+
+func TestReconcileWorkloads(t *testing.T) {
+	tmpl := newMonitorTemplate(v1alpha1.DatadogTemplateTarget{
+		Workloads: &v1alpha1.DatadogTemplateWorkloadSelector{
+			Kind:     v1alpha1.DatadogTemplateWorkloadKindDeployment,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+		},
+	})
+	tmpl.Spec.Template.Name = "Restarts of [[ .Workload.Name ]]"
+	tmpl.Spec.Template.Tags = []string{"team:[[ .Workload.Labels.team ]]"}
+	r, c := newTestReconciler(MonitorKind, map[string]cache.Config{"payments": {}},
+		tmpl,
+		newNamespace("payments", nil),
+		newNamespace("unwatched", nil),
+		newDeployment("payments", "api", map[string]string{"tier": "web", "team": "pay"}),
+		newDeployment("payments", "worker", map[string]string{"tier": "batch", "team": "pay"}),
+		newDeployment("unwatched", "api", map[string]string{"tier": "web", "team": "other"}),
+	)
+
+	reconcileTemplate(t, r, "restarts")
+	monitors := listMonitors(t, c)
+	require.Len(t, monitors, 1)
+	api := monitors["payments/restarts-api"]
+	assert.Equal(t, "Restarts of api", api.Spec.Name)
+	assert.Equal(t, []string{"team:pay"}, api.Spec.Tags)
+}
+
+func TestReconcileFailures(t *testing.T) {
+	tmpl := newMonitorTemplate(v1alpha1.DatadogTemplateTarget{})
+	tmpl.Spec.Template.Tags = []string{"team:[[ .Namespace.Labels.team ]]"}
+	unowned := &v1alpha1.DatadogMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: "checkout", Name: "restarts"}}
+	r, c := newTestReconciler(MonitorKind, nil,
+		tmpl,
+		newNamespace("payments", map[string]string{"team": "pay"}),
+		newNamespace("checkout", map[string]string{"team": "shop"}),
+		newNamespace("sandbox", nil),
+		unowned,
+	)
+
+	reconcileTemplate(t, r, "restarts")
+	monitors := listMonitors(t, c)
+	require.Len(t, monitors, 2)
+	assert.Equal(t, []string{"team:pay"}, monitors["payments/restarts"].Spec.Tags)
+	// The existing monitor is not taken over.
+	assert.Empty(t, monitors["checkout/restarts"].Spec.Tags)
+	assert.NotContains(t, monitors, "sandbox/restarts")
+
+	status := getMonitorTemplate(t, c, "restarts").Status
+	assert.Equal(t, int32(3), status.Targets)
+	assert.Equal(t, int32(1), status.Instances)
+	ready := meta.FindStatusCondition(status.Conditions, v1alpha1.DatadogTemplateConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, ReasonInstantiationFailed, ready.Reason)
+	assert.Contains(t, ready.Message, "datadogmonitor checkout/restarts already exists and is not generated by this template")
+	assert.Contains(t, ready.Message, "datadogmonitor sandbox/restarts: failed to render tags[0]")
+
+	// A monitor is kept when the template can no longer be rendered for its target.
+	ns := &corev1.Namespace{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "payments"}, ns))
+	ns.Labels = nil
+	require.NoError(t, c.Update(context.Background(), ns))
+	reconcileTemplate(t, r, "restarts")
+	assert.Contains(t, listMonitors(t, c), "payments/restarts")
+}
+
+func TestReconcileInvalidTarget(t *testing.T) {
+	tmpl := newMonitorTemplate(v1alpha1.DatadogTemplateTarget{
+		NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}}},
+	})
+	r, c := newTestReconciler(MonitorKind, nil, tmpl, newNamespace("payments", nil))
+
+	result := reconcileTemplate(t, r, "restarts")
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, listMonitors(t, c))
+	ready := meta.FindStatusCondition(getMonitorTemplate(t, c, "restarts").Status.Conditions, v1alpha1.DatadogTemplateConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, ReasonInvalidTarget, ready.Reason)
+}
+
+func TestReconcileSLOTemplate(t *testing.T) {
+	tmpl := &v1alpha1.DatadogSLOTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "availability", UID: "slo-template-uid"},
+		Spec: v1alpha1.DatadogSLOTemplateSpec{
+			Target: v1alpha1.DatadogTemplateTarget{},
+			Template: v1alpha1.DatadogSLOSpec{
+				Name:            "[[ .Namespace.Name ]] availability",
+				Type:            v1alpha1.DatadogSLOTypeMetric,
+				TargetThreshold: resource.MustParse("99.9"),
+				Timeframe:       v1alpha1.DatadogSLOTimeFrame30d,
+				Query: &v1alpha1.DatadogSLOQuery{
+					Numerator:   "sum:requests.ok{kube_namespace:[[ .Namespace.Name ]]}.as_count()",
+					Denominator: "sum:requests.total{kube_namespace:[[ .Namespace.Name ]]}.as_count()",
+				},
+			},
+		},
+	}
+	r, c := newTestReconciler(SLOKind, nil, tmpl, newNamespace("payments", nil))
+
+	reconcileTemplate(t, r, "availability")
+	slo := &v1alpha1.DatadogSLO{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "payments", Name: "availability"}, slo))
+	assert.Equal(t, "payments availability", slo.Spec.Name)
+	assert.Equal(t, "sum:requests.ok{kube_namespace:payments}.as_count()", slo.Spec.Query.Numerator)
+
+	// The template is deleted: nothing is left to reconcile.
+	require.NoError(t, c.Delete(context.Background(), tmpl))
+	reconcileTemplate(t, r, "availability")
+	err := c.Get(context.Background(), types.NamespacedName{Name: "availability"}, &v1alpha1.DatadogSLOTemplate{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
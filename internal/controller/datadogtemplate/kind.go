@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+// Kind adapts the reconciliation to a template kind and the kind of the
+// resources it generates.
+type Kind struct {
+	// TemplateKind is the kind of the templates.
+	TemplateKind string
+	// ChildKind is the kind of the generated resources.
+	ChildKind string
+
+	newTemplate     func() client.Object
+	newTemplateList func() client.ObjectList
+	newChild        func() client.Object
+	newChildList    func() client.ObjectList
+	// target returns the target of a template.
+	target func(client.Object) *v1alpha1.DatadogTemplateTarget
+	// status returns the status of a template.
+	status func(client.Object) *v1alpha1.DatadogTemplateStatus
+	// specTemplate returns the spec template of a template.
+	specTemplate func(client.Object) any
+	// childSpec returns a pointer to the spec of a generated resource.
+	childSpec func(client.Object) any
+	// validate validates the spec of a generated resource.
+	validate func(client.Object) error
+}
+
+// MonitorKind generates DatadogMonitors from DatadogMonitorTemplates.
+var MonitorKind = Kind{
+	TemplateKind:    "DatadogMonitorTemplate",
+	ChildKind:       "DatadogMonitor",
+	newTemplate:     func() client.Object { return &v1alpha1.DatadogMonitorTemplate{} },
+	newTemplateList: func() client.ObjectList { return &v1alpha1.DatadogMonitorTemplateList{} },
+	newChild:        func() client.Object { return &v1alpha1.DatadogMonitor{} },
+	newChildList:    func() client.ObjectList { return &v1alpha1.DatadogMonitorList{} },
+	target: func(obj client.Object) *v1alpha1.DatadogTemplateTarget {
+		return &obj.(*v1alpha1.DatadogMonitorTemplate).Spec.Target
+	},
+	status: func(obj client.Object) *v1alpha1.DatadogTemplateStatus {
+		return &obj.(*v1alpha1.DatadogMonitorTemplate).Status
+	},
+	specTemplate: func(obj client.Object) any { return &obj.(*v1alpha1.DatadogMonitorTemplate).Spec.Template },
+	childSpec:    func(obj client.Object) any { return &obj.(*v1alpha1.DatadogMonitor).Spec },
+	validate: func(obj client.Object) error {
+		return v1alpha1.IsValidDatadogMonitor(&obj.(*v1alpha1.DatadogMonitor).Spec)
+	},
+}
+
+// SLOKind generates DatadogSLOs from DatadogSLOTemplates.
+var SLOKind = Kind{
+	TemplateKind:    "DatadogSLOTemplate",
+	ChildKind:       "DatadogSLO",
+	newTemplate:     func() client.Object { return &v1alpha1.DatadogSLOTemplate{} },
+	newTemplateList: func() client.ObjectList { return &v1alpha1.DatadogSLOTemplateList{} },
+	newChild:        func() client.Object { return &v1alpha1.DatadogSLO{} },
+	newChildList:    func() client.ObjectList { return &v1alpha1.DatadogSLOList{} },
+	target: func(obj client.Object) *v1alpha1.DatadogTemplateTarget {
+		return &obj.(*v1alpha1.DatadogSLOTemplate).Spec.Target
+	},
+	status: func(obj client.Object) *v1alpha1.DatadogTemplateStatus {
+		return &obj.(*v1alpha1.DatadogSLOTemplate).Status
+	},
+	specTemplate: func(obj client.Object) any { return &obj.(*v1alpha1.DatadogSLOTemplate).Spec.Template },
+	childSpec:    func(obj client.Object) any { return &obj.(*v1alpha1.DatadogSLO).Spec },
+	validate: func(obj client.Object) error {
+		return v1alpha1.IsValidDatadogSLO(&obj.(*v1alpha1.DatadogSLO).Spec)
+	},
+}
+
+// NewTemplate returns an empty template of this kind.
+func (k Kind) NewTemplate() client.Object {
+	return k.newTemplate()
+}
+
+// NewChild returns an empty resource of the kind generated by the templates.
+func (k Kind) NewChild() client.Object {
+	return k.newChild()
+}
@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectData describes a namespace or a workload to the template fields.
+type ObjectData struct {
+	Kind        string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// TemplateData is the data the template fields are rendered with. Workload is
+// nil when the template targets namespaces.
+type TemplateData struct {
+	Namespace ObjectData
+	Workload  *ObjectData
+}
+
+func objectData(kind string, obj client.Object) ObjectData {
+	return ObjectData{
+		Kind:        kind,
+		Name:        obj.GetName(),
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+	}
+}
+
+// renderSpec renders the string fields of a spec template and decodes the
+// result into out. Strings without actions are copied as is.
+func renderSpec(specTemplate any, data TemplateData, out any) error {
+	raw, err := json.Marshal(specTemplate)
+	if err != nil {
+		return err
+	}
+	var tree any
+	if err = json.Unmarshal(raw, &tree); err != nil {
+		return err
+	}
+	if tree, err = renderValue(tree, data, ""); err != nil {
+		return err
+	}
+	if raw, err = json.Marshal(tree); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// renderValue renders the strings of a decoded JSON value. path locates the
+// value in error messages.
+func renderValue(value any, data TemplateData, path string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, leftDelimiter) {
+			return v, nil
+		}
+		tmpl, err := template.New(path).Delims(leftDelimiter, rightDelimiter).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in %s: %w", path, err)
+		}
+		var out strings.Builder
+		if err = tmpl.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		return out.String(), nil
+	case map[string]any:
+		// Keys are sorted so that the first error reported does not change between reconciles.
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			rendered, err := renderValue(v[key], data, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			rendered, err := renderValue(item, data, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// childName returns the name of the resource generated for a target: the
+// template name for a namespace, suffixed with the workload name for a
+// workload. Names too long are truncated and suffixed with a hash.
+func childName(templateName string, data TemplateData) string {
+	name := templateName
+	if data.Workload != nil {
+		name = templateName + "-" + data.Workload.Name
+	}
+	if len(name) <= maxNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(name[:maxNameLength-len(suffix)-1], "-.") + "-" + suffix
+}
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func TestRenderSpec(t *testing.T) {
+	spec := &v1alpha1.DatadogMonitorSpec{
+		Name:    "[[ .Workload.Name ]] restarts in [[ .Namespace.Name ]]",
+		Message: "{{#is_alert}}Restarting[[ with .Namespace.Labels.team ]] @team-[[ . ]][[ end ]]{{/is_alert}}",
+		Query:   `change(sum(last_5m),last_5m):sum:kubernetes.containers.restarts{kube_namespace:[[ .Namespace.Name ]],kube_[[ .Workload.Kind | printf "%s" ]]:[[ .Workload.Name ]]} > 5`,
+		Type:    v1alpha1.DatadogMonitorTypeMetric,
+		Tags:    []string{"team:[[ index .Namespace.Labels \"team\" ]]", "static:tag"},
+	}
+	data := TemplateData{
+		Namespace: ObjectData{Kind: "Namespace", Name: "payments", Labels: map[string]string{"team": "pay"}},
+		Workload:  &ObjectData{Kind: "Deployment", Name: "api"},
+	}
+
+	out := &v1alpha1.DatadogMonitorSpec{}
+	require.NoError(t, renderSpec(spec, data, out))
+	assert.Equal(t, "api restarts in payments", out.Name)
+	assert.Equal(t, "{{#is_alert}}Restarting @team-pay{{/is_alert}}", out.Message)
+	assert.Equal(t, "change(sum(last_5m),last_5m):sum:kubernetes.containers.restarts{kube_namespace:payments,kube_Deployment:api} > 5", out.Query)
+	assert.Equal(t, []string{"team:pay", "static:tag"}, out.Tags)
+	assert.Equal(t, v1alpha1.DatadogMonitorTypeMetric, out.Type)
+	// The spec template is left untouched.
+	assert.Equal(t, "[[ .Workload.Name ]] restarts in [[ .Namespace.Name ]]", spec.Name)
+}
+
+func TestRenderSpecErrors(t *testing.T) {
+	data := TemplateData{Namespace: ObjectData{Kind: "Namespace", Name: "payments"}}
+
+	err := renderSpec(&v1alpha1.DatadogMonitorSpec{Name: "[[ .Workload.Name ]]"}, data, &v1alpha1.DatadogMonitorSpec{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render name")
+
+	err = renderSpec(&v1alpha1.DatadogMonitorSpec{Tags: []string{"ok", "team:[[ .Namespace.Labels.team ]]"}}, data, &v1alpha1.DatadogMonitorSpec{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render tags[1]")
+
+	err = renderSpec(&v1alpha1.DatadogMonitorSpec{Query: "[[ .Namespace.Name "}, data, &v1alpha1.DatadogMonitorSpec{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid template in query")
+}
+
+func TestChildName(t *testing.T) {
+	assert.Equal(t, "restarts", childName("restarts", TemplateData{}))
+	assert.Equal(t, "restarts-api", childName("restarts", TemplateData{Workload: &ObjectData{Name: "api"}}))
+
+	long := childName("restarts", TemplateData{Workload: &ObjectData{Name: strings.Repeat("a", 250)}})
+	assert.Len(t, long, maxNameLength)
+	assert.NotEqual(t, long, childName("restarts", TemplateData{Workload: &ObjectData{Name: strings.Repeat("a", 251)}}))
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"context"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+// workloadGVKs are the kinds of the workloads a template can select.
+var workloadGVKs = map[v1alpha1.DatadogTemplateWorkloadKind]schema.GroupVersionKind{
+	v1alpha1.DatadogTemplateWorkloadKindDeployment:  appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	v1alpha1.DatadogTemplateWorkloadKindStatefulSet: appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+	v1alpha1.DatadogTemplateWorkloadKindDaemonSet:   appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+}
+
+// WorkloadKinds are the kinds of the workloads a template can select.
+var WorkloadKinds = []v1alpha1.DatadogTemplateWorkloadKind{
+	v1alpha1.DatadogTemplateWorkloadKindDeployment,
+	v1alpha1.DatadogTemplateWorkloadKindStatefulSet,
+	v1alpha1.DatadogTemplateWorkloadKindDaemonSet,
+}
+
+// NewWorkloadMetadata returns an empty metadata object of a workload kind, to
+// watch the workloads with a metadata-only informer.
+func NewWorkloadMetadata(kind v1alpha1.DatadogTemplateWorkloadKind) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(workloadGVKs[kind])
+	return obj
+}
+
+// TargetChangedPredicate filters the namespace and workload events to the
+// changes of the data available to the templates, and to the namespaces being
+// terminated.
+var TargetChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+			!maps.Equal(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) ||
+			e.ObjectOld.GetDeletionTimestamp().IsZero() != e.ObjectNew.GetDeletionTimestamp().IsZero()
+	},
+}
+
+// NamespaceRequests enqueues the templates whose namespace selector matches a
+// namespace. Updates are mapped with the old and the new namespace, so the
+// templates that no longer select a namespace are enqueued as well.
+func (r *Reconciler) NamespaceRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.templateRequests(ctx, func(target *v1alpha1.DatadogTemplateTarget) bool {
+		return selects(&target.NamespaceSelector, obj.GetLabels())
+	})
+}
+
+// WorkloadRequests returns a map function enqueuing the templates selecting
+// the workloads of a kind.
+func (r *Reconciler) WorkloadRequests(kind v1alpha1.DatadogTemplateWorkloadKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		ns := &corev1.Namespace{}
+		err := r.client.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, ns)
+		if err != nil {
+			// The namespace selectors are not evaluated, the templates check them when reconciled.
+			r.log.V(1).Info("Unable to get the namespace of a workload", "namespace", obj.GetNamespace(), "error", err.Error())
+		}
+		return r.templateRequests(ctx, func(target *v1alpha1.DatadogTemplateTarget) bool {
+			if target.Workloads == nil || target.Workloads.Kind != kind {
+				return false
+			}
+			if err == nil && !selects(&target.NamespaceSelector, ns.Labels) {
+				return false
+			}
+			return target.Workloads.Selector == nil || selects(target.Workloads.Selector, obj.GetLabels())
+		})
+	}
+}
+
+// templateRequests enqueues the templates whose target matches.
+func (r *Reconciler) templateRequests(ctx context.Context, matches func(*v1alpha1.DatadogTemplateTarget) bool) []reconcile.Request {
+	list := r.kind.newTemplateList()
+	if err := r.client.List(ctx, list); err != nil {
+		r.log.Error(err, "Unable to list the templates", "kind", r.kind.TemplateKind)
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range items {
+		tmpl := item.(client.Object)
+		if matches(r.kind.target(tmpl)) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tmpl)})
+		}
+	}
+	return requests
+}
+
+// selects returns true when a label selector matches labels. An invalid
+// selector matches, the template reports it when reconciled.
+func selects(selector *metav1.LabelSelector, objLabels map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return true
+	}
+	return s.Matches(labels.Set(objLabels))
+}
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogtemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func requestNames(requests []reconcile.Request) []string {
+	var names []string
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	return names
+}
+
+func namedTemplate(name string, target v1alpha1.DatadogTemplateTarget) *v1alpha1.DatadogMonitorTemplate {
+	tmpl := newMonitorTemplate(target)
+	tmpl.Name = name
+	tmpl.UID = types.UID(name)
+	return tmpl
+}
+
+func TestNamespaceRequests(t *testing.T) {
+	r, _ := newTestReconciler(MonitorKind, nil,
+		namedTemplate("monitored", v1alpha1.DatadogTemplateTarget{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"monitored": "true"}},
+		}),
+		namedTemplate("all", v1alpha1.DatadogTemplateTarget{}),
+	)
+
+	assert.ElementsMatch(t, []string{"monitored", "all"},
+		requestNames(r.NamespaceRequests(context.Background(), newNamespace("payments", map[string]string{"monitored": "true"}))))
+	assert.Equal(t, []string{"all"},
+		requestNames(r.NamespaceRequests(context.Background(), newNamespace("kube-system", nil))))
+}
+
+func TestWorkloadRequests(t *testing.T) {
+	webDeployments := v1alpha1.DatadogTemplateTarget{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"monitored": "true"}},
+		Workloads: &v1alpha1.DatadogTemplateWorkloadSelector{
+			Kind:     v1alpha1.DatadogTemplateWorkloadKindDeployment,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+		},
+	}
+	r, _ := newTestReconciler(MonitorKind, nil,
+		namedTemplate("web", webDeployments),
+		namedTemplate("statefulsets", v1alpha1.DatadogTemplateTarget{
+			Workloads: &v1alpha1.DatadogTemplateWorkloadSelector{Kind: v1alpha1.DatadogTemplateWorkloadKindStatefulSet},
+		}),
+		namedTemplate("namespaces", v1alpha1.DatadogTemplateTarget{}),
+		newNamespace("payments", map[string]string{"monitored": "true"}),
+		newNamespace("kube-system", nil),
+	)
+	deployments := r.WorkloadRequests(v1alpha1.DatadogTemplateWorkloadKindDeployment)
+
+	assert.Equal(t, []string{"web"}, requestNames(deployments(context.Background(), newDeployment("payments", "api", map[string]string{"tier": "web"}))))
+	assert.Empty(t, deployments(context.Background(), newDeployment("payments", "worker", map[string]string{"tier": "batch"})))
+	assert.Empty(t, deployments(context.Background(), newDeployment("kube-system", "api", map[string]string{"tier": "web"})))
+	assert.Equal(t, []string{"statefulsets"},
+		requestNames(r.WorkloadRequests(v1alpha1.DatadogTemplateWorkloadKindStatefulSet)(context.Background(), NewWorkloadMetadata(v1alpha1.DatadogTemplateWorkloadKindStatefulSet))))
+}
+
+func TestTargetChangedPredicate(t *testing.T) {
+	old := newNamespace("payments", map[string]string{"monitored": "true"})
+
+	unchanged := old.DeepCopy()
+	unchanged.ResourceVersion = "2"
+	assert.False(t, TargetChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: unchanged}))
+
+	relabeled := old.DeepCopy()
+	relabeled.Labels = nil
+	assert.True(t, TargetChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: relabeled}))
+
+	terminating := old.DeepCopy()
+	terminating.DeletionTimestamp = ptr.To(metav1.Now())
+	assert.True(t, TargetChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: terminating}))
+
+	assert.True(t, TargetChangedPredicate.Create(event.CreateEvent{Object: old}))
+}
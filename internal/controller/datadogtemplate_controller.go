@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogtemplate"
+)
+
+// DatadogTemplateReconciler generates DatadogMonitors or DatadogSLOs from the
+// DatadogMonitorTemplates or DatadogSLOTemplates, depending on its Kind.
+type DatadogTemplateReconciler struct {
+	Client    client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	Kind      datadogtemplate.Kind
+	// Namespaces are the namespaces watched by the controller of the generated resources.
+	Namespaces map[string]cache.Config
+}
+
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitortemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitortemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitortemplates/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslotemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslotemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslotemplates/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+
+// SetupWithManager sets up the controller if the template CRD is installed in the cluster.
+func (r *DatadogTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	gvk := v1alpha1.GroupVersion.WithKind(r.Kind.TemplateKind)
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			r.Log.Info("CRD not installed, not starting the controller", "kind", r.Kind.TemplateKind)
+			return nil
+		}
+		return err
+	}
+
+	reconciler := datadogtemplate.NewReconciler(r.Client, r.APIReader, r.Log, r.Recorder, r.Kind, r.Namespaces)
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(r.Kind.NewTemplate(), ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Generated resources are restored when they are modified or deleted.
+		Owns(r.Kind.NewChild(), ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Templates are instantiated again when the namespaces and workloads they select change.
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.NamespaceRequests),
+			ctrlbuilder.WithPredicates(datadogtemplate.TargetChangedPredicate))
+	for _, kind := range datadogtemplate.WorkloadKinds {
+		builder = builder.WatchesMetadata(datadogtemplate.NewWorkloadMetadata(kind),
+			handler.EnqueueRequestsFromMapFunc(reconciler.WorkloadRequests(kind)),
+			ctrlbuilder.WithPredicates(datadogtemplate.TargetChangedPredicate))
+	}
+	return builder.Complete(reconciler)
+}
@@ -31,6 +31,16 @@ const (
 	// readiness or scheduling timeout fired under policy=remove.
 	UntaintRemovalReasonTimeout = "timeout"
 
+	// UntaintRuleBlockReasonContainerNotReady signals that a container listed
+	// by a readiness rule is not Ready.
+	UntaintRuleBlockReasonContainerNotReady = "container_not_ready"
+	// UntaintRuleBlockReasonSocketNotReady signals that the container creating
+	// the host path socket of a readiness rule is not Ready.
+	UntaintRuleBlockReasonSocketNotReady = "socket_not_ready"
+	// UntaintRuleBlockReasonMinReadySeconds signals that the agent pod has not
+	// been Ready for the minimum duration of a readiness rule.
+	UntaintRuleBlockReasonMinReadySeconds = "min_ready_seconds"
+
 	// untaintNodeLabel is the label key carrying the node name on the
 	// node-scoped untaint metrics.
 	untaintNodeLabel = "node"
@@ -82,11 +92,45 @@ var (
 			Help:      "Total number of errors encountered while attempting to remove the agent-not-ready taint from a node",
 		},
 	)
+
+	// StartupTaintsAppliedTotal counts the agent-not-ready taints added to new
+	// nodes by the operator when the startup taint mode is enabled.
+	StartupTaintsAppliedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: untaintSubsystem,
+			Name:      "startup_taints_applied_total",
+			Help:      "Total number of agent-not-ready taints added to new nodes by the operator",
+		},
+	)
+
+	// StartupTaintErrorsTotal counts hard errors encountered while adding the
+	// agent-not-ready taint to new nodes. Conflicts are requeued and not counted.
+	StartupTaintErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: untaintSubsystem,
+			Name:      "startup_taint_errors_total",
+			Help:      "Total number of errors encountered while adding the agent-not-ready taint to new nodes",
+		},
+	)
+
+	// ReadinessRuleBlocking is set to 1 for every DatadogAgent untaint readiness
+	// rule currently blocking the taint removal of a node, broken down by node,
+	// rule name and block reason. Series are deleted once the rule passes or the
+	// node is untainted.
+	ReadinessRuleBlocking = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: untaintSubsystem,
+			Name:      "readiness_rule_blocking",
+			Help:      "Untaint readiness rules currently blocking the taint removal of a node, by node, rule and reason",
+		},
+		[]string{untaintNodeLabel, "rule", "reason"},
+	)
 )
 
 // DeleteNodeSeries removes every child series labeled with the given node from
-// the node-scoped untaint metrics: TaintRemovalsTotal (across all reason values)
-// and TaintRemovalLatency (across all histogram buckets). It is a no-op when the
+// the node-scoped untaint metrics: TaintRemovalsTotal (across all reason values),
+// TaintRemovalLatency (across all histogram buckets) and ReadinessRuleBlocking
+// (across all rules). It is a no-op when the
 // node has no series. Call it when a node is deleted to prevent unbounded growth
 // of per-node series for the operator's lifetime as clusters autoscale or
 // replace nodes.
@@ -94,6 +138,13 @@ func DeleteNodeSeries(node string) {
 	match := prometheus.Labels{untaintNodeLabel: node}
 	TaintRemovalsTotal.DeletePartialMatch(match)
 	TaintRemovalLatency.DeletePartialMatch(match)
+	DeleteReadinessRuleSeries(node)
+}
+
+// DeleteReadinessRuleSeries removes every ReadinessRuleBlocking series of the
+// given node.
+func DeleteReadinessRuleSeries(node string) {
+	ReadinessRuleBlocking.DeletePartialMatch(prometheus.Labels{untaintNodeLabel: node})
 }
 
 func init() {
@@ -101,4 +152,7 @@ func init() {
 	metrics.Registry.MustRegister(TaintRemovalLatency)
 	metrics.Registry.MustRegister(TaintTimeoutsTotal)
 	metrics.Registry.MustRegister(TaintRemovalErrorsTotal)
+	metrics.Registry.MustRegister(ReadinessRuleBlocking)
+	metrics.Registry.MustRegister(StartupTaintsAppliedTotal)
+	metrics.Registry.MustRegister(StartupTaintErrorsTotal)
 }
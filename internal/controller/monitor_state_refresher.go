@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadoggenericresource"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogmonitor"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+	"github.com/DataDog/datadog-operator/pkg/sharding"
+)
+
+// defaultMonitorStateRefreshPeriod is shorter than the period after which the reconcilers
+// refresh the state of a monitor themselves, so that they only do it for the monitors missing
+// from the bulk refresh. The states are only written when they change or are about to become
+// stale for the reconciler, see datadogmonitor.MonitorStateStalePeriod and
+// datadoggenericresource.MonitorStateStalePeriod.
+const defaultMonitorStateRefreshPeriod = 30 * time.Second
+
+// monitorStateRefresher periodically lists the states of the monitors created by the operator
+// and writes them to the status of the DatadogMonitors and DatadogGenericResource monitors, so
+// that their reconcilers don't need to get each monitor. The reconcilers still get the monitors
+// missing from the list, for example a DatadogMonitor with DisableRequiredTags.
+type monitorStateRefresher struct {
+	client         client.Client
+	monitorsClient *datadogV1.MonitorsApi
+	credsManager   *config.CredentialManager
+	sharder        sharding.Sharder
+	workloads      *datadogmonitor.WorkloadNotifier
+	log            logr.Logger
+	period         time.Duration
+
+	datadogMonitorEnabled         bool
+	datadogGenericResourceEnabled bool
+}
+
+// setupMonitorStateRefresher starts the monitor state refresher when the DatadogMonitor or the
+// DatadogGenericResource controller is enabled.
+func setupMonitorStateRefresher(logger logr.Logger, mgr manager.Manager, options SetupOptions) error {
+	if !options.DatadogMonitorEnabled && !options.DatadogGenericResourceEnabled {
+		return nil
+	}
+	period := defaultMonitorStateRefreshPeriod
+	if options.DatadogGenericResourceEnabled && options.DatadogGenericResourceRequeue > 0 && options.DatadogGenericResourceRequeue/2 < period {
+		period = options.DatadogGenericResourceRequeue / 2
+	}
+	return mgr.Add(&monitorStateRefresher{
+		client:                        mgr.GetClient(),
+		monitorsClient:                datadogclient.InitMonitorClient(),
+		credsManager:                  options.CredsManager,
+		sharder:                       options.sharder,
+		workloads:                     options.workloadNotifier,
+		log:                           logger.WithName("monitor-state-refresher"),
+		period:                        period,
+		datadogMonitorEnabled:         options.DatadogMonitorEnabled,
+		datadogGenericResourceEnabled: options.DatadogGenericResourceEnabled,
+	})
+}
+
+// NeedLeaderElection runs the refresher on all the replicas when the reconciliation is sharded,
+// each one refreshing the objects of the shards it holds.
+func (r *monitorStateRefresher) NeedLeaderElection() bool {
+	return r.sharder == nil
+}
+
+// Start refreshes the states of the monitors every period until the context is done.
+func (r *monitorStateRefresher) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.refresh, r.period)
+	return nil
+}
+
+func (r *monitorStateRefresher) refresh(ctx context.Context) {
+	auth, err := r.credsManager.GetAuth()
+	if err != nil {
+		r.log.Error(err, "Unable to get credentials")
+		return
+	}
+	monitors, err := datadogmonitor.ListManagedMonitors(auth, r.monitorsClient)
+	if err != nil {
+		r.log.Error(err, "Unable to list monitors")
+		return
+	}
+
+	now := metav1.NewTime(time.Now())
+	if r.datadogMonitorEnabled {
+		r.refreshDatadogMonitors(ctx, monitors, now)
+	}
+	if r.datadogGenericResourceEnabled {
+		r.refreshDatadogGenericResources(ctx, monitors, now)
+	}
+}
+
+func (r *monitorStateRefresher) refreshDatadogMonitors(ctx context.Context, monitors map[int64]datadogV1.Monitor, now metav1.Time) {
+	list := &v1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, list); err != nil {
+		r.log.Error(err, "Unable to list DatadogMonitors")
+		return
+	}
+	updated, unchanged, missing := 0, 0, 0
+	for i := range list.Items {
+		instance := &list.Items[i]
+		if instance.Status.ID == 0 || !r.owns(instance) {
+			continue
+		}
+		m, found := monitors[int64(instance.Status.ID)]
+		if !found {
+			missing++
+			continue
+		}
+		previous := instance.DeepCopy()
+		if !datadogmonitor.ApplyMonitorState(m, now, &instance.Status) && !r.aboutToBeStale(previous.Status.MonitorStateLastUpdateTime, now, datadogmonitor.MonitorStateStalePeriod) {
+			unchanged++
+			continue
+		}
+		if err := r.client.Status().Patch(ctx, instance, client.MergeFrom(previous)); err != nil {
+			r.log.Error(err, "Unable to update DatadogMonitor state", "datadogmonitor", client.ObjectKeyFromObject(instance))
+			continue
+		}
+		r.workloads.Notify(ctx, instance, previous.Status.TriggeredState, instance.Status.TriggeredState)
+		updated++
+	}
+	r.log.V(1).Info("Refreshed DatadogMonitor states", "updated", updated, "unchanged", unchanged, "missing", missing)
+}
+
+func (r *monitorStateRefresher) refreshDatadogGenericResources(ctx context.Context, monitors map[int64]datadogV1.Monitor, now metav1.Time) {
+	list := &v1alpha1.DatadogGenericResourceList{}
+	if err := r.client.List(ctx, list); err != nil {
+		r.log.Error(err, "Unable to list DatadogGenericResources")
+		return
+	}
+	updated, unchanged, missing := 0, 0, 0
+	for i := range list.Items {
+		instance := &list.Items[i]
+		if instance.Spec.Type != v1alpha1.Monitor || instance.Status.Id == "" || !r.owns(instance) {
+			continue
+		}
+		monitorID, err := strconv.ParseInt(instance.Status.Id, 10, 64)
+		if err != nil {
+			continue
+		}
+		m, found := monitors[monitorID]
+		if !found {
+			missing++
+			continue
+		}
+		previous := instance.DeepCopy()
+		if !datadoggenericresource.ApplyMonitorState(m, now, &instance.Status) && !r.aboutToBeStale(previous.Status.StateLastUpdateTime, now, datadoggenericresource.MonitorStateStalePeriod) {
+			unchanged++
+			continue
+		}
+		if err := r.client.Status().Patch(ctx, instance, client.MergeFrom(previous)); err != nil {
+			r.log.Error(err, "Unable to update DatadogGenericResource state", "datadoggenericresource", client.ObjectKeyFromObject(instance))
+			continue
+		}
+		updated++
+	}
+	r.log.V(1).Info("Refreshed DatadogGenericResource monitor states", "updated", updated, "unchanged", unchanged, "missing", missing)
+}
+
+// aboutToBeStale returns whether a state last updated at lastUpdate becomes stale, after
+// stalePeriod, before the next refresh.
+func (r *monitorStateRefresher) aboutToBeStale(lastUpdate *metav1.Time, now metav1.Time, stalePeriod time.Duration) bool {
+	return lastUpdate == nil || now.Sub(lastUpdate.Time)+r.period >= stalePeriod
+}
+
+func (r *monitorStateRefresher) owns(obj client.Object) bool {
+	return obj.GetDeletionTimestamp() == nil && (r.sharder == nil || r.sharder.Owns(obj))
+}
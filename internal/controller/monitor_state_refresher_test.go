@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogmonitor"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+type ownedNames map[string]bool
+
+func (o ownedNames) Owns(obj client.Object) bool {
+	return o[obj.GetName()]
+}
+
+func TestMonitorStateRefresher(t *testing.T) {
+	var lists, gets atomic.Int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v1/monitor" {
+			gets.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lists.Add(1)
+		assert.Equal(t, "generated:kubernetes", r.URL.Query().Get("monitor_tags"))
+		monitors := []datadogV1.Monitor{}
+		if r.URL.Query().Get("page") == "0" {
+			for id, state := range map[int64]datadogV1.MonitorOverallStates{
+				1: datadogV1.MONITOROVERALLSTATES_ALERT,
+				2: datadogV1.MONITOROVERALLSTATES_WARN,
+				3: datadogV1.MONITOROVERALLSTATES_OK,
+			} {
+				m := datadogV1.Monitor{Id: &id}
+				m.SetOverallState(state)
+				monitors = append(monitors, m)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(monitors)
+	}))
+	defer httpServer.Close()
+	t.Setenv("DD_URL", httpServer.URL)
+	t.Setenv("DD_API_KEY", "api-key")
+	t.Setenv("DD_APP_KEY", "app-key")
+
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	old := metav1.NewTime(time.Now().Add(-datadogmonitor.MonitorStateStalePeriod))
+	syncedConditions := []metav1.Condition{{
+		Type:               string(condition.DatadogConditionTypeStateSynced),
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: recent,
+		Reason:             "Synced",
+		Message:            "State refreshed from Datadog",
+	}}
+	s := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(s))
+	objects := []client.Object{
+		&v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "listed"},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: 1, MonitorState: v1alpha1.DatadogMonitorStateOK},
+		},
+		&v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing"},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: 4, MonitorState: v1alpha1.DatadogMonitorStateOK},
+		},
+		&v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unchanged"},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: 3, MonitorState: v1alpha1.DatadogMonitorStateOK, MonitorStateSyncStatus: v1alpha1.MonitorStateSyncStatusOK, MonitorStateLastUpdateTime: &recent},
+		},
+		&v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unchanged-stale"},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: 3, MonitorState: v1alpha1.DatadogMonitorStateOK, MonitorStateSyncStatus: v1alpha1.MonitorStateSyncStatusOK, MonitorStateLastUpdateTime: &old},
+		},
+		&v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "not-owned"},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: 3, MonitorState: v1alpha1.DatadogMonitorStateAlert},
+		},
+		&v1alpha1.DatadogGenericResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "generic-monitor"},
+			Spec:       v1alpha1.DatadogGenericResourceSpec{Type: v1alpha1.Monitor},
+			Status:     v1alpha1.DatadogGenericResourceStatus{Id: "2"},
+		},
+		&v1alpha1.DatadogGenericResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "generic-unchanged"},
+			Spec:       v1alpha1.DatadogGenericResourceSpec{Type: v1alpha1.Monitor},
+			Status:     v1alpha1.DatadogGenericResourceStatus{Id: "3", State: string(datadogV1.MONITOROVERALLSTATES_OK), StateLastUpdateTime: &recent, Conditions: syncedConditions},
+		},
+		&v1alpha1.DatadogGenericResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "generic-unchanged-stale"},
+			Spec:       v1alpha1.DatadogGenericResourceSpec{Type: v1alpha1.Monitor},
+			Status:     v1alpha1.DatadogGenericResourceStatus{Id: "3", State: string(datadogV1.MONITOROVERALLSTATES_OK), StateLastUpdateTime: &old, Conditions: syncedConditions},
+		},
+		&v1alpha1.DatadogGenericResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "generic-notebook"},
+			Spec:       v1alpha1.DatadogGenericResourceSpec{Type: v1alpha1.Notebook},
+			Status:     v1alpha1.DatadogGenericResourceStatus{Id: "3"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&v1alpha1.DatadogMonitor{}, &v1alpha1.DatadogGenericResource{}).Build()
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	refresher := &monitorStateRefresher{
+		client:                        c,
+		monitorsClient:                datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+		credsManager:                  config.NewCredentialManager(c),
+		sharder:                       ownedNames{"listed": true, "missing": true, "unchanged": true, "unchanged-stale": true, "generic-monitor": true, "generic-unchanged": true, "generic-unchanged-stale": true, "generic-notebook": true},
+		log:                           log.Log,
+		period:                        defaultMonitorStateRefreshPeriod,
+		datadogMonitorEnabled:         true,
+		datadogGenericResourceEnabled: true,
+	}
+	refresher.refresh(context.Background())
+
+	assert.Equal(t, int32(1), lists.Load(), "the monitors are listed in a single page")
+	assert.Zero(t, gets.Load(), "the monitors are not fetched one by one")
+
+	dm := &v1alpha1.DatadogMonitor{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "listed"}, dm))
+	assert.Equal(t, v1alpha1.DatadogMonitorStateAlert, dm.Status.MonitorState)
+	assert.NotNil(t, dm.Status.MonitorStateLastUpdateTime)
+	assert.NotNil(t, dm.Status.MonitorStateLastTransitionTime)
+	assert.Equal(t, v1alpha1.MonitorStateSyncStatusOK, dm.Status.MonitorStateSyncStatus)
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "missing"}, dm))
+	assert.Nil(t, dm.Status.MonitorStateLastUpdateTime, "the reconciler gets the monitors missing from the list")
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "unchanged"}, dm))
+	assert.Equal(t, recent.Unix(), dm.Status.MonitorStateLastUpdateTime.Unix(), "the unchanged states are not written")
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "unchanged-stale"}, dm))
+	assert.Greater(t, dm.Status.MonitorStateLastUpdateTime.Unix(), old.Unix(), "the states about to be stale for the reconciler are written")
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "not-owned"}, dm))
+	assert.Equal(t, v1alpha1.DatadogMonitorStateAlert, dm.Status.MonitorState, "the objects of other shards are left to their replica")
+
+	ddgr := &v1alpha1.DatadogGenericResource{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "generic-monitor"}, ddgr))
+	assert.Equal(t, string(datadogV1.MONITOROVERALLSTATES_WARN), ddgr.Status.State)
+	assert.NotNil(t, ddgr.Status.StateLastUpdateTime)
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "generic-unchanged"}, ddgr))
+	assert.Equal(t, recent.Unix(), ddgr.Status.StateLastUpdateTime.Unix(), "the unchanged states are not written")
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "generic-unchanged-stale"}, ddgr))
+	assert.Greater(t, ddgr.Status.StateLastUpdateTime.Unix(), old.Unix(), "the states about to be stale for the reconciler are written")
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "generic-notebook"}, ddgr))
+	assert.Empty(t, ddgr.Status.State, "only the monitors are refreshed")
+}
@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package prometheusmonitor
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ServiceMonitorKind is the kind of the Prometheus Operator ServiceMonitor CRD.
+	ServiceMonitorKind = "ServiceMonitor"
+	// PodMonitorKind is the kind of the Prometheus Operator PodMonitor CRD.
+	PodMonitorKind = "PodMonitor"
+
+	// MetricNamespaceAnnotationKey can be set on a monitor to choose the
+	// namespace of the generated OpenMetrics checks. It defaults to the monitor name.
+	MetricNamespaceAnnotationKey = "prometheus.datadoghq.com/metric-namespace"
+	// TranslationStatusAnnotationKey is written on the monitors with the result
+	// of their translation.
+	TranslationStatusAnnotationKey = "prometheus.datadoghq.com/translation-status"
+
+	// checkConfigsLabelKey marks the ConfigMap holding the generated check configurations.
+	checkConfigsLabelKey = "prometheus.datadoghq.com/check-configs"
+
+	// DefaultCheckConfigsConfigMapName is the default name of the ConfigMap
+	// holding the generated check configurations, in the operator namespace.
+	DefaultCheckConfigsConfigMapName = "datadog-prometheus-monitors"
+
+	// defaultResyncPeriod bounds the delay before new Services and Pods matching
+	// a monitor selector are added to its check configuration.
+	defaultResyncPeriod = time.Minute
+
+	// maxCheckConfigsSize is the size of the data of a ConfigMap accepted by the API server.
+	maxCheckConfigsSize = 1024 * 1024
+)
+
+// Translation states reported in the TranslationStatusAnnotationKey annotation.
+const (
+	StateTranslated = "Translated"
+	StatePartial    = "Partial"
+	StateFailed     = "Failed"
+)
+
+var monitoringGroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+// GroupVersionKind returns the GroupVersionKind of a Prometheus Operator monitor kind.
+func GroupVersionKind(kind string) schema.GroupVersionKind {
+	return monitoringGroupVersion.WithKind(kind)
+}
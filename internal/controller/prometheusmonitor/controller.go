@@ -0,0 +1,502 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package prometheusmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Reconciler translates the ServiceMonitors or PodMonitors of a cluster into
+// OpenMetrics cluster checks, written to a ConfigMap mounted in the openmetrics.d
+// configuration directory of the Cluster Agent. Each monitor has its own key in
+// the ConfigMap:
+//   - a ServiceMonitor becomes an endpoints check, whose advanced_ad_identifiers
+//     reference the Services it selects: the Cluster Agent resolves their
+//     endpoints and dispatches the checks to the node Agents running them;
+//   - a PodMonitor becomes an endpoints check on the Services in front of the
+//     Pods it selects, with its named ports resolved from the container ports.
+//
+// The checks do not reference the Pods themselves, so that the ConfigMap, whose
+// changes roll out the Cluster Agent, is not rewritten each time a Pod restarts.
+// The selected Services and Pods are never modified. They are read through the
+// API reader rather than the manager cache, whose Pod informer only covers the
+// Agent pods.
+type Reconciler struct {
+	client       client.Client
+	reader       client.Reader
+	log          logr.Logger
+	recorder     record.EventRecorder
+	kind         string
+	configMap    types.NamespacedName
+	resyncPeriod time.Duration
+	maxDataSize  int
+}
+
+// NewReconciler returns a Reconciler for the ServiceMonitorKind or PodMonitorKind
+// monitors, writing their checks to the configMap ConfigMap.
+func NewReconciler(c client.Client, reader client.Reader, log logr.Logger, recorder record.EventRecorder, kind string, configMap types.NamespacedName) *Reconciler {
+	return &Reconciler{
+		client:       c,
+		reader:       reader,
+		log:          log,
+		recorder:     recorder,
+		kind:         kind,
+		configMap:    configMap,
+		resyncPeriod: defaultResyncPeriod,
+		maxDataSize:  maxCheckConfigsSize,
+	}
+}
+
+// NewMonitorObject returns an empty unstructured monitor of the given kind.
+func NewMonitorObject(kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersionKind(kind))
+	return obj
+}
+
+// translationStatus is the content of the TranslationStatusAnnotationKey annotation.
+type translationStatus struct {
+	State    string   `json:"state"`
+	Targets  int      `json:"targets"`
+	Message  string   `json:"message,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// checkConfig is an openmetrics check configuration file loaded by the Cluster Agent.
+type checkConfig struct {
+	AdvancedADIdentifiers []adIdentifier   `json:"advanced_ad_identifiers,omitempty"`
+	ClusterCheck          bool             `json:"cluster_check"`
+	InitConfig            map[string]any   `json:"init_config"`
+	Instances             []map[string]any `json:"instances"`
+}
+
+type adIdentifier struct {
+	KubeEndpoints kubeEndpointsIdentifier `json:"kube_endpoints"`
+}
+
+type kubeEndpointsIdentifier struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Reconcile translates a monitor and writes the checks of the Services or Pods
+// it selects to the ConfigMap. The monitor is requeued periodically to pick up
+// new Services and Pods.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues(strings.ToLower(r.kind), req.NamespacedName)
+	key := checkConfigKey(r.kind, req.NamespacedName)
+
+	monitor := NewMonitorObject(r.kind)
+	if err := r.client.Get(ctx, req.NamespacedName, monitor); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.writeCheckConfig(ctx, key, nil)
+		}
+		return ctrl.Result{}, err
+	}
+	if !monitor.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.writeCheckConfig(ctx, key, nil)
+	}
+
+	spec, err := parseMonitorSpec(monitor)
+	if err != nil {
+		log.V(1).Info("Monitor cannot be translated", "error", err.Error())
+		if writeErr := r.writeCheckConfig(ctx, key, nil); writeErr != nil {
+			return ctrl.Result{}, writeErr
+		}
+		// The monitor is reconciled again when its spec changes.
+		return ctrl.Result{}, r.reportStatus(ctx, monitor, translationStatus{State: StateFailed, Message: err.Error()})
+	}
+
+	instances, warnings := translateEndpoints(r.kind, monitor.GetName(), metricNamespace(monitor), spec.endpoints(r.kind))
+	if len(instances) == 0 {
+		if writeErr := r.writeCheckConfig(ctx, key, nil); writeErr != nil {
+			return ctrl.Result{}, writeErr
+		}
+		return ctrl.Result{}, r.reportStatus(ctx, monitor, translationStatus{
+			State:    StateFailed,
+			Message:  "no scrape endpoint could be translated",
+			Warnings: warnings,
+		})
+	}
+
+	targets, err := r.listTargets(ctx, monitor.GetNamespace(), spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var config *checkConfig
+	var configured int
+	var targetWarnings []string
+	if r.kind == PodMonitorKind {
+		services, listErr := r.listPodServices(ctx, targets)
+		if listErr != nil {
+			return ctrl.Result{}, listErr
+		}
+		config, configured, targetWarnings = podCheckConfig(targets, services, instances)
+	} else {
+		config, configured = serviceCheckConfig(targets, instances)
+	}
+	warnings = append(warnings, targetWarnings...)
+	if err = r.writeCheckConfig(ctx, key, config); err != nil {
+		if !errors.Is(err, errCheckConfigsTooLarge) {
+			return ctrl.Result{}, err
+		}
+		// The checks previously written for the monitor are kept. The monitor
+		// is retried as the other monitors may have released some space.
+		return ctrl.Result{RequeueAfter: r.resyncPeriod}, r.reportStatus(ctx, monitor, translationStatus{
+			State:    StateFailed,
+			Message:  err.Error(),
+			Warnings: warnings,
+		})
+	}
+
+	status := translationStatus{
+		State:    StateTranslated,
+		Targets:  configured,
+		Warnings: warnings,
+	}
+	if len(status.Warnings) > 0 {
+		status.State = StatePartial
+	}
+	if err = r.reportStatus(ctx, monitor, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.resyncPeriod}, nil
+}
+
+// errCheckConfigsTooLarge is returned when the checks of a monitor do not fit in the ConfigMap.
+var errCheckConfigsTooLarge = errors.New("the check configurations exceed the ConfigMap size limit")
+
+// checkConfigKey is the ConfigMap key of the checks of a monitor.
+func checkConfigKey(kind string, nsn types.NamespacedName) string {
+	return fmt.Sprintf("%s_%s_%s.yaml", strings.ToLower(kind), nsn.Namespace, nsn.Name)
+}
+
+// listTargets lists the Services or running Pods selected by a monitor.
+func (r *Reconciler) listTargets(ctx context.Context, monitorNamespace string, spec *monitorSpec) ([]client.Object, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	namespaces := []string{monitorNamespace}
+	switch {
+	case spec.NamespaceSelector.Any:
+		namespaces = []string{metav1.NamespaceAll}
+	case len(spec.NamespaceSelector.MatchNames) > 0:
+		namespaces = spec.NamespaceSelector.MatchNames
+	}
+
+	var targets []client.Object
+	for _, ns := range namespaces {
+		objs, listErr := r.list(ctx, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector})
+		if listErr != nil {
+			return nil, listErr
+		}
+		targets = append(targets, objs...)
+	}
+	slices.SortFunc(targets, func(a, b client.Object) int {
+		return strings.Compare(client.ObjectKeyFromObject(a).String(), client.ObjectKeyFromObject(b).String())
+	})
+	return targets, nil
+}
+
+// list lists the Services or Pods, depending on the monitor kind. Terminated
+// Pods are skipped.
+func (r *Reconciler) list(ctx context.Context, opts ...client.ListOption) ([]client.Object, error) {
+	var objs []client.Object
+	if r.kind == PodMonitorKind {
+		pods := &corev1.PodList{}
+		if err := r.reader.List(ctx, pods, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for i := range pods.Items {
+			if phase := pods.Items[i].Status.Phase; phase != corev1.PodSucceeded && phase != corev1.PodFailed {
+				objs = append(objs, &pods.Items[i])
+			}
+		}
+		return objs, nil
+	}
+	services := &corev1.ServiceList{}
+	if err := r.reader.List(ctx, services, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	for i := range services.Items {
+		objs = append(objs, &services.Items[i])
+	}
+	return objs, nil
+}
+
+// serviceCheckConfig returns the endpoints check running the instances on the
+// endpoints of the Services, and the number of Services. It returns a nil
+// configuration when no Service is selected.
+func serviceCheckConfig(services []client.Object, instances []checkInstance) (*checkConfig, int) {
+	if len(services) == 0 {
+		return nil, 0
+	}
+	config := &checkConfig{
+		ClusterCheck: true,
+		InitConfig:   map[string]any{},
+	}
+	for _, svc := range services {
+		config.AdvancedADIdentifiers = append(config.AdvancedADIdentifiers, adIdentifier{
+			KubeEndpoints: kubeEndpointsIdentifier{Name: svc.GetName(), Namespace: svc.GetNamespace()},
+		})
+	}
+	for _, instance := range instances {
+		config.Instances = append(config.Instances, instance.config)
+	}
+	return config, len(services)
+}
+
+// listPodServices lists the Services of the namespaces of the Pods.
+func (r *Reconciler) listPodServices(ctx context.Context, pods []client.Object) ([]client.Object, error) {
+	namespaces := map[string]struct{}{}
+	for _, pod := range pods {
+		namespaces[pod.GetNamespace()] = struct{}{}
+	}
+	var services []client.Object
+	for _, ns := range slices.Sorted(maps.Keys(namespaces)) {
+		list := &corev1.ServiceList{}
+		if err := r.reader.List(ctx, list, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		for i := range list.Items {
+			services = append(services, &list.Items[i])
+		}
+	}
+	return services, nil
+}
+
+// podCheckConfig returns the endpoints check running the instances on the
+// endpoints of the Services in front of the Pods, the number of Pods behind
+// these Services, and warnings for the Pods behind no Service and the ports not
+// declared by the Pods. Named ports are resolved from the container ports of
+// the Pods. It returns a nil configuration when no instance could be resolved.
+func podCheckConfig(pods, services []client.Object, instances []checkInstance) (*checkConfig, int, []string) {
+	warnings := map[string]struct{}{}
+	config := &checkConfig{
+		ClusterCheck: true,
+		InitConfig:   map[string]any{},
+	}
+	covered := map[client.ObjectKey]struct{}{}
+	for _, obj := range services {
+		svc := obj.(*corev1.Service)
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		matched := false
+		for _, pod := range pods {
+			if pod.GetNamespace() == svc.Namespace && selector.Matches(labels.Set(pod.GetLabels())) {
+				covered[client.ObjectKeyFromObject(pod)] = struct{}{}
+				matched = true
+			}
+		}
+		if matched {
+			config.AdvancedADIdentifiers = append(config.AdvancedADIdentifiers, adIdentifier{
+				KubeEndpoints: kubeEndpointsIdentifier{Name: svc.Name, Namespace: svc.Namespace},
+			})
+		}
+	}
+	var coveredPods []*corev1.Pod
+	for _, pod := range pods {
+		if _, found := covered[client.ObjectKeyFromObject(pod)]; found {
+			coveredPods = append(coveredPods, pod.(*corev1.Pod))
+		}
+	}
+	if uncovered := len(pods) - len(coveredPods); uncovered > 0 {
+		warnings[fmt.Sprintf("%d selected pod(s) are not behind a Service and are not scraped", uncovered)] = struct{}{}
+	}
+	if len(coveredPods) == 0 {
+		return nil, 0, slices.Sorted(maps.Keys(warnings))
+	}
+
+	for _, instance := range instances {
+		if instance.port == "" {
+			config.Instances = append(config.Instances, instance.config)
+			continue
+		}
+		number, found, conflict := podsPortNumber(coveredPods, instance.port)
+		if !found {
+			warnings[fmt.Sprintf("port %s is not declared by the containers of the selected pods", instance.port)] = struct{}{}
+			continue
+		}
+		if conflict {
+			warnings[fmt.Sprintf("port %s has different numbers in the selected pods, %d is used", instance.port, number)] = struct{}{}
+		}
+		instanceConfig := maps.Clone(instance.config)
+		endpoint, _ := instanceConfig["openmetrics_endpoint"].(string)
+		instanceConfig["openmetrics_endpoint"] = strings.Replace(endpoint, portTemplateVariable(instance.port), strconv.Itoa(int(number)), 1)
+		config.Instances = append(config.Instances, instanceConfig)
+	}
+	if len(config.Instances) == 0 {
+		return nil, 0, slices.Sorted(maps.Keys(warnings))
+	}
+	return config, len(coveredPods), slices.Sorted(maps.Keys(warnings))
+}
+
+// podsPortNumber returns the number of the named port declared by a container
+// of the first Pod declaring it, and whether other Pods declare it with another
+// number.
+func podsPortNumber(pods []*corev1.Pod, name string) (int32, bool, bool) {
+	var number int32
+	found, conflict := false, false
+	for _, pod := range pods {
+		podNumber, podFound := podPortNumber(pod, name)
+		switch {
+		case !podFound:
+		case !found:
+			number, found = podNumber, true
+		case podNumber != number:
+			conflict = true
+		}
+	}
+	return number, found, conflict
+}
+
+// podPortNumber returns the number of the named port declared by a container of the Pod.
+func podPortNumber(pod *corev1.Pod, name string) (int32, bool) {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == name {
+				return p.ContainerPort, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// writeCheckConfig writes the check configuration of a monitor to its key of
+// the ConfigMap, creating the ConfigMap if needed. A nil configuration removes
+// the key. It returns errCheckConfigsTooLarge, without writing the ConfigMap,
+// when its data would exceed the size accepted by the API server.
+func (r *Reconciler) writeCheckConfig(ctx context.Context, key string, config *checkConfig) error {
+	var raw string
+	if config != nil {
+		out, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal check configuration: %w", err)
+		}
+		raw = string(out)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.reader.Get(ctx, r.configMap, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ConfigMap %s: %w", r.configMap, err)
+		}
+		if config == nil {
+			return nil
+		}
+		if size := len(key) + len(raw); size > r.maxDataSize {
+			return fmt.Errorf("%w: %d bytes", errCheckConfigsTooLarge, size)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.configMap.Namespace,
+				Name:      r.configMap.Name,
+				Labels:    map[string]string{checkConfigsLabelKey: "true"},
+			},
+			Data: map[string]string{key: raw},
+		}
+		if err = r.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s: %w", r.configMap, err)
+		}
+		return nil
+	}
+
+	current, found := cm.Data[key]
+	if (config == nil && !found) || (config != nil && found && current == raw) {
+		return nil
+	}
+	patched := cm.DeepCopy()
+	if config == nil {
+		delete(patched.Data, key)
+	} else {
+		if patched.Data == nil {
+			patched.Data = map[string]string{}
+		}
+		patched.Data[key] = raw
+		if size := configMapDataSize(patched.Data); size > r.maxDataSize {
+			return fmt.Errorf("%w: %d bytes", errCheckConfigsTooLarge, size)
+		}
+	}
+	if err := r.client.Patch(ctx, patched, client.MergeFrom(cm)); err != nil {
+		return fmt.Errorf("failed to write the checks of %s to ConfigMap %s: %w", key, r.configMap, err)
+	}
+	return nil
+}
+
+// configMapDataSize returns the size of the keys and values of a ConfigMap data.
+func configMapDataSize(data map[string]string) int {
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
+// reportStatus writes the translation status on the monitor and records an
+// event when it changes.
+func (r *Reconciler) reportStatus(ctx context.Context, monitor *unstructured.Unstructured, status translationStatus) error {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if monitor.GetAnnotations()[TranslationStatusAnnotationKey] == string(raw) {
+		return nil
+	}
+
+	patched := monitor.DeepCopy()
+	annotations := patched.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[TranslationStatusAnnotationKey] = string(raw)
+	patched.SetAnnotations(annotations)
+	if err = r.client.Patch(ctx, patched, client.MergeFrom(monitor)); err != nil {
+		return fmt.Errorf("failed to write translation status: %w", err)
+	}
+
+	switch status.State {
+	case StateTranslated:
+		r.recorder.Eventf(monitor, corev1.EventTypeNormal, "Translated",
+			"Translated to OpenMetrics checks on %d %s(s)", status.Targets, targetKindForMonitor(r.kind))
+	case StatePartial:
+		r.recorder.Eventf(monitor, corev1.EventTypeWarning, "TranslationPartial",
+			"Translated to OpenMetrics checks on %d %s(s) with warnings: %s", status.Targets, targetKindForMonitor(r.kind), strings.Join(status.Warnings, "; "))
+	default:
+		r.recorder.Eventf(monitor, corev1.EventTypeWarning, "TranslationFailed", "Translation failed: %s", status.Message)
+	}
+	return nil
+}
+
+func targetKindForMonitor(kind string) string {
+	if kind == PodMonitorKind {
+		return "Pod"
+	}
+	return "Service"
+}
@@ -0,0 +1,321 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package prometheusmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+const testNamespace = "apps"
+
+func testScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	for _, kind := range []string{ServiceMonitorKind, PodMonitorKind} {
+		s.AddKnownTypeWithName(GroupVersionKind(kind), &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(GroupVersionKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return s
+}
+
+func newMonitor(kind, name string, spec map[string]any) *unstructured.Unstructured {
+	obj := NewMonitorObject(kind)
+	obj.SetNamespace(testNamespace)
+	obj.SetName(name)
+	obj.Object["spec"] = spec
+	return obj
+}
+
+func newService(name string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace: testNamespace,
+		Name:      name,
+		Labels:    labels,
+	}}
+}
+
+var testConfigMap = types.NamespacedName{Namespace: "datadog", Name: DefaultCheckConfigsConfigMapName}
+
+func newTestReconciler(kind string, objs ...client.Object) (*Reconciler, client.Client, *record.FakeRecorder) {
+	c := fake.NewClientBuilder().WithScheme(testScheme()).WithObjects(objs...).Build()
+	rec := record.NewFakeRecorder(16)
+	return NewReconciler(c, c, log.Log.WithName("test"), rec, kind, testConfigMap), c, rec
+}
+
+func reconcileMonitor(t *testing.T, r *Reconciler, name string) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}})
+	require.NoError(t, err)
+	return result
+}
+
+func getService(t *testing.T, c client.Client, name string) *corev1.Service {
+	t.Helper()
+	svc := &corev1.Service{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: name}, svc))
+	return svc
+}
+
+func monitorStatus(t *testing.T, c client.Client, kind, name string) translationStatus {
+	t.Helper()
+	obj := NewMonitorObject(kind)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: name}, obj))
+	var status translationStatus
+	require.NoError(t, json.Unmarshal([]byte(obj.GetAnnotations()[TranslationStatusAnnotationKey]), &status))
+	return status
+}
+
+// checkConfigs returns the content of the check configurations ConfigMap.
+func checkConfigs(t *testing.T, c client.Client) map[string]string {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), testConfigMap, cm))
+	assert.Equal(t, "true", cm.Labels[checkConfigsLabelKey])
+	return cm.Data
+}
+
+func decodeCheckConfig(t *testing.T, raw string) checkConfig {
+	t.Helper()
+	var config checkConfig
+	require.NoError(t, yaml.Unmarshal([]byte(raw), &config))
+	return config
+}
+
+var webServiceMonitorSpec = map[string]any{
+	"selector":  map[string]any{"matchLabels": map[string]any{"app": "web"}},
+	"endpoints": []any{map[string]any{"port": "metrics", "path": "/prom"}},
+}
+
+func TestReconcile_ServiceMonitor(t *testing.T) {
+	r, c, rec := newTestReconciler(ServiceMonitorKind,
+		newMonitor(ServiceMonitorKind, "web", webServiceMonitorSpec),
+		newService("web", map[string]string{"app": "web"}),
+		newService("web-canary", map[string]string{"app": "web"}),
+		newService("db", map[string]string{"app": "db"}),
+	)
+
+	assert.Equal(t, ctrl.Result{RequeueAfter: defaultResyncPeriod}, reconcileMonitor(t, r, "web"))
+
+	configs := checkConfigs(t, c)
+	require.Contains(t, configs, "servicemonitor_apps_web.yaml")
+	config := decodeCheckConfig(t, configs["servicemonitor_apps_web.yaml"])
+	assert.True(t, config.ClusterCheck)
+	assert.Equal(t, []adIdentifier{
+		{KubeEndpoints: kubeEndpointsIdentifier{Name: "web", Namespace: testNamespace}},
+		{KubeEndpoints: kubeEndpointsIdentifier{Name: "web-canary", Namespace: testNamespace}},
+	}, config.AdvancedADIdentifiers)
+	require.Len(t, config.Instances, 1)
+	assert.Equal(t, "http://%%host%%:%%port_metrics%%/prom", config.Instances[0]["openmetrics_endpoint"])
+	assert.Equal(t, "web", config.Instances[0]["namespace"])
+
+	// The selected Services are not modified.
+	web := getService(t, c, "web")
+	assert.Equal(t, map[string]string{"app": "web"}, web.Labels)
+	assert.Empty(t, web.Annotations)
+
+	assert.Equal(t, translationStatus{State: StateTranslated, Targets: 2}, monitorStatus(t, c, ServiceMonitorKind, "web"))
+	assert.Contains(t, <-rec.Events, "Translated")
+
+	// An unchanged monitor does not record a new event.
+	reconcileMonitor(t, r, "web")
+	assert.Empty(t, rec.Events)
+}
+
+func TestReconcile_ServiceMonitorWithoutSelectedServices(t *testing.T) {
+	r, c, _ := newTestReconciler(ServiceMonitorKind,
+		newMonitor(ServiceMonitorKind, "web", webServiceMonitorSpec),
+		newService("web", map[string]string{"app": "web"}),
+	)
+	reconcileMonitor(t, r, "web")
+	require.Contains(t, checkConfigs(t, c), "servicemonitor_apps_web.yaml")
+
+	// The Service no longer matches the selector.
+	svc := getService(t, c, "web")
+	svc.Labels["app"] = "other"
+	require.NoError(t, c.Update(context.Background(), svc))
+	reconcileMonitor(t, r, "web")
+
+	assert.NotContains(t, checkConfigs(t, c), "servicemonitor_apps_web.yaml")
+	assert.Equal(t, 0, monitorStatus(t, c, ServiceMonitorKind, "web").Targets)
+}
+
+func TestReconcile_DeletedMonitorRemovesChecks(t *testing.T) {
+	monitor := newMonitor(ServiceMonitorKind, "web", webServiceMonitorSpec)
+	other := newMonitor(ServiceMonitorKind, "other", webServiceMonitorSpec)
+	r, c, _ := newTestReconciler(ServiceMonitorKind, monitor, other, newService("web", map[string]string{"app": "web"}))
+	reconcileMonitor(t, r, "web")
+	reconcileMonitor(t, r, "other")
+	require.Len(t, checkConfigs(t, c), 2)
+
+	require.NoError(t, c.Delete(context.Background(), monitor))
+	assert.Equal(t, ctrl.Result{}, reconcileMonitor(t, r, "web"))
+	configs := checkConfigs(t, c)
+	assert.NotContains(t, configs, "servicemonitor_apps_web.yaml")
+	assert.Contains(t, configs, "servicemonitor_apps_other.yaml")
+}
+
+func TestReconcile_InvalidMonitor(t *testing.T) {
+	r, c, rec := newTestReconciler(ServiceMonitorKind,
+		newMonitor(ServiceMonitorKind, "web", map[string]any{"endpoints": []any{map[string]any{"path": "/metrics"}}}),
+	)
+	assert.Equal(t, ctrl.Result{}, reconcileMonitor(t, r, "web"))
+
+	status := monitorStatus(t, c, ServiceMonitorKind, "web")
+	assert.Equal(t, StateFailed, status.State)
+	assert.Equal(t, []string{"endpoints[0]: skipped: no port is defined"}, status.Warnings)
+	assert.Contains(t, <-rec.Events, "TranslationFailed")
+
+	err := c.Get(context.Background(), testConfigMap, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err), "no ConfigMap is created without checks")
+}
+
+func TestReconcile_InvalidSelector(t *testing.T) {
+	r, c, rec := newTestReconciler(ServiceMonitorKind,
+		newMonitor(ServiceMonitorKind, "web", map[string]any{
+			"selector": map[string]any{"matchExpressions": []any{
+				map[string]any{"key": "app", "operator": "In"},
+			}},
+			"endpoints": []any{map[string]any{"port": "metrics"}},
+		}),
+	)
+	// The monitor is not requeued: it is reconciled again when its spec changes.
+	assert.Equal(t, ctrl.Result{}, reconcileMonitor(t, r, "web"))
+
+	status := monitorStatus(t, c, ServiceMonitorKind, "web")
+	assert.Equal(t, StateFailed, status.State)
+	assert.Contains(t, status.Message, "invalid selector")
+	assert.Contains(t, <-rec.Events, "TranslationFailed")
+}
+
+func newPod(name string, labels map[string]string, ports ...corev1.ContainerPort) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name, Labels: labels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "proxy"},
+			{Name: "app", Ports: ports},
+		}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.12"},
+	}
+}
+
+func newSelectingService(name string, selector map[string]string) *corev1.Service {
+	svc := newService(name, nil)
+	svc.Spec.Selector = selector
+	return svc
+}
+
+func TestReconcile_PodMonitor(t *testing.T) {
+	metricsPort := corev1.ContainerPort{Name: "metrics", ContainerPort: 9090}
+	pod := newPod("web-0", map[string]string{"app": "web", "tier": "front"}, metricsPort)
+	pending := newPod("web-1", map[string]string{"app": "web", "tier": "front"}, metricsPort)
+	pending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+	completed := newPod("web-job", map[string]string{"app": "web", "tier": "front"}, metricsPort)
+	completed.Status.Phase = corev1.PodSucceeded
+	standalone := newPod("web-debug", map[string]string{"app": "web"}, metricsPort)
+
+	r, c, _ := newTestReconciler(PodMonitorKind,
+		newMonitor(PodMonitorKind, "web", map[string]any{
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "web"}},
+			"podMetricsEndpoints": []any{
+				map[string]any{"port": "metrics"},
+				map[string]any{"port": "admin"},
+			},
+		}),
+		pod, pending, completed, standalone,
+		newSelectingService("web", map[string]string{"tier": "front"}),
+		newSelectingService("db", map[string]string{"app": "db"}),
+		newService("external", nil),
+	)
+	reconcileMonitor(t, r, "web")
+
+	config := decodeCheckConfig(t, checkConfigs(t, c)["podmonitor_apps_web.yaml"])
+	assert.True(t, config.ClusterCheck)
+	assert.Equal(t, []adIdentifier{
+		{KubeEndpoints: kubeEndpointsIdentifier{Name: "web", Namespace: testNamespace}},
+	}, config.AdvancedADIdentifiers)
+	require.Len(t, config.Instances, 1)
+	assert.Equal(t, "http://%%host%%:9090/metrics", config.Instances[0]["openmetrics_endpoint"])
+	assert.Equal(t, []any{"pod_monitor:web"}, config.Instances[0]["tags"])
+
+	got := &corev1.Pod{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pod), got))
+	assert.Empty(t, got.Annotations, "the selected pods are not modified")
+
+	status := monitorStatus(t, c, PodMonitorKind, "web")
+	assert.Equal(t, StatePartial, status.State)
+	assert.Equal(t, 2, status.Targets, "terminated pods are not counted")
+	assert.Equal(t, []string{
+		"1 selected pod(s) are not behind a Service and are not scraped",
+		"port admin is not declared by the containers of the selected pods",
+	}, status.Warnings)
+
+	// Restarted pods do not change the checks, that only reference the Services.
+	require.NoError(t, c.Delete(context.Background(), pod))
+	restarted := newPod("web-2", map[string]string{"app": "web", "tier": "front"}, metricsPort)
+	restarted.Status.PodIP = "10.0.0.13"
+	require.NoError(t, c.Create(context.Background(), restarted))
+	before := checkConfigs(t, c)
+	reconcileMonitor(t, r, "web")
+	assert.Equal(t, before, checkConfigs(t, c))
+}
+
+func TestPodCheckConfig(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	svc := newSelectingService("web", labels)
+	instance := checkInstance{port: "metrics", config: map[string]any{"openmetrics_endpoint": "http://%%host%%:%%port_metrics%%/metrics"}}
+
+	pods := []client.Object{
+		newPod("web-0", labels, corev1.ContainerPort{Name: "metrics", ContainerPort: 9090}),
+		newPod("web-1", labels, corev1.ContainerPort{Name: "metrics", ContainerPort: 9091}),
+	}
+	config, configured, warnings := podCheckConfig(pods, []client.Object{svc}, []checkInstance{instance})
+	require.NotNil(t, config)
+	assert.Equal(t, 2, configured)
+	assert.Equal(t, "http://%%host%%:9090/metrics", config.Instances[0]["openmetrics_endpoint"])
+	assert.Equal(t, []string{"port metrics has different numbers in the selected pods, 9090 is used"}, warnings)
+	assert.Equal(t, "http://%%host%%:%%port_metrics%%/metrics", instance.config["openmetrics_endpoint"], "the translated instance is not modified")
+
+	config, configured, warnings = podCheckConfig(pods, nil, []checkInstance{instance})
+	assert.Nil(t, config)
+	assert.Zero(t, configured)
+	assert.Equal(t, []string{"2 selected pod(s) are not behind a Service and are not scraped"}, warnings)
+}
+
+func TestReconcile_CheckConfigsTooLarge(t *testing.T) {
+	monitor := newMonitor(ServiceMonitorKind, "web", webServiceMonitorSpec)
+	other := newMonitor(ServiceMonitorKind, "other", webServiceMonitorSpec)
+	r, c, rec := newTestReconciler(ServiceMonitorKind, monitor, other, newService("web", map[string]string{"app": "web"}))
+	reconcileMonitor(t, r, "web")
+	<-rec.Events
+	r.maxDataSize = configMapDataSize(checkConfigs(t, c)) + 1
+
+	assert.Equal(t, ctrl.Result{RequeueAfter: defaultResyncPeriod}, reconcileMonitor(t, r, "other"))
+	configs := checkConfigs(t, c)
+	assert.Contains(t, configs, "servicemonitor_apps_web.yaml")
+	assert.NotContains(t, configs, "servicemonitor_apps_other.yaml")
+
+	status := monitorStatus(t, c, ServiceMonitorKind, "other")
+	assert.Equal(t, StateFailed, status.State)
+	assert.Contains(t, status.Message, "exceed the ConfigMap size limit")
+	assert.Contains(t, <-rec.Events, "TranslationFailed")
+}
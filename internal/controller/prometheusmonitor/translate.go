@@ -0,0 +1,360 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package prometheusmonitor
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// monitorSpec is the subset of the ServiceMonitor and PodMonitor specs used by
+// the translation. Unknown fields are ignored by the unstructured conversion.
+type monitorSpec struct {
+	Selector            metav1.LabelSelector `json:"selector"`
+	NamespaceSelector   namespaceSelector    `json:"namespaceSelector"`
+	Endpoints           []scrapeEndpoint     `json:"endpoints"`
+	PodMetricsEndpoints []scrapeEndpoint     `json:"podMetricsEndpoints"`
+}
+
+type namespaceSelector struct {
+	Any        bool     `json:"any"`
+	MatchNames []string `json:"matchNames"`
+}
+
+// scrapeEndpoint merges the ServiceMonitor Endpoint and PodMonitor
+// PodMetricsEndpoint fields.
+type scrapeEndpoint struct {
+	Port              string              `json:"port"`
+	PortNumber        *int32              `json:"portNumber"`
+	TargetPort        *intstr.IntOrString `json:"targetPort"`
+	Path              string              `json:"path"`
+	Scheme            string              `json:"scheme"`
+	Params            map[string][]string `json:"params"`
+	Interval          string              `json:"interval"`
+	ScrapeTimeout     string              `json:"scrapeTimeout"`
+	HonorLabels       bool                `json:"honorLabels"`
+	BearerTokenFile   string              `json:"bearerTokenFile"`
+	TLSConfig         *tlsConfig          `json:"tlsConfig"`
+	BasicAuth         map[string]any      `json:"basicAuth"`
+	Authorization     map[string]any      `json:"authorization"`
+	OAuth2            map[string]any      `json:"oauth2"`
+	BearerTokenSecret map[string]any      `json:"bearerTokenSecret"`
+	MetricRelabelings []relabelConfig     `json:"metricRelabelings"`
+	Relabelings       []relabelConfig     `json:"relabelings"`
+}
+
+type tlsConfig struct {
+	InsecureSkipVerify bool `json:"insecureSkipVerify"`
+}
+
+type relabelConfig struct {
+	SourceLabels []string `json:"sourceLabels"`
+	Separator    string   `json:"separator"`
+	TargetLabel  string   `json:"targetLabel"`
+	Regex        string   `json:"regex"`
+	Replacement  *string  `json:"replacement"`
+	Action       string   `json:"action"`
+}
+
+// endpoints returns the scrape endpoints of the monitor kind.
+func (s *monitorSpec) endpoints(kind string) []scrapeEndpoint {
+	if kind == PodMonitorKind {
+		return s.PodMetricsEndpoints
+	}
+	return s.Endpoints
+}
+
+// parseMonitorSpec converts the spec of an unstructured ServiceMonitor or PodMonitor.
+func parseMonitorSpec(obj *unstructured.Unstructured) (*monitorSpec, error) {
+	rawSpec, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("spec is missing")
+	}
+	spec := &monitorSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if len(spec.endpoints(obj.GetKind())) == 0 {
+		return nil, fmt.Errorf("no scrape endpoint is defined")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&spec.Selector); err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	return spec, nil
+}
+
+// checkInstance is an OpenMetrics check instance generated for a scrape
+// endpoint. port is the endpoint port name, resolved from the container ports
+// for a PodMonitor; it is empty for numeric ports.
+type checkInstance struct {
+	port   string
+	config map[string]any
+}
+
+// translateEndpoints builds one OpenMetrics instance per scrape endpoint. The
+// returned warnings list the endpoint settings that have no OpenMetrics
+// equivalent and were ignored or approximated.
+func translateEndpoints(kind, monitorName, metricNamespace string, endpoints []scrapeEndpoint) ([]checkInstance, []string) {
+	var instances []checkInstance
+	var warnings []string
+	for i, ep := range endpoints {
+		field := fmt.Sprintf("%s[%d]", endpointsField(kind), i)
+		instance, epWarnings, err := translateEndpoint(kind, monitorName, metricNamespace, ep)
+		for _, w := range epWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", field, w))
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: skipped: %v", field, err))
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	return instances, warnings
+}
+
+func endpointsField(kind string) string {
+	if kind == PodMonitorKind {
+		return "podMetricsEndpoints"
+	}
+	return "endpoints"
+}
+
+func translateEndpoint(kind, monitorName, metricNamespace string, ep scrapeEndpoint) (checkInstance, []string, error) {
+	var warnings []string
+
+	port, portName, err := endpointPort(ep)
+	if err != nil {
+		return checkInstance{}, nil, err
+	}
+	scheme := ep.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := ep.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	endpoint := scheme + "://%%host%%:" + port + path
+	if len(ep.Params) > 0 {
+		endpoint += "?" + url.Values(ep.Params).Encode()
+	}
+
+	filters, err := translateMetricRelabelings(ep.MetricRelabelings)
+	if err != nil {
+		return checkInstance{}, nil, err
+	}
+	warnings = append(warnings, filters.warnings...)
+	if filters.namespace != "" {
+		metricNamespace = filters.namespace
+	}
+	metrics := filters.include
+	if len(metrics) == 0 {
+		metrics = []string{".*"}
+	}
+
+	config := map[string]any{
+		"openmetrics_endpoint": endpoint,
+		"namespace":            metricNamespace,
+		"metrics":              metrics,
+		"tags":                 []string{fmt.Sprintf("%s:%s", monitorTagKey(kind), monitorName)},
+	}
+	if len(filters.exclude) > 0 {
+		config["exclude_metrics"] = filters.exclude
+	}
+	if len(filters.excludeLabels) > 0 {
+		config["exclude_labels"] = filters.excludeLabels
+	}
+	if ep.Interval != "" {
+		seconds, durErr := durationSeconds(ep.Interval)
+		if durErr != nil {
+			warnings = append(warnings, fmt.Sprintf("interval ignored: %v", durErr))
+		} else {
+			config["min_collection_interval"] = seconds
+		}
+	}
+	if ep.ScrapeTimeout != "" {
+		seconds, durErr := durationSeconds(ep.ScrapeTimeout)
+		if durErr != nil {
+			warnings = append(warnings, fmt.Sprintf("scrapeTimeout ignored: %v", durErr))
+		} else {
+			config["timeout"] = seconds
+		}
+	}
+	if ep.TLSConfig != nil && ep.TLSConfig.InsecureSkipVerify {
+		config["tls_verify"] = false
+	}
+	if ep.BearerTokenFile != "" {
+		config["auth_token"] = map[string]any{
+			"reader": map[string]any{"type": "file", "path": ep.BearerTokenFile},
+			"writer": map[string]any{"type": "header", "name": "Authorization", "value": "Bearer <TOKEN>"},
+		}
+	}
+
+	if ep.BasicAuth != nil || ep.Authorization != nil || ep.OAuth2 != nil || ep.BearerTokenSecret != nil {
+		warnings = append(warnings, "credentials read from Secrets are not translated")
+	}
+	if ep.HonorLabels {
+		warnings = append(warnings, "honorLabels has no OpenMetrics check equivalent")
+	}
+	if len(ep.Relabelings) > 0 {
+		warnings = append(warnings, "relabelings are not translated")
+	}
+
+	return checkInstance{port: portName, config: config}, warnings, nil
+}
+
+// endpointPort returns the port of the openmetrics_endpoint, as an
+// Autodiscovery template variable for named ports, and the port name.
+func endpointPort(ep scrapeEndpoint) (string, string, error) {
+	switch {
+	case ep.Port != "":
+		return portTemplateVariable(ep.Port), ep.Port, nil
+	case ep.PortNumber != nil:
+		return fmt.Sprintf("%d", *ep.PortNumber), "", nil
+	case ep.TargetPort != nil && ep.TargetPort.Type == intstr.String:
+		return portTemplateVariable(ep.TargetPort.StrVal), ep.TargetPort.StrVal, nil
+	case ep.TargetPort != nil:
+		return fmt.Sprintf("%d", ep.TargetPort.IntVal), "", nil
+	}
+	return "", "", fmt.Errorf("no port is defined")
+}
+
+func portTemplateVariable(name string) string {
+	return "%%port_" + name + "%%"
+}
+
+func monitorTagKey(kind string) string {
+	if kind == PodMonitorKind {
+		return "pod_monitor"
+	}
+	return "service_monitor"
+}
+
+// durationSeconds converts a Prometheus duration to a number of seconds,
+// rounded up to at least one second.
+func durationSeconds(raw string) (int64, error) {
+	d, err := parsePrometheusDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+	return max(1, int64(math.Ceil(d.Seconds()))), nil
+}
+
+var prometheusDurationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+var prometheusDurationRegexp = regexp.MustCompile(`^(\d+)(ms|s|m|h|d|w|y)`)
+
+// parsePrometheusDuration parses durations such as 30s or 1m30s, which also
+// accept the d, w and y units unlike time.ParseDuration.
+func parsePrometheusDuration(raw string) (time.Duration, error) {
+	rest := raw
+	var total time.Duration
+	for rest != "" {
+		match := prometheusDurationRegexp.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		var n int64
+		if _, err := fmt.Sscanf(match[1], "%d", &n); err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", raw, err)
+		}
+		total += time.Duration(n) * prometheusDurationUnits[match[2]]
+		rest = rest[len(match[0]):]
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return total, nil
+}
+
+// metricFilters is the translation of metricRelabelings to OpenMetrics check
+// options.
+type metricFilters struct {
+	include       []string
+	exclude       []string
+	excludeLabels []string
+	namespace     string
+	warnings      []string
+}
+
+// prefixReplacementRegexp matches a replacement adding a prefix to the
+// metric name, such as myapp_$1 or myapp_${1}.
+var prefixReplacementRegexp = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*?)_?\$(1|\{1\})$`)
+
+// translateMetricRelabelings maps the metricRelabelings that filter or
+// prefix metric names to the metrics, exclude_metrics, exclude_labels and
+// namespace options. Other relabelings are reported as warnings. An error is
+// returned when a filter regex is invalid, as the instance would be rejected
+// by the check.
+func translateMetricRelabelings(relabelings []relabelConfig) (metricFilters, error) {
+	var f metricFilters
+	for i, rc := range relabelings {
+		field := fmt.Sprintf("metricRelabelings[%d]", i)
+		action := strings.ToLower(rc.Action)
+		if action == "" {
+			action = "replace"
+		}
+		regex := rc.Regex
+		if regex == "" {
+			regex = "(.*)"
+		}
+		onName := len(rc.SourceLabels) == 1 && rc.SourceLabels[0] == "__name__"
+
+		switch {
+		case (action == "keep" || action == "drop") && onName:
+			if _, err := regexp.Compile(regex); err != nil {
+				return f, fmt.Errorf("%s: invalid regex %q: %w", field, regex, err)
+			}
+			if action == "keep" {
+				f.include = append(f.include, regex)
+			} else {
+				f.exclude = append(f.exclude, regex)
+			}
+		case action == "labeldrop" && regexp.QuoteMeta(regex) == regex:
+			f.excludeLabels = append(f.excludeLabels, regex)
+		case action == "replace" && onName && rc.TargetLabel == "__name__" && regex == "(.*)" && rc.Replacement != nil &&
+			prefixReplacementRegexp.MatchString(*rc.Replacement):
+			f.namespace = prefixReplacementRegexp.FindStringSubmatch(*rc.Replacement)[1]
+		default:
+			f.warnings = append(f.warnings, fmt.Sprintf("%s: %s relabeling is not translated", field, action))
+		}
+	}
+	if len(f.include) > 1 {
+		f.warnings = append(f.warnings, "several keep relabelings are translated as a union of metric filters")
+	}
+	return f, nil
+}
+
+var invalidNamespaceChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// metricNamespace returns the OpenMetrics namespace of a monitor: the
+// MetricNamespaceAnnotationKey annotation when set, the monitor name otherwise.
+func metricNamespace(obj *unstructured.Unstructured) string {
+	if ns := obj.GetAnnotations()[MetricNamespaceAnnotationKey]; ns != "" {
+		return ns
+	}
+	return invalidNamespaceChars.ReplaceAllString(obj.GetName(), "_")
+}
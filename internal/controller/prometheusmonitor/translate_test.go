@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package prometheusmonitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestParseMonitorSpec(t *testing.T) {
+	obj := NewMonitorObject(ServiceMonitorKind)
+	obj.Object["spec"] = map[string]any{
+		"selector":          map[string]any{"matchLabels": map[string]any{"app": "web"}},
+		"namespaceSelector": map[string]any{"matchNames": []any{"a", "b"}},
+		"endpoints": []any{
+			map[string]any{"port": "metrics", "interval": "30s", "unknownField": true},
+		},
+	}
+	spec, err := parseMonitorSpec(obj)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "web"}, spec.Selector.MatchLabels)
+	assert.Equal(t, []string{"a", "b"}, spec.NamespaceSelector.MatchNames)
+	require.Len(t, spec.endpoints(ServiceMonitorKind), 1)
+	assert.Equal(t, "metrics", spec.Endpoints[0].Port)
+
+	_, err = parseMonitorSpec(NewMonitorObject(ServiceMonitorKind))
+	assert.ErrorContains(t, err, "spec is missing")
+
+	podMonitor := NewMonitorObject(PodMonitorKind)
+	podMonitor.Object["spec"] = map[string]any{"endpoints": []any{map[string]any{"port": "metrics"}}}
+	_, err = parseMonitorSpec(podMonitor)
+	assert.ErrorContains(t, err, "no scrape endpoint", "PodMonitors use podMetricsEndpoints")
+
+	invalidSelector := NewMonitorObject(ServiceMonitorKind)
+	invalidSelector.Object["spec"] = map[string]any{
+		"selector": map[string]any{"matchExpressions": []any{
+			map[string]any{"key": "app", "operator": "Equals", "values": []any{"web"}},
+		}},
+		"endpoints": []any{map[string]any{"port": "metrics"}},
+	}
+	_, err = parseMonitorSpec(invalidSelector)
+	assert.ErrorContains(t, err, "invalid selector")
+}
+
+func TestTranslateEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		endpoint     scrapeEndpoint
+		wantPort     string
+		wantConfig   map[string]any
+		wantWarnings []string
+		wantErr      string
+	}{
+		{
+			name:     "named port with defaults",
+			kind:     ServiceMonitorKind,
+			endpoint: scrapeEndpoint{Port: "metrics"},
+			wantPort: "metrics",
+			wantConfig: map[string]any{
+				"openmetrics_endpoint": "http://%%host%%:%%port_metrics%%/metrics",
+				"namespace":            "web",
+				"metrics":              []string{".*"},
+				"tags":                 []string{"service_monitor:web"},
+			},
+		},
+		{
+			name: "scrape options",
+			kind: PodMonitorKind,
+			endpoint: scrapeEndpoint{
+				PortNumber:      ptr.To[int32](9100),
+				Path:            "/stats",
+				Scheme:          "https",
+				Params:          map[string][]string{"format": {"prometheus"}},
+				Interval:        "1m30s",
+				ScrapeTimeout:   "500ms",
+				TLSConfig:       &tlsConfig{InsecureSkipVerify: true},
+				BearerTokenFile: "/var/run/token",
+			},
+			wantConfig: map[string]any{
+				"openmetrics_endpoint":    "https://%%host%%:9100/stats?format=prometheus",
+				"namespace":               "web",
+				"metrics":                 []string{".*"},
+				"tags":                    []string{"pod_monitor:web"},
+				"min_collection_interval": int64(90),
+				"timeout":                 int64(1),
+				"tls_verify":              false,
+				"auth_token": map[string]any{
+					"reader": map[string]any{"type": "file", "path": "/var/run/token"},
+					"writer": map[string]any{"type": "header", "name": "Authorization", "value": "Bearer <TOKEN>"},
+				},
+			},
+		},
+		{
+			name:     "numeric target port",
+			kind:     ServiceMonitorKind,
+			endpoint: scrapeEndpoint{TargetPort: ptr.To(intstr.FromInt32(8080))},
+			wantConfig: map[string]any{
+				"openmetrics_endpoint": "http://%%host%%:8080/metrics",
+				"namespace":            "web",
+				"metrics":              []string{".*"},
+				"tags":                 []string{"service_monitor:web"},
+			},
+		},
+		{
+			name: "unsupported settings",
+			kind: ServiceMonitorKind,
+			endpoint: scrapeEndpoint{
+				Port:        "metrics",
+				HonorLabels: true,
+				BasicAuth:   map[string]any{"username": map[string]any{"name": "secret"}},
+				Relabelings: []relabelConfig{{Action: "replace"}},
+			},
+			wantPort: "metrics",
+			wantConfig: map[string]any{
+				"openmetrics_endpoint": "http://%%host%%:%%port_metrics%%/metrics",
+				"namespace":            "web",
+				"metrics":              []string{".*"},
+				"tags":                 []string{"service_monitor:web"},
+			},
+			wantWarnings: []string{
+				"credentials read from Secrets are not translated",
+				"honorLabels has no OpenMetrics check equivalent",
+				"relabelings are not translated",
+			},
+		},
+		{
+			name:     "no port",
+			kind:     ServiceMonitorKind,
+			endpoint: scrapeEndpoint{Path: "/metrics"},
+			wantErr:  "no port is defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, warnings, err := translateEndpoint(tt.kind, "web", "web", tt.endpoint)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPort, instance.port)
+			assert.Equal(t, tt.wantConfig, instance.config)
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestTranslateMetricRelabelings(t *testing.T) {
+	relabelings := []relabelConfig{
+		{Action: "keep", SourceLabels: []string{"__name__"}, Regex: "http_.*"},
+		{Action: "drop", SourceLabels: []string{"__name__"}, Regex: "http_debug_.*"},
+		{Action: "labeldrop", Regex: "pod_template_hash"},
+		{Action: "labeldrop", Regex: "tmp_.*"},
+		{SourceLabels: []string{"__name__"}, TargetLabel: "__name__", Replacement: ptr.To("myapp_$1")},
+		{Action: "hashmod", SourceLabels: []string{"instance"}, TargetLabel: "shard"},
+	}
+	f, err := translateMetricRelabelings(relabelings)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http_.*"}, f.include)
+	assert.Equal(t, []string{"http_debug_.*"}, f.exclude)
+	assert.Equal(t, []string{"pod_template_hash"}, f.excludeLabels)
+	assert.Equal(t, "myapp", f.namespace)
+	assert.Equal(t, []string{
+		"metricRelabelings[3]: labeldrop relabeling is not translated",
+		"metricRelabelings[5]: hashmod relabeling is not translated",
+	}, f.warnings)
+
+	_, err = translateMetricRelabelings([]relabelConfig{{Action: "keep", SourceLabels: []string{"__name__"}, Regex: "("}})
+	assert.ErrorContains(t, err, "metricRelabelings[0]: invalid regex")
+}
+
+func TestTranslateEndpoints_SkipsInvalidEndpoints(t *testing.T) {
+	instances, warnings := translateEndpoints(PodMonitorKind, "web", "web", []scrapeEndpoint{
+		{Port: "metrics"},
+		{Path: "/metrics"},
+	})
+	require.Len(t, instances, 1)
+	assert.Equal(t, []string{"podMetricsEndpoints[1]: skipped: no port is defined"}, warnings)
+}
+
+func TestParsePrometheusDuration(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"30s":    30 * time.Second,
+		"1m30s":  90 * time.Second,
+		"1d":     24 * time.Hour,
+		"250ms":  250 * time.Millisecond,
+		"1h5m2s": time.Hour + 5*time.Minute + 2*time.Second,
+	} {
+		got, err := parsePrometheusDuration(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+	for _, raw := range []string{"", "0s", "30", "1.5s", "1m-"} {
+		_, err := parsePrometheusDuration(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestMetricNamespace(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetName("web-frontend.v2")
+	assert.Equal(t, "web_frontend_v2", metricNamespace(obj))
+
+	obj.SetAnnotations(map[string]string{MetricNamespaceAnnotationKey: "frontend"})
+	assert.Equal(t, "frontend", metricNamespace(obj))
+}
@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/DataDog/datadog-operator/internal/controller/prometheusmonitor"
+)
+
+// PrometheusMonitorReconciler translates Prometheus Operator ServiceMonitors and
+// PodMonitors into OpenMetrics cluster checks, written to a ConfigMap. A controller
+// is started for each kind whose CRD is installed in the cluster.
+type PrometheusMonitorReconciler struct {
+	Client    client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	ConfigMap types.NamespacedName
+}
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=services;pods,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
+
+// SetupWithManager sets up a controller for each installed monitor CRD.
+func (r *PrometheusMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for _, kind := range []string{prometheusmonitor.ServiceMonitorKind, prometheusmonitor.PodMonitorKind} {
+		gvk := prometheusmonitor.GroupVersionKind(kind)
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				r.Log.Info("CRD not installed, not translating monitors", "kind", kind)
+				continue
+			}
+			return err
+		}
+
+		reconciler := prometheusmonitor.NewReconciler(r.Client, r.APIReader, r.Log.WithName(kind), r.Recorder, kind, r.ConfigMap)
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named(kind+"Translation").
+			// The metric namespace of a monitor is set by annotation.
+			For(prometheusmonitor.NewMonitorObject(kind), builder.WithPredicates(
+				predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+			)).
+			Complete(reconciler)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
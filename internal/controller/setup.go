@@ -7,10 +7,12 @@ package controller
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,46 +20,79 @@ import (
 
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagent"
 	componentagent "github.com/DataDog/datadog-operator/internal/controller/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogagent/recommender"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogagentinternal"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogmonitor"
+	"github.com/DataDog/datadog-operator/internal/controller/datadogtemplate"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/sharding"
 )
 
 const (
-	agentControllerName           = "DatadogAgent"
-	agentInternalControllerName   = "DatadogAgentInternal"
-	monitorControllerName         = "DatadogMonitor"
-	sloControllerName             = "DatadogSLO"
-	profileControllerName         = "DatadogAgentProfile"
-	dashboardControllerName       = "DatadogDashboard"
-	genericResourceControllerName = "DatadogGenericResource"
-	csiDriverControllerName       = "DatadogCSIDriver"
+	agentControllerName             = "DatadogAgent"
+	agentInternalControllerName     = "DatadogAgentInternal"
+	monitorControllerName           = "DatadogMonitor"
+	sloControllerName               = "DatadogSLO"
+	profileControllerName           = "DatadogAgentProfile"
+	dashboardControllerName         = "DatadogDashboard"
+	genericResourceControllerName   = "DatadogGenericResource"
+	csiDriverControllerName         = "DatadogCSIDriver"
+	prometheusMonitorControllerName = "PrometheusMonitorTranslation"
+	monitorTemplateControllerName   = "DatadogMonitorTemplate"
+	sloTemplateControllerName       = "DatadogSLOTemplate"
 )
 
 // SetupOptions defines options for setting up controllers to ease testing
 type SetupOptions struct {
-	SupportExtendedDaemonset          ExtendedDaemonsetOptions
-	SupportCilium                     bool
-	CredsManager                      *config.CredentialManager
-	DatadogAgentEnabled               bool
-	DatadogMonitorEnabled             bool
-	DatadogSLOEnabled                 bool
-	OperatorMetricsEnabled            bool
-	V2APIEnabled                      bool
-	IntrospectionEnabled              bool
-	DatadogAgentProfileEnabled        bool
-	OtelAgentEnabled                  bool
-	DatadogDashboardEnabled           bool
-	DatadogGenericResourceEnabled     bool
-	DatadogGenericResourceMaxWorkers  int
-	DatadogGenericResourceRequeue     time.Duration
-	CreateControllerRevisions         bool
-	DatadogCSIDriverEnabled           bool
-	UntaintControllerEnabled          bool
-	UntaintControllerWaitForCSIDriver bool
-	RolloutOnConfigMapChangeEnabled   bool
-	ClusterProviderDetector           datadogagent.ProviderReader
+	SupportExtendedDaemonset            ExtendedDaemonsetOptions
+	SupportCilium                       bool
+	CredsManager                        *config.CredentialManager
+	DatadogAgentEnabled                 bool
+	DatadogMonitorEnabled               bool
+	DatadogSLOEnabled                   bool
+	DatadogTemplateEnabled              bool
+	OperatorMetricsEnabled              bool
+	V2APIEnabled                        bool
+	IntrospectionEnabled                bool
+	DatadogAgentProfileEnabled          bool
+	OtelAgentEnabled                    bool
+	DatadogDashboardEnabled             bool
+	DatadogGenericResourceEnabled       bool
+	DatadogGenericResourceMaxWorkers    int
+	DatadogGenericResourceRequeue       time.Duration
+	CreateControllerRevisions           bool
+	DatadogCSIDriverEnabled             bool
+	UntaintControllerEnabled            bool
+	UntaintControllerWaitForCSIDriver   bool
+	RolloutOnConfigMapChangeEnabled     bool
+	PrometheusMonitorTranslationEnabled bool
+	ClusterProviderDetector             datadogagent.ProviderReader
+	// PrometheusMonitorConfigMap is the ConfigMap the checks translated from the
+	// Prometheus Operator monitors are written to.
+	PrometheusMonitorConfigMap types.NamespacedName
+	// WatchNamespaces are the namespaces the operator is restricted to in namespace-scoped mode.
+	WatchNamespaces []string
+	// ReconcileShards is the number of shards the DatadogMonitors and DatadogGenericResources are
+	// spread across, between the replicas of the operator. Sharding is disabled below 2.
+	ReconcileShards int
+	// ShardLeaseNamespace is the namespace of the shard Leases.
+	ShardLeaseNamespace string
+	// MonitorWebhookBindAddress is the address of the receiver of the Datadog webhook
+	// notifications updating the state of the DatadogMonitors. Empty disables the receiver.
+	MonitorWebhookBindAddress string
+	// MonitorWebhookSecret is the secret shared with the Datadog webhook.
+	MonitorWebhookSecret string
+	// MonitorWorkloadEventsEnabled enables the events on the Kubernetes objects named by the
+	// tags of the triggered DatadogMonitor groups.
+	MonitorWorkloadEventsEnabled bool
+	// MonitorWorkloadEventsAllNamespaces records these events on the objects of all the namespaces
+	// and on the nodes, instead of only on the objects of the namespace of the DatadogMonitor.
+	MonitorWorkloadEventsAllNamespaces bool
+
+	sharder          sharding.Sharder
+	workloadNotifier *datadogmonitor.WorkloadNotifier
 }
 
 // ExtendedDaemonsetOptions defines ExtendedDaemonset options
@@ -79,15 +114,18 @@ type ExtendedDaemonsetOptions struct {
 type starterFunc func(logr.Logger, manager.Manager, kubernetes.PlatformInfo, SetupOptions, datadog.MetricsForwardersManager) error
 
 var controllerStarters = map[string]starterFunc{
-	agentControllerName:           startDatadogAgent,
-	agentInternalControllerName:   startDatadogAgentInternal,
-	monitorControllerName:         startDatadogMonitor,
-	sloControllerName:             startDatadogSLO,
-	profileControllerName:         startDatadogAgentProfiles,
-	dashboardControllerName:       startDatadogDashboard,
-	genericResourceControllerName: startDatadogGenericResource,
-	csiDriverControllerName:       startDatadogCSIDriver,
-	untaintControllerName:         startUntaint,
+	agentControllerName:             startDatadogAgent,
+	agentInternalControllerName:     startDatadogAgentInternal,
+	monitorControllerName:           startDatadogMonitor,
+	sloControllerName:               startDatadogSLO,
+	profileControllerName:           startDatadogAgentProfiles,
+	dashboardControllerName:         startDatadogDashboard,
+	genericResourceControllerName:   startDatadogGenericResource,
+	csiDriverControllerName:         startDatadogCSIDriver,
+	untaintControllerName:           startUntaint,
+	prometheusMonitorControllerName: startPrometheusMonitorTranslation,
+	monitorTemplateControllerName:   startDatadogMonitorTemplate,
+	sloTemplateControllerName:       startDatadogSLOTemplate,
 }
 
 // SetupControllers starts all controllers (also used by e2e tests)
@@ -98,12 +136,31 @@ func SetupControllers(logger logr.Logger, mgr manager.Manager, platformInfo kube
 		metricForwardersMgr = datadog.NewForwardersManager(mgr.GetClient(), &platformInfo, options.CredsManager)
 	}
 
+	sharder, err := setupSharding(logger, mgr, options)
+	if err != nil {
+		return fmt.Errorf("unable to setup sharding: %w", err)
+	}
+	options.sharder = sharder
+	if options.DatadogMonitorEnabled && options.MonitorWorkloadEventsEnabled {
+		options.workloadNotifier = datadogmonitor.NewWorkloadNotifier(
+			mgr.GetAPIReader(),
+			mgr.GetEventRecorderFor(monitorControllerName),
+			ctrl.Log.WithName("controllers").WithName(monitorControllerName).WithName("workloads"),
+			options.WatchNamespaces,
+			options.MonitorWorkloadEventsAllNamespaces,
+		)
+	}
+
 	for controller, starter := range controllerStarters {
 		if err := starter(logger, mgr, platformInfo, options, metricForwardersMgr); err != nil {
 			logger.Error(err, "Couldn't start controller", "controller", controller)
 		}
 	}
 
+	if err := setupMonitorStateRefresher(logger, mgr, options); err != nil {
+		return fmt.Errorf("unable to setup the monitor state refresher: %w", err)
+	}
+
 	return nil
 }
 
@@ -114,6 +171,11 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, pInfo kubernetes
 		return nil
 	}
 
+	var downtimeClient datadogagent.DowntimeClient
+	if options.CredsManager != nil {
+		downtimeClient = datadogagent.NewDowntimeClient(options.CredsManager)
+	}
+
 	return (&DatadogAgentReconciler{
 		Client:       mgr.GetClient(),
 		PlatformInfo: pInfo,
@@ -142,6 +204,8 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, pInfo kubernetes
 			DatadogCSIDriverEnabled:    options.DatadogCSIDriverEnabled,
 			CreateControllerRevisions:  options.CreateControllerRevisions,
 			ClusterProviderDetector:    options.ClusterProviderDetector,
+			DowntimeClient:             downtimeClient,
+			ResourceUsageSource:        recommender.NewMetricsUsageSource(mgr.GetClient()),
 		},
 	}).SetupWithManager(mgr, metricForwardersMgr)
 }
@@ -196,10 +260,56 @@ func startDatadogMonitor(logger logr.Logger, mgr manager.Manager, pInfo kubernet
 		Log:                    ctrl.Log.WithName("controllers").WithName(monitorControllerName),
 		Scheme:                 mgr.GetScheme(),
 		Recorder:               mgr.GetEventRecorderFor(monitorControllerName),
+		Sharder:                options.sharder,
+		WorkloadNotifier:       options.workloadNotifier,
 		operatorMetricsEnabled: options.OperatorMetricsEnabled,
 	}
 
-	return monitorReconciler.SetupWithManager(mgr, metricForwardersMgr)
+	if err := monitorReconciler.SetupWithManager(mgr, metricForwardersMgr); err != nil {
+		return err
+	}
+
+	if options.MonitorWebhookBindAddress == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle(datadogmonitor.WebhookPath, datadogmonitor.NewWebhookReceiver(
+		mgr.GetClient(),
+		mgr.GetEventRecorderFor(monitorControllerName),
+		options.workloadNotifier,
+		ctrl.Log.WithName("controllers").WithName(monitorControllerName).WithName("webhook"),
+		options.MonitorWebhookSecret,
+	))
+	// Notifications can reach any replica: the receiver runs without leader election.
+	return mgr.Add(&manager.Server{
+		Name: "datadogmonitor-webhook",
+		Server: &http.Server{
+			Addr:              options.MonitorWebhookBindAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	})
+}
+
+func startDatadogMonitorTemplate(logger logr.Logger, mgr manager.Manager, _ kubernetes.PlatformInfo, options SetupOptions, _ datadog.MetricsForwardersManager) error {
+	if !options.DatadogMonitorEnabled || !options.DatadogTemplateEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", monitorTemplateControllerName)
+		return nil
+	}
+	if len(options.WatchNamespaces) > 0 {
+		// Templates select their target namespaces cluster-wide.
+		logger.Info("Not supported in namespace-scoped mode, not starting the controller", "controller", monitorTemplateControllerName)
+		return nil
+	}
+
+	return (&DatadogTemplateReconciler{
+		Client:     mgr.GetClient(),
+		APIReader:  mgr.GetAPIReader(),
+		Log:        ctrl.Log.WithName("controllers").WithName(monitorTemplateControllerName),
+		Recorder:   mgr.GetEventRecorderFor(monitorTemplateControllerName),
+		Kind:       datadogtemplate.MonitorKind,
+		Namespaces: config.MonitorWatchNamespaces(logger),
+	}).SetupWithManager(mgr)
 }
 
 func startDatadogDashboard(logger logr.Logger, mgr manager.Manager, pInfo kubernetes.PlatformInfo, options SetupOptions, metricForwardersMgr datadog.MetricsForwardersManager) error {
@@ -231,6 +341,7 @@ func startDatadogGenericResource(logger logr.Logger, mgr manager.Manager, pInfo
 		Log:          ctrl.Log.WithName("controllers").WithName(genericResourceControllerName),
 		Scheme:       mgr.GetScheme(),
 		Recorder:     mgr.GetEventRecorderFor(genericResourceControllerName),
+		Sharder:      options.sharder,
 		Options: DatadogGenericResourceReconcilerOptions{
 			MaxConcurrentReconciles: options.DatadogGenericResourceMaxWorkers,
 			RequeuePeriod:           options.DatadogGenericResourceRequeue,
@@ -257,6 +368,27 @@ func startDatadogSLO(logger logr.Logger, mgr manager.Manager, pInfo kubernetes.P
 	return sloReconciler.SetupWithManager(mgr)
 }
 
+func startDatadogSLOTemplate(logger logr.Logger, mgr manager.Manager, _ kubernetes.PlatformInfo, options SetupOptions, _ datadog.MetricsForwardersManager) error {
+	if !options.DatadogSLOEnabled || !options.DatadogTemplateEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", sloTemplateControllerName)
+		return nil
+	}
+	if len(options.WatchNamespaces) > 0 {
+		// Templates select their target namespaces cluster-wide.
+		logger.Info("Not supported in namespace-scoped mode, not starting the controller", "controller", sloTemplateControllerName)
+		return nil
+	}
+
+	return (&DatadogTemplateReconciler{
+		Client:     mgr.GetClient(),
+		APIReader:  mgr.GetAPIReader(),
+		Log:        ctrl.Log.WithName("controllers").WithName(sloTemplateControllerName),
+		Recorder:   mgr.GetEventRecorderFor(sloTemplateControllerName),
+		Kind:       datadogtemplate.SLOKind,
+		Namespaces: config.SLOWatchNamespaces(logger),
+	}).SetupWithManager(mgr)
+}
+
 func startUntaint(logger logr.Logger, mgr manager.Manager, _ kubernetes.PlatformInfo, options SetupOptions, _ datadog.MetricsForwardersManager) error {
 	if !options.UntaintControllerEnabled {
 		logger.Info("Feature disabled, not starting the controller", "controller", untaintControllerName)
@@ -271,7 +403,37 @@ func startUntaint(logger logr.Logger, mgr manager.Manager, _ kubernetes.Platform
 	if err != nil {
 		return fmt.Errorf("untaint controller setup: %w", err)
 	}
-	return reconciler.SetupWithManager(mgr)
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	if !startupTaintEnabled() {
+		return nil
+	}
+	startupTaint, err := NewStartupTaintReconciler(
+		mgr.GetClient(),
+		ctrl.Log.WithName("controllers").WithName(startupTaintControllerName),
+		mgr.GetEventRecorderFor(startupTaintControllerName),
+	)
+	if err != nil {
+		return fmt.Errorf("untaint controller startup taint setup: %w", err)
+	}
+	return startupTaint.SetupWithManager(mgr)
+}
+
+func startPrometheusMonitorTranslation(logger logr.Logger, mgr manager.Manager, _ kubernetes.PlatformInfo, options SetupOptions, _ datadog.MetricsForwardersManager) error {
+	if !options.PrometheusMonitorTranslationEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", prometheusMonitorControllerName)
+		return nil
+	}
+
+	return (&PrometheusMonitorReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName(prometheusMonitorControllerName),
+		Recorder:  mgr.GetEventRecorderFor(prometheusMonitorControllerName),
+		ConfigMap: options.PrometheusMonitorConfigMap,
+	}).SetupWithManager(mgr)
 }
 
 func startDatadogAgentProfiles(logger logr.Logger, mgr manager.Manager, pInfo kubernetes.PlatformInfo, options SetupOptions, metricForwardersMgr datadog.MetricsForwardersManager) error {
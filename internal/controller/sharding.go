@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/DataDog/datadog-operator/pkg/sharding"
+)
+
+const (
+	shardLeaseGroup = "datadog-operator"
+	// unownedRequeuePeriod is the period at which the objects of the shards held by other
+	// replicas are requeued, so that they are reconciled soon after their shard is acquired.
+	unownedRequeuePeriod = 60 * time.Second
+)
+
+// setupSharding starts the manager of the shard Leases when the reconciliation is sharded
+// across the replicas of the operator.
+func setupSharding(logger logr.Logger, mgr manager.Manager, options SetupOptions) (sharding.Sharder, error) {
+	if options.ReconcileShards <= 1 || (!options.DatadogMonitorEnabled && !options.DatadogGenericResourceEnabled) {
+		return nil, nil
+	}
+	leaseManager := sharding.NewLeaseManager(mgr.GetClient(), mgr.GetAPIReader(), logger.WithName("sharding"), sharding.LeaseManagerOptions{
+		Namespace: options.ShardLeaseNamespace,
+		Group:     shardLeaseGroup,
+		Identity:  sharding.NewIdentity(),
+		Shards:    options.ReconcileShards,
+	})
+	if err := mgr.Add(leaseManager); err != nil {
+		return nil, err
+	}
+	return leaseManager, nil
+}
+
+// reconcileIfOwned reconciles the object only if its shard is held by the replica.
+func reconcileIfOwned(sharder sharding.Sharder, obj client.Object, reconcile func() (ctrl.Result, error)) (ctrl.Result, error) {
+	if sharder != nil && !sharder.Owns(obj) {
+		return ctrl.Result{RequeueAfter: unownedRequeuePeriod}, nil
+	}
+	return reconcile()
+}
+
+// shardedControllerOptions returns the options of a sharded controller: it runs on all
+// the replicas, each one reconciling the objects of the shards it holds.
+func shardedControllerOptions(sharder sharding.Sharder, options ctrlcontroller.Options) ctrlcontroller.Options {
+	if sharder != nil {
+		options.NeedLeaderElection = ptr.To(false)
+	}
+	return options
+}
@@ -182,7 +182,8 @@ func durationFromEnv(envVar string, def time.Duration) (time.Duration, error) {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile decides what to do with a tainted node:
-//   - by default: if any agent pod on the node is Ready, untaint
+//   - by default: if any agent pod on the node is Ready and satisfies the
+//     untaint readiness rules of its DatadogAgent, untaint
 //   - with --untaintControllerWaitForCSIDriver: agent and CSI node-server pods
 //     must both be Ready before untaint
 //   - if pods exist but readiness criteria are not met and the readiness timeout
@@ -238,10 +239,10 @@ func (r *UntaintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			return ctrl.Result{}, fmt.Errorf("failed to list CSI driver pods on node %s: %w", req.Name, err)
 		}
 
-		agentReady := slices.ContainsFunc(podList.Items, func(p corev1.Pod) bool {
-			_, ok := podReadyTransition(&p)
-			return ok
-		})
+		_, agentReady, blocked, err := r.readyAgentPod(ctx, podList.Items)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 		csiReady := len(csiPodList.Items) > 0 && slices.ContainsFunc(csiPodList.Items, func(p corev1.Pod) bool {
 			_, ok := podReadyTransition(&p)
 			return ok
@@ -256,26 +257,27 @@ func (r *UntaintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				"Removed taint %s from node %s after node agent and CSI node-server pods became ready",
 			)
 		}
-		return r.reconcileTaintedNodeTimeouts(ctx, node, log, podList, csiPodList)
-	} else {
-		// Agent-only mode: untaint when any node-agent pod is Ready; otherwise timeouts below.
-		var readyAt time.Time
-		var anyReady bool
-		for i := range podList.Items {
-			if ts, ok := podReadyTransition(&podList.Items[i]); ok {
-				readyAt, anyReady = ts, true
-				break
-			}
-		}
-		if anyReady {
-			return r.completeUntaintFromReadiness(ctx, node, log, readyAt,
-				fmt.Sprintf("Removed agent-not-ready taint from node %s", node.Name),
-				"Removed taint %s from node %s after agent became ready",
-			)
-		}
+		r.reportBlockedRules(node, log, blocked)
+		result, err := r.reconcileTaintedNodeTimeouts(ctx, node, log, podList, csiPodList)
+		return requeueForBlockedRules(result, blocked), err
+	}
+
+	// Agent-only mode: untaint when any node-agent pod is Ready and satisfies
+	// the readiness rules of its DatadogAgent; otherwise timeouts below.
+	readyAt, anyReady, blocked, err := r.readyAgentPod(ctx, podList.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if anyReady {
+		return r.completeUntaintFromReadiness(ctx, node, log, readyAt,
+			fmt.Sprintf("Removed agent-not-ready taint from node %s", node.Name),
+			"Removed taint %s from node %s after agent became ready",
+		)
 	}
 
-	return r.reconcileTaintedNodeTimeouts(ctx, node, log, podList, nil)
+	r.reportBlockedRules(node, log, blocked)
+	result, err := r.reconcileTaintedNodeTimeouts(ctx, node, log, podList, nil)
+	return requeueForBlockedRules(result, blocked), err
 }
 
 // completeUntaintFromReadiness runs removeTaint after readiness gates passed, then
@@ -514,6 +516,7 @@ func (r *UntaintReconciler) removeTaint(ctx context.Context, node *corev1.Node)
 		metrics.TaintRemovalErrorsTotal.Inc()
 		return ctrl.Result{}, fmt.Errorf("failed to remove taint from node %s: %w", node.Name, err)
 	}
+	metrics.DeleteReadinessRuleSeries(node.Name)
 	return ctrl.Result{}, nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/internal/controller/datadogcsidriver"
 	"github.com/DataDog/datadog-operator/internal/controller/metrics"
 	"github.com/DataDog/datadog-operator/pkg/constants"
@@ -135,6 +137,7 @@ func newFakeClient(t *testing.T, objs ...client.Object) client.WithWatch {
 	t.Helper()
 	s := runtime.NewScheme()
 	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, v2alpha1.AddToScheme(s))
 	return fake.NewClientBuilder().
 		WithScheme(s).
 		WithObjects(objs...).
@@ -906,6 +909,7 @@ func TestPodWatchPredicate_withCSI(t *testing.T) {
 func resetUntaintMetrics() {
 	metrics.TaintRemovalsTotal.Reset()
 	metrics.TaintRemovalLatency.Reset()
+	metrics.ReadinessRuleBlocking.Reset()
 }
 
 func TestReconcile_PodReady_RecordsLabeledMetrics(t *testing.T) {
@@ -985,3 +989,253 @@ func TestReconcile_NodeDeleted_CleansUpPerNodeSeries(t *testing.T) {
 		metrics.TaintRemovalsTotal.WithLabelValues(liveNode, metrics.UntaintRemovalReasonAgentReady)),
 		"liveNode's counter value must survive another node's deletion")
 }
+
+// -----------------------------------------------------------------------------
+// DatadogAgent readiness rules
+// -----------------------------------------------------------------------------
+
+const testDDAName = "datadog"
+
+func ddaWithReadinessRules(rules ...v2alpha1.UntaintReadinessRule) *v2alpha1.DatadogAgent {
+	return &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: testDDAName, Namespace: testPodNS},
+		Spec: v2alpha1.DatadogAgentSpec{
+			Global: &v2alpha1.GlobalConfig{
+				Untaint: &v2alpha1.UntaintConfig{ReadinessRules: rules},
+			},
+		},
+	}
+}
+
+// ddaAgentPod returns a Ready agent pod of the test DatadogAgent, Ready since
+// readyAgo, running the given containers with their Ready status.
+func ddaAgentPod(readyAgo time.Duration, now time.Time, containers map[common.AgentContainerName]bool) *corev1.Pod {
+	pod := agentPod(testPodName, testPodNS, testNodeName, true, readyAgo, now)
+	pod.Labels[common.AgentDeploymentNameLabelKey] = testDDAName
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-readyAgo))
+	for name, ready := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: string(name)})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: string(name), Ready: ready})
+	}
+	return pod
+}
+
+func TestEvaluateReadinessRules(t *testing.T) {
+	now := testNow()
+	socketRule := v2alpha1.UntaintReadinessRule{
+		Name: "apm-socket",
+		HostPathSocket: &v2alpha1.UntaintHostPathSocket{
+			Container: common.TraceAgentContainerName,
+			Path:      "/var/run/datadog/apm.socket",
+		},
+	}
+
+	cases := []struct {
+		name          string
+		containers    map[common.AgentContainerName]bool
+		rules         []v2alpha1.UntaintReadinessRule
+		expectReasons []string
+		expectRequeue time.Duration
+	}{
+		{
+			name:       "no rules",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true},
+		},
+		{
+			name:       "containers ready",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true, common.TraceAgentContainerName: true},
+			rules:      []v2alpha1.UntaintReadinessRule{{Name: "apm", Containers: []common.AgentContainerName{common.TraceAgentContainerName}}},
+		},
+		{
+			name:          "container not ready",
+			containers:    map[common.AgentContainerName]bool{common.CoreAgentContainerName: true, common.TraceAgentContainerName: false},
+			rules:         []v2alpha1.UntaintReadinessRule{{Name: "apm", Containers: []common.AgentContainerName{common.TraceAgentContainerName}}},
+			expectReasons: []string{metrics.UntaintRuleBlockReasonContainerNotReady},
+		},
+		{
+			name:       "missing container is ignored",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true},
+			rules:      []v2alpha1.UntaintReadinessRule{{Name: "sysprobe", Containers: []common.AgentContainerName{common.SystemProbeContainerName}}},
+		},
+		{
+			name:          "socket not ready",
+			containers:    map[common.AgentContainerName]bool{common.CoreAgentContainerName: true, common.TraceAgentContainerName: false},
+			rules:         []v2alpha1.UntaintReadinessRule{socketRule},
+			expectReasons: []string{metrics.UntaintRuleBlockReasonSocketNotReady},
+		},
+		{
+			name:       "socket ready",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true, common.TraceAgentContainerName: true},
+			rules:      []v2alpha1.UntaintReadinessRule{socketRule},
+		},
+		{
+			name:          "min ready seconds not reached",
+			containers:    map[common.AgentContainerName]bool{common.CoreAgentContainerName: true},
+			rules:         []v2alpha1.UntaintReadinessRule{{Name: "warmup", MinReadySeconds: ptr.To[int32](60)}},
+			expectReasons: []string{metrics.UntaintRuleBlockReasonMinReadySeconds},
+			expectRequeue: 50 * time.Second,
+		},
+		{
+			name:       "min ready seconds reached",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true},
+			rules:      []v2alpha1.UntaintReadinessRule{{Name: "warmup", MinReadySeconds: ptr.To[int32](5)}},
+		},
+		{
+			name:       "every rule is reported",
+			containers: map[common.AgentContainerName]bool{common.CoreAgentContainerName: true, common.TraceAgentContainerName: false},
+			rules: []v2alpha1.UntaintReadinessRule{
+				{Name: "apm", Containers: []common.AgentContainerName{common.TraceAgentContainerName}},
+				{Name: "warmup", MinReadySeconds: ptr.To[int32](60)},
+			},
+			expectReasons: []string{metrics.UntaintRuleBlockReasonContainerNotReady, metrics.UntaintRuleBlockReasonMinReadySeconds},
+			expectRequeue: 50 * time.Second,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := ddaAgentPod(10*time.Second, now, tc.containers)
+			readyAt, ok := podReadyTransition(pod)
+			require.True(t, ok)
+
+			blocked := evaluateReadinessRules(pod, readyAt, tc.rules, now)
+
+			var reasons []string
+			for _, b := range blocked {
+				reasons = append(reasons, b.reason)
+			}
+			assert.Equal(t, tc.expectReasons, reasons)
+			assert.Equal(t, tc.expectRequeue, requeueForBlockedRules(ctrl.Result{}, blocked).RequeueAfter)
+		})
+	}
+}
+
+func TestReconcile_ReadinessRuleBlocksUntaint(t *testing.T) {
+	resetUntaintMetrics()
+	now := testNow()
+	node := taintedNode(testNodeName, 0, now)
+	pod := ddaAgentPod(time.Minute, now, map[common.AgentContainerName]bool{
+		common.CoreAgentContainerName:  true,
+		common.TraceAgentContainerName: false,
+	})
+	dda := ddaWithReadinessRules(v2alpha1.UntaintReadinessRule{
+		Name:       "apm",
+		Containers: []common.AgentContainerName{common.TraceAgentContainerName},
+	})
+
+	c := newFakeClient(t, node, pod, dda)
+	r, rec := newReconciler(t, c, now, PolicyRemove, 10*time.Minute, time.Minute, false)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+	// Readiness timeout remains the safety net: requeue for its remaining window.
+	assert.Equal(t, ctrl.Result{RequeueAfter: 9 * time.Minute}, result)
+
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.True(t, hasTaint(fresh), "taint must be kept while a readiness rule blocks")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(
+		metrics.ReadinessRuleBlocking.WithLabelValues(testNodeName, "apm", metrics.UntaintRuleBlockReasonContainerNotReady)))
+	select {
+	case ev := <-rec.Events:
+		assert.Contains(t, ev, "UntaintBlocked")
+		assert.Contains(t, ev, "apm")
+	default:
+		t.Fatal("expected UntaintBlocked event")
+	}
+
+	// The trace-agent becomes Ready: the rule passes and the node is untainted.
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: string(common.CoreAgentContainerName), Ready: true},
+		{Name: string(common.TraceAgentContainerName), Ready: true},
+	}
+	require.NoError(t, c.Status().Update(context.Background(), pod))
+
+	result, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.False(t, hasTaint(fresh))
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.ReadinessRuleBlocking),
+		"blocking series must be cleared once the node is untainted")
+}
+
+func TestReconcile_MinReadySecondsRequeues(t *testing.T) {
+	resetUntaintMetrics()
+	now := testNow()
+	node := taintedNode(testNodeName, 0, now)
+	pod := ddaAgentPod(20*time.Second, now, map[common.AgentContainerName]bool{common.CoreAgentContainerName: true})
+	dda := ddaWithReadinessRules(v2alpha1.UntaintReadinessRule{Name: "warmup", MinReadySeconds: ptr.To[int32](60)})
+
+	c := newFakeClient(t, node, pod, dda)
+	r, _ := newReconciler(t, c, now, PolicyRemove, 10*time.Minute, time.Minute, false)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 40 * time.Second}, result)
+}
+
+func TestReconcile_ReadinessRuleTimeoutStillApplies(t *testing.T) {
+	resetUntaintMetrics()
+	now := testNow()
+	node := taintedNode(testNodeName, 30*time.Minute, now)
+	pod := ddaAgentPod(11*time.Minute, now, map[common.AgentContainerName]bool{
+		common.CoreAgentContainerName:  true,
+		common.TraceAgentContainerName: false,
+	})
+	dda := ddaWithReadinessRules(v2alpha1.UntaintReadinessRule{
+		Name:       "apm",
+		Containers: []common.AgentContainerName{common.TraceAgentContainerName},
+	})
+
+	c := newFakeClient(t, node, pod, dda)
+	r, _ := newReconciler(t, c, now, PolicyRemove, 10*time.Minute, time.Minute, false)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.False(t, hasTaint(fresh), "readiness timeout with policy=remove must untaint despite the blocking rule")
+	assert.Equal(t, 1.0, testutil.ToFloat64(
+		metrics.TaintRemovalsTotal.WithLabelValues(testNodeName, metrics.UntaintRemovalReasonTimeout)))
+}
+
+func TestReconcile_ReadinessRulesWithoutDatadogAgent(t *testing.T) {
+	now := testNow()
+	node := taintedNode(testNodeName, 0, now)
+	pod := ddaAgentPod(time.Minute, now, map[common.AgentContainerName]bool{common.CoreAgentContainerName: true})
+
+	c := newFakeClient(t, node, pod)
+	r, _ := newReconciler(t, c, now, PolicyRemove, 10*time.Minute, time.Minute, false)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.False(t, hasTaint(fresh), "pods of a deleted DatadogAgent have no readiness rules")
+}
+
+func TestReconcile_CSI_readinessRuleBlocksUntaint(t *testing.T) {
+	resetUntaintMetrics()
+	now := testNow()
+	node := taintedNode(testNodeName, 0, now)
+	pod := ddaAgentPod(time.Minute, now, map[common.AgentContainerName]bool{common.CoreAgentContainerName: true})
+	csi := csiNodeServerPod("csi-1", testPodNS, testNodeName, true, time.Minute, now)
+	dda := ddaWithReadinessRules(v2alpha1.UntaintReadinessRule{Name: "warmup", MinReadySeconds: ptr.To[int32](120)})
+
+	c := newFakeClient(t, node, pod, csi, dda)
+	r, _ := newReconciler(t, c, now, PolicyRemove, 10*time.Minute, time.Minute, true)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, result)
+
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.True(t, hasTaint(fresh))
+	assert.Equal(t, 1.0, testutil.ToFloat64(
+		metrics.ReadinessRuleBlocking.WithLabelValues(testNodeName, "warmup", metrics.UntaintRuleBlockReasonMinReadySeconds)))
+}
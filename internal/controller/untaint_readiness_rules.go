@@ -0,0 +1,177 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/api/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/internal/controller/metrics"
+)
+
+// blockedRule is a DatadogAgent untaint readiness rule not satisfied by an agent pod.
+type blockedRule struct {
+	rule    string
+	reason  string
+	message string
+	// requeueAfter is set when the rule passes on its own after that delay
+	// (minReadySeconds), zero when it waits for a pod update.
+	requeueAfter time.Duration
+}
+
+// readyAgentPod looks for a Ready agent pod satisfying the readiness rules of its
+// DatadogAgent. It returns the pod Ready transition time and true when one is found.
+// Otherwise it returns the rules blocking the first Ready pod, if any.
+func (r *UntaintReconciler) readyAgentPod(ctx context.Context, pods []corev1.Pod) (time.Time, bool, []blockedRule, error) {
+	var blocked []blockedRule
+	for i := range pods {
+		pod := &pods[i]
+		readyAt, ok := podReadyTransition(pod)
+		if !ok {
+			continue
+		}
+		rules, err := r.untaintReadinessRules(ctx, pod)
+		if err != nil {
+			return time.Time{}, false, nil, err
+		}
+		podBlocked := evaluateReadinessRules(pod, readyAt, rules, r.clock.Now())
+		if len(podBlocked) == 0 {
+			return readyAt, true, nil, nil
+		}
+		if blocked == nil {
+			blocked = podBlocked
+		}
+	}
+	return time.Time{}, false, blocked, nil
+}
+
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents,verbs=get;list;watch
+
+// untaintReadinessRules returns the untaint readiness rules of the DatadogAgent
+// owning the agent pod. Pods not linked to an existing DatadogAgent have no rules.
+func (r *UntaintReconciler) untaintReadinessRules(ctx context.Context, pod *corev1.Pod) ([]v2alpha1.UntaintReadinessRule, error) {
+	name := pod.Labels[common.AgentDeploymentNameLabelKey]
+	if name == "" {
+		return nil, nil
+	}
+	dda := &v2alpha1.DatadogAgent{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, dda); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get DatadogAgent %s/%s: %w", pod.Namespace, name, err)
+	}
+	if dda.Spec.Global == nil || dda.Spec.Global.Untaint == nil {
+		return nil, nil
+	}
+	return dda.Spec.Global.Untaint.ReadinessRules, nil
+}
+
+// evaluateReadinessRules returns the rules not satisfied by a Ready agent pod.
+// Containers that are not part of the pod, because the features they serve are
+// disabled, are ignored.
+func evaluateReadinessRules(pod *corev1.Pod, readyAt time.Time, rules []v2alpha1.UntaintReadinessRule, now time.Time) []blockedRule {
+	var blocked []blockedRule
+	for _, rule := range rules {
+		var notReady []string
+		for _, name := range rule.Containers {
+			if ready, found := containerReady(pod, string(name)); found && !ready {
+				notReady = append(notReady, string(name))
+			}
+		}
+		if len(notReady) > 0 {
+			blocked = append(blocked, blockedRule{
+				rule:    rule.Name,
+				reason:  metrics.UntaintRuleBlockReasonContainerNotReady,
+				message: fmt.Sprintf("containers %s are not ready", strings.Join(notReady, ", ")),
+			})
+			continue
+		}
+
+		// The socket is observed through the readiness probe the operator adds
+		// to the container creating it.
+		if socket := rule.HostPathSocket; socket != nil {
+			if ready, found := containerReady(pod, string(socket.Container)); found && !ready {
+				blocked = append(blocked, blockedRule{
+					rule:    rule.Name,
+					reason:  metrics.UntaintRuleBlockReasonSocketNotReady,
+					message: fmt.Sprintf("socket %s of container %s is not ready", socket.Path, socket.Container),
+				})
+				continue
+			}
+		}
+
+		if rule.MinReadySeconds != nil {
+			minReady := time.Duration(*rule.MinReadySeconds) * time.Second
+			if remaining := readyAt.Add(minReady).Sub(now); remaining > 0 {
+				blocked = append(blocked, blockedRule{
+					rule:         rule.Name,
+					reason:       metrics.UntaintRuleBlockReasonMinReadySeconds,
+					message:      fmt.Sprintf("agent pod has been ready for less than %s", minReady),
+					requeueAfter: remaining,
+				})
+			}
+		}
+	}
+	return blocked
+}
+
+// containerReady reports whether the named container is Ready, and whether the
+// container is part of the pod at all.
+func containerReady(pod *corev1.Pod, name string) (ready bool, found bool) {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == name {
+			return status.Ready, true
+		}
+	}
+	return false, true
+}
+
+// reportBlockedRules records the readiness rules blocking the untaint of a node
+// in the operator logs, the ReadinessRuleBlocking metric and, when enabled, in
+// node events.
+func (r *UntaintReconciler) reportBlockedRules(node *corev1.Node, log logr.Logger, blocked []blockedRule) {
+	metrics.DeleteReadinessRuleSeries(node.Name)
+	for _, b := range blocked {
+		log.V(1).Info("Untaint readiness rule not satisfied", "rule", b.rule, "reason", b.reason, "detail", b.message)
+		metrics.ReadinessRuleBlocking.WithLabelValues(node.Name, b.rule, b.reason).Set(1)
+		if r.eventsEnabled {
+			r.recorder.Eventf(node, corev1.EventTypeNormal, "UntaintBlocked",
+				"Untaint readiness rule %s blocks taint removal from node %s: %s", b.rule, node.Name, b.message)
+		}
+	}
+}
+
+// requeueForBlockedRules shortens the requeue of result so that the node is
+// re-evaluated as soon as a time-based readiness rule may pass.
+func requeueForBlockedRules(result ctrl.Result, blocked []blockedRule) ctrl.Result {
+	for _, b := range blocked {
+		if b.requeueAfter > 0 && (result.RequeueAfter == 0 || b.requeueAfter < result.RequeueAfter) {
+			result.RequeueAfter = b.requeueAfter
+		}
+	}
+	return result
+}
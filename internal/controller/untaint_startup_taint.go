@@ -0,0 +1,210 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/common"
+	"github.com/DataDog/datadog-operator/internal/controller/metrics"
+	"github.com/DataDog/datadog-operator/pkg/constants"
+	"github.com/DataDog/datadog-operator/pkg/untaint"
+)
+
+// Environment variables consumed by the startup taint mode of the untaint
+// controller, in which the operator adds the agent-not-ready taint to new nodes
+// itself instead of relying on the node provisioner.
+const (
+	EnvApplyStartupTaint        = "DD_UNTAINT_CONTROLLER_APPLY_STARTUP_TAINT"
+	EnvStartupTaintNodeSelector = "DD_UNTAINT_CONTROLLER_STARTUP_TAINT_NODE_SELECTOR"
+	EnvStartupTaintMaxNodeAge   = "DD_UNTAINT_CONTROLLER_STARTUP_TAINT_MAX_NODE_AGE"
+)
+
+const (
+	startupTaintControllerName = "UntaintStartupTaint"
+
+	// Role labels identifying control-plane nodes, which are never tainted.
+	controlPlaneNodeRoleLabelKey = "node-role.kubernetes.io/control-plane"
+	legacyMasterNodeRoleLabelKey = "node-role.kubernetes.io/master"
+
+	// DefaultStartupTaintMaxNodeAge bounds which nodes are considered new: the
+	// taint is only added to nodes created less than this duration ago, so that
+	// an operator restart does not taint nodes already running workloads.
+	DefaultStartupTaintMaxNodeAge = 2 * time.Minute
+)
+
+// startupTaintEnabled reports whether the startup taint mode is enabled.
+func startupTaintEnabled() bool {
+	return os.Getenv(EnvApplyStartupTaint) == "true"
+}
+
+// StartupTaintReconciler adds the agent-not-ready taint to nodes as soon as
+// they are observed, so that clusters whose node provisioner does not set the
+// taint still benefit from the untaint controller. The UntaintReconciler then
+// removes the taint once the node Agent is ready, or when a timeout fires.
+//
+// Nodes are tainted on their first observation only, when they are younger than
+// maxNodeAge, match nodeSelector, are not control-plane nodes and have no Ready
+// node Agent pod yet.
+type StartupTaintReconciler struct {
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
+	clock    clock.PassiveClock
+
+	eventsEnabled bool
+	nodeSelector  labels.Selector
+	maxNodeAge    time.Duration
+}
+
+// NewStartupTaintReconciler builds a StartupTaintReconciler configured from the
+// DD_UNTAINT_CONTROLLER_STARTUP_TAINT_* environment variables. As for the
+// UntaintReconciler, invalid values return an error and abort the setup.
+func NewStartupTaintReconciler(c client.Client, log logr.Logger, rec record.EventRecorder) (*StartupTaintReconciler, error) {
+	selector := labels.Everything()
+	if raw := os.Getenv(EnvStartupTaintNodeSelector); raw != "" {
+		parsed, err := labels.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s=%q: %w", EnvStartupTaintNodeSelector, raw, err)
+		}
+		selector = parsed
+	}
+	maxNodeAge, err := durationFromEnv(EnvStartupTaintMaxNodeAge, DefaultStartupTaintMaxNodeAge)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &StartupTaintReconciler{
+		client:        c,
+		log:           log,
+		recorder:      rec,
+		clock:         clock.RealClock{},
+		eventsEnabled: os.Getenv(EnvEventsEnabled) == "true",
+		nodeSelector:  selector,
+		maxNodeAge:    maxNodeAge,
+	}
+
+	log.Info("untaint controller startup taint configured",
+		"nodeSelector", r.nodeSelector.String(),
+		"maxNodeAge", r.maxNodeAge,
+	)
+	return r, nil
+}
+
+// Reconcile adds the agent-not-ready taint to a new node. It only runs for
+// nodes observed for the first time (see newNodePredicate) and re-checks the
+// eligibility of the node against the cache.
+func (r *StartupTaintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get node %s: %w", req.Name, err)
+	}
+
+	if hasTaint(node) || !r.eligible(node) {
+		return ctrl.Result{}, nil
+	}
+
+	// The node Agent may already be running, e.g. when the operator restarts
+	// shortly after the node joined: tainting it again would only delay workloads.
+	podList := &corev1.PodList{}
+	if err := r.client.List(ctx, podList,
+		client.MatchingLabels{common.AgentDeploymentComponentLabelKey: constants.DefaultAgentResourceSuffix},
+		client.MatchingFields{untaintPodNodeIndex: node.Name},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods on node %s: %w", node.Name, err)
+	}
+	if slices.ContainsFunc(podList.Items, func(p corev1.Pod) bool {
+		_, ok := podReadyTransition(&p)
+		return ok
+	}) {
+		log.V(1).Info("Node Agent already ready, not adding the startup taint")
+		return ctrl.Result{}, nil
+	}
+
+	patched := node.DeepCopy()
+	patched.Spec.Taints = append(patched.Spec.Taints, untaint.AgentNotReadyTaint())
+	if err := r.client.Patch(ctx, patched, client.MergeFromWithOptions(node, client.MergeFromWithOptimisticLock{})); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{RequeueAfter: conflictRequeueDelay}, nil
+		}
+		metrics.StartupTaintErrorsTotal.Inc()
+		return ctrl.Result{}, fmt.Errorf("failed to add taint to node %s: %w", node.Name, err)
+	}
+
+	log.Info(fmt.Sprintf("Added agent-not-ready taint to node %s", node.Name))
+	metrics.StartupTaintsAppliedTotal.Inc()
+	if r.eventsEnabled {
+		r.recorder.Eventf(node, corev1.EventTypeNormal, "StartupTaintApplied",
+			"Added taint %s to new node %s until the node Agent is ready", untaint.AgentNotReadyTaintKey, node.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// eligible reports whether the startup taint may be added to the node.
+func (r *StartupTaintReconciler) eligible(node *corev1.Node) bool {
+	if isControlPlaneNode(node) || !r.nodeSelector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	created := node.CreationTimestamp.Time
+	return !created.IsZero() && r.clock.Since(created) < r.maxNodeAge
+}
+
+// isControlPlaneNode reports whether the node carries a control-plane role label.
+func isControlPlaneNode(node *corev1.Node) bool {
+	_, controlPlane := node.Labels[controlPlaneNodeRoleLabelKey]
+	_, master := node.Labels[legacyMasterNodeRoleLabelKey]
+	return controlPlane || master
+}
+
+// SetupWithManager wires the Node watch. It relies on the pod index registered
+// by UntaintReconciler.SetupWithManager, which must be called first.
+func (r *StartupTaintReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(startupTaintControllerName).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(nodeToRequest),
+			builder.WithPredicates(r.newNodePredicate()),
+		).
+		Complete(r)
+}
+
+// newNodePredicate only enqueues nodes on their first observation. Node
+// updates never enqueue: once the UntaintReconciler removed the taint, adding
+// it back would keep the node tainted for the node lifetime.
+func (r *StartupTaintReconciler) newNodePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			node, ok := e.Object.(*corev1.Node)
+			return ok && !hasTaint(node) && r.eligible(node)
+		},
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/internal/controller/metrics"
+)
+
+func newNode(name string, createdAgo time.Duration, now time.Time, nodeLabels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            nodeLabels,
+			CreationTimestamp: metav1.NewTime(now.Add(-createdAgo)),
+		},
+	}
+}
+
+func newStartupTaintReconciler(t *testing.T, c client.Client, now time.Time, selector labels.Selector) (*StartupTaintReconciler, *record.FakeRecorder) {
+	t.Helper()
+	rec := record.NewFakeRecorder(16)
+	return &StartupTaintReconciler{
+		client:        c,
+		log:           log.Log.WithName("test"),
+		recorder:      rec,
+		clock:         clocktesting.NewFakePassiveClock(now),
+		eventsEnabled: true,
+		nodeSelector:  selector,
+		maxNodeAge:    DefaultStartupTaintMaxNodeAge,
+	}, rec
+}
+
+func reconcileStartupTaint(t *testing.T, r *StartupTaintReconciler) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testNodeName}})
+	require.NoError(t, err)
+	return result
+}
+
+func nodeIsTainted(t *testing.T, c client.Client) bool {
+	t.Helper()
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	return hasTaint(fresh)
+}
+
+func TestNewStartupTaintReconciler_Config(t *testing.T) {
+	t.Setenv(EnvStartupTaintNodeSelector, "pool in (general,batch)")
+	t.Setenv(EnvStartupTaintMaxNodeAge, "30s")
+	r, err := NewStartupTaintReconciler(nil, log.Log.WithName("test"), record.NewFakeRecorder(1))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, r.maxNodeAge)
+	assert.True(t, r.nodeSelector.Matches(labels.Set{"pool": "batch"}))
+	assert.False(t, r.nodeSelector.Matches(labels.Set{"pool": "gpu"}))
+
+	t.Setenv(EnvStartupTaintNodeSelector, "pool in (")
+	_, err = NewStartupTaintReconciler(nil, log.Log.WithName("test"), record.NewFakeRecorder(1))
+	assert.ErrorContains(t, err, EnvStartupTaintNodeSelector)
+
+	t.Setenv(EnvStartupTaintNodeSelector, "")
+	t.Setenv(EnvStartupTaintMaxNodeAge, "-1m")
+	_, err = NewStartupTaintReconciler(nil, log.Log.WithName("test"), record.NewFakeRecorder(1))
+	assert.ErrorContains(t, err, EnvStartupTaintMaxNodeAge)
+}
+
+func TestStartupTaint_TaintsNewNode(t *testing.T) {
+	before := testutil.ToFloat64(metrics.StartupTaintsAppliedTotal)
+	now := testNow()
+	c := newFakeClient(t, newNode(testNodeName, 10*time.Second, now, nil))
+	r, rec := newStartupTaintReconciler(t, c, now, labels.Everything())
+
+	assert.Equal(t, ctrl.Result{}, reconcileStartupTaint(t, r))
+	assert.True(t, nodeIsTainted(t, c))
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.StartupTaintsAppliedTotal))
+	select {
+	case ev := <-rec.Events:
+		assert.Contains(t, ev, "StartupTaintApplied")
+	default:
+		t.Fatal("expected StartupTaintApplied event")
+	}
+
+	// A second reconcile is a no-op: the taint is only added once.
+	reconcileStartupTaint(t, r)
+	fresh := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: testNodeName}, fresh))
+	assert.Len(t, fresh.Spec.Taints, 1)
+}
+
+func TestStartupTaint_SkipsIneligibleNodes(t *testing.T) {
+	now := testNow()
+	selector, err := labels.Parse("pool=general")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		node *corev1.Node
+		pods []client.Object
+	}{
+		{
+			name: "old node",
+			node: newNode(testNodeName, time.Hour, now, map[string]string{"pool": "general"}),
+		},
+		{
+			name: "selector mismatch",
+			node: newNode(testNodeName, 10*time.Second, now, map[string]string{"pool": "gpu"}),
+		},
+		{
+			name: "control-plane node",
+			node: newNode(testNodeName, 10*time.Second, now, map[string]string{"pool": "general", controlPlaneNodeRoleLabelKey: ""}),
+		},
+		{
+			name: "legacy master node",
+			node: newNode(testNodeName, 10*time.Second, now, map[string]string{"pool": "general", legacyMasterNodeRoleLabelKey: ""}),
+		},
+		{
+			name: "agent already ready",
+			node: newNode(testNodeName, 10*time.Second, now, map[string]string{"pool": "general"}),
+			pods: []client.Object{agentPod(testPodName, testPodNS, testNodeName, true, 5*time.Second, now)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeClient(t, append([]client.Object{tc.node}, tc.pods...)...)
+			r, _ := newStartupTaintReconciler(t, c, now, selector)
+
+			assert.Equal(t, ctrl.Result{}, reconcileStartupTaint(t, r))
+			assert.False(t, nodeIsTainted(t, c))
+		})
+	}
+}
+
+func TestStartupTaint_NotReadyAgentPodStillTaints(t *testing.T) {
+	now := testNow()
+	node := newNode(testNodeName, 10*time.Second, now, nil)
+	pod := agentPod(testPodName, testPodNS, testNodeName, false, 5*time.Second, now)
+	c := newFakeClient(t, node, pod)
+	r, _ := newStartupTaintReconciler(t, c, now, labels.Everything())
+
+	reconcileStartupTaint(t, r)
+	assert.True(t, nodeIsTainted(t, c))
+}
+
+func TestStartupTaint_ConflictRequeues(t *testing.T) {
+	now := testNow()
+	base := newFakeClient(t, newNode(testNodeName, 10*time.Second, now, nil))
+	c := interceptor.NewClient(base, interceptor.Funcs{
+		Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+			return apierrors.NewConflict(schema.GroupResource{Resource: "nodes"}, testNodeName, nil)
+		},
+	})
+	r, _ := newStartupTaintReconciler(t, c, now, labels.Everything())
+
+	assert.Equal(t, ctrl.Result{RequeueAfter: conflictRequeueDelay}, reconcileStartupTaint(t, r))
+}
+
+func TestStartupTaint_NewNodePredicate(t *testing.T) {
+	now := testNow()
+	r, _ := newStartupTaintReconciler(t, nil, now, labels.Everything())
+	p := r.newNodePredicate()
+
+	fresh := newNode(testNodeName, 10*time.Second, now, nil)
+	old := newNode(testNodeName, time.Hour, now, nil)
+	tainted := taintedNode(testNodeName, 10*time.Second, now)
+
+	assert.True(t, p.Create(event.CreateEvent{Object: fresh}))
+	assert.False(t, p.Create(event.CreateEvent{Object: old}))
+	assert.False(t, p.Create(event.CreateEvent{Object: tainted}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: tainted, ObjectNew: fresh}),
+		"a node untainted by the untaint controller must not be tainted again")
+	assert.False(t, p.Delete(event.DeleteEvent{Object: fresh}))
+}
@@ -60,6 +60,8 @@ var (
 	agentInternalObj   = &datadoghqv1alpha1.DatadogAgentInternal{}
 	csiDriverObj       = &datadoghqv1alpha1.DatadogCSIDriver{}
 	csiDaemonSetObj    = &appsv1.DaemonSet{}
+	deploymentObj      = &appsv1.Deployment{}
+	statefulSetObj     = &appsv1.StatefulSet{}
 	podObj             = &corev1.Pod{}
 	nodeObj            = &corev1.Node{}
 )
@@ -68,6 +70,7 @@ type WatchOptions struct {
 	DatadogAgentEnabled               bool
 	DatadogMonitorEnabled             bool
 	DatadogSLOEnabled                 bool
+	DatadogTemplateEnabled            bool
 	DatadogAgentProfileEnabled        bool
 	IntrospectionEnabled              bool
 	DatadogDashboardEnabled           bool
@@ -77,13 +80,22 @@ type WatchOptions struct {
 	UntaintControllerWaitForCSIDriver bool
 	ManagedAgentInstallationEnabled   bool
 	ManagedAgentInstallationNamespace string
+	// WatchNamespaces, when set, restricts all the caches to these namespaces and takes precedence
+	// over the watch namespaces environment variables. It is used by the namespace-scoped mode of the operator.
+	WatchNamespaces []string
 }
 
 // CacheOptions function configures Controller Runtime cache options on a resource level (supported in v0.16+).
 // Datadog CRDs and additional resources required for their reconciliation will be cached only if the respective feature is enabled.
 func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	byObject := map[client.Object]cache.ByObject{}
-	agentNamespaces := GetWatchNamespacesFromEnv(logger, AgentWatchNamespaceEnvVar)
+	watchNamespaces := func(envVar string) map[string]cache.Config {
+		if len(opts.WatchNamespaces) > 0 {
+			return namespacesConfig(opts.WatchNamespaces)
+		}
+		return GetWatchNamespacesFromEnv(logger, envVar)
+	}
+	agentNamespaces := watchNamespaces(AgentWatchNamespaceEnvVar)
 	if opts.ManagedAgentInstallationEnabled {
 		agentNamespaces = includeWatchNamespace(agentNamespaces, opts.ManagedAgentInstallationNamespace)
 	}
@@ -96,7 +108,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogDashboardEnabled {
-		dashboardNamespaces := GetWatchNamespacesFromEnv(logger, dashboardWatchNamespaceEnvVar)
+		dashboardNamespaces := watchNamespaces(dashboardWatchNamespaceEnvVar)
 		logger.Info("DatadogDashboard Enabled", "watching namespaces", slices.Collect(maps.Keys(dashboardNamespaces)))
 		byObject[dashboardObj] = cache.ByObject{
 			Namespaces: dashboardNamespaces,
@@ -104,7 +116,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogGenericResourceEnabled {
-		genericResourceNamespaces := GetWatchNamespacesFromEnv(logger, genericResourceWatchNamespaceEnvVar)
+		genericResourceNamespaces := watchNamespaces(genericResourceWatchNamespaceEnvVar)
 		logger.Info("DatadogGenericResource Enabled", "watching namespaces", slices.Collect(maps.Keys(genericResourceNamespaces)))
 		byObject[genericResourceObj] = cache.ByObject{
 			Namespaces: genericResourceNamespaces,
@@ -112,7 +124,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogMonitorEnabled {
-		monitorNamespaces := GetWatchNamespacesFromEnv(logger, monitorWatchNamespaceEnvVar)
+		monitorNamespaces := watchNamespaces(monitorWatchNamespaceEnvVar)
 		logger.Info("DatadogMonitor Enabled", "watching namespaces", slices.Collect(maps.Keys(monitorNamespaces)))
 		byObject[monitorObj] = cache.ByObject{
 			Namespaces: monitorNamespaces,
@@ -120,7 +132,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogSLOEnabled {
-		sloNamespaces := GetWatchNamespacesFromEnv(logger, sloWatchNamespaceEnvVar)
+		sloNamespaces := watchNamespaces(sloWatchNamespaceEnvVar)
 		logger.Info("DatadogSLO Enabled", "watching namespaces", slices.Collect(maps.Keys(sloNamespaces)))
 		byObject[sloObj] = cache.ByObject{
 			Namespaces: sloNamespaces,
@@ -128,7 +140,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogAgentProfileEnabled {
-		agentProfileNamespaces := GetWatchNamespacesFromEnv(logger, profileWatchNamespaceEnvVar)
+		agentProfileNamespaces := watchNamespaces(profileWatchNamespaceEnvVar)
 		if opts.ManagedAgentInstallationEnabled {
 			agentProfileNamespaces = includeWatchNamespace(agentProfileNamespaces, opts.ManagedAgentInstallationNamespace)
 		}
@@ -148,7 +160,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 		podNamespaces := agentNamespaces
 		var podLabel labels.Selector
 		if opts.UntaintControllerEnabled && opts.UntaintControllerWaitForCSIDriver {
-			csiDriverNamespaces := GetWatchNamespacesFromEnv(logger, csiDriverWatchNamespaceEnvVar)
+			csiDriverNamespaces := watchNamespaces(csiDriverWatchNamespaceEnvVar)
 			podNamespaces = maps.Clone(agentNamespaces)
 			maps.Copy(podNamespaces, csiDriverNamespaces)
 			logger.Info("Pod cache enabled for untaint with wait-for-CSI",
@@ -233,7 +245,7 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 	}
 
 	if opts.DatadogCSIDriverEnabled {
-		csiDriverNamespaces := GetWatchNamespacesFromEnv(logger, csiDriverWatchNamespaceEnvVar)
+		csiDriverNamespaces := watchNamespaces(csiDriverWatchNamespaceEnvVar)
 		logger.Info("DatadogCSIDriver Enabled", "watching namespaces", slices.Collect(maps.Keys(csiDriverNamespaces)))
 		byObject[csiDriverObj] = cache.ByObject{
 			Namespaces: csiDriverNamespaces,
@@ -248,6 +260,30 @@ func CacheOptions(logger logr.Logger, opts WatchOptions) cache.Options {
 		}
 	}
 
+	if opts.DatadogTemplateEnabled && (opts.DatadogMonitorEnabled || opts.DatadogSLOEnabled) && len(opts.WatchNamespaces) == 0 {
+		// The DatadogMonitorTemplate and DatadogSLOTemplate controllers watch the workloads of the
+		// namespaces where monitors and SLOs are generated, in addition to the DatadogAgent ones.
+		// The caches are keyed by kind, so the typed workload informers are widened as well: this
+		// is only done when the template controllers are enabled.
+		workloadNamespaces := agentNamespaces
+		if opts.DatadogMonitorEnabled {
+			workloadNamespaces = mergeWatchNamespaces(workloadNamespaces, watchNamespaces(monitorWatchNamespaceEnvVar))
+		}
+		if opts.DatadogSLOEnabled {
+			workloadNamespaces = mergeWatchNamespaces(workloadNamespaces, watchNamespaces(sloWatchNamespaceEnvVar))
+		}
+		logger.Info("Template workloads enabled", "watching namespaces", slices.Collect(maps.Keys(workloadNamespaces)))
+		for _, obj := range []client.Object{deploymentObj, statefulSetObj, csiDaemonSetObj} {
+			namespaces := workloadNamespaces
+			if current, found := byObject[obj]; found {
+				namespaces = mergeWatchNamespaces(namespaces, current.Namespaces)
+			}
+			byObject[obj] = cache.ByObject{
+				Namespaces: namespaces,
+			}
+		}
+	}
+
 	return cache.Options{
 		// DefaultNamespaces is set to DatadogAgent CRD namespaces so all resources needed for DatadogAgent reconciliation
 		// are cached from the same namespace(s) as the DatadogAgent.
@@ -269,6 +305,29 @@ func includeWatchNamespace(namespaces map[string]cache.Config, namespace string)
 	return namespaces
 }
 
+// mergeWatchNamespaces returns the union of two namespace configurations.
+func mergeWatchNamespaces(a, b map[string]cache.Config) map[string]cache.Config {
+	if _, all := a[cache.AllNamespaces]; all {
+		return a
+	}
+	if _, all := b[cache.AllNamespaces]; all {
+		return b
+	}
+	merged := maps.Clone(a)
+	maps.Copy(merged, b)
+	return merged
+}
+
+// MonitorWatchNamespaces returns the namespaces watched by the DatadogMonitor controller.
+func MonitorWatchNamespaces(logger logr.Logger) map[string]cache.Config {
+	return GetWatchNamespacesFromEnv(logger, monitorWatchNamespaceEnvVar)
+}
+
+// SLOWatchNamespaces returns the namespaces watched by the DatadogSLO controller.
+func SLOWatchNamespaces(logger logr.Logger) map[string]cache.Config {
+	return GetWatchNamespacesFromEnv(logger, sloWatchNamespaceEnvVar)
+}
+
 // GetWatchNamespacesFromEnv retrieves the list of namespaces to watch from environment variables.
 func GetWatchNamespacesFromEnv(logger logr.Logger, envVar string) map[string]cache.Config {
 	cacheConfig := cache.Config{}
@@ -290,3 +349,22 @@ func GetWatchNamespacesFromEnv(logger logr.Logger, envVar string) map[string]cac
 	}
 	return nsConfigs
 }
+
+// ParseNamespaces parses a comma-separated list of namespaces, ignoring the empty entries.
+func ParseNamespaces(value string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+func namespacesConfig(namespaces []string) map[string]cache.Config {
+	nsConfigs := make(map[string]cache.Config, len(namespaces))
+	for _, ns := range namespaces {
+		nsConfigs[ns] = cache.Config{}
+	}
+	return nsConfigs
+}
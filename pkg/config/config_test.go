@@ -39,6 +39,7 @@ func Test_CacheConfig(t *testing.T) {
 				DatadogAgentEnabled:           true,
 				DatadogMonitorEnabled:         true,
 				DatadogSLOEnabled:             true,
+				DatadogTemplateEnabled:        true,
 				DatadogAgentProfileEnabled:    true,
 				DatadogDashboardEnabled:       true,
 				DatadogGenericResourceEnabled: true,
@@ -68,7 +69,68 @@ func Test_CacheConfig(t *testing.T) {
 				podObj:             {configured: true, namespaces: []string{"agentNs"}},
 				nodeObj:            {configured: true, namespaces: nil},
 				csiDriverObj:       {configured: true, namespaces: []string{"csiDriverNs"}},
-				csiDaemonSetObj:    {configured: true, namespaces: []string{"csiDriverNs", "agentNs"}},
+				csiDaemonSetObj:    {configured: true, namespaces: []string{"csiDriverNs", "agentNs", "monitorNs", "monitorNs2", "nsWithSpace"}},
+				deploymentObj:      {configured: true, namespaces: []string{"agentNs", "monitorNs", "monitorNs2", "nsWithSpace"}},
+				statefulSetObj:     {configured: true, namespaces: []string{"agentNs", "monitorNs", "monitorNs2", "nsWithSpace"}},
+			},
+		},
+		{
+			name: "Monitor enabled; template workloads cached in the monitor namespaces",
+			watchOptions: WatchOptions{
+				DatadogAgentEnabled:    true,
+				DatadogMonitorEnabled:  true,
+				DatadogTemplateEnabled: true,
+			},
+
+			envConfig: map[string]string{
+				AgentWatchNamespaceEnvVar:   "agentNs",
+				monitorWatchNamespaceEnvVar: "monitorNs",
+			},
+
+			wantDefaultNamepsace: objectConfig{configured: true, namespaces: []string{"agentNs"}},
+
+			wantObjectConfig: map[client.Object]objectConfig{
+				deploymentObj:   {configured: true, namespaces: []string{"agentNs", "monitorNs"}},
+				statefulSetObj:  {configured: true, namespaces: []string{"agentNs", "monitorNs"}},
+				csiDaemonSetObj: {configured: true, namespaces: []string{"agentNs", "monitorNs"}},
+			},
+		},
+		{
+			name: "Monitor enabled; template workloads cached in all namespaces",
+			watchOptions: WatchOptions{
+				DatadogAgentEnabled:    true,
+				DatadogMonitorEnabled:  true,
+				DatadogTemplateEnabled: true,
+			},
+
+			envConfig: map[string]string{
+				AgentWatchNamespaceEnvVar: "agentNs",
+			},
+
+			wantDefaultNamepsace: objectConfig{configured: true, namespaces: []string{"agentNs"}},
+
+			wantObjectConfig: map[client.Object]objectConfig{
+				deploymentObj:  {configured: true, namespaces: []string{cache.AllNamespaces}},
+				statefulSetObj: {configured: true, namespaces: []string{cache.AllNamespaces}},
+			},
+		},
+		{
+			name: "Monitor enabled without templates; workloads cached in the agent namespaces",
+			watchOptions: WatchOptions{
+				DatadogAgentEnabled:   true,
+				DatadogMonitorEnabled: true,
+			},
+
+			envConfig: map[string]string{
+				AgentWatchNamespaceEnvVar: "agentNs",
+			},
+
+			wantDefaultNamepsace: objectConfig{configured: true, namespaces: []string{"agentNs"}},
+
+			wantObjectConfig: map[client.Object]objectConfig{
+				deploymentObj:   {configured: false},
+				statefulSetObj:  {configured: false},
+				csiDaemonSetObj: {configured: false},
 			},
 		},
 		{
@@ -259,6 +321,35 @@ func Test_CacheConfig(t *testing.T) {
 				csiDriverObj: {configured: false},
 			},
 		},
+		{
+			name: "Namespace-scoped mode; watch namespaces take precedence over the environment variables",
+
+			watchOptions: WatchOptions{
+				DatadogMonitorEnabled:         true,
+				DatadogSLOEnabled:             true,
+				DatadogDashboardEnabled:       true,
+				DatadogGenericResourceEnabled: true,
+				WatchNamespaces:               []string{"team-a", "team-b"},
+			},
+
+			envConfig: map[string]string{
+				WatchNamespaceEnvVar:        "datadog",
+				monitorWatchNamespaceEnvVar: "monitorNs",
+			},
+
+			wantDefaultNamepsace: objectConfig{configured: true, namespaces: []string{"team-a", "team-b"}},
+			wantObjectConfig: map[client.Object]objectConfig{
+				agentObj:           {configured: false},
+				dashboardObj:       {configured: true, namespaces: []string{"team-a", "team-b"}},
+				genericResourceObj: {configured: true, namespaces: []string{"team-a", "team-b"}},
+				monitorObj:         {configured: true, namespaces: []string{"team-a", "team-b"}},
+				sloObj:             {configured: true, namespaces: []string{"team-a", "team-b"}},
+				profileObj:         {configured: false},
+				podObj:             {configured: false},
+				nodeObj:            {configured: false},
+				csiDriverObj:       {configured: false},
+			},
+		},
 		{
 			name: "Managed Agent installation namespace is included in Agent resource caches",
 
@@ -312,6 +403,11 @@ func TestIncludeWatchNamespacePreservesClusterWideWatch(t *testing.T) {
 	assert.Equal(t, namespaces, includeWatchNamespace(namespaces, "datadog-agent"))
 }
 
+func TestParseNamespaces(t *testing.T) {
+	assert.Equal(t, []string{"team-a", "team-b"}, ParseNamespaces(" team-a,,team-b , "))
+	assert.Empty(t, ParseNamespaces(""))
+}
+
 func verifyResourceNamespace(t *testing.T, resource client.Object, wantConfig objectConfig, cacheOptions cache.Options) {
 	byObjectOptions, ok := cacheOptions.ByObject[resource]
 	assert.Equal(t, wantConfig.configured, ok)
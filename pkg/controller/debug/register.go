@@ -8,6 +8,8 @@ package debug
 import (
 	"net/http"
 	"net/http/pprof"
+
+	"github.com/DataDog/datadog-operator/pkg/scrubber"
 )
 
 // GetExtraMetricHandlers creates debug endpoints if enabled.
@@ -19,7 +21,9 @@ func GetExtraMetricHandlers(enabled bool) map[string]http.Handler {
 	}
 
 	handlers["/debug/pprof/"] = http.HandlerFunc(pprof.Index)
-	handlers["/debug/pprof/cmdline"] = http.HandlerFunc(pprof.Cmdline)
+	// Only the command line is scrubbed: it's the only text endpoint that can contain credentials,
+	// passed as flags. The index lists the profiles, the other endpoints serve binary profiles.
+	handlers["/debug/pprof/cmdline"] = scrubber.Handler(http.HandlerFunc(pprof.Cmdline), scrubber.New())
 	handlers["/debug/pprof/profile"] = http.HandlerFunc(pprof.Profile)
 	handlers["/debug/pprof/symbol"] = http.HandlerFunc(pprof.Symbol)
 	handlers["/debug/pprof/trace"] = http.HandlerFunc(pprof.Trace)
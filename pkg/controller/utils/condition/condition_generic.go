@@ -26,6 +26,9 @@ const (
 	// the CR. Applies to any resource type that exposes live state — only set
 	// by controllers/handlers that perform state refresh.
 	DatadogConditionTypeStateSynced Type = "StateSynced"
+	// DatadogConditionTypeWarning means the Datadog CRD is synced, but part of its spec couldn't
+	// be validated by the controller
+	DatadogConditionTypeWarning Type = "Warning"
 )
 
 // UpdateFailureStatusConditions is a generic method to update the failure StatusConditions.
@@ -6,6 +6,8 @@
 package datadogclient
 
 import (
+	"net/http"
+
 	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
@@ -13,21 +15,21 @@ import (
 
 // InitMonitorClient creates a stateless Datadog Monitor API client.
 func InitMonitorClient() *datadogV1.MonitorsApi {
-	configV1 := datadogapi.NewConfiguration()
+	configV1 := newConfiguration()
 	apiClient := datadogapi.NewAPIClient(configV1)
 	return datadogV1.NewMonitorsApi(apiClient)
 }
 
 // InitSLOClient creates a stateless Datadog SLO API client.
 func InitSLOClient() *datadogV1.ServiceLevelObjectivesApi {
-	configV1 := datadogapi.NewConfiguration()
+	configV1 := newConfiguration()
 	apiClient := datadogapi.NewAPIClient(configV1)
 	return datadogV1.NewServiceLevelObjectivesApi(apiClient)
 }
 
 // InitDashboardClient creates a stateless Datadog Dashboard API client.
 func InitDashboardClient() *datadogV1.DashboardsApi {
-	configV1 := datadogapi.NewConfiguration()
+	configV1 := newConfiguration()
 	apiClient := datadogapi.NewAPIClient(configV1)
 	return datadogV1.NewDashboardsApi(apiClient)
 }
@@ -45,7 +47,7 @@ type GenericClients struct {
 
 // InitGenericClients creates stateless Datadog API clients for generic resource operations.
 func InitGenericClients() *GenericClients {
-	configV1 := datadogapi.NewConfiguration()
+	configV1 := newConfiguration()
 	apiClient := datadogapi.NewAPIClient(configV1)
 	return &GenericClients{
 		DashboardsClient:               datadogV1.NewDashboardsApi(apiClient),
@@ -57,3 +59,11 @@ func InitGenericClients() *GenericClients {
 		MonitorNotificationRulesClient: datadogV2.NewMonitorsApi(apiClient),
 	}
 }
+
+// newConfiguration returns the configuration of the Datadog API clients, sending the
+// requests through the rate limiter shared by all the clients.
+func newConfiguration() *datadogapi.Configuration {
+	configuration := datadogapi.NewConfiguration()
+	configuration.HTTPClient = &http.Client{Transport: sharedTransport}
+	return configuration
+}
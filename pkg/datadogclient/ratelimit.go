@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultRateLimit is the default number of requests per second sent to the Datadog API by the operator.
+	DefaultRateLimit = 20
+	// DefaultRateLimitBurst is the default number of requests that can be sent at once to the Datadog API.
+	DefaultRateLimitBurst = 40
+
+	rateLimitNameHeader      = "X-RateLimit-Name"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// idSegment matches the identifiers in the request paths: numeric IDs, public IDs such as
+// abc-def-ghi, and UUIDs.
+var idSegment = regexp.MustCompile(`^([0-9]+|[a-z0-9]{3}-[a-z0-9]{3}-[a-z0-9]{3}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// sharedTransport is used by all the Datadog API clients of the operator, so that
+// they share the same rate limit.
+var sharedTransport = newRateLimitedTransport(http.DefaultTransport, DefaultRateLimit, DefaultRateLimitBurst)
+
+// SetRateLimit sets the number of requests per second and the burst of the rate limiter shared
+// by the Datadog API clients. A rate lower or equal to zero disables the token bucket; the
+// X-RateLimit headers returned by the API are honoured in any case.
+func SetRateLimit(requestsPerSecond float64, burst int) {
+	limit := rate.Limit(requestsPerSecond)
+	if requestsPerSecond <= 0 {
+		limit = rate.Inf
+	}
+	sharedTransport.limiter.SetLimit(limit)
+	sharedTransport.limiter.SetBurst(burst)
+}
+
+// rateLimitedTransport is an http.RoundTripper limiting the requests sent to the Datadog API
+// with a token bucket. It also honours the X-RateLimit headers of the responses: when the
+// rate limit of an endpoint is exhausted, the requests to this endpoint wait for its reset.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+	now     func() time.Time
+
+	mu sync.Mutex
+	// limitNames are the names of the rate limits of the endpoints, learnt from the responses.
+	limitNames map[string]string
+	// blockedUntil are the reset times of the exhausted rate limits, by name.
+	blockedUntil map[string]time.Time
+}
+
+func newRateLimitedTransport(next http.RoundTripper, requestsPerSecond float64, burst int) *rateLimitedTransport {
+	return &rateLimitedTransport{
+		next:         next,
+		limiter:      rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		now:          time.Now,
+		limitNames:   map[string]string{},
+		blockedUntil: map[string]time.Time{},
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	endpoint := req.Method + " " + endpointPath(req.URL.Path)
+
+	if wait := t.waitFor(endpoint); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := t.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	t.observe(endpoint, resp)
+	return resp, nil
+}
+
+// endpointPath replaces the identifiers of a request path, so that the requests to the same
+// endpoint share their rate limit, for instance /api/v1/monitor/123 and /api/v1/monitor/456.
+func endpointPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// waitFor returns how long a request to the endpoint must wait for its rate limit to reset.
+func (t *rateLimitedTransport) waitFor(endpoint string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name, found := t.limitNames[endpoint]
+	if !found {
+		return 0
+	}
+	until, blocked := t.blockedUntil[name]
+	if !blocked {
+		return 0
+	}
+	wait := until.Sub(t.now())
+	if wait <= 0 {
+		delete(t.blockedUntil, name)
+		return 0
+	}
+	return wait
+}
+
+// observe records the rate limit of the endpoint from the X-RateLimit headers of the response.
+func (t *rateLimitedTransport) observe(endpoint string, resp *http.Response) {
+	name := resp.Header.Get(rateLimitNameHeader)
+	if name == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.limitNames[endpoint] = name
+	exhausted := resp.StatusCode == http.StatusTooManyRequests
+	if remaining, err := strconv.Atoi(resp.Header.Get(rateLimitRemainingHeader)); err == nil && remaining <= 0 {
+		exhausted = true
+	}
+	if !exhausted {
+		return
+	}
+	reset, err := strconv.Atoi(resp.Header.Get(rateLimitResetHeader))
+	if err != nil || reset <= 0 {
+		return
+	}
+	t.blockedUntil[name] = t.now().Add(time.Duration(reset) * time.Second)
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestEndpointPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/monitor/*", endpointPath("/api/v1/monitor/12345"))
+	assert.Equal(t, "/api/v1/dashboard/*", endpointPath("/api/v1/dashboard/abc-123-xyz"))
+	assert.Equal(t, "/api/v2/downtime/*", endpointPath("/api/v2/downtime/00000000-0000-0000-0000-000000000001"))
+	assert.Equal(t, "/api/v1/monitor/*/*", endpointPath("/api/v1/monitor/1/2"))
+	assert.Equal(t, "/api/v1/monitor/validate", endpointPath("/api/v1/monitor/validate"))
+}
+
+func TestRateLimitedTransport_HonoursRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headers := http.Header{}
+	status := http.StatusOK
+	transport := newRateLimitedTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status, Header: headers.Clone()}, nil
+	}), 1000, 1000)
+	transport.now = func() time.Time { return now }
+
+	send := func(path string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		_, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	headers.Set(rateLimitNameHeader, "monitor_get")
+	headers.Set(rateLimitRemainingHeader, "10")
+	headers.Set(rateLimitResetHeader, "30")
+	send("/api/v1/monitor/1")
+	assert.Zero(t, transport.waitFor("GET /api/v1/monitor/*"), "the rate limit is not exhausted")
+
+	headers.Set(rateLimitRemainingHeader, "0")
+	send("/api/v1/monitor/2")
+	assert.Equal(t, 30*time.Second, transport.waitFor("GET /api/v1/monitor/*"))
+	assert.Zero(t, transport.waitFor("GET /api/v1/dashboard/*"), "other endpoints are not limited")
+
+	now = now.Add(31 * time.Second)
+	assert.Zero(t, transport.waitFor("GET /api/v1/monitor/*"), "the rate limit is reset")
+
+	headers.Del(rateLimitRemainingHeader)
+	status = http.StatusTooManyRequests
+	send("/api/v1/monitor/3")
+	assert.Equal(t, 30*time.Second, transport.waitFor("GET /api/v1/monitor/*"), "too many requests without remaining header")
+}
+
+func TestRateLimitedTransport_WaitIsCancelledWithTheRequest(t *testing.T) {
+	transport := newRateLimitedTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("the request must not be sent")
+		return nil, nil
+	}), 1000, 1000)
+	transport.limitNames["GET /api/v1/monitor/*"] = "monitor_get"
+	transport.blockedUntil["monitor_get"] = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/monitor/1", nil).WithContext(ctx)
+	_, err := transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
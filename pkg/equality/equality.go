@@ -57,6 +57,8 @@ func IsEqualObject(kind kubernetes.ObjectKind, a, b client.Object) bool {
 		return IsEqualNetworkPolicies(a, b)
 	case kubernetes.CiliumNetworkPoliciesKind:
 		return IsEqualCiliumNetworkPolicies(a, b)
+	case kubernetes.VerticalPodAutoscalersKind:
+		return IsEqualVerticalPodAutoscalers(a, b)
 	default:
 		return false
 	}
@@ -228,6 +230,21 @@ func IsEqualCiliumNetworkPolicies(objA, objB client.Object) bool {
 	return apiequality.Semantic.DeepEqual(unstructuredA["specs"], unstructuredB["specs"])
 }
 
+// IsEqualVerticalPodAutoscalers return true if the two VerticalPodAutoscalers are equal
+func IsEqualVerticalPodAutoscalers(objA, objB client.Object) bool {
+	unstructuredA, errA := runtime.DefaultUnstructuredConverter.ToUnstructured(objA)
+	if errA != nil {
+		return false
+	}
+
+	unstructuredB, errB := runtime.DefaultUnstructuredConverter.ToUnstructured(objB)
+	if errB != nil {
+		return false
+	}
+
+	return apiequality.Semantic.DeepEqual(unstructuredA["spec"], unstructuredB["spec"])
+}
+
 // IsEqualOperatorObjectMeta return true if the meta information added by the Operator are equal:
 // Annotations, Labels, OwnerReference
 func IsEqualOperatorObjectMeta(a, b metav1.Object) bool {
@@ -0,0 +1,474 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-operator/pkg/remoteconfig"
+	"github.com/DataDog/datadog-operator/pkg/version"
+)
+
+const (
+	// ConfigMapSourceConfigsKey holds the list of installer configs, in YAML or
+	// JSON, with the same schema as the INSTALLER_CONFIG Remote Config product.
+	ConfigMapSourceConfigsKey = "configs"
+	// ConfigMapSourceTaskKey holds a single experiment task, in YAML or JSON,
+	// with the same schema as the UPDATER_TASK Remote Config product.
+	ConfigMapSourceTaskKey = "task"
+	// ConfigMapSourceStatusAnnotation is written by the operator with the
+	// installer state and the apply status of the ConfigMap content. It is an
+	// annotation rather than a data key so that GitOps tools applying the
+	// ConfigMap do not report the status as drift.
+	ConfigMapSourceStatusAnnotation = "fleet.datadoghq.com/experiment-status"
+
+	defaultConfigMapSourcePollInterval = 10 * time.Second
+	configMapSourceRestoreTimeout      = 10 * time.Second
+)
+
+var _ remoteconfig.RCClient = &ConfigMapTaskSource{}
+var _ manager.Runnable = &ConfigMapTaskSource{}
+var _ manager.LeaderElectionRunnable = &ConfigMapTaskSource{}
+
+// ConfigMapTaskSource is an RCClient reading installer configs and experiment
+// tasks from a ConfigMap instead of Datadog Remote Config. It lets clusters
+// that cannot reach Remote Config, or whose change control requires Git, drive
+// DatadogAgent experiments with the same Daemon state machine.
+//
+// The installer state normally persisted by Remote Config is reported in the
+// ConfigMapSourceStatusAnnotation of the ConfigMap, along with the hash of the
+// last delivered task. Both are restored from the annotation when the state is
+// first accessed, so that a restarted operator, or a new leader, neither replays
+// the last task nor rejects the next one for an outdated expected state. The
+// ConfigMap is polled through the API reader so that it does not depend on the
+// namespaces watched by the manager cache.
+type ConfigMapTaskSource struct {
+	reader       client.Reader
+	writer       client.Client
+	key          types.NamespacedName
+	pollInterval time.Duration
+
+	mu            sync.Mutex
+	subscribers   map[string]func(map[string]state.RawConfig, func(string, state.ApplyStatus))
+	packages      []*pbgo.PackageState
+	applyStatuses map[string]state.ApplyStatus
+	// restored is set once the installer state and the last task hash were
+	// restored from the status annotation.
+	restored bool
+	// Last delivered configs and hash of the last delivered task: the ConfigMap
+	// is only forwarded to the daemon when it changes, so that a rejected task is
+	// not retried on every poll. Configs are not persisted, as the daemon keeps
+	// them in memory: they are delivered again after a restart.
+	lastConfigs  []byte
+	lastTaskHash string
+	// notify wakes up the poll loop to write the status as soon as the daemon
+	// updates the installer state.
+	notify chan struct{}
+}
+
+// NewConfigMapTaskSource returns a ConfigMapTaskSource reading the ConfigMap key.
+// It must be added to the manager alongside the Daemon using it.
+func NewConfigMapTaskSource(mgr manager.Manager, key types.NamespacedName) *ConfigMapTaskSource {
+	return newConfigMapTaskSource(mgr.GetAPIReader(), mgr.GetClient(), key)
+}
+
+func newConfigMapTaskSource(reader client.Reader, writer client.Client, key types.NamespacedName) *ConfigMapTaskSource {
+	return &ConfigMapTaskSource{
+		reader:       reader,
+		writer:       writer,
+		key:          key,
+		pollInterval: defaultConfigMapSourcePollInterval,
+		subscribers:  make(map[string]func(map[string]state.RawConfig, func(string, state.ApplyStatus))),
+		packages: []*pbgo.PackageState{
+			{
+				Package:             packageDatadogOperator,
+				StableVersion:       version.Version,
+				StableConfigVersion: "empty",
+			},
+		},
+		applyStatuses: make(map[string]state.ApplyStatus),
+		notify:        make(chan struct{}, 1),
+	}
+}
+
+// Subscribe implements remoteconfig.RCClient. Only the INSTALLER_CONFIG and
+// UPDATER_TASK products are ever delivered.
+func (s *ConfigMapTaskSource) Subscribe(product string, fn func(update map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus))) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers[product] = fn
+}
+
+// GetInstallerState implements remoteconfig.RCClient.
+func (s *ConfigMapTaskSource) GetInstallerState() []*pbgo.PackageState {
+	s.restoreWithTimeout()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packages
+}
+
+// SetInstallerState implements remoteconfig.RCClient. The new state is reported
+// in the ConfigMap status annotation on the next poll loop iteration.
+func (s *ConfigMapTaskSource) SetInstallerState(packages []*pbgo.PackageState) {
+	s.restoreWithTimeout()
+	s.mu.Lock()
+	s.packages = packages
+	s.restored = true
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The source only
+// runs on the elected leader, like the Daemon it feeds.
+func (s *ConfigMapTaskSource) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable. It polls the ConfigMap until ctx is done.
+func (s *ConfigMapTaskSource) Start(ctx context.Context) error {
+	logger := ctrl.LoggerFrom(ctx).WithName("fleet-configmap-source").WithValues("configmap", s.key.String())
+	ctx = ctrl.LoggerInto(ctx, logger)
+	logger.Info("Starting Fleet ConfigMap task source")
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.sync(ctx); err != nil {
+			logger.Error(err, "Failed to sync Fleet ConfigMap task source")
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping Fleet ConfigMap task source")
+			return nil
+		case <-ticker.C:
+		case <-s.notify:
+		}
+	}
+}
+
+// sync delivers the ConfigMap configs and task to the subscribers when they
+// changed, then writes the status annotation back.
+func (s *ConfigMapTaskSource) sync(ctx context.Context) error {
+	cm := &corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, s.key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).V(1).Info("Fleet ConfigMap not found")
+			return nil
+		}
+		return fmt.Errorf("failed to get ConfigMap %s: %w", s.key, err)
+	}
+	s.restoreFrom(ctx, cm)
+
+	// Subscribers are only set once the daemon started: wait for them so that
+	// the ConfigMap content is not consumed before it can be handled.
+	s.mu.Lock()
+	configsFn := s.subscribers[state.ProductInstallerConfig]
+	taskFn := s.subscribers[state.ProductUpdaterTask]
+	s.mu.Unlock()
+	if configsFn == nil || taskFn == nil {
+		return nil
+	}
+
+	// Configs are delivered before the task, as the task expected state may
+	// reference a config introduced by the same commit.
+	configs, err := yamlToJSON(cm.Data[ConfigMapSourceConfigsKey])
+	if err != nil {
+		s.setApplyStatus(ConfigMapSourceConfigsKey, state.ApplyStatus{State: state.ApplyStateError, Error: err.Error()})
+	} else if !bytes.Equal(configs, s.lastConfigs) {
+		updates, parseErr := installerConfigUpdates(configs)
+		if parseErr != nil {
+			s.setApplyStatus(ConfigMapSourceConfigsKey, state.ApplyStatus{State: state.ApplyStateError, Error: parseErr.Error()})
+		} else {
+			s.clearApplyStatuses(ConfigMapSourceConfigsKey)
+			configsFn(updates, s.setApplyStatus)
+		}
+		s.lastConfigs = configs
+	}
+
+	task, err := yamlToJSON(cm.Data[ConfigMapSourceTaskKey])
+	if err != nil {
+		s.setApplyStatus(ConfigMapSourceTaskKey, state.ApplyStatus{State: state.ApplyStateError, Error: err.Error()})
+	} else if taskHash := contentHash(task); len(task) > 0 && taskHash != s.getLastTaskHash() {
+		// The hash is recorded before delivery so that the task is not delivered
+		// again if the daemon updates the installer state while handling it.
+		s.setLastTaskHash(taskHash)
+		taskFn(map[string]state.RawConfig{ConfigMapSourceTaskKey: {Config: task}}, s.setApplyStatus)
+	}
+
+	return s.writeStatus(ctx, cm)
+}
+
+// restoreWithTimeout restores the state from the ConfigMap, for RCClient
+// methods which have no context.
+func (s *ConfigMapTaskSource) restoreWithTimeout() {
+	s.mu.Lock()
+	restored := s.restored
+	s.mu.Unlock()
+	if restored {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), configMapSourceRestoreTimeout)
+	defer cancel()
+	cm := &corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, s.key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			ctrl.Log.WithName("fleet-configmap-source").Error(err, "Failed to restore the installer state from the Fleet ConfigMap", "configmap", s.key.String())
+			return
+		}
+		cm = &corev1.ConfigMap{}
+	}
+	s.restoreFrom(ctx, cm)
+}
+
+// restoreFrom restores the installer state and the last task hash from the
+// status annotation of cm, once. Without a valid annotation, the seeded state
+// is kept.
+func (s *ConfigMapTaskSource) restoreFrom(ctx context.Context, cm *corev1.ConfigMap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restored {
+		return
+	}
+	s.restored = true
+
+	raw, found := cm.Annotations[ConfigMapSourceStatusAnnotation]
+	if !found {
+		return
+	}
+	var st configMapSourceStatus
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Ignoring invalid Fleet ConfigMap status annotation")
+		return
+	}
+	if len(st.Packages) > 0 {
+		s.packages = make([]*pbgo.PackageState, 0, len(st.Packages))
+		for _, ps := range st.Packages {
+			s.packages = append(s.packages, ps.packageState())
+		}
+	}
+	s.lastTaskHash = st.LastTask
+}
+
+func (s *ConfigMapTaskSource) getLastTaskHash() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastTaskHash
+}
+
+func (s *ConfigMapTaskSource) setLastTaskHash(hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastTaskHash = hash
+}
+
+// contentHash returns the hex encoded SHA-256 of content, or an empty string
+// for empty content.
+func contentHash(content []byte) string {
+	if len(content) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// yamlToJSON converts a YAML or JSON ConfigMap value to JSON. Empty values
+// return nil.
+func yamlToJSON(raw string) ([]byte, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	out, err := yaml.YAMLToJSON([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ConfigMap value: %w", err)
+	}
+	return out, nil
+}
+
+// installerConfigUpdates splits the list of installer configs into one
+// RawConfig per config, keyed like Remote Config paths.
+func installerConfigUpdates(raw []byte) (map[string]state.RawConfig, error) {
+	updates := map[string]state.RawConfig{}
+	if len(raw) == 0 {
+		return updates, nil
+	}
+	var configs []json.RawMessage
+	if err := json.Unmarshal(raw, &configs); err != nil {
+		return nil, fmt.Errorf("%s must be a list of installer configs: %w", ConfigMapSourceConfigsKey, err)
+	}
+	for i, cfg := range configs {
+		var meta struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(cfg, &meta); err != nil {
+			return nil, fmt.Errorf("invalid installer config at index %d: %w", i, err)
+		}
+		if meta.ID == "" {
+			return nil, fmt.Errorf("installer config at index %d has no id", i)
+		}
+		path := ConfigMapSourceConfigsKey + "/" + meta.ID
+		if _, ok := updates[path]; ok {
+			return nil, fmt.Errorf("duplicate installer config id %q", meta.ID)
+		}
+		updates[path] = state.RawConfig{Config: cfg}
+	}
+	return updates, nil
+}
+
+func (s *ConfigMapTaskSource) setApplyStatus(path string, status state.ApplyStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applyStatuses[path] = status
+}
+
+// clearApplyStatuses forgets the apply status of key and of the configs it holds.
+func (s *ConfigMapTaskSource) clearApplyStatuses(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path := range s.applyStatuses {
+		if path == key || strings.HasPrefix(path, key+"/") {
+			delete(s.applyStatuses, path)
+		}
+	}
+}
+
+// configMapSourceStatus is the content of the ConfigMapSourceStatusAnnotation.
+type configMapSourceStatus struct {
+	Packages []configMapPackageStatus        `json:"packages,omitempty"`
+	Applied  map[string]configMapApplyStatus `json:"applied,omitempty"`
+	// LastTask is the SHA-256 of the last task delivered to the daemon.
+	LastTask string `json:"last_task,omitempty"`
+}
+
+type configMapPackageStatus struct {
+	Package           string               `json:"package"`
+	StableVersion     string               `json:"stable_version,omitempty"`
+	ExperimentVersion string               `json:"experiment_version,omitempty"`
+	StableConfig      string               `json:"stable_config,omitempty"`
+	ExperimentConfig  string               `json:"experiment_config,omitempty"`
+	Task              *configMapTaskStatus `json:"task,omitempty"`
+}
+
+// packageState converts a package status restored from the annotation back to
+// the installer state.
+func (ps configMapPackageStatus) packageState() *pbgo.PackageState {
+	pkg := &pbgo.PackageState{
+		Package:                 ps.Package,
+		StableVersion:           ps.StableVersion,
+		ExperimentVersion:       ps.ExperimentVersion,
+		StableConfigVersion:     ps.StableConfig,
+		ExperimentConfigVersion: ps.ExperimentConfig,
+	}
+	if ps.Task != nil {
+		pkg.Task = &pbgo.PackageStateTask{
+			Id:    ps.Task.ID,
+			State: pbgo.TaskState(pbgo.TaskState_value[ps.Task.State]),
+		}
+		if ps.Task.Error != "" {
+			pkg.Task.Error = &pbgo.TaskError{Message: ps.Task.Error}
+		}
+	}
+	return pkg
+}
+
+type configMapTaskStatus struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+type configMapApplyStatus struct {
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// status builds the status reported in the ConfigMap annotation.
+func (s *ConfigMapTaskSource) status() configMapSourceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := configMapSourceStatus{LastTask: s.lastTaskHash}
+	for _, pkg := range s.packages {
+		ps := configMapPackageStatus{
+			Package:           pkg.GetPackage(),
+			StableVersion:     pkg.GetStableVersion(),
+			ExperimentVersion: pkg.GetExperimentVersion(),
+			StableConfig:      pkg.GetStableConfigVersion(),
+			ExperimentConfig:  pkg.GetExperimentConfigVersion(),
+		}
+		if task := pkg.GetTask(); task != nil {
+			ps.Task = &configMapTaskStatus{
+				ID:    task.GetId(),
+				State: task.GetState().String(),
+				Error: task.GetError().GetMessage(),
+			}
+		}
+		st.Packages = append(st.Packages, ps)
+	}
+	sort.Slice(st.Packages, func(i, j int) bool { return st.Packages[i].Package < st.Packages[j].Package })
+
+	if len(s.applyStatuses) > 0 {
+		st.Applied = make(map[string]configMapApplyStatus, len(s.applyStatuses))
+		for path, as := range s.applyStatuses {
+			st.Applied[path] = configMapApplyStatus{State: applyStateString(as.State), Error: as.Error}
+		}
+	}
+	return st
+}
+
+func applyStateString(s state.ApplyState) string {
+	switch s {
+	case state.ApplyStateUnacknowledged:
+		return "unacknowledged"
+	case state.ApplyStateAcknowledged:
+		return "acknowledged"
+	case state.ApplyStateError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// writeStatus patches the status annotation of the ConfigMap when it changed.
+func (s *ConfigMapTaskSource) writeStatus(ctx context.Context, cm *corev1.ConfigMap) error {
+	raw, err := json.Marshal(s.status())
+	if err != nil {
+		return fmt.Errorf("failed to marshal Fleet ConfigMap status: %w", err)
+	}
+	if cm.Annotations[ConfigMapSourceStatusAnnotation] == string(raw) {
+		return nil
+	}
+	patched := cm.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+	patched.Annotations[ConfigMapSourceStatusAnnotation] = string(raw)
+	if err := s.writer.Patch(ctx, patched, client.MergeFrom(cm)); err != nil {
+		return fmt.Errorf("failed to write status to ConfigMap %s: %w", s.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/DataDog/datadog-operator/pkg/version"
+)
+
+var testSourceKey = types.NamespacedName{Namespace: "datadog", Name: "fleet-experiments"}
+
+const testSourceConfigs = `
+- id: apm-on
+  operations:
+  - operation: update
+    config:
+      spec:
+        features:
+          apm:
+            enabled: true
+`
+
+const testSourceTask = `
+id: task-1
+package_name: datadog-operator
+expected_state:
+  stable_config: ""
+  experiment_config: ""
+method: operator/start_datadogagent_experiment
+params:
+  version: apm-on
+  namespaced_name:
+    namespace: datadog
+    name: datadog-agent
+`
+
+// sourceRecorder subscribes to a ConfigMapTaskSource with the daemon RC
+// handlers and records the configs and tasks they receive.
+type sourceRecorder struct {
+	configs []map[string]installerConfig
+	tasks   []remoteAPIRequest
+	taskErr error
+}
+
+func newTestConfigMapSource(t *testing.T, data map[string]string) (*ConfigMapTaskSource, client.Client, *sourceRecorder) {
+	t.Helper()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testSourceKey.Namespace, Name: testSourceKey.Name},
+		Data:       data,
+	}
+	c := fake.NewClientBuilder().WithScheme(testFleetScheme()).WithObjects(cm).Build()
+	s := newConfigMapTaskSource(c, c, testSourceKey)
+
+	rec := &sourceRecorder{}
+	ctx := context.Background()
+	s.Subscribe(state.ProductInstallerConfig, handleInstallerConfigUpdate(ctx, func(configs map[string]installerConfig) error {
+		rec.configs = append(rec.configs, configs)
+		return nil
+	}))
+	s.Subscribe(state.ProductUpdaterTask, handleUpdaterTaskUpdate(ctx, func(req remoteAPIRequest) error {
+		rec.tasks = append(rec.tasks, req)
+		return rec.taskErr
+	}))
+	return s, c, rec
+}
+
+func readSourceStatus(t *testing.T, c client.Client) configMapSourceStatus {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), testSourceKey, cm))
+	var st configMapSourceStatus
+	require.NoError(t, json.Unmarshal([]byte(cm.Annotations[ConfigMapSourceStatusAnnotation]), &st))
+	return st
+}
+
+func TestConfigMapTaskSource_DeliversConfigsAndTask(t *testing.T) {
+	s, c, rec := newTestConfigMapSource(t, map[string]string{
+		ConfigMapSourceConfigsKey: testSourceConfigs,
+		ConfigMapSourceTaskKey:    testSourceTask,
+	})
+
+	require.NoError(t, s.sync(context.Background()))
+
+	require.Len(t, rec.configs, 1)
+	cfg, ok := rec.configs[0]["configs/apm-on"]
+	require.True(t, ok)
+	assert.Equal(t, "apm-on", cfg.ID)
+	require.Len(t, cfg.Operations, 1)
+	assert.Equal(t, OperationUpdate, cfg.Operations[0].Operation)
+	assert.JSONEq(t, `{"spec":{"features":{"apm":{"enabled":true}}}}`, string(cfg.Operations[0].Config))
+
+	require.Len(t, rec.tasks, 1)
+	assert.Equal(t, "task-1", rec.tasks[0].ID)
+	assert.Equal(t, methodStartDatadogAgentExperiment, rec.tasks[0].Method)
+	assert.Equal(t, types.NamespacedName{Namespace: "datadog", Name: "datadog-agent"}, rec.tasks[0].Params.NamespacedName)
+
+	st := readSourceStatus(t, c)
+	assert.Equal(t, "acknowledged", st.Applied["configs/apm-on"].State)
+	assert.Equal(t, "acknowledged", st.Applied[ConfigMapSourceTaskKey].State)
+
+	// Unchanged content is not delivered again.
+	require.NoError(t, s.sync(context.Background()))
+	assert.Len(t, rec.configs, 1)
+	assert.Len(t, rec.tasks, 1)
+}
+
+func TestConfigMapTaskSource_RejectedTaskNotRetried(t *testing.T) {
+	s, c, rec := newTestConfigMapSource(t, map[string]string{ConfigMapSourceTaskKey: testSourceTask})
+	rec.taskErr = errors.New("expected state does not match")
+
+	require.NoError(t, s.sync(context.Background()))
+	require.NoError(t, s.sync(context.Background()))
+
+	assert.Len(t, rec.tasks, 1)
+	st := readSourceStatus(t, c)
+	assert.Equal(t, "error", st.Applied[ConfigMapSourceTaskKey].State)
+	assert.Equal(t, "expected state does not match", st.Applied[ConfigMapSourceTaskKey].Error)
+}
+
+func TestConfigMapTaskSource_InvalidConfigs(t *testing.T) {
+	cases := map[string]string{
+		"not a list":   "id: apm-on",
+		"missing id":   "- operations: []",
+		"duplicate id": "- id: a\n- id: a",
+		"invalid yaml": "- id: [",
+	}
+	for name, configs := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, c, rec := newTestConfigMapSource(t, map[string]string{ConfigMapSourceConfigsKey: configs})
+
+			require.NoError(t, s.sync(context.Background()))
+
+			assert.Empty(t, rec.configs)
+			st := readSourceStatus(t, c)
+			assert.Equal(t, "error", st.Applied[ConfigMapSourceConfigsKey].State)
+			assert.NotEmpty(t, st.Applied[ConfigMapSourceConfigsKey].Error)
+		})
+	}
+}
+
+func TestConfigMapTaskSource_ReportsInstallerState(t *testing.T) {
+	s, c, _ := newTestConfigMapSource(t, nil)
+
+	s.SetInstallerState([]*pbgo.PackageState{{
+		Package:                 packageDatadogOperator,
+		StableConfigVersion:     "base",
+		ExperimentConfigVersion: "apm-on",
+		Task: &pbgo.PackageStateTask{
+			Id:    "task-1",
+			State: pbgo.TaskState_ERROR,
+			Error: &pbgo.TaskError{Message: "boom"},
+		},
+	}})
+	require.NoError(t, s.sync(context.Background()))
+
+	st := readSourceStatus(t, c)
+	require.Len(t, st.Packages, 1)
+	assert.Equal(t, configMapPackageStatus{
+		Package:          packageDatadogOperator,
+		StableConfig:     "base",
+		ExperimentConfig: "apm-on",
+		Task:             &configMapTaskStatus{ID: "task-1", State: "ERROR", Error: "boom"},
+	}, st.Packages[0])
+	assert.Equal(t, "base", s.GetInstallerState()[0].GetStableConfigVersion())
+}
+
+func TestConfigMapTaskSource_SeedsInstallerState(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(testFleetScheme()).Build()
+	s := newConfigMapTaskSource(c, c, testSourceKey)
+
+	packages := s.GetInstallerState()
+	require.Len(t, packages, 1)
+	assert.Equal(t, packageDatadogOperator, packages[0].GetPackage())
+	assert.Equal(t, version.Version, packages[0].GetStableVersion())
+	assert.Equal(t, "empty", packages[0].GetStableConfigVersion())
+}
+
+func TestConfigMapTaskSource_RestoresStateAfterRestart(t *testing.T) {
+	s, c, rec := newTestConfigMapSource(t, map[string]string{ConfigMapSourceTaskKey: testSourceTask})
+	require.NoError(t, s.sync(context.Background()))
+	require.Len(t, rec.tasks, 1)
+	s.SetInstallerState([]*pbgo.PackageState{{
+		Package:                 packageDatadogOperator,
+		StableVersion:           "1.20.0",
+		StableConfigVersion:     "base",
+		ExperimentConfigVersion: "apm-on",
+		Task:                    &pbgo.PackageStateTask{Id: "task-1", State: pbgo.TaskState_DONE},
+	}})
+	require.NoError(t, s.sync(context.Background()))
+
+	// A new source, as after a restart, restores the state from the annotation
+	// and does not deliver the same task again.
+	restarted := newConfigMapTaskSource(c, c, testSourceKey)
+	restartedRec := &sourceRecorder{}
+	ctx := context.Background()
+	restarted.Subscribe(state.ProductInstallerConfig, handleInstallerConfigUpdate(ctx, func(configs map[string]installerConfig) error {
+		restartedRec.configs = append(restartedRec.configs, configs)
+		return nil
+	}))
+	restarted.Subscribe(state.ProductUpdaterTask, handleUpdaterTaskUpdate(ctx, func(req remoteAPIRequest) error {
+		restartedRec.tasks = append(restartedRec.tasks, req)
+		return nil
+	}))
+
+	packages := restarted.GetInstallerState()
+	require.Len(t, packages, 1)
+	assert.Equal(t, "1.20.0", packages[0].GetStableVersion())
+	assert.Equal(t, "base", packages[0].GetStableConfigVersion())
+	assert.Equal(t, "apm-on", packages[0].GetExperimentConfigVersion())
+	assert.Equal(t, "task-1", packages[0].GetTask().GetId())
+	assert.Equal(t, pbgo.TaskState_DONE, packages[0].GetTask().GetState())
+
+	require.NoError(t, restarted.sync(ctx))
+	assert.Empty(t, restartedRec.tasks)
+
+	// A new task is delivered.
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, testSourceKey, cm))
+	cm.Data[ConfigMapSourceTaskKey] = strings.Replace(testSourceTask, "task-1", "task-2", 1)
+	require.NoError(t, c.Update(ctx, cm))
+	require.NoError(t, restarted.sync(ctx))
+	require.Len(t, restartedRec.tasks, 1)
+	assert.Equal(t, "task-2", restartedRec.tasks[0].ID)
+}
+
+func TestConfigMapTaskSource_WaitsForSubscribers(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testSourceKey.Namespace, Name: testSourceKey.Name},
+		Data:       map[string]string{ConfigMapSourceTaskKey: testSourceTask},
+	}
+	c := fake.NewClientBuilder().WithScheme(testFleetScheme()).WithObjects(cm).Build()
+	s := newConfigMapTaskSource(c, c, testSourceKey)
+
+	require.NoError(t, s.sync(context.Background()))
+	assert.Empty(t, s.lastTaskHash, "the task must not be consumed before the daemon subscribed")
+}
+
+func TestConfigMapTaskSource_MissingConfigMap(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(testFleetScheme()).Build()
+	s := newConfigMapTaskSource(c, c, testSourceKey)
+	assert.NoError(t, s.sync(context.Background()))
+	assert.True(t, s.NeedLeaderElection())
+}
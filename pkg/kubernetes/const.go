@@ -59,10 +59,12 @@ const (
 	ServicesKind = "services"
 	// ValidatingWebhookConfigurationsKind is the ValidatingWebhookConfigurations resource kind
 	ValidatingWebhookConfigurationsKind = "validatingwebhookconfigurations"
+	// VerticalPodAutoscalersKind is the VerticalPodAutoscalers resource kind
+	VerticalPodAutoscalersKind = "verticalpodautoscalers"
 )
 
 // getResourcesKind return the list of all possible ObjectKind supported as DatadogAgent dependencies
-func getResourcesKind(withCiliumResources bool) []ObjectKind {
+func getResourcesKind(withCiliumResources, withVPAResources bool) []ObjectKind {
 	resources := []ObjectKind{
 		APIServiceKind,
 		ClusterRolesKind,
@@ -83,6 +85,10 @@ func getResourcesKind(withCiliumResources bool) []ObjectKind {
 		resources = append(resources, CiliumNetworkPoliciesKind)
 	}
 
+	if withVPAResources {
+		resources = append(resources, VerticalPodAutoscalersKind)
+	}
+
 	return resources
 }
 
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ciliumv1 "github.com/DataDog/datadog-operator/pkg/cilium/v1"
+	vpav1 "github.com/DataDog/datadog-operator/pkg/vpa/v1"
 )
 
 // ObjectFromKind returns the corresponding object list from a kind
@@ -47,6 +48,8 @@ func ObjectFromKind(kind ObjectKind, platformInfo PlatformInfo) client.Object {
 		return &networkingv1.NetworkPolicy{}
 	case CiliumNetworkPoliciesKind:
 		return ciliumv1.EmptyCiliumUnstructuredPolicy()
+	case VerticalPodAutoscalersKind:
+		return vpav1.EmptyUnstructuredVerticalPodAutoscaler()
 	case NodeKind:
 		return &corev1.Node{}
 	}
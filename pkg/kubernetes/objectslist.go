@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ciliumv1 "github.com/DataDog/datadog-operator/pkg/cilium/v1"
+	vpav1 "github.com/DataDog/datadog-operator/pkg/vpa/v1"
 )
 
 // ObjectListFromKind returns the corresponding object list from a kind
@@ -47,6 +48,8 @@ func ObjectListFromKind(kind ObjectKind, platformInfo PlatformInfo) client.Objec
 		return &networkingv1.NetworkPolicyList{}
 	case CiliumNetworkPoliciesKind:
 		return ciliumv1.EmptyCiliumUnstructuredListPolicy()
+	case VerticalPodAutoscalersKind:
+		return vpav1.EmptyUnstructuredVerticalPodAutoscalerList()
 	}
 
 	return nil
@@ -87,7 +87,7 @@ func (platformInfo *PlatformInfo) CreatePDBObjectList() client.ObjectList {
 }
 
 func (platformInfo *PlatformInfo) GetAgentResourcesKind(withCiliumResources bool) []ObjectKind {
-	return getResourcesKind(withCiliumResources)
+	return getResourcesKind(withCiliumResources, platformInfo.IsResourceSupported("VerticalPodAutoscaler"))
 }
 
 // IsResourceSupported returns true if a Kubernetes resource is supported by the server
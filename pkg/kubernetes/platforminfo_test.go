@@ -126,6 +126,16 @@ func Test_getPDBFlag(t *testing.T) {
 	}
 }
 
+func Test_GetAgentResourcesKind(t *testing.T) {
+	withVPA := NewPlatformInfoFromVersionMaps(nil, map[string]string{"VerticalPodAutoscaler": "autoscaling.k8s.io/v1"}, nil)
+	withoutVPA := NewPlatformInfoFromVersionMaps(nil, map[string]string{}, nil)
+
+	assert.Contains(t, withVPA.GetAgentResourcesKind(false), ObjectKind(VerticalPodAutoscalersKind))
+	assert.NotContains(t, withVPA.GetAgentResourcesKind(false), ObjectKind(CiliumNetworkPoliciesKind))
+	assert.NotContains(t, withoutVPA.GetAgentResourcesKind(true), ObjectKind(VerticalPodAutoscalersKind))
+	assert.Contains(t, withoutVPA.GetAgentResourcesKind(true), ObjectKind(CiliumNetworkPoliciesKind))
+}
+
 func Test_getDatadogAgentVersions(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package common
+
+import (
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewTable returns a left-aligned, borderless table with the given header
+func NewTable(out io.Writer, header ...any) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.Header(header...)
+	table.Options(
+		tablewriter.WithHeaderAlignment(tw.AlignLeft),
+		tablewriter.WithRowAlignment(tw.AlignLeft),
+		tablewriter.WithRendition(tw.Rendition{
+			Borders: tw.Border{Left: tw.Off, Top: tw.Off, Right: tw.Off, Bottom: tw.Off},
+			Settings: tw.Settings{
+				Lines:      tw.Lines{ShowHeaderLine: tw.Off},
+				Separators: tw.Separators{BetweenRows: tw.Off},
+			},
+		}),
+	)
+	return table
+}
+
+// IsPodReady returns true if the pod has the Ready condition set to True
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -19,7 +19,7 @@ import (
 )
 
 // update regenerates the golden files instead of comparing against them.
-// Run: go test ./internal/controller/testutils/renderer/ -run TestRender_Golden -update
+// Run: go test ./pkg/renderer/ -run TestRender_Golden -update
 var update = flag.Bool("update", false, "update golden files")
 
 // goldenImageTag pins the node Agent and Cluster Agent image tags used by
@@ -64,6 +64,10 @@ type Options struct {
 	// It gates version-dependent resources such as the local agent service
 	// (k8s >= 1.22). Empty defaults to DefaultKubernetesVersion.
 	KubernetesVersion string
+	// DDAICRD is the DatadogAgentInternal CRD pre-loaded in the fake client.
+	// Callers running outside of the source tree (e.g. against a live cluster)
+	// can provide it; nil loads it from config/crd/bases/v1/.
+	DDAICRD *apiextensionsv1.CustomResourceDefinition
 }
 
 // DefaultKubernetesVersion is the simulated server version used when
@@ -105,8 +109,8 @@ func loadDDAICRD(scheme *runtime.Scheme) (*apiextensionsv1.CustomResourceDefinit
 	if !ok {
 		return nil, fmt.Errorf("unable to resolve renderer source path")
 	}
-	// internal/controller/testutils/renderer/ → repo root is 4 levels up.
-	path := filepath.Join(filepath.Dir(filename), "..", "..", "..", "..",
+	// pkg/renderer/ → repo root is 2 levels up.
+	path := filepath.Join(filepath.Dir(filename), "..", "..",
 		"config", "crd", "bases", "v1", "datadoghq.com_datadogagentinternals.yaml")
 	body, err := os.ReadFile(path)
 	if err != nil {
@@ -153,9 +157,12 @@ func Render(opts Options) ([]client.Object, *runtime.Scheme, error) {
 
 	scheme := BuildScheme()
 
-	crd, err := loadDDAICRD(scheme)
-	if err != nil {
-		return nil, nil, err
+	crd := opts.DDAICRD
+	if crd == nil {
+		var err error
+		if crd, err = loadDDAICRD(scheme); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Build fake client pre-populated with DDA, DAPs, and the DDAI CRD.
@@ -0,0 +1,234 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package scrubber redacts credentials from the objects and logs written by the
+// operator tools (kubectl datadog flare, operator-render, debug endpoints).
+//
+// It recognizes Datadog API and application keys, Cluster Agent tokens, secret
+// backend handles (see secrets.IsEnc), bearer tokens, passwords in URLs and YAML
+// keys, and PEM blocks. Extra patterns can be registered with AddPatterns.
+package scrubber
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-operator/pkg/secrets"
+)
+
+// Redacted is the placeholder written in place of scrubbed values.
+const Redacted = "********"
+
+// Replacer stores a regex matching a credential and how to replace it.
+type Replacer struct {
+	// Regex matches the content to replace.
+	Regex *regexp.Regexp
+	// Hints are lower-case strings that must be present in the content for the
+	// regex to be evaluated. If none of them is present, the regex cannot match.
+	Hints []string
+	// Repl is the replacement, it can reference the Regex capture groups.
+	Repl []byte
+	// ReplFunc computes the replacement, it takes precedence over Repl.
+	ReplFunc func(b []byte) []byte
+}
+
+func (r Replacer) apply(b []byte) []byte {
+	if len(r.Hints) > 0 {
+		lower := strings.ToLower(string(b))
+		containsHint := false
+		for _, hint := range r.Hints {
+			if strings.Contains(lower, hint) {
+				containsHint = true
+				break
+			}
+		}
+		if !containsHint {
+			return b
+		}
+	}
+	if r.ReplFunc != nil {
+		return r.Regex.ReplaceAllFunc(b, r.ReplFunc)
+	}
+	return r.Regex.ReplaceAll(b, r.Repl)
+}
+
+var (
+	commentRegex = regexp.MustCompile(`^\s*#.*$`)
+	blankRegex   = regexp.MustCompile(`^\s*$`)
+)
+
+// Scrubber redacts credentials from text content.
+type Scrubber struct {
+	singleLineReplacers []Replacer
+	multiLineReplacers  []Replacer
+}
+
+// New returns a Scrubber configured with the default replacers.
+func New() *Scrubber {
+	return &Scrubber{
+		singleLineReplacers: defaultSingleLineReplacers(),
+		multiLineReplacers:  defaultMultiLineReplacers(),
+	}
+}
+
+// AddSingleLineReplacer registers a replacer applied to each line.
+func (s *Scrubber) AddSingleLineReplacer(r Replacer) {
+	s.singleLineReplacers = append(s.singleLineReplacers, r)
+}
+
+// AddMultiLineReplacer registers a replacer applied to the whole content, after the single line replacers.
+func (s *Scrubber) AddMultiLineReplacer(r Replacer) {
+	s.multiLineReplacers = append(s.multiLineReplacers, r)
+}
+
+// AddPatterns registers extra single line regular expressions. Every match is replaced with Redacted.
+func (s *Scrubber) AddPatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid scrubbing pattern %q: %w", pattern, err)
+		}
+		s.AddSingleLineReplacer(Replacer{Regex: regex, Repl: []byte(Redacted)})
+	}
+	return nil
+}
+
+// ScrubBytes scrubs credentials from data.
+// Comments and blank lines are removed, as they may contain commented-out credentials.
+func (s *Scrubber) ScrubBytes(data []byte) ([]byte, error) {
+	return s.scrub(data, false)
+}
+
+// ScrubLines scrubs credentials from data, keeping all its lines, comments and blank lines
+// included. It's meant for content such as logs, where every line matters.
+func (s *Scrubber) ScrubLines(data []byte) ([]byte, error) {
+	return s.scrub(data, true)
+}
+
+func (s *Scrubber) scrub(data []byte, keepAllLines bool) ([]byte, error) {
+	var cleaned []byte
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	// First, we go through the content line by line, applying any
+	// single-line replacer that matches the line.
+	first := true
+	for scanner.Scan() {
+		b := scanner.Bytes()
+		if !keepAllLines && (commentRegex.Match(b) || blankRegex.Match(b)) {
+			continue
+		}
+		for _, repl := range s.singleLineReplacers {
+			b = repl.apply(b)
+		}
+		if !first {
+			cleaned = append(cleaned, '\n')
+		}
+		cleaned = append(cleaned, b...)
+		first = false
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Then we apply multiline replacers on the cleaned content
+	for _, repl := range s.multiLineReplacers {
+		cleaned = repl.apply(cleaned)
+	}
+
+	return cleaned, nil
+}
+
+// ScrubString scrubs credentials from a string.
+func (s *Scrubber) ScrubString(data string) (string, error) {
+	cleaned, err := s.ScrubBytes([]byte(data))
+	return string(cleaned), err
+}
+
+// maxLineSize is the maximum size of a line, large enough for single-line JSON objects.
+const maxLineSize = 4 * 1024 * 1024
+
+func defaultSingleLineReplacers() []Replacer {
+	apiKeyReplacer := Replacer{
+		Regex: regexp.MustCompile(`\b[a-fA-F0-9]{27}([a-fA-F0-9]{5})\b`),
+		Repl:  []byte(`***************************$1`),
+	}
+	appKeyReplacer := Replacer{
+		Regex: regexp.MustCompile(`\b[a-fA-F0-9]{35}([a-fA-F0-9]{5})\b`),
+		Repl:  []byte(`***********************************$1`),
+	}
+	uriPasswordReplacer := Replacer{
+		Regex: regexp.MustCompile(`([A-Za-z]+\:\/\/|\b)([A-Za-z0-9_]+)\:([^\s-]+)\@`),
+		Repl:  []byte(`$1$2:********@`),
+	}
+	passwordReplacer := Replacer{
+		Regex: matchYAMLKeyPart(`(pass(word)?|pwd)`),
+		Hints: []string{"pass", "pwd"},
+		Repl:  []byte(`$1 ********`),
+	}
+	tokenReplacer := Replacer{
+		Regex: matchYAMLKeyPart(`(?i:token)`),
+		Hints: []string{"token"},
+		Repl:  []byte(`$1 ********`),
+	}
+	clusterAgentTokenEnvReplacer := Replacer{
+		// DD_CLUSTER_AGENT_AUTH_TOKEN=<token> and "name":"DD_CLUSTER_AGENT_AUTH_TOKEN","value":"<token>"
+		Regex: regexp.MustCompile(`(DD_CLUSTER_AGENT_AUTH_TOKEN("\s*,\s*"value"\s*:\s*"|=))[^"\s,]+`),
+		Hints: []string{"dd_cluster_agent_auth_token"},
+		Repl:  []byte(`${1}********`),
+	}
+	bearerReplacer := Replacer{
+		Regex: regexp.MustCompile(`\b([Bb]earer)\s+[A-Za-z0-9\-._~+/]+=*`),
+		Hints: []string{"bearer"},
+		Repl:  []byte(`$1 ********`),
+	}
+	encHandleReplacer := Replacer{
+		Regex: regexp.MustCompile(`ENC\[[^\]\s]*\]`),
+		Hints: []string{"enc["},
+		ReplFunc: func(b []byte) []byte {
+			if !secrets.IsEnc(string(b)) {
+				return b
+			}
+			return []byte("ENC[" + Redacted + "]")
+		},
+	}
+	return []Replacer{apiKeyReplacer, appKeyReplacer, uriPasswordReplacer, passwordReplacer, tokenReplacer, clusterAgentTokenEnvReplacer, bearerReplacer, encHandleReplacer}
+}
+
+func defaultMultiLineReplacers() []Replacer {
+	certReplacer := Replacer{
+		Regex: matchCert(),
+		Hints: []string{"begin"},
+		Repl:  []byte(Redacted),
+	}
+	clusterAgentTokenEnvReplacer := Replacer{
+		// - name: DD_CLUSTER_AGENT_AUTH_TOKEN
+		//   value: <token>
+		Regex: regexp.MustCompile(`(name:\s*"?DD_CLUSTER_AGENT_AUTH_TOKEN"?\s*\n\s*value:)[^\n]+`),
+		Hints: []string{"dd_cluster_agent_auth_token"},
+		Repl:  []byte(`$1 ********`),
+	}
+	return []Replacer{certReplacer, clusterAgentTokenEnvReplacer}
+}
+
+func matchYAMLKeyPart(part string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(\s*(\w|_)*%s(\w|_)*\s*:).+`, part))
+}
+
+func matchCert() *regexp.Regexp {
+	/*
+	   Try to match as accurately as possible RFC 7468's ABNF
+	   Backreferences are not available in go, so we cannot verify
+	   here that the BEGIN label is the same as the END label.
+	*/
+	return regexp.MustCompile(
+		`-----BEGIN (?:.*)-----[A-Za-z0-9=\+\/\s]*-----END (?:.*)-----`,
+	)
+}
@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package scrubber
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// Writer is a writer that scrubs content before writing to its target.
+type Writer struct {
+	target       io.Writer
+	scrubber     *Scrubber
+	keepAllLines bool
+}
+
+// NewWriter instantiates a Writer scrubbing content with s before writing to target.
+// Comments and blank lines are removed, as ScrubBytes does.
+func NewWriter(target io.Writer, s *Scrubber) *Writer {
+	return &Writer{
+		target:   target,
+		scrubber: s,
+	}
+}
+
+// NewLogWriter instantiates a Writer scrubbing content with s before writing to target,
+// keeping all the lines, as ScrubLines does, so that it can be used as a log sink.
+func NewLogWriter(target io.Writer, s *Scrubber) *Writer {
+	return &Writer{
+		target:       target,
+		scrubber:     s,
+		keepAllLines: true,
+	}
+}
+
+// Write writes the scrubbed content of p to the target.
+// Each call is scrubbed independently, a trailing new line is preserved so that
+// the writer can be used as a log sink.
+func (w *Writer) Write(p []byte) (int, error) {
+	scrub := w.scrubber.ScrubBytes
+	if w.keepAllLines {
+		scrub = w.scrubber.ScrubLines
+	}
+	cleaned, err := scrub(p)
+	if err != nil {
+		return 0, err
+	}
+	if bytes.HasSuffix(p, []byte("\n")) && (len(cleaned) > 0 || w.keepAllLines) {
+		cleaned = append(cleaned, '\n')
+	}
+
+	n, err := w.target.Write(cleaned)
+	if err == nil && n != len(cleaned) {
+		err = io.ErrShortWrite
+	}
+
+	return len(p), err
+}
+
+// Handler wraps an HTTP handler serving text content and scrubs its responses.
+// The response of h is buffered, so it must be small, and it must not be streamed.
+func Handler(h http.Handler, s *Scrubber) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffered := &bufferedResponseWriter{header: http.Header{}, code: http.StatusOK}
+		h.ServeHTTP(buffered, r)
+
+		cleaned, err := s.ScrubBytes(buffered.body.Bytes())
+		if err != nil {
+			http.Error(w, "unable to scrub response", http.StatusInternalServerError)
+			return
+		}
+
+		for key, values := range buffered.header {
+			if key == "Content-Length" {
+				continue
+			}
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+		w.WriteHeader(buffered.code)
+		_, _ = w.Write(cleaned)
+	})
+}
+
+// bufferedResponseWriter holds a response until it's scrubbed.
+type bufferedResponseWriter struct {
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponseWriter) WriteHeader(code int) {
+	if b.wroteHeader {
+		return
+	}
+	b.code = code
+	b.wroteHeader = true
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
@@ -0,0 +1,391 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultLeaseDuration is the duration after which a shard that is not renewed can be taken by another replica.
+	DefaultLeaseDuration = 30 * time.Second
+	// DefaultRenewPeriod is the period at which the Leases are renewed and the shards rebalanced.
+	DefaultRenewPeriod = 10 * time.Second
+
+	groupLabelKey = "sharding.datadoghq.com/group"
+	roleLabelKey  = "sharding.datadoghq.com/role"
+	shardLabelKey = "sharding.datadoghq.com/shard"
+	memberRole    = "member"
+	shardRole     = "shard"
+
+	// staleMemberLeaseDurations is the number of lease durations after which the expired Lease of
+	// a replica that was not stopped gracefully is deleted.
+	staleMemberLeaseDurations = 10
+)
+
+// LeaseManagerOptions configures a LeaseManager.
+type LeaseManagerOptions struct {
+	// Namespace is the namespace of the Leases, usually the namespace of the operator.
+	Namespace string
+	// Group prefixes the names of the Leases.
+	Group string
+	// Identity identifies the replica in the Leases it holds.
+	Identity string
+	// Shards is the number of shards.
+	Shards        int
+	LeaseDuration time.Duration
+	RenewPeriod   time.Duration
+}
+
+// LeaseManager holds a share of the shards on behalf of a replica of the operator. Each replica
+// renews a member Lease, so that the replicas know how many they are, and holds at most
+// ceil(shards / replicas) shard Leases. When a replica joins, the others release their extra
+// shards; when a replica stops, or stops renewing its Leases, the others take its shards over.
+//
+// It runs on all the replicas, not only on the leader.
+type LeaseManager struct {
+	client  client.Client
+	reader  client.Reader
+	log     logr.Logger
+	options LeaseManagerOptions
+	now     func() time.Time
+
+	mu sync.RWMutex
+	// owned are the shards held by the replica, with the time until which the ownership is valid
+	// if the Lease is not renewed.
+	owned map[int]time.Time
+}
+
+// NewLeaseManager returns a LeaseManager. The Leases are read with the reader, which should not
+// be cached, and written with the client.
+func NewLeaseManager(c client.Client, reader client.Reader, log logr.Logger, options LeaseManagerOptions) *LeaseManager {
+	if options.LeaseDuration <= 0 {
+		options.LeaseDuration = DefaultLeaseDuration
+	}
+	if options.RenewPeriod <= 0 {
+		options.RenewPeriod = DefaultRenewPeriod
+	}
+	return &LeaseManager{
+		client:  c,
+		reader:  reader,
+		log:     log,
+		options: options,
+		now:     time.Now,
+		owned:   map[int]time.Time{},
+	}
+}
+
+// NewIdentity returns a unique identity for the replica, built from its hostname.
+func NewIdentity() string {
+	hostname, _ := os.Hostname()
+	return hostname + "_" + uuid.NewString()
+}
+
+// Owns implements Sharder.
+func (m *LeaseManager) Owns(obj client.Object) bool {
+	shard := ShardOf(obj.GetNamespace(), obj.GetName(), m.options.Shards)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	until, found := m.owned[shard]
+	return found && m.now().Before(until)
+}
+
+// OwnedShards returns the shards currently held by the replica.
+func (m *LeaseManager) OwnedShards() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	now := m.now()
+	shards := []int{}
+	for shard, until := range m.owned {
+		if now.Before(until) {
+			shards = append(shards, shard)
+		}
+	}
+	slices.Sort(shards)
+	return shards
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: the shards are held by all the replicas.
+func (m *LeaseManager) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. The shards are released when the context is cancelled.
+func (m *LeaseManager) Start(ctx context.Context) error {
+	m.log.Info("Starting shard lease manager", "shards", m.options.Shards, "identity", m.options.Identity)
+	ticker := time.NewTicker(m.options.RenewPeriod)
+	defer ticker.Stop()
+	for {
+		m.sync(ctx)
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), m.options.RenewPeriod)
+			defer cancel()
+			m.releaseAll(releaseCtx)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync renews the member Lease of the replica, and renews, releases or acquires shard Leases
+// to hold its share of the shards.
+func (m *LeaseManager) sync(ctx context.Context) {
+	now := m.now()
+	members, err := m.syncMembers(ctx, now)
+	if err != nil {
+		m.log.Error(err, "Unable to renew the member Lease")
+		return
+	}
+	leases, err := m.listLeases(ctx, shardRole)
+	if err != nil {
+		m.log.Error(err, "Unable to list the shard Leases")
+		return
+	}
+
+	target := (m.options.Shards + members - 1) / members
+	var held, free []int
+	for shard := range m.options.Shards {
+		lease := leases[shard]
+		switch {
+		case lease == nil || ptr.Deref(lease.Spec.HolderIdentity, "") == "" || m.expired(lease, now):
+			free = append(free, shard)
+		case *lease.Spec.HolderIdentity == m.options.Identity:
+			held = append(held, shard)
+		}
+	}
+
+	m.mu.Lock()
+	for shard := range m.owned {
+		if !slices.Contains(held, shard) {
+			delete(m.owned, shard)
+		}
+	}
+	m.mu.Unlock()
+
+	count := 0
+	for _, shard := range held {
+		if count >= target {
+			m.release(ctx, leases[shard])
+			continue
+		}
+		if m.renew(ctx, leases[shard], now) {
+			count++
+		}
+	}
+	for _, shard := range free {
+		if count >= target {
+			break
+		}
+		if m.acquire(ctx, shard, leases[shard], now) {
+			count++
+		}
+	}
+}
+
+// syncMembers renews the member Lease of the replica and returns the number of live replicas.
+func (m *LeaseManager) syncMembers(ctx context.Context, now time.Time) (int, error) {
+	name := m.memberLeaseName()
+	lease := &coordinationv1.Lease{}
+	err := m.reader.Get(ctx, client.ObjectKey{Namespace: m.options.Namespace, Name: name}, lease)
+	switch {
+	case apierrors.IsNotFound(err):
+		lease = m.newLease(name, memberRole, nil, now)
+		if err = m.client.Create(ctx, lease); err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	default:
+		lease.Spec.HolderIdentity = ptr.To(m.options.Identity)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.options.LeaseDuration.Seconds()))
+		lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(now))
+		if err = m.client.Update(ctx, lease); err != nil {
+			return 0, err
+		}
+	}
+
+	leases, err := m.listLeases(ctx, memberRole)
+	if err != nil {
+		return 0, err
+	}
+	members := 1
+	for _, member := range leases {
+		if member.Name == name {
+			continue
+		}
+		if !m.expired(member, now) {
+			members++
+		} else if m.expired(member, now.Add(-staleMemberLeaseDurations*m.options.LeaseDuration)) {
+			if err := m.client.Delete(ctx, member); client.IgnoreNotFound(err) != nil {
+				m.log.V(1).Info("Unable to delete a stale member Lease", "lease", member.Name, "error", err)
+			}
+		}
+	}
+	return members, nil
+}
+
+// listLeases lists the Leases of the group with this role, by shard for the shard Leases.
+func (m *LeaseManager) listLeases(ctx context.Context, role string) (map[int]*coordinationv1.Lease, error) {
+	list := &coordinationv1.LeaseList{}
+	if err := m.reader.List(ctx, list, client.InNamespace(m.options.Namespace), client.MatchingLabels{
+		groupLabelKey: m.options.Group,
+		roleLabelKey:  role,
+	}); err != nil {
+		return nil, err
+	}
+	leases := make(map[int]*coordinationv1.Lease, len(list.Items))
+	for i := range list.Items {
+		key := i
+		if role == shardRole {
+			shard, err := strconv.Atoi(list.Items[i].Labels[shardLabelKey])
+			if err != nil {
+				continue
+			}
+			key = shard
+		}
+		leases[key] = &list.Items[i]
+	}
+	return leases, nil
+}
+
+func (m *LeaseManager) renew(ctx context.Context, lease *coordinationv1.Lease, now time.Time) bool {
+	shard := m.shardOf(lease)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.options.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(now))
+	if err := m.client.Update(ctx, lease); err != nil {
+		m.log.Info("Unable to renew a shard Lease", "shard", shard, "error", err)
+		m.setOwned(shard, false, now)
+		return false
+	}
+	m.setOwned(shard, true, now)
+	return true
+}
+
+func (m *LeaseManager) acquire(ctx context.Context, shard int, lease *coordinationv1.Lease, now time.Time) bool {
+	var err error
+	if lease == nil {
+		err = m.client.Create(ctx, m.newLease(m.shardLeaseName(shard), shardRole, &shard, now))
+	} else {
+		lease.Spec.HolderIdentity = ptr.To(m.options.Identity)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.options.LeaseDuration.Seconds()))
+		lease.Spec.AcquireTime = ptr.To(metav1.NewMicroTime(now))
+		lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(now))
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		err = m.client.Update(ctx, lease)
+	}
+	if err != nil {
+		// Another replica acquired the shard first.
+		m.log.V(1).Info("Unable to acquire a shard Lease", "shard", shard, "error", err)
+		return false
+	}
+	m.log.Info("Acquired shard", "shard", shard)
+	m.setOwned(shard, true, now)
+	return true
+}
+
+func (m *LeaseManager) release(ctx context.Context, lease *coordinationv1.Lease) {
+	shard := m.shardOf(lease)
+	// Stop reconciling the objects of the shard before another replica can acquire it.
+	m.setOwned(shard, false, m.now())
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	if err := m.client.Update(ctx, lease); err != nil {
+		m.log.Info("Unable to release a shard Lease, it is taken over once expired", "shard", shard, "error", err)
+		return
+	}
+	m.log.Info("Released shard", "shard", shard)
+}
+
+// releaseAll releases the shards held by the replica and deletes its member Lease.
+func (m *LeaseManager) releaseAll(ctx context.Context) {
+	leases, err := m.listLeases(ctx, shardRole)
+	if err != nil {
+		m.log.Error(err, "Unable to release the shard Leases")
+		return
+	}
+	for _, lease := range leases {
+		if ptr.Deref(lease.Spec.HolderIdentity, "") == m.options.Identity {
+			m.release(ctx, lease)
+		}
+	}
+	member := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: m.options.Namespace, Name: m.memberLeaseName()}}
+	if err := m.client.Delete(ctx, member); client.IgnoreNotFound(err) != nil {
+		m.log.Error(err, "Unable to delete the member Lease")
+	}
+}
+
+func (m *LeaseManager) setOwned(shard int, owned bool, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !owned {
+		delete(m.owned, shard)
+		return
+	}
+	// The ownership lapses one renew period before the Lease expires, so that the replica stops
+	// reconciling the shard before another replica can acquire it.
+	m.owned[shard] = now.Add(m.options.LeaseDuration - m.options.RenewPeriod)
+}
+
+func (m *LeaseManager) expired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
+
+func (m *LeaseManager) newLease(name, role string, shard *int, now time.Time) *coordinationv1.Lease {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: m.options.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				groupLabelKey: m.options.Group,
+				roleLabelKey:  role,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(m.options.Identity),
+			LeaseDurationSeconds: ptr.To(int32(m.options.LeaseDuration.Seconds())),
+			AcquireTime:          ptr.To(metav1.NewMicroTime(now)),
+			RenewTime:            ptr.To(metav1.NewMicroTime(now)),
+		},
+	}
+	if shard != nil {
+		lease.Labels[shardLabelKey] = strconv.Itoa(*shard)
+	}
+	return lease
+}
+
+func (m *LeaseManager) shardOf(lease *coordinationv1.Lease) int {
+	shard, _ := strconv.Atoi(lease.Labels[shardLabelKey])
+	return shard
+}
+
+func (m *LeaseManager) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", m.options.Group, shard)
+}
+
+func (m *LeaseManager) memberLeaseName() string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(m.options.Identity))
+	return fmt.Sprintf("%s-member-%08x", m.options.Group, h.Sum32())
+}
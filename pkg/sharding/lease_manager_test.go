@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sharding
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/api/datadoghq/v1alpha1"
+)
+
+func TestShardOf(t *testing.T) {
+	assert.Equal(t, 0, ShardOf("default", "monitor", 0))
+	assert.Equal(t, 0, ShardOf("default", "monitor", 1))
+
+	counts := make([]int, 4)
+	for i := range 1000 {
+		shard := ShardOf("default", "monitor-"+strconv.Itoa(i), 4)
+		require.GreaterOrEqual(t, shard, 0)
+		require.Less(t, shard, 4)
+		counts[shard]++
+	}
+	for shard, count := range counts {
+		assert.Greater(t, count, 150, "shard %d is underused", shard)
+	}
+	assert.Equal(t, ShardOf("default", "monitor", 4), ShardOf("default", "monitor", 4), "the shard of an object is stable")
+}
+
+func TestLeaseManager(t *testing.T) {
+	const shards = 4
+	scheme := runtime.NewScheme()
+	require.NoError(t, coordinationv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	newManager := func(identity string) *LeaseManager {
+		m := NewLeaseManager(c, c, logf.Log, LeaseManagerOptions{Namespace: "datadog", Group: "datadog-operator", Identity: identity, Shards: shards})
+		m.now = func() time.Time { return now }
+		return m
+	}
+	objectOfShard := func(shard int) client.Object {
+		for i := 0; ; i++ {
+			obj := &v1alpha1.DatadogMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "monitor-" + strconv.Itoa(i)}}
+			if ShardOf(obj.Namespace, obj.Name, shards) == shard {
+				return obj
+			}
+		}
+	}
+
+	a := newManager("replica-a")
+	a.sync(ctx)
+	require.Equal(t, []int{0, 1, 2, 3}, a.OwnedShards(), "a single replica holds all the shards")
+	for shard := range shards {
+		assert.True(t, a.Owns(objectOfShard(shard)))
+	}
+
+	b := newManager("replica-b")
+	b.sync(ctx)
+	assert.Empty(t, b.OwnedShards(), "the shards are held by the first replica")
+	a.sync(ctx)
+	assert.Len(t, a.OwnedShards(), 2, "the first replica releases its extra shards")
+	b.sync(ctx)
+	assert.Len(t, b.OwnedShards(), 2, "the second replica acquires the released shards")
+	assert.ElementsMatch(t, []int{0, 1, 2, 3}, append(a.OwnedShards(), b.OwnedShards()...))
+	for shard := range shards {
+		assert.NotEqual(t, a.Owns(objectOfShard(shard)), b.Owns(objectOfShard(shard)), "shard %d is reconciled by a single replica", shard)
+	}
+
+	t.Run("shards of a replica that stops renewing its Leases are taken over", func(t *testing.T) {
+		now = now.Add(DefaultLeaseDuration + time.Second)
+		assert.Empty(t, a.OwnedShards(), "the ownership lapses when the Leases are not renewed")
+		b.sync(ctx)
+		assert.Equal(t, []int{0, 1, 2, 3}, b.OwnedShards())
+	})
+
+	t.Run("shards are released when a replica stops", func(t *testing.T) {
+		b.releaseAll(ctx)
+		assert.Empty(t, b.OwnedShards())
+
+		replicaC := newManager("replica-c")
+		replicaC.sync(ctx)
+		assert.Equal(t, []int{0, 1, 2, 3}, replicaC.OwnedShards())
+	})
+}
@@ -0,0 +1,30 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package sharding spreads the reconciliation of objects across the replicas of the operator.
+// Each object belongs to a shard, computed from the hash of its namespace and name, and each
+// shard is reconciled by the replica holding its Lease.
+package sharding
+
+import (
+	"hash/fnv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Sharder tells whether the replica of the operator is responsible for reconciling an object.
+type Sharder interface {
+	Owns(obj client.Object) bool
+}
+
+// ShardOf returns the shard of the object with this namespace and name, between 0 and shards-1.
+func ShardOf(namespace, name string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace + "/" + name))
+	return int(h.Sum32() % uint32(shards))
+}
@@ -614,6 +614,19 @@ func (builder *DatadogAgentBuilder) WithLogCollectionPaths(podLogs, containerLog
 	return builder
 }
 
+func (builder *DatadogAgentBuilder) WithLogCollectionProcessingRules(rules ...v2alpha1.LogProcessingRule) *DatadogAgentBuilder {
+	builder.initLogCollection()
+	builder.datadogAgent.Spec.Features.LogCollection.ProcessingRules = rules
+	return builder
+}
+
+func (builder *DatadogAgentBuilder) WithLogCollectionContainerFilters(include, exclude *v2alpha1.LogContainerFilter) *DatadogAgentBuilder {
+	builder.initLogCollection()
+	builder.datadogAgent.Spec.Features.LogCollection.ContainerInclude = include
+	builder.datadogAgent.Spec.Features.LogCollection.ContainerExclude = exclude
+	return builder
+}
+
 // Event Collection
 func (builder *DatadogAgentBuilder) initEventCollection() {
 	if builder.datadogAgent.Spec.Features.EventCollection == nil {
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vpa
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionVerticalPodAutoscalerListKind return the schema.GroupVersionKind for VerticalPodAutoscalerList
+func GroupVersionVerticalPodAutoscalerListKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "autoscaling.k8s.io",
+		Version: "v1",
+		Kind:    "VerticalPodAutoscalerList",
+	}
+}
+
+// GroupVersionVerticalPodAutoscalerKind return the schema.GroupVersionKind for VerticalPodAutoscaler
+func GroupVersionVerticalPodAutoscalerKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "autoscaling.k8s.io",
+		Version: "v1",
+		Kind:    "VerticalPodAutoscaler",
+	}
+}
+
+// EmptyUnstructuredVerticalPodAutoscalerList return a new unstructured.UnstructuredList for VerticalPodAutoscaler
+func EmptyUnstructuredVerticalPodAutoscalerList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersionVerticalPodAutoscalerListKind())
+
+	return list
+}
+
+// EmptyUnstructuredVerticalPodAutoscaler return a new unstructured.Unstructured for VerticalPodAutoscaler
+func EmptyUnstructuredVerticalPodAutoscaler() *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(GroupVersionVerticalPodAutoscalerKind())
+
+	return vpa
+}
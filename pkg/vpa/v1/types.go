@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package vpa
+
+import (
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContainerNameAll is the container name matching all the containers in a container resource policy
+const ContainerNameAll = "*"
+
+// VerticalPodAutoscaler is the subset of the VerticalPodAutoscaler fields managed by the operator
+type VerticalPodAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec VerticalPodAutoscalerSpec `json:"spec"`
+}
+
+// VerticalPodAutoscalerSpec is a VerticalPodAutoscaler spec
+type VerticalPodAutoscalerSpec struct {
+	TargetRef      *autoscalingv1.CrossVersionObjectReference `json:"targetRef"`
+	UpdatePolicy   *PodUpdatePolicy                           `json:"updatePolicy,omitempty"`
+	ResourcePolicy *PodResourcePolicy                         `json:"resourcePolicy,omitempty"`
+}
+
+// PodUpdatePolicy is a VerticalPodAutoscaler update policy
+type PodUpdatePolicy struct {
+	UpdateMode string `json:"updateMode,omitempty"`
+}
+
+// PodResourcePolicy is a VerticalPodAutoscaler resource policy
+type PodResourcePolicy struct {
+	ContainerPolicies []ContainerResourcePolicy `json:"containerPolicies,omitempty"`
+}
+
+// ContainerResourcePolicy is a VerticalPodAutoscaler container resource policy
+type ContainerResourcePolicy struct {
+	ContainerName       string                `json:"containerName,omitempty"`
+	MinAllowed          corev1.ResourceList   `json:"minAllowed,omitempty"`
+	MaxAllowed          corev1.ResourceList   `json:"maxAllowed,omitempty"`
+	ControlledResources []corev1.ResourceName `json:"controlledResources,omitempty"`
+	ControlledValues    string                `json:"controlledValues,omitempty"`
+}